@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+func NewCommand(ctx context.Context) *cobra.Command {
+	command := &cobra.Command{
+		Use:   fmt.Sprintf("%s [command]", cmd.CommandNameAuth),
+		Short: "Authenticate with third party services",
+		Example: `  its auth trakt
+  its auth simkl`,
+		RunE: func(c *cobra.Command, args []string) error {
+			return c.Help()
+		},
+	}
+	command.AddCommand(
+		newSimklCommand(ctx),
+		newTraktCommand(ctx),
+	)
+	return command
+}
+
+func newTraktCommand(ctx context.Context) *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   cmd.CommandNameTrakt,
+		Short: "Authorize the application to access your Trakt account using the device code flow",
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			conf, err = loadConfig(c, config.SyncDestinationTrakt)
+			return err
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			store, err := state.NewStore(*conf.State.Dir, *conf.State.URL)
+			if err != nil {
+				return fmt.Errorf("error initialising state store: %w", err)
+			}
+			prompt := func(authCodes *entities.TraktAuthCodesResponse) {
+				c.Printf("Open %s in your browser and enter the code: %s\n", authCodes.VerificationURL, authCodes.UserCode)
+			}
+			if err = client.AuthorizeTraktDevice(ctx, conf.Trakt, conf.HTTP, store, logger.NewLogger(os.Stderr), prompt); err != nil {
+				return fmt.Errorf("error authorizing trakt: %w", err)
+			}
+			c.Printf("Successfully authorized, set TRAKT_AUTH to %s to use the stored tokens\n", config.TraktAuthMethodDevice)
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to use, which is required when the config defines profiles")
+	return command
+}
+
+func newSimklCommand(ctx context.Context) *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   cmd.CommandNameSimkl,
+		Short: "Authorize the application to access your Simkl account using the pin flow",
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			conf, err = loadConfig(c, config.SyncDestinationSimkl)
+			return err
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			store, err := state.NewStore(*conf.State.Dir, *conf.State.URL)
+			if err != nil {
+				return fmt.Errorf("error initialising state store: %w", err)
+			}
+			prompt := func(pin *entities.SimklPinResponse) {
+				c.Printf("Open %s in your browser and enter the code: %s\n", pin.VerificationURL, pin.UserCode)
+			}
+			if err = client.AuthorizeSimklPin(ctx, conf.Simkl, conf.HTTP, store, logger.NewLogger(os.Stderr), prompt); err != nil {
+				return fmt.Errorf("error authorizing simkl: %w", err)
+			}
+			c.Printf("Successfully authorized, set SYNC_DESTINATION to %s to sync to Simkl\n", config.SyncDestinationSimkl)
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to use, which is required when the config defines profiles")
+	return command
+}
+
+// loadConfig returns the config of the destination that is authorized, so that the tokens are stored in the state of
+// that destination, which is apart from the state of SYNC_DESTINATION for the destinations of SYNC_DESTINATIONS.
+func loadConfig(c *cobra.Command, destination string) (*config.Config, error) {
+	conf, err := cmd.LoadConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	if destinationConf, err := conf.DestinationConfig(destination); err == nil {
+		return destinationConf, nil
+	}
+	return conf, nil
+}
@@ -0,0 +1,103 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/backup"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+func NewCommand(ctx context.Context) *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   cmd.CommandNameBackup,
+		Short: "Back up Trakt ratings, watchlist, lists, history and collection to a JSON archive",
+		Example: `  its backup
+  its backup --output-dir /backups/trakt`,
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			conf, err = loadConfig(c)
+			return err
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			outputDir, err := c.Flags().GetString(cmd.FlagNameOutputDir)
+			if err != nil {
+				return err
+			}
+			manager, err := newManager(ctx, conf)
+			if err != nil {
+				return err
+			}
+			path, err := manager.Backup(outputDir)
+			if err != nil {
+				return fmt.Errorf("error backing up trakt data: %w", err)
+			}
+			c.Printf("Successfully backed up Trakt data to %s\n", path)
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to use, which is required when the config defines profiles")
+	command.Flags().String(cmd.FlagNameOutputDir, cmd.BackupDirDefault, "directory to write the backup archive to")
+	return command
+}
+
+// NewRestoreCommand returns the command that restores the archives created by the backup command.
+func NewRestoreCommand(ctx context.Context) *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:     fmt.Sprintf("%s <archive>", cmd.CommandNameRestore),
+		Short:   "Restore Trakt data from a backup archive",
+		Example: `  its restore backup/trakt-backup-20240131T120000Z.json`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			conf, err = loadConfig(c)
+			return err
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			manager, err := newManager(ctx, conf)
+			if err != nil {
+				return err
+			}
+			if err = manager.Restore(args[0]); err != nil {
+				return fmt.Errorf("error restoring trakt data: %w", err)
+			}
+			c.Printf("Successfully restored Trakt data from %s\n", args[0])
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to use, which is required when the config defines profiles")
+	return command
+}
+
+func loadConfig(c *cobra.Command) (*config.Config, error) {
+	conf, err := cmd.LoadConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	if *conf.Sync.Destination != config.SyncDestinationTrakt {
+		return nil, fmt.Errorf("backups are only supported for sync destination %s", config.SyncDestinationTrakt)
+	}
+	return conf, nil
+}
+
+func newManager(ctx context.Context, conf *config.Config) (*backup.Manager, error) {
+	store, err := state.NewStore(*conf.State.Dir, *conf.State.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error initialising state store: %w", err)
+	}
+	log := logger.NewLogger(os.Stderr)
+	traktClient, err := client.NewTraktClient(ctx, conf.Trakt, conf.HTTP, store, log)
+	if err != nil {
+		return nil, fmt.Errorf("error initialising trakt client: %w", err)
+	}
+	return backup.NewManager(traktClient, log), nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+)
+
+// LoadConfig loads and validates the config file of the command. Commands that have the profile flag get the config
+// of the selected profile, and commands that have the destination flag get the config of the selected destination,
+// since both keep their state apart from the state of the top level config.
+func LoadConfig(c *cobra.Command) (*config.Config, error) {
+	confPath, err := c.Flags().GetString(FlagNameConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	conf, err := config.New(confPath, true)
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+	if c.Flags().Lookup(FlagNameProfile) != nil {
+		name, err := c.Flags().GetString(FlagNameProfile)
+		if err != nil {
+			return nil, err
+		}
+		if conf, err = SelectProfile(conf, name); err != nil {
+			return nil, err
+		}
+	}
+	if err = conf.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating config: %w", err)
+	}
+	if c.Flags().Lookup(FlagNameDestination) == nil {
+		return conf, nil
+	}
+	destination, err := c.Flags().GetString(FlagNameDestination)
+	if err != nil {
+		return nil, err
+	}
+	if destination == "" {
+		return conf, nil
+	}
+	if conf, err = conf.DestinationConfig(destination); err != nil {
+		return nil, fmt.Errorf("error selecting destination: %w", err)
+	}
+	return conf, nil
+}
+
+// SelectProfile returns the config of the profile with the given name. A profile has to be selected when the config
+// defines profiles, while the top level config is returned when it does not.
+func SelectProfile(conf *config.Config, name string) (*config.Config, error) {
+	profiles, err := conf.Profiles()
+	if err != nil {
+		return nil, fmt.Errorf("error loading config profiles: %w", err)
+	}
+	if len(profiles) == 1 && profiles[0].Profile() == "" {
+		if name != "" {
+			return nil, fmt.Errorf("error finding config profile %s, the config defines no profiles", name)
+		}
+		return profiles[0], nil
+	}
+	idx := slices.IndexFunc(profiles, func(profile *config.Config) bool {
+		return profile.Profile() == name
+	})
+	if idx == -1 {
+		names := make([]string, len(profiles))
+		for i, profile := range profiles {
+			names[i] = profile.Profile()
+		}
+		if name == "" {
+			return nil, fmt.Errorf("flag '%s' is required since the config defines profiles: %s", FlagNameProfile, strings.Join(names, ", "))
+		}
+		return nil, fmt.Errorf("error finding config profile %s, the config defines profiles: %s", name, strings.Join(names, ", "))
+	}
+	return profiles[idx], nil
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+)
+
+func TestSelectProfile(t *testing.T) {
+	withProfiles, err := config.NewFromMap(map[string]interface{}{
+		"PROFILES_alice_TRAKT_EMAIL": "alice@domain.com",
+		"PROFILES_bob_TRAKT_EMAIL":   "bob@domain.com",
+	})
+	require.NoError(t, err)
+	withoutProfiles, err := config.NewFromMap(map[string]interface{}{})
+	require.NoError(t, err)
+	tests := []struct {
+		name       string
+		conf       *config.Config
+		profile    string
+		assertions func(*assert.Assertions, *config.Config, error)
+	}{
+		{
+			name:    "select profile",
+			conf:    withProfiles,
+			profile: "alice",
+			assertions: func(assertions *assert.Assertions, conf *config.Config, err error) {
+				assertions.NoError(err)
+				assertions.Equal("alice", conf.Profile())
+				assertions.Equal(filepath.Join(config.StateDirDefault, "alice"), *conf.State.Dir)
+				assertions.Equal("alice@domain.com", *conf.Trakt.Email)
+			},
+		},
+		{
+			name: "failure selecting no profile when the config defines profiles",
+			conf: withProfiles,
+			assertions: func(assertions *assert.Assertions, conf *config.Config, err error) {
+				assertions.ErrorContains(err, FlagNameProfile)
+			},
+		},
+		{
+			name:    "failure selecting unknown profile",
+			conf:    withProfiles,
+			profile: "carol",
+			assertions: func(assertions *assert.Assertions, conf *config.Config, err error) {
+				assertions.ErrorContains(err, "carol")
+			},
+		},
+		{
+			name: "top level config without profiles",
+			conf: withoutProfiles,
+			assertions: func(assertions *assert.Assertions, conf *config.Config, err error) {
+				assertions.NoError(err)
+				assertions.Same(withoutProfiles, conf)
+			},
+		},
+		{
+			name:    "failure selecting profile when the config defines no profiles",
+			conf:    withoutProfiles,
+			profile: "alice",
+			assertions: func(assertions *assert.Assertions, conf *config.Config, err error) {
+				assertions.ErrorContains(err, "defines no profiles")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf, err := SelectProfile(tt.conf, tt.profile)
+			tt.assertions(assert.New(t), conf, err)
+		})
+	}
+}
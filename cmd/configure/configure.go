@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
@@ -16,8 +17,13 @@ func NewCommand(ctx context.Context) *cobra.Command {
 	var conf *config.Config
 	var confPath string
 	command := &cobra.Command{
-		Use:   fmt.Sprintf("%s [command]", cmd.CommandNameConfigure),
-		Short: "Configure provider credentials and sync options",
+		Use:     fmt.Sprintf("%s [command]", cmd.CommandNameConfigure),
+		Aliases: []string{cmd.CommandAliasConfigure},
+		Short:   "Configure provider credentials and sync options",
+		Example: `  its configure
+  its config init
+  its config validate --config-file ./config.yaml
+  its config encrypt --method age`,
 		PreRunE: func(c *cobra.Command, args []string) (err error) {
 			confPath, err = c.Flags().GetString(cmd.FlagNameConfigFile)
 			if err != nil {
@@ -57,5 +63,101 @@ func NewCommand(ctx context.Context) *cobra.Command {
 		},
 	}
 	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.AddCommand(
+		newInitCommand(),
+		newValidateCommand(),
+		newEncryptCommand(),
+	)
+	return command
+}
+
+func newInitCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   cmd.CommandNameInit,
+		Short: "Create a config file populated with the default values",
+		RunE: func(c *cobra.Command, args []string) error {
+			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
+			if err != nil {
+				return err
+			}
+			force, err := c.Flags().GetBool(cmd.FlagNameForce)
+			if err != nil {
+				return err
+			}
+			if _, err = os.Stat(confPath); err == nil && !force {
+				return fmt.Errorf("config file %s already exists, use --%s to overwrite it", confPath, cmd.FlagNameForce)
+			}
+			conf, err := config.NewDefault()
+			if err != nil {
+				return fmt.Errorf("error creating default config: %w", err)
+			}
+			if err = conf.WriteFile(confPath); err != nil {
+				return fmt.Errorf("error writing config file: %w", err)
+			}
+			c.Printf("Created config file %s, run '%s %s' to fill in your credentials\n", confPath, cmd.CommandNameRoot, cmd.CommandNameConfigure)
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().Bool(cmd.FlagNameForce, false, "overwrite the config file if it already exists")
+	return command
+}
+
+func newValidateCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   cmd.CommandNameValidate,
+		Short: "Check that the config file and environment variables form a valid configuration",
+		RunE: func(c *cobra.Command, args []string) error {
+			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
+			if err != nil {
+				return err
+			}
+			conf, err := config.New(confPath, true)
+			if err != nil {
+				return fmt.Errorf("error loading config: %w", err)
+			}
+			if err = conf.Validate(); err != nil {
+				return fmt.Errorf("error validating config: %w", err)
+			}
+			c.Printf("Config file %s is valid\n", confPath)
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	return command
+}
+
+func newEncryptCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   cmd.CommandNameEncrypt,
+		Short: "Encrypt the credentials of the config file, so that it can be shared or committed safely",
+		Example: `  ITS_SECRETS_PASSPHRASE=passphrase its config encrypt
+  its config encrypt --method age`,
+		RunE: func(c *cobra.Command, args []string) error {
+			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
+			if err != nil {
+				return err
+			}
+			method, err := c.Flags().GetString(cmd.FlagNameMethod)
+			if err != nil {
+				return err
+			}
+			conf, err := config.New(confPath, false)
+			if err != nil {
+				return fmt.Errorf("error loading config: %w", err)
+			}
+			count, err := conf.EncryptSecrets(method)
+			if err != nil {
+				return fmt.Errorf("error encrypting config: %w", err)
+			}
+			if err = conf.WriteFile(confPath); err != nil {
+				return fmt.Errorf("error writing config file: %w", err)
+			}
+			c.Printf("Encrypted %d fields of config file %s\n", count, confPath)
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().String(cmd.FlagNameMethod, config.EncryptionMethodPassphrase, "encryption method, either passphrase or age")
 	return command
 }
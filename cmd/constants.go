@@ -1,10 +1,56 @@
 package cmd
 
+import "time"
+
 const (
-	CommandAliasRoot     = "imdb-trakt-sync"
-	CommandNameConfigure = "configure"
-	CommandNameRoot      = "its"
-	CommandNameSync      = "sync"
-	ConfigFileDefault    = "config.yaml"
-	FlagNameConfigFile   = "config-file"
+	CommandAliasConfigure     = "config"
+	CommandAliasRoot          = "imdb-trakt-sync"
+	CommandAliasServe         = "cron"
+	CommandNameAuth           = "auth"
+	CommandNameBackup         = "backup"
+	CommandNameClear          = "clear"
+	CommandNameConfigure      = "configure"
+	CommandNameEncrypt        = "encrypt"
+	CommandNameExport         = "export"
+	CommandNameHealthcheck    = "healthcheck"
+	CommandNameInit           = "init"
+	CommandNameList           = "list"
+	CommandNameRestore        = "restore"
+	CommandNameRoot           = "its"
+	CommandNameServe          = "serve"
+	CommandNameSimkl          = "simkl"
+	CommandNameSkipList       = "skiplist"
+	CommandNameStats          = "stats"
+	CommandNameStatus         = "status"
+	CommandNameSync           = "sync"
+	CommandNameTrakt          = "trakt"
+	CommandNameUndo           = "undo"
+	CommandNameValidate       = "validate"
+	BackupDirDefault          = "backup"
+	ConfigFileDefault         = "config.yaml"
+	ExpiryThresholdDefault    = time.Hour * 24 * 7
+	FlagNameConfigFile        = "config-file"
+	FlagNameDaemon            = "daemon"
+	FlagNameDestination       = "destination"
+	FlagNameExpiryThreshold   = "expiry-threshold"
+	FlagNameForce             = "force"
+	FlagNameFormat            = "format"
+	FlagNameIMDbExport        = "imdb-export-dir"
+	FlagNameInteractive       = "interactive"
+	FlagNameInterval          = "interval"
+	FlagNameJitter            = "jitter"
+	FlagNameLast              = "last"
+	FlagNameMaxDuration       = "max-duration"
+	FlagNameMethod            = "method"
+	FlagNameOutputDir         = "output-dir"
+	FlagNameParallel          = "parallel"
+	FlagNameProfile           = "profile"
+	FlagNameProgress          = "progress"
+	FlagNameSchedule          = "schedule"
+	FlagNameSnapshotIn        = "snapshot-in"
+	FlagNameSnapshotOut       = "snapshot-out"
+	FlagNameTimeout           = "timeout"
+	HealthcheckTimeoutDefault = time.Second * 5
+	IntervalDefault           = time.Hour * 12
+	OutputDirDefault          = "export"
 )
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/exporter"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+func NewCommand(ctx context.Context) *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   cmd.CommandNameExport,
+		Short: "Export Trakt ratings, watchlist and lists to IMDb compatible CSV files",
+		Example: `  its export
+  its export --output-dir backup`,
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
+			if err != nil {
+				return err
+			}
+			if conf, err = config.New(confPath, true); err != nil {
+				return fmt.Errorf("error loading config: %w", err)
+			}
+			if err = conf.Validate(); err != nil {
+				return fmt.Errorf("error validating config: %w", err)
+			}
+			if *conf.Sync.Destination != config.SyncDestinationTrakt {
+				return fmt.Errorf("export is only supported for sync destination %s", config.SyncDestinationTrakt)
+			}
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			outputDir, err := c.Flags().GetString(cmd.FlagNameOutputDir)
+			if err != nil {
+				return err
+			}
+			store, err := state.NewStore(*conf.State.Dir, *conf.State.URL)
+			if err != nil {
+				return fmt.Errorf("error initialising state store: %w", err)
+			}
+			log := logger.NewLogger(os.Stderr)
+			traktClient, err := client.NewTraktClient(ctx, conf.Trakt, conf.HTTP, store, log)
+			if err != nil {
+				return fmt.Errorf("error initialising trakt client: %w", err)
+			}
+			if err = exporter.NewExporter(traktClient, outputDir, log).Export(); err != nil {
+				return fmt.Errorf("error exporting trakt data: %w", err)
+			}
+			c.Printf("Successfully exported Trakt data to %s\n", outputDir)
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().String(cmd.FlagNameOutputDir, cmd.OutputDirDefault, "directory to write the CSV files to")
+	return command
+}
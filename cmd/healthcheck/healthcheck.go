@@ -0,0 +1,48 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/server"
+)
+
+func NewCommand(ctx context.Context) *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   cmd.CommandNameHealthcheck,
+		Short: "Check the health endpoint of a running syncer, exiting with a non-zero code when it is unhealthy",
+		Example: `  its healthcheck
+  its healthcheck --timeout 10s`,
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
+			if err != nil {
+				return err
+			}
+			if conf, err = config.New(confPath, true); err != nil {
+				return fmt.Errorf("error loading config: %w", err)
+			}
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			timeout, err := c.Flags().GetDuration(cmd.FlagNameTimeout)
+			if err != nil {
+				return err
+			}
+			timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			if err = server.Probe(timeoutCtx, *conf.Server.Address); err != nil {
+				return fmt.Errorf("error checking health: %w", err)
+			}
+			c.Println("syncer is healthy")
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().Duration(cmd.FlagNameTimeout, cmd.HealthcheckTimeoutDefault, "maximum duration of the health check")
+	return command
+}
@@ -7,8 +7,17 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/cmd/auth"
+	"github.com/cecobask/imdb-trakt-sync/cmd/backup"
 	"github.com/cecobask/imdb-trakt-sync/cmd/configure"
+	"github.com/cecobask/imdb-trakt-sync/cmd/export"
+	"github.com/cecobask/imdb-trakt-sync/cmd/healthcheck"
+	"github.com/cecobask/imdb-trakt-sync/cmd/skiplist"
+	"github.com/cecobask/imdb-trakt-sync/cmd/stats"
+	"github.com/cecobask/imdb-trakt-sync/cmd/status"
 	"github.com/cecobask/imdb-trakt-sync/cmd/sync"
+	"github.com/cecobask/imdb-trakt-sync/cmd/undo"
+	"github.com/cecobask/imdb-trakt-sync/cmd/validate"
 )
 
 func NewCommand(ctx context.Context) *cobra.Command {
@@ -32,8 +41,19 @@ func NewCommand(ctx context.Context) *cobra.Command {
 		Hidden: true,
 	})
 	command.AddCommand(
+		auth.NewCommand(ctx),
+		backup.NewCommand(ctx),
+		backup.NewRestoreCommand(ctx),
 		configure.NewCommand(ctx),
+		export.NewCommand(ctx),
+		healthcheck.NewCommand(ctx),
+		skiplist.NewCommand(ctx),
+		stats.NewCommand(ctx),
+		status.NewCommand(ctx),
 		sync.NewCommand(ctx),
+		sync.NewServeCommand(ctx),
+		undo.NewCommand(ctx),
+		validate.NewCommand(ctx),
 	)
 	command.SetOut(os.Stdout)
 	command.SetErr(os.Stderr)
@@ -0,0 +1,107 @@
+package skiplist
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/skiplist"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+)
+
+func NewCommand(ctx context.Context) *cobra.Command {
+	command := &cobra.Command{
+		Use:   fmt.Sprintf("%s [command]", cmd.CommandNameSkipList),
+		Short: "Manage the items that are skipped because Trakt could not find them",
+		Example: `  its skiplist list
+  its skiplist clear tt0000001
+  its skiplist clear`,
+		RunE: func(c *cobra.Command, args []string) error {
+			return c.Help()
+		},
+	}
+	command.AddCommand(
+		newListCommand(),
+		newClearCommand(),
+	)
+	return command
+}
+
+func newListCommand() *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   cmd.CommandNameList,
+		Short: "List the skipped items and when they are retried",
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			conf, err = cmd.LoadConfig(c)
+			return err
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			store, err := state.NewStore(*conf.State.Dir, *conf.State.URL)
+			if err != nil {
+				return fmt.Errorf("error initialising state store: %w", err)
+			}
+			entries, err := skiplist.Load(store)
+			if err != nil {
+				return fmt.Errorf("error loading skip-list: %w", err)
+			}
+			if len(entries) == 0 {
+				c.Println("No items are skipped")
+				return nil
+			}
+			tw := tabwriter.NewWriter(c.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "IMDB\tTYPE\tMISSES\tFIRST MISSED\tLAST MISSED\tRETRY AT")
+			for _, entry := range entries {
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\n",
+					entry.IMDb,
+					entry.Type,
+					entry.Misses,
+					entry.FirstMissedAt.Local().Format(time.DateTime),
+					entry.LastMissedAt.Local().Format(time.DateTime),
+					entry.RetryAt(*conf.Sync.SkipListTTL).Local().Format(time.DateTime),
+				)
+			}
+			if err = tw.Flush(); err != nil {
+				return fmt.Errorf("error printing skip-list: %w", err)
+			}
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to use, which is required when the config defines profiles")
+	command.Flags().String(cmd.FlagNameDestination, "", "destination of the skip-list, either SYNC_DESTINATION or one of SYNC_DESTINATIONS (default SYNC_DESTINATION)")
+	return command
+}
+
+func newClearCommand() *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   fmt.Sprintf("%s [imdb-id...]", cmd.CommandNameClear),
+		Short: "Remove the given items from the skip-list, or every item when none are given",
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			conf, err = cmd.LoadConfig(c)
+			return err
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			store, err := state.NewStore(*conf.State.Dir, *conf.State.URL)
+			if err != nil {
+				return fmt.Errorf("error initialising state store: %w", err)
+			}
+			cleared, err := skiplist.Clear(store, args...)
+			if err != nil {
+				return fmt.Errorf("error clearing skip-list: %w", err)
+			}
+			c.Printf("Removed %d item(s) from the skip-list, they are retried on the next sync\n", cleared)
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to use, which is required when the config defines profiles")
+	command.Flags().String(cmd.FlagNameDestination, "", "destination of the skip-list, either SYNC_DESTINATION or one of SYNC_DESTINATIONS (default SYNC_DESTINATION)")
+	return command
+}
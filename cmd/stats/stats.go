@@ -0,0 +1,67 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/internal/stats"
+)
+
+func NewCommand(ctx context.Context) *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   cmd.CommandNameStats,
+		Short: "Show the history of the sync runs",
+		Example: `  its stats
+  its stats --last 30
+  its stats --format csv > stats.csv`,
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			format, err := c.Flags().GetString(cmd.FlagNameFormat)
+			if err != nil {
+				return err
+			}
+			if !slices.Contains(stats.Formats(), format) {
+				return fmt.Errorf("flag '%s' must be one of: %s", cmd.FlagNameFormat, strings.Join(stats.Formats(), ", "))
+			}
+			conf, err = cmd.LoadConfig(c)
+			return err
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			format, err := c.Flags().GetString(cmd.FlagNameFormat)
+			if err != nil {
+				return err
+			}
+			last, err := c.Flags().GetInt(cmd.FlagNameLast)
+			if err != nil {
+				return err
+			}
+			store, err := state.NewStore(*conf.State.Dir, *conf.State.URL)
+			if err != nil {
+				return fmt.Errorf("error initialising state store: %w", err)
+			}
+			runs, err := stats.Load(store)
+			if err != nil {
+				return fmt.Errorf("error loading sync statistics: %w", err)
+			}
+			if last > 0 && len(runs) > last {
+				runs = runs[len(runs)-last:]
+			}
+			if err = stats.Write(c.OutOrStdout(), runs, format); err != nil {
+				return fmt.Errorf("error printing sync statistics: %w", err)
+			}
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to use, which is required when the config defines profiles")
+	command.Flags().String(cmd.FlagNameFormat, stats.FormatTable, fmt.Sprintf("output format, one of: %s", strings.Join(stats.Formats(), ", ")))
+	command.Flags().Int(cmd.FlagNameLast, 0, "only show the given number of most recent runs, or all runs when 0")
+	return command
+}
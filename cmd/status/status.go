@@ -0,0 +1,55 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+func NewCommand(ctx context.Context) *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   cmd.CommandNameStatus,
+		Short: "Show how far Trakt is out of sync with IMDb, without syncing anything",
+		Example: `  its status
+  its status --destination jellyfin`,
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			conf, err = cmd.LoadConfig(c)
+			return err
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			level, err := logger.ParseLevel(*conf.Log.Level)
+			if err != nil {
+				return fmt.Errorf("error parsing log level: %w", err)
+			}
+			// the logs go to stderr, so that the drift summary can be piped on its own
+			log := logger.NewLoggerWithOptions(os.Stderr, level, *conf.Log.Format)
+			timeoutCtx, cancel := context.WithTimeout(ctx, *conf.Sync.Timeout)
+			defer cancel()
+			s, err := syncer.NewSyncer(timeoutCtx, conf, log)
+			if err != nil {
+				return fmt.Errorf("error creating syncer: %w", err)
+			}
+			defer s.Close()
+			drifts, err := s.Status()
+			if err != nil {
+				return fmt.Errorf("error fetching status: %w", err)
+			}
+			for _, drift := range drifts {
+				c.Println(drift.String())
+			}
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to use, which is required when the config defines profiles")
+	command.Flags().String(cmd.FlagNameDestination, "", "destination to show the status of, either SYNC_DESTINATION or one of SYNC_DESTINATIONS (default SYNC_DESTINATION)")
+	return command
+}
@@ -2,46 +2,465 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	gosync "sync"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/actions"
+	"github.com/cecobask/imdb-trakt-sync/internal/budget"
 	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/notification"
+	"github.com/cecobask/imdb-trakt-sync/internal/progress"
+	"github.com/cecobask/imdb-trakt-sync/internal/review"
+	"github.com/cecobask/imdb-trakt-sync/internal/scheduler"
+	"github.com/cecobask/imdb-trakt-sync/internal/server"
 	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+	"github.com/cecobask/imdb-trakt-sync/internal/tracing"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
 )
 
 func NewCommand(ctx context.Context) *cobra.Command {
-	var conf *config.Config
+	return newCommand(ctx, false)
+}
+
+// NewServeCommand returns the entrypoint of the container images, which syncs on a schedule like the daemon mode of
+// the sync command, and always serves the health endpoints, so that the container runtime can check them.
+func NewServeCommand(ctx context.Context) *cobra.Command {
+	return newCommand(ctx, true)
+}
+
+func newCommand(ctx context.Context, serve bool) *cobra.Command {
+	var (
+		conf     *config.Config
+		profiles []*config.Config
+		log      *slog.Logger
+	)
+	load := func(c *cobra.Command, confPath string) (*config.Config, []*config.Config, error) {
+		loaded, loadedProfiles, err := loadProfiles(c, confPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if serve {
+			enabled := true
+			loaded.Server.Enabled = &enabled
+		}
+		return loaded, loadedProfiles, nil
+	}
 	command := &cobra.Command{
-		Use:   fmt.Sprintf("%s [command]", cmd.CommandNameSync),
-		Short: "Sync IMDb data to Trakt",
+		Use:   cmd.CommandNameSync,
+		Short: "Sync IMDb data to Trakt, Simkl, Jellyfin or Kodi",
+		Example: `  its sync
+  its sync --imdb-export-dir ./exports.zip
+  its sync --interactive
+  its sync --force
+  its sync --max-duration 20m
+  its sync --snapshot-out ./snapshot.json
+  its sync --snapshot-in ./snapshot.json
+  its sync --profile alice --profile bob --parallel
+  its sync --daemon --schedule "0 */6 * * *" --jitter 10m`,
 		PreRunE: func(c *cobra.Command, args []string) (err error) {
 			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
 			if err != nil {
 				return err
 			}
-			if conf, err = config.New(confPath, true); err != nil {
-				return fmt.Errorf("error loading config: %w", err)
+			if conf, profiles, err = load(c, confPath); err != nil {
+				return err
 			}
-			if err = conf.Validate(); err != nil {
-				return fmt.Errorf("error validating config: %w", err)
+			level, err := logger.ParseLevel(*conf.Log.Level)
+			if err != nil {
+				return fmt.Errorf("error parsing log level: %w", err)
 			}
+			log = logger.NewLoggerWithOptions(os.Stdout, level, *conf.Log.Format)
 			return nil
 		},
 		RunE: func(c *cobra.Command, args []string) error {
-			timeoutCtx, cancel := context.WithTimeout(ctx, *conf.Sync.Timeout)
-			defer cancel()
-			s, err := syncer.NewSyncer(timeoutCtx, conf)
+			daemon, err := serve, error(nil)
+			if !serve {
+				if daemon, err = c.Flags().GetBool(cmd.FlagNameDaemon); err != nil {
+					return err
+				}
+			}
+			var sched *scheduler.Scheduler
+			if daemon {
+				if sched, err = buildScheduler(c, conf, log); err != nil {
+					return err
+				}
+			}
+			var health *server.Health
+			if *conf.Server.Enabled {
+				health = server.NewHealth(*conf.Server.MaxSyncAge)
+				srv := server.NewServer(*conf.Server.Address, health, log)
+				if daemon && *conf.Server.TriggerToken != "" {
+					srv.HandleTrigger(*conf.Server.TriggerToken, sched.Trigger)
+				}
+				srv.Start()
+				defer srv.Stop()
+			}
+			notifier, err := buildNotifier(conf)
 			if err != nil {
-				return fmt.Errorf("error creating syncer: %w", err)
+				return fmt.Errorf("error building notifier: %w", err)
 			}
-			if err = s.Sync(); err != nil {
-				return fmt.Errorf("error performing sync: %w", err)
+			shutdownTracing, err := tracing.Setup(ctx, conf.Tracing)
+			if err != nil {
+				return fmt.Errorf("error setting up tracing: %w", err)
 			}
-			return nil
+			defer func() {
+				if err := shutdownTracing(context.WithoutCancel(ctx)); err != nil {
+					log.Warn("failure flushing traces", logger.Error(err))
+				}
+			}()
+			force, err := c.Flags().GetBool(cmd.FlagNameForce)
+			if err != nil {
+				return err
+			}
+			parallel, err := c.Flags().GetBool(cmd.FlagNameParallel)
+			if err != nil {
+				return err
+			}
+			showProgress, err := c.Flags().GetBool(cmd.FlagNameProgress)
+			if err != nil {
+				return err
+			}
+			var prog *progress.Progress
+			if showProgress {
+				prog = progress.New(os.Stdout, log)
+			}
+			if !daemon {
+				interactive, err := c.Flags().GetBool(cmd.FlagNameInteractive)
+				if err != nil {
+					return err
+				}
+				var reviewer syncer.Reviewer
+				if interactive {
+					reviewer = review.NewReviewer(tea.WithContext(ctx), tea.WithOutput(c.OutOrStdout()))
+				}
+				return runProfiles(ctx, profiles, log, notifier, health, reviewer, prog, force, parallel)
+			}
+			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
+			if err != nil {
+				return err
+			}
+			var mu gosync.Mutex
+			reload := func() {
+				reloaded, reloadedProfiles, err := load(c, confPath)
+				if err != nil {
+					log.Error("failure reloading config, the previous config is kept", logger.Error(err))
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				changes := config.Diff(conf, reloaded)
+				if len(changes) == 0 {
+					return
+				}
+				log.Info("config reloaded", slog.Any("changes", changes))
+				if restartRequired(changes) {
+					log.Warn("changes to the log, notification, server and tracing config take effect after a restart")
+				}
+				if *reloaded.Sync.Schedule != *conf.Sync.Schedule {
+					if c.Flags().Changed(cmd.FlagNameSchedule) || c.Flags().Changed(cmd.FlagNameInterval) {
+						log.Warn("ignoring the reloaded schedule, since the schedule is set by a command line flag")
+					} else if schedule, err := buildSchedule(c, reloaded); err != nil {
+						log.Error("failure building reloaded schedule", logger.Error(err))
+					} else {
+						sched.SetSchedule(schedule)
+					}
+				}
+				conf, profiles = reloaded, reloadedProfiles
+			}
+			if err = config.Watch(ctx, confPath, reload); err != nil {
+				log.Warn("failure watching config file, changes take effect after a restart", logger.Error(err))
+			}
+			return sched.Run(ctx, func(ctx context.Context) error {
+				mu.Lock()
+				current := profiles
+				mu.Unlock()
+				return runProfiles(ctx, current, log, notifier, health, nil, prog, force, parallel)
+			})
 		},
 	}
 	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().String(cmd.FlagNameIMDbExport, "", "path to a directory or zip archive of imdb csv exports to be used instead of scraping imdb")
+	command.Flags().Duration(cmd.FlagNameInterval, cmd.IntervalDefault, "interval between syncs in daemon mode")
+	command.Flags().String(cmd.FlagNameSchedule, "", "cron expression to schedule syncs in daemon mode")
+	command.Flags().Duration(cmd.FlagNameJitter, 0, "maximum random delay added to each scheduled sync in daemon mode")
+	command.Flags().Duration(cmd.FlagNameMaxDuration, 0, "time budget of each sync, after which the sync checkpoints its progress and stops, to resume on the next sync")
+	command.Flags().Bool(cmd.FlagNameForce, false, "proceed with the sync even if the imdb data shrank more than the guardrail allows")
+	command.Flags().StringSlice(cmd.FlagNameProfile, nil, "name of a profile to sync, which can be repeated to sync several profiles (default all profiles)")
+	command.Flags().Bool(cmd.FlagNameParallel, false, "sync the profiles in parallel instead of one after the other")
+	command.Flags().Bool(cmd.FlagNameProgress, false, "show progress bars, or log the progress periodically when the output is not a terminal")
+	command.Flags().String(cmd.FlagNameSnapshotOut, "", "path to save the fetched imdb data to, so that the sync can be reproduced later")
+	command.MarkFlagsMutuallyExclusive(cmd.FlagNameInterval, cmd.FlagNameSchedule)
+	if serve {
+		command.Use = cmd.CommandNameServe
+		command.Aliases = []string{cmd.CommandAliasServe}
+		command.Short = "Sync IMDb data on a schedule and serve the health endpoints, as the entrypoint of a container"
+		command.Example = `  its serve
+  its serve --schedule "0 */6 * * *"
+  its cron --interval 6h --jitter 10m`
+		return command
+	}
+	command.Flags().Bool(cmd.FlagNameDaemon, false, "run continuously and trigger syncs on a schedule")
+	command.Flags().Bool(cmd.FlagNameInteractive, false, "review and approve the changes of each sync step before they are applied")
+	command.Flags().String(cmd.FlagNameSnapshotIn, "", "path to an imdb snapshot to sync instead of fetching the imdb data")
+	command.MarkFlagsMutuallyExclusive(cmd.FlagNameDaemon, cmd.FlagNameInteractive)
+	command.MarkFlagsMutuallyExclusive(cmd.FlagNameParallel, cmd.FlagNameInteractive)
+	command.MarkFlagsMutuallyExclusive(cmd.FlagNameSnapshotIn, cmd.FlagNameSnapshotOut)
+	command.MarkFlagsMutuallyExclusive(cmd.FlagNameSnapshotIn, cmd.FlagNameIMDbExport)
+	command.MarkFlagsMutuallyExclusive(cmd.FlagNameSnapshotIn, cmd.FlagNameDaemon)
 	return command
 }
+
+// loadProfiles loads the config file and returns the validated config of each selected profile, along with the top
+// level config.
+func loadProfiles(c *cobra.Command, confPath string) (*config.Config, []*config.Config, error) {
+	conf, err := config.New(confPath, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading config: %w", err)
+	}
+	profiles, err := selectProfiles(c, conf)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, profile := range profiles {
+		if c.Flags().Changed(cmd.FlagNameIMDbExport) {
+			exportDir, err := c.Flags().GetString(cmd.FlagNameIMDbExport)
+			if err != nil {
+				return nil, nil, err
+			}
+			profile.IMDb.ExportDir = &exportDir
+		}
+		if c.Flags().Changed(cmd.FlagNameMaxDuration) {
+			maxDuration, err := c.Flags().GetDuration(cmd.FlagNameMaxDuration)
+			if err != nil {
+				return nil, nil, err
+			}
+			profile.Sync.MaxDuration = &maxDuration
+		}
+		for flag, field := range map[string]**string{
+			cmd.FlagNameSnapshotIn:  &profile.IMDb.SnapshotIn,
+			cmd.FlagNameSnapshotOut: &profile.IMDb.SnapshotOut,
+		} {
+			if !c.Flags().Changed(flag) {
+				continue
+			}
+			snapshotPath, err := c.Flags().GetString(flag)
+			if err != nil {
+				return nil, nil, err
+			}
+			snapshotPath = profileSnapshotPath(snapshotPath, profile.Profile())
+			*field = &snapshotPath
+		}
+		if err = profile.Validate(); err != nil {
+			if profile.Profile() != "" {
+				return nil, nil, fmt.Errorf("error validating config of profile %s: %w", profile.Profile(), err)
+			}
+			return nil, nil, fmt.Errorf("error validating config: %w", err)
+		}
+	}
+	return conf, profiles, nil
+}
+
+// profileSnapshotPath adds the name of the profile to the path of the snapshot, so that each profile keeps a snapshot
+// of its own imdb data.
+func profileSnapshotPath(snapshotPath, profile string) string {
+	if profile == "" {
+		return snapshotPath
+	}
+	ext := filepath.Ext(snapshotPath)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(snapshotPath, ext), profile, ext)
+}
+
+// restartRequired reports whether the changes touch the config that is only read when the daemon starts.
+func restartRequired(changes []string) bool {
+	return slices.ContainsFunc(changes, func(change string) bool {
+		return slices.ContainsFunc([]string{"LOG_", "NOTIFICATION_", "SERVER_", "TRACING_"}, func(prefix string) bool {
+			return strings.HasPrefix(change, prefix)
+		})
+	})
+}
+
+// selectProfiles returns the profiles of the config that were requested with the profile flag, or all of them.
+func selectProfiles(c *cobra.Command, conf *config.Config) ([]*config.Config, error) {
+	profiles, err := conf.Profiles()
+	if err != nil {
+		return nil, fmt.Errorf("error loading config profiles: %w", err)
+	}
+	names, err := c.Flags().GetStringSlice(cmd.FlagNameProfile)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return profiles, nil
+	}
+	selected := make([]*config.Config, 0, len(names))
+	for _, name := range names {
+		idx := slices.IndexFunc(profiles, func(profile *config.Config) bool {
+			return profile.Profile() == name
+		})
+		if idx == -1 {
+			return nil, fmt.Errorf("error finding config profile %s", name)
+		}
+		selected = append(selected, profiles[idx])
+	}
+	return selected, nil
+}
+
+// runProfiles syncs the profiles one after the other, or all at once when parallel is set. A failing profile does not
+// prevent the other profiles from being synced, and the errors of every failing profile are returned.
+func runProfiles(ctx context.Context, profiles []*config.Config, log *slog.Logger, notifier notification.Notifier, health *server.Health, reviewer syncer.Reviewer, prog *progress.Progress, force, parallel bool) error {
+	errs := make([]error, len(profiles))
+	if !parallel {
+		for i, profile := range profiles {
+			errs[i] = runSync(ctx, profile, log, notifier, health, reviewer, prog, force)
+		}
+		return errors.Join(errs...)
+	}
+	var wg gosync.WaitGroup
+	for i, profile := range profiles {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = runSync(ctx, profile, log, notifier, health, reviewer, prog, force)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func runSync(ctx context.Context, conf *config.Config, log *slog.Logger, notifier notification.Notifier, health *server.Health, reviewer syncer.Reviewer, prog *progress.Progress, force bool) (err error) {
+	prefix, reportDir := "", *conf.Report.Dir
+	title, reportTitle := "Sync", "Sync report"
+	if profile := conf.Profile(); profile != "" {
+		log = log.With(slog.String("profile", profile))
+		prefix = fmt.Sprintf("[%s] ", profile)
+		title, reportTitle = fmt.Sprintf("Sync of profile %s", profile), fmt.Sprintf("Sync report of profile %s", profile)
+		if reportDir != "" {
+			reportDir = filepath.Join(reportDir, profile)
+		}
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, *conf.Sync.Timeout)
+	defer cancel()
+	timeoutCtx = budget.WithBudget(timeoutCtx, budget.New(*conf.Sync.MaxDuration))
+	// the span of the sync covers the creation of the clients as well, since they authenticate and fetch data early
+	spanCtx, endSpan := tracing.Start(tracing.WithScope(timeoutCtx), "sync",
+		attribute.String("its.profile", conf.Profile()),
+		attribute.String("its.destination", *conf.Sync.Destination),
+		attribute.String("its.mode", *conf.Sync.Mode),
+	)
+	defer func() {
+		endSpan(err)
+	}()
+	s, err := syncer.NewSyncer(spanCtx, conf, log)
+	health.ObserveCredentials(conf.Profile(), err == nil)
+	if err != nil {
+		err = fmt.Errorf("%serror creating syncer: %w", prefix, err)
+		notify(ctx, notifier, log, fmt.Sprintf("Sync failed: %s", err), "")
+		if actions.Enabled() {
+			actions.Annotate(os.Stdout, actions.LevelError, title, err.Error())
+		}
+		return err
+	}
+	defer s.Close()
+	if reviewer != nil {
+		s.SetReviewer(reviewer)
+	}
+	s.SetForce(force)
+	s.SetProgress(prog)
+	err = s.Sync()
+	summary := s.Summary()
+	notify(ctx, notifier, log, prefix+summary.String(), summary.Markdown(reportTitle))
+	if reportDir != "" {
+		if reportErr := summary.WriteReport(reportDir); reportErr != nil {
+			log.Warn("failure writing sync report", logger.Error(reportErr))
+		}
+	}
+	if actions.Enabled() {
+		summary.Annotate(os.Stdout, title)
+		if summaryErr := actions.AppendStepSummary(summary.Markdown(reportTitle)); summaryErr != nil {
+			log.Warn("failure writing github actions step summary", logger.Error(summaryErr))
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%serror performing sync: %w", prefix, err)
+	}
+	health.ObserveSuccess(conf.Profile())
+	return nil
+}
+
+func buildNotifier(conf *config.Config) (notification.Notifier, error) {
+	switch *conf.Notification.Provider {
+	case config.NotificationProviderNone:
+		return nil, nil
+	case config.NotificationProviderSMTP:
+		return notification.NewSMTPNotifier(notification.SMTPOptions{
+			Host:     *conf.Notification.SMTPHost,
+			Port:     *conf.Notification.SMTPPort,
+			TLS:      *conf.Notification.SMTPTLS,
+			Username: *conf.Notification.SMTPUsername,
+			Password: *conf.Notification.SMTPPassword,
+			From:     *conf.Notification.SMTPFrom,
+			To:       *conf.Notification.SMTPTo,
+		})
+	default:
+		return notification.NewNotifier(*conf.Notification.Provider, *conf.Notification.URL)
+	}
+}
+
+// notify sends the message, along with the details for the notifiers that have room for them.
+func notify(ctx context.Context, notifier notification.Notifier, log *slog.Logger, message, details string) {
+	if notifier == nil {
+		return
+	}
+	var err error
+	if detailed, ok := notifier.(notification.DetailsNotifier); ok && details != "" {
+		err = detailed.NotifyDetails(ctx, message, details)
+	} else {
+		err = notifier.Notify(ctx, message)
+	}
+	if err != nil {
+		log.Warn("failure sending notification", logger.Error(err))
+	}
+}
+
+func buildScheduler(c *cobra.Command, conf *config.Config, log *slog.Logger) (*scheduler.Scheduler, error) {
+	schedule, err := buildSchedule(c, conf)
+	if err != nil {
+		return nil, fmt.Errorf("error building schedule: %w", err)
+	}
+	jitter, err := c.Flags().GetDuration(cmd.FlagNameJitter)
+	if err != nil {
+		return nil, err
+	}
+	return scheduler.NewScheduler(schedule, jitter, log), nil
+}
+
+// buildSchedule returns the schedule of the schedule or interval flag, or else of SYNC_SCHEDULE, or else the default
+// interval.
+func buildSchedule(c *cobra.Command, conf *config.Config) (scheduler.Schedule, error) {
+	expression, err := c.Flags().GetString(cmd.FlagNameSchedule)
+	if err != nil {
+		return nil, err
+	}
+	if expression == "" && !c.Flags().Changed(cmd.FlagNameInterval) {
+		expression = *conf.Sync.Schedule
+	}
+	if expression != "" {
+		return scheduler.NewCronSchedule(expression)
+	}
+	interval, err := c.Flags().GetDuration(cmd.FlagNameInterval)
+	if err != nil {
+		return nil, err
+	}
+	return scheduler.NewIntervalSchedule(interval)
+}
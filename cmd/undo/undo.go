@@ -0,0 +1,81 @@
+package undo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/journal"
+	"github.com/cecobask/imdb-trakt-sync/internal/mapping"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+func NewCommand(ctx context.Context) *cobra.Command {
+	var conf *config.Config
+	command := &cobra.Command{
+		Use:   cmd.CommandNameUndo,
+		Short: "Revert the changes made by the last sync",
+		Example: `  its undo
+  its undo --destination simkl`,
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			conf, err = cmd.LoadConfig(c)
+			return err
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			store, err := state.NewStore(*conf.State.Dir, *conf.State.URL)
+			if err != nil {
+				return fmt.Errorf("error initialising state store: %w", err)
+			}
+			j, err := journal.Load(store)
+			if err != nil {
+				if errors.Is(err, state.ErrNotFound) {
+					return fmt.Errorf("no sync journal found, there is nothing to undo")
+				}
+				return fmt.Errorf("error loading sync journal: %w", err)
+			}
+			log := logger.NewLogger(os.Stderr)
+			destination, err := newDestinationClient(ctx, conf, store, log)
+			if err != nil {
+				return fmt.Errorf("error initialising %s client: %w", *conf.Sync.Destination, err)
+			}
+			if *conf.Sync.MappingFile != "" {
+				mappings, err := mapping.Load(*conf.Sync.MappingFile)
+				if err != nil {
+					return fmt.Errorf("error loading title mappings: %w", err)
+				}
+				destination = mapping.NewClient(destination, mappings)
+			}
+			c.Printf("Reverting %d operation(s) of the sync started at %s\n", len(j.Operations), j.StartedAt.Format(time.RFC3339))
+			if err = journal.Undo(destination, store, j, log); err != nil {
+				return fmt.Errorf("error undoing last sync: %w", err)
+			}
+			c.Println("Successfully reverted the last sync")
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().String(cmd.FlagNameProfile, "", "name of the profile to use, which is required when the config defines profiles")
+	command.Flags().String(cmd.FlagNameDestination, "", "destination to revert the last sync of, either SYNC_DESTINATION or one of SYNC_DESTINATIONS (default SYNC_DESTINATION)")
+	return command
+}
+
+func newDestinationClient(ctx context.Context, conf *config.Config, store state.Store, log *slog.Logger) (client.DestinationClientInterface, error) {
+	switch *conf.Sync.Destination {
+	case config.SyncDestinationSimkl:
+		return client.NewSimklClient(ctx, conf.Simkl, conf.HTTP, store, log)
+	case config.SyncDestinationJellyfin:
+		return client.NewJellyfinClient(ctx, conf.Jellyfin, conf.HTTP, *conf.Sync.RatingRounding, store, log)
+	case config.SyncDestinationKodi:
+		return client.NewKodiClient(ctx, conf.Kodi, conf.HTTP, store, log)
+	}
+	return client.NewTraktClient(ctx, conf.Trakt, conf.HTTP, store, log)
+}
@@ -0,0 +1,142 @@
+package validate
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cecobask/imdb-trakt-sync/cmd"
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+type check struct {
+	name      string
+	autoRenew bool
+	run       func() (*time.Time, error)
+}
+
+func NewCommand(ctx context.Context) *cobra.Command {
+	var profiles []*config.Config
+	command := &cobra.Command{
+		Use:   cmd.CommandNameValidate,
+		Short: "Check that the configured credentials are valid and not about to expire",
+		Example: `  its validate
+  its validate --expiry-threshold 72h`,
+		PreRunE: func(c *cobra.Command, args []string) (err error) {
+			confPath, err := c.Flags().GetString(cmd.FlagNameConfigFile)
+			if err != nil {
+				return err
+			}
+			conf, err := config.New(confPath, true)
+			if err != nil {
+				return fmt.Errorf("error loading config: %w", err)
+			}
+			if profiles, err = conf.Profiles(); err != nil {
+				return fmt.Errorf("error loading config profiles: %w", err)
+			}
+			for _, profile := range profiles {
+				if err = profile.Validate(); err != nil {
+					if profile.Profile() != "" {
+						return fmt.Errorf("error validating config of profile %s: %w", profile.Profile(), err)
+					}
+					return fmt.Errorf("error validating config: %w", err)
+				}
+			}
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			threshold, err := c.Flags().GetDuration(cmd.FlagNameExpiryThreshold)
+			if err != nil {
+				return err
+			}
+			var failed []string
+			for _, conf := range profiles {
+				store, err := state.NewStore(*conf.State.Dir, *conf.State.URL)
+				if err != nil {
+					return fmt.Errorf("error initialising state store: %w", err)
+				}
+				for _, chk := range buildChecks(ctx, conf, store, logger.NewLogger(os.Stderr)) {
+					// checks are named after the profile as well, so that households can tell their accounts apart
+					if profile := conf.Profile(); profile != "" {
+						chk.name = profile + "/" + chk.name
+					}
+					expiresAt, err := chk.run()
+					switch {
+					case err != nil:
+						c.Printf("%s: invalid: %s\n", chk.name, err)
+						failed = append(failed, chk.name)
+					case expiresAt == nil:
+						c.Printf("%s: valid\n", chk.name)
+					case !chk.autoRenew && time.Until(*expiresAt) < threshold:
+						c.Printf("%s: expires soon at %s\n", chk.name, expiresAt.Format(time.RFC3339))
+						failed = append(failed, chk.name)
+					default:
+						c.Printf("%s: valid until %s\n", chk.name, expiresAt.Format(time.RFC3339))
+					}
+				}
+			}
+			if len(failed) > 0 {
+				return fmt.Errorf("credentials are invalid or about to expire for: %s", strings.Join(failed, ", "))
+			}
+			return nil
+		},
+	}
+	command.Flags().String(cmd.FlagNameConfigFile, cmd.ConfigFileDefault, "path to the config file")
+	command.Flags().Duration(cmd.FlagNameExpiryThreshold, cmd.ExpiryThresholdDefault, "report credentials that expire within this duration as invalid")
+	return command
+}
+
+func buildChecks(ctx context.Context, conf *config.Config, store state.Store, log *slog.Logger) []check {
+	checks := make([]check, 0, 2)
+	if *conf.IMDb.ExportDir == "" {
+		checks = append(checks, check{
+			name:      "imdb",
+			autoRenew: *conf.IMDb.CookieRefresh,
+			run: func() (*time.Time, error) {
+				if *conf.IMDb.Backend == config.IMDbBackendGraphQL {
+					_, err := client.NewIMDbGraphQLClient(ctx, &conf.IMDb, conf.HTTP, store, log)
+					return nil, err
+				}
+				return client.ValidateIMDbAuth(ctx, &conf.IMDb, conf.HTTP, store, log)
+			},
+		})
+	}
+	switch *conf.Sync.Destination {
+	case config.SyncDestinationSimkl:
+		return append(checks, check{
+			name: "simkl",
+			run: func() (*time.Time, error) {
+				return nil, client.ValidateSimklAuth(ctx, conf.Simkl, conf.HTTP, store, log)
+			},
+		})
+	case config.SyncDestinationJellyfin:
+		return append(checks, check{
+			name: "jellyfin",
+			run: func() (*time.Time, error) {
+				return nil, client.ValidateJellyfinAuth(ctx, conf.Jellyfin, conf.HTTP, store, log)
+			},
+		})
+	case config.SyncDestinationKodi:
+		return append(checks, check{
+			name: "kodi",
+			run: func() (*time.Time, error) {
+				return nil, client.ValidateKodiAuth(ctx, conf.Kodi, conf.HTTP, store, log)
+			},
+		})
+	}
+	return append(checks, check{
+		name: "trakt",
+		run: func() (*time.Time, error) {
+			_, err := client.NewTraktClient(ctx, conf.Trakt, conf.HTTP, store, log)
+			return nil, err
+		},
+	})
+}
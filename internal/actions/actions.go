@@ -0,0 +1,53 @@
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	LevelError   = "error"
+	LevelNotice  = "notice"
+	LevelWarning = "warning"
+
+	envActions     = "GITHUB_ACTIONS"
+	envStepSummary = "GITHUB_STEP_SUMMARY"
+)
+
+// Enabled reports whether the application runs in a GitHub Actions workflow.
+func Enabled() bool {
+	return os.Getenv(envActions) == "true"
+}
+
+// Annotate writes a workflow command that annotates the workflow run with the message, at the given level.
+func Annotate(w io.Writer, level, title, message string) {
+	_, _ = fmt.Fprintf(w, "::%s title=%s::%s\n", level, escapeProperty(title), escapeData(message))
+}
+
+// AppendStepSummary appends the markdown to the summary of the running job step, which is shown on the summary page
+// of the workflow run. Nothing is written outside GitHub Actions.
+func AppendStepSummary(markdown string) error {
+	path := os.Getenv(envStepSummary)
+	if path == "" {
+		return nil
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failure opening step summary: %w", err)
+	}
+	defer file.Close()
+	if _, err = file.WriteString(markdown + "\n"); err != nil {
+		return fmt.Errorf("failure writing step summary: %w", err)
+	}
+	return nil
+}
+
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,30 @@
+package actions
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotate(t *testing.T) {
+	var buf bytes.Buffer
+	Annotate(&buf, LevelError, "Sync failed: ratings, lists", "failure adding ratings: 100%\nunexpected status code")
+	assert.Equal(t, "::error title=Sync failed%3A ratings%2C lists::failure adding ratings: 100%25%0Aunexpected status code\n", buf.String())
+}
+
+func TestAppendStepSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv(envStepSummary, path)
+	require.NoError(t, AppendStepSummary("# first"))
+	require.NoError(t, AppendStepSummary("# second"))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# first\n# second\n", string(data))
+
+	t.Setenv(envStepSummary, "")
+	assert.NoError(t, AppendStepSummary("# ignored"))
+}
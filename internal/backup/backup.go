@@ -0,0 +1,225 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+const (
+	archiveVersion    = 1
+	archiveFileFormat = "trakt-backup-20060102T150405Z.json"
+)
+
+// Archive is a snapshot of the trakt user data, which is stored as a json file.
+type Archive struct {
+	Version    int                 `json:"version"`
+	CreatedAt  time.Time           `json:"created_at"`
+	Ratings    entities.TraktItems `json:"ratings"`
+	Watchlist  entities.TraktItems `json:"watchlist"`
+	Lists      []List              `json:"lists"`
+	History    entities.TraktItems `json:"history"`
+	Collection entities.TraktItems `json:"collection"`
+}
+
+type List struct {
+	Slug        string              `json:"slug"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Items       entities.TraktItems `json:"items"`
+}
+
+// Manager creates archives of the trakt user data and restores the data from them.
+type Manager struct {
+	logger *slog.Logger
+	client client.TraktClientInterface
+}
+
+func NewManager(traktClient client.TraktClientInterface, logger *slog.Logger) *Manager {
+	return &Manager{
+		logger: logger,
+		client: traktClient,
+	}
+}
+
+// Backup writes a timestamped archive of the trakt user data to the directory and returns its path.
+func (m *Manager) Backup(dir string) (string, error) {
+	archive, err := m.snapshot()
+	if err != nil {
+		return "", err
+	}
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failure creating backup directory: %w", err)
+	}
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failure marshalling backup archive: %w", err)
+	}
+	path := filepath.Join(dir, archive.CreatedAt.Format(archiveFileFormat))
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("failure writing backup archive: %w", err)
+	}
+	m.logger.Info("created trakt backup",
+		slog.String("path", path),
+		slog.Int("ratings", len(archive.Ratings)),
+		slog.Int("watchlist", len(archive.Watchlist)),
+		slog.Int("lists", len(archive.Lists)),
+		slog.Int("history", len(archive.History)),
+		slog.Int("collection", len(archive.Collection)),
+	)
+	return path, nil
+}
+
+func (m *Manager) snapshot() (*Archive, error) {
+	archive := Archive{
+		Version:   archiveVersion,
+		CreatedAt: time.Now().UTC(),
+	}
+	var err error
+	if archive.Ratings, err = m.client.RatingsGet(); err != nil {
+		return nil, fmt.Errorf("failure getting ratings: %w", err)
+	}
+	watchlist, err := m.client.WatchlistGet()
+	if err != nil {
+		return nil, fmt.Errorf("failure getting watchlist: %w", err)
+	}
+	archive.Watchlist = watchlist.ListItems
+	lists, err := m.client.UserListsGet()
+	if err != nil {
+		return nil, fmt.Errorf("failure getting lists: %w", err)
+	}
+	for _, summary := range lists {
+		list, err := m.client.ListGet(summary.IDMeta.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("failure getting list %s: %w", summary.IDMeta.Slug, err)
+		}
+		backupList := List{
+			Slug:        summary.IDMeta.Slug,
+			Description: summary.Description,
+			Items:       list.ListItems,
+		}
+		if summary.Name != nil {
+			backupList.Name = *summary.Name
+		}
+		archive.Lists = append(archive.Lists, backupList)
+	}
+	if archive.History, err = m.client.HistoryGetAll(); err != nil {
+		return nil, fmt.Errorf("failure getting history: %w", err)
+	}
+	if archive.Collection, err = m.client.CollectionGet(); err != nil {
+		return nil, fmt.Errorf("failure getting collection: %w", err)
+	}
+	return &archive, nil
+}
+
+// Restore adds the data of the archive to the trakt account. Restoring never removes any data,
+// and history entries that already exist with the same watch time are skipped to avoid duplicate plays.
+func (m *Manager) Restore(path string) error {
+	archive, err := readArchive(path)
+	if err != nil {
+		return err
+	}
+	if len(archive.Ratings) > 0 {
+		if err = m.client.RatingsAdd(archive.Ratings.MapSpecs(func(item entities.TraktItem, spec *entities.TraktItemSpec) {
+			spec.Rating = &item.Rating
+			spec.RatedAt = &item.RatedAt
+		})); err != nil {
+			return fmt.Errorf("failure restoring ratings: %w", err)
+		}
+	}
+	if len(archive.Watchlist) > 0 {
+		if err = m.client.WatchlistItemsAdd(archive.Watchlist); err != nil {
+			return fmt.Errorf("failure restoring watchlist: %w", err)
+		}
+	}
+	for _, list := range archive.Lists {
+		if err = m.restoreList(list); err != nil {
+			return fmt.Errorf("failure restoring list %s: %w", list.Slug, err)
+		}
+	}
+	if err = m.restoreHistory(archive.History); err != nil {
+		return fmt.Errorf("failure restoring history: %w", err)
+	}
+	if len(archive.Collection) > 0 {
+		if err = m.client.CollectionAdd(archive.Collection.MapSpecs(func(item entities.TraktItem, spec *entities.TraktItemSpec) {
+			spec.CollectedAt = &item.CollectedAt
+			spec.Seasons = item.Seasons
+		})); err != nil {
+			return fmt.Errorf("failure restoring collection: %w", err)
+		}
+	}
+	m.logger.Info("restored trakt backup", slog.String("path", path), slog.Time("createdAt", archive.CreatedAt))
+	return nil
+}
+
+func (m *Manager) restoreList(list List) error {
+	if _, err := m.client.ListGet(list.Slug); err != nil {
+		var notFoundError *client.TraktListNotFoundError
+		if !errors.As(err, &notFoundError) {
+			return err
+		}
+		if err = m.client.ListAdd(list.Slug, list.Name, list.Description); err != nil {
+			return err
+		}
+	}
+	if len(list.Items) == 0 {
+		return nil
+	}
+	return m.client.ListItemsAdd(list.Slug, list.Items)
+}
+
+func (m *Manager) restoreHistory(history entities.TraktItems) error {
+	if len(history) == 0 {
+		return nil
+	}
+	existing, err := m.client.HistoryGetAll()
+	if err != nil {
+		return err
+	}
+	watched := make(map[string]struct{}, len(existing))
+	for _, item := range existing {
+		watched[historyKey(item)] = struct{}{}
+	}
+	missing := make(entities.TraktItems, 0, len(history))
+	for _, item := range history {
+		if _, ok := watched[historyKey(item)]; !ok {
+			missing = append(missing, item)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return m.client.HistoryAdd(missing.MapSpecs(func(item entities.TraktItem, spec *entities.TraktItemSpec) {
+		spec.WatchedAt = &item.WatchedAt
+	}))
+}
+
+func readArchive(path string) (*Archive, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading backup archive: %w", err)
+	}
+	var archive Archive
+	if err = json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("failure unmarshalling backup archive: %w", err)
+	}
+	if archive.Version != archiveVersion {
+		return nil, fmt.Errorf("unsupported backup archive version %d", archive.Version)
+	}
+	return &archive, nil
+}
+
+func historyKey(item entities.TraktItem) string {
+	var traktID int64
+	if spec := item.Spec(); spec != nil {
+		traktID = spec.IDMeta.Trakt
+	}
+	return fmt.Sprintf("%s:%d:%s", item.Type, traktID, item.WatchedAt)
+}
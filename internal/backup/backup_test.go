@@ -0,0 +1,229 @@
+package backup
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+type mockTraktClient struct {
+	client.TraktClientInterface
+	ratings    entities.TraktItems
+	watchlist  entities.TraktItems
+	lists      map[string]entities.TraktItems
+	history    entities.TraktItems
+	collection entities.TraktItems
+	err        error
+	added      map[string]entities.TraktItems
+	created    []string
+}
+
+func (m *mockTraktClient) RatingsGet() (entities.TraktItems, error) {
+	return m.ratings, m.err
+}
+
+func (m *mockTraktClient) RatingsAdd(items entities.TraktItems) error {
+	m.added["ratings"] = items
+	return nil
+}
+
+func (m *mockTraktClient) WatchlistGet() (*entities.TraktList, error) {
+	return &entities.TraktList{ListItems: m.watchlist, IsWatchlist: true}, nil
+}
+
+func (m *mockTraktClient) WatchlistItemsAdd(items entities.TraktItems) error {
+	m.added["watchlist"] = items
+	return nil
+}
+
+func (m *mockTraktClient) UserListsGet() ([]entities.TraktList, error) {
+	lists := make([]entities.TraktList, 0, len(m.lists))
+	for slug := range m.lists {
+		lists = append(lists, entities.TraktList{Name: &slug, IDMeta: entities.TraktIDMeta{Slug: slug}})
+	}
+	return lists, nil
+}
+
+func (m *mockTraktClient) ListGet(listID string) (*entities.TraktList, error) {
+	items, ok := m.lists[listID]
+	if !ok {
+		return nil, &client.TraktListNotFoundError{Slug: listID}
+	}
+	return &entities.TraktList{IDMeta: entities.TraktIDMeta{Slug: listID}, ListItems: items}, nil
+}
+
+func (m *mockTraktClient) ListAdd(listID, listName, description string) error {
+	m.created = append(m.created, listID)
+	return nil
+}
+
+func (m *mockTraktClient) ListItemsAdd(listID string, items entities.TraktItems) error {
+	m.added[listID] = items
+	return nil
+}
+
+func (m *mockTraktClient) HistoryGetAll() (entities.TraktItems, error) {
+	return m.history, nil
+}
+
+func (m *mockTraktClient) HistoryAdd(items entities.TraktItems) error {
+	m.added["history"] = items
+	return nil
+}
+
+func (m *mockTraktClient) CollectionGet() (entities.TraktItems, error) {
+	return m.collection, nil
+}
+
+func (m *mockTraktClient) CollectionAdd(items entities.TraktItems) error {
+	m.added["collection"] = items
+	return nil
+}
+
+func TestManager_Backup(t *testing.T) {
+	movie := entities.TraktItem{
+		Type:      entities.TraktItemTypeMovie,
+		RatedAt:   "2023-12-31T15:20:50.000Z",
+		Rating:    8,
+		WatchedAt: "2024-01-30T00:00:00.000Z",
+		Movie: entities.TraktItemSpec{
+			IDMeta: entities.TraktIDMeta{Trakt: 224641, IMDb: "tt5013056"},
+		},
+	}
+	episode := entities.TraktItem{
+		Type:      entities.TraktItemTypeEpisode,
+		WatchedAt: "2024-01-31T00:00:00.000Z",
+		Episode: entities.TraktItemSpec{
+			IDMeta: entities.TraktIDMeta{Trakt: 73482},
+		},
+	}
+	tests := []struct {
+		name       string
+		client     *mockTraktClient
+		assertions func(*assert.Assertions, string, error)
+	}{
+		{
+			name: "successfully create backup",
+			client: &mockTraktClient{
+				ratings:    entities.TraktItems{movie},
+				watchlist:  entities.TraktItems{movie},
+				lists:      map[string]entities.TraktItems{"watched": {movie}},
+				history:    entities.TraktItems{movie, episode},
+				collection: entities.TraktItems{movie},
+			},
+			assertions: func(assertions *assert.Assertions, path string, err error) {
+				assertions.NoError(err)
+				assertions.Regexp(`trakt-backup-\d{8}T\d{6}Z\.json$`, path)
+				archive, err := readArchive(path)
+				assertions.NoError(err)
+				assertions.Equal(archiveVersion, archive.Version)
+				assertions.Equal(entities.TraktItems{movie}, archive.Ratings)
+				assertions.Equal(1, len(archive.Lists))
+				assertions.Equal("watched", archive.Lists[0].Name)
+				assertions.Equal(2, len(archive.History))
+			},
+		},
+		{
+			name: "failure getting ratings",
+			client: &mockTraktClient{
+				err: errors.New("unexpected status code"),
+			},
+			assertions: func(assertions *assert.Assertions, path string, err error) {
+				assertions.Empty(path)
+				assertions.ErrorContains(err, "failure getting ratings")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := NewManager(tt.client, logger.NewLogger(io.Discard)).Backup(t.TempDir())
+			tt.assertions(assert.New(t), path, err)
+		})
+	}
+}
+
+func TestManager_Restore(t *testing.T) {
+	movie := entities.TraktItem{
+		Type:      entities.TraktItemTypeMovie,
+		RatedAt:   "2023-12-31T15:20:50.000Z",
+		Rating:    8,
+		WatchedAt: "2024-01-30T00:00:00.000Z",
+		Movie: entities.TraktItemSpec{
+			IDMeta: entities.TraktIDMeta{Trakt: 224641, IMDb: "tt5013056"},
+		},
+	}
+	episode := entities.TraktItem{
+		Type:      entities.TraktItemTypeEpisode,
+		WatchedAt: "2024-01-31T00:00:00.000Z",
+		Episode: entities.TraktItemSpec{
+			IDMeta: entities.TraktIDMeta{Trakt: 73482},
+		},
+	}
+	tests := []struct {
+		name       string
+		archive    string
+		client     *mockTraktClient
+		assertions func(*assert.Assertions, *mockTraktClient, error)
+	}{
+		{
+			name: "successfully restore backup",
+			client: &mockTraktClient{
+				lists:   map[string]entities.TraktItems{"favourites": nil},
+				history: entities.TraktItems{movie},
+			},
+			assertions: func(assertions *assert.Assertions, c *mockTraktClient, err error) {
+				assertions.NoError(err)
+				assertions.Equal(8, *c.added["ratings"][0].Movie.Rating)
+				assertions.Equal("2023-12-31T15:20:50.000Z", *c.added["ratings"][0].Movie.RatedAt)
+				assertions.Equal(1, len(c.added["watchlist"]))
+				assertions.Equal([]string{"watched"}, c.created)
+				assertions.Equal(1, len(c.added["watched"]))
+				assertions.Equal(1, len(c.added["favourites"]))
+				assertions.Equal(1, len(c.added["history"]))
+				assertions.Equal(entities.TraktItemTypeEpisode, c.added["history"][0].Type)
+				assertions.Equal("2024-01-31T00:00:00.000Z", *c.added["history"][0].Episode.WatchedAt)
+				assertions.Equal(1, len(c.added["collection"]))
+			},
+		},
+		{
+			name:    "failure with unsupported archive version",
+			archive: `{"version":2}`,
+			client:  &mockTraktClient{},
+			assertions: func(assertions *assert.Assertions, c *mockTraktClient, err error) {
+				assertions.ErrorContains(err, "unsupported backup archive version 2")
+				assertions.Empty(c.added)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.client.added = make(map[string]entities.TraktItems)
+			path := filepath.Join(dir, "backup.json")
+			if tt.archive == "" {
+				source := &mockTraktClient{
+					ratings:    entities.TraktItems{movie},
+					watchlist:  entities.TraktItems{movie},
+					lists:      map[string]entities.TraktItems{"watched": {movie}, "favourites": {episode}},
+					history:    entities.TraktItems{movie, episode},
+					collection: entities.TraktItems{movie},
+				}
+				var err error
+				path, err = NewManager(source, logger.NewLogger(io.Discard)).Backup(dir)
+				assert.NoError(t, err)
+			} else {
+				assert.NoError(t, os.WriteFile(path, []byte(tt.archive), 0o600))
+			}
+			err := NewManager(tt.client, logger.NewLogger(io.Discard)).Restore(path)
+			tt.assertions(assert.New(t), tt.client, err)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// marginMax is the longest time that is reserved at the end of a budget, for the request that is in flight when the
+// budget runs out to complete.
+const marginMax = time.Minute
+
+var ErrExhausted = errors.New("sync ran out of its time budget")
+
+type contextKey struct{}
+
+// Budget is the maximum duration of a sync, after which the sync stops gracefully instead of being killed while it
+// mutates the destination. A budget runs out a tenth of its duration early, and at most a minute early, so that the
+// batch that is in flight by then can complete within the budget.
+type Budget struct {
+	deadline time.Time
+	now      func() time.Time
+}
+
+// New starts a budget of the given duration. A duration of zero means that the sync has no budget, which is when nil
+// is returned.
+func New(maxDuration time.Duration) *Budget {
+	if maxDuration <= 0 {
+		return nil
+	}
+	margin := min(maxDuration/10, marginMax)
+	return &Budget{
+		deadline: time.Now().Add(maxDuration - margin),
+		now:      time.Now,
+	}
+}
+
+// WithBudget returns a copy of the context that carries the budget, so that the clients of the sync can check it.
+func WithBudget(ctx context.Context, b *Budget) context.Context {
+	if b == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, b)
+}
+
+// FromContext returns the budget of the context, or nil when the context has none.
+func FromContext(ctx context.Context) *Budget {
+	b, _ := ctx.Value(contextKey{}).(*Budget)
+	return b
+}
+
+// Exhausted reports whether the budget ran out, which is never the case for a nil budget.
+func (b *Budget) Exhausted() bool {
+	return b != nil && !b.now().Before(b.deadline)
+}
+
+// Check returns ErrExhausted when the budget ran out, and nil otherwise.
+func (b *Budget) Check() error {
+	if b.Exhausted() {
+		return ErrExhausted
+	}
+	return nil
+}
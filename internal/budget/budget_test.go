@@ -0,0 +1,65 @@
+package budget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxDuration time.Duration
+		elapsed     time.Duration
+		assertions  func(*assert.Assertions, *Budget)
+	}{
+		{
+			name:        "no budget",
+			maxDuration: 0,
+			assertions: func(assertions *assert.Assertions, b *Budget) {
+				assertions.Nil(b)
+				assertions.False(b.Exhausted())
+				assertions.NoError(b.Check())
+			},
+		},
+		{
+			name:        "budget left",
+			maxDuration: time.Minute * 20,
+			elapsed:     time.Minute * 18,
+			assertions: func(assertions *assert.Assertions, b *Budget) {
+				assertions.False(b.Exhausted())
+				assertions.NoError(b.Check())
+			},
+		},
+		{
+			name:        "budget exhausted within the margin",
+			maxDuration: time.Minute * 20,
+			elapsed:     time.Minute*19 + time.Second,
+			assertions: func(assertions *assert.Assertions, b *Budget) {
+				assertions.True(b.Exhausted())
+				assertions.ErrorIs(b.Check(), ErrExhausted)
+			},
+		},
+		{
+			name:        "margin of a short budget",
+			maxDuration: time.Minute,
+			elapsed:     time.Second * 55,
+			assertions: func(assertions *assert.Assertions, b *Budget) {
+				assertions.True(b.Exhausted())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.maxDuration)
+			if b != nil {
+				now := time.Now().Add(tt.elapsed)
+				b.now = func() time.Time { return now }
+			}
+			ctx := WithBudget(context.Background(), b)
+			tt.assertions(assert.New(t), FromContext(ctx))
+		})
+	}
+}
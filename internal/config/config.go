@@ -2,10 +2,16 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"reflect"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/knadh/koanf/parsers/yaml"
@@ -13,54 +19,332 @@ import (
 	"github.com/knadh/koanf/providers/env"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
+	"github.com/robfig/cron/v3"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/rating"
+	"github.com/cecobask/imdb-trakt-sync/internal/secrets"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
 )
 
 type IMDb struct {
-	Auth           *string   `koanf:"AUTH"`
-	Email          *string   `koanf:"EMAIL"`
-	Password       *string   `koanf:"PASSWORD"`
-	CookieAtMain   *string   `koanf:"COOKIEATMAIN"`
-	CookieUbidMain *string   `koanf:"COOKIEUBIDMAIN"`
-	Lists          *[]string `koanf:"LISTS"`
-	Trace          *bool     `koanf:"TRACE"`
-	Headless       *bool     `koanf:"HEADLESS"`
-	BrowserPath    *string   `koanf:"BROWSERPATH"`
+	Auth             *string        `koanf:"AUTH"`
+	Backend          *string        `koanf:"BACKEND"`
+	Email            *string        `koanf:"EMAIL"`
+	Password         *string        `koanf:"PASSWORD"`
+	CookieAtMain     *string        `koanf:"COOKIEATMAIN"`
+	CookieUbidMain   *string        `koanf:"COOKIEUBIDMAIN"`
+	CookieRefresh    *bool          `koanf:"COOKIEREFRESH"`
+	CookieJar        *bool          `koanf:"COOKIEJAR"`
+	Lists            *[]string      `koanf:"LISTS"`
+	Charts           *[]string      `koanf:"CHARTS"`
+	UserID           *string        `koanf:"USERID"`
+	Trace            *bool          `koanf:"TRACE"`
+	Headless         *bool          `koanf:"HEADLESS"`
+	BrowserPath      *string        `koanf:"BROWSERPATH"`
+	ExportDir        *string        `koanf:"EXPORTDIR"`
+	RatingsPaginated *bool          `koanf:"RATINGSPAGINATED"`
+	RetryMaxAttempts *int           `koanf:"RETRYMAXATTEMPTS"`
+	RetryBackoff     *time.Duration `koanf:"RETRYBACKOFF"`
+	RetryJitter      *time.Duration `koanf:"RETRYJITTER"`
+	UserAgent        *string        `koanf:"USERAGENT"`
+	UserAgentRotate  *bool          `koanf:"USERAGENTROTATE"`
+	// SnapshotIn and SnapshotOut are only set by the flags of the sync command, since they are meant for debugging.
+	SnapshotIn  *string `koanf:"-"`
+	SnapshotOut *string `koanf:"-"`
 }
 
 type Trakt struct {
-	Email        *string `koanf:"EMAIL"`
-	Password     *string `koanf:"PASSWORD"`
-	ClientID     *string `koanf:"CLIENTID"`
-	ClientSecret *string `koanf:"CLIENTSECRET"`
+	Auth               *string   `koanf:"AUTH"`
+	BatchSize          *int      `koanf:"BATCHSIZE"`
+	Email              *string   `koanf:"EMAIL"`
+	Password           *string   `koanf:"PASSWORD"`
+	ClientID           *string   `koanf:"CLIENTID"`
+	ClientSecret       *string   `koanf:"CLIENTSECRET"`
+	ListPrivacy        *string   `koanf:"LISTPRIVACY"`
+	ListPrivacies      *[]string `koanf:"LISTPRIVACIES"`
+	ListDisplayNumbers *bool     `koanf:"LISTDISPLAYNUMBERS"`
+	ListAllowComments  *bool     `koanf:"LISTALLOWCOMMENTS"`
+	ListSortBy         *string   `koanf:"LISTSORTBY"`
+	ListSortHow        *string   `koanf:"LISTSORTHOW"`
+	TokenStorage       *string   `koanf:"TOKENSTORAGE"`
+	TokenPassphrase    *string   `koanf:"TOKENPASSPHRASE"`
+	KeyringService     *string   `koanf:"KEYRINGSERVICE"`
+}
+
+type Anime struct {
+	Provider    *string `koanf:"PROVIDER"`
+	Username    *string `koanf:"USERNAME"`
+	MALClientID *string `koanf:"MALCLIENTID"`
+}
+
+type Simkl struct {
+	ClientID *string `koanf:"CLIENTID"`
+}
+
+type Jellyfin struct {
+	URL      *string `koanf:"URL"`
+	APIKey   *string `koanf:"APIKEY"`
+	Username *string `koanf:"USERNAME"`
+}
+
+type Kodi struct {
+	URL      *string `koanf:"URL"`
+	Username *string `koanf:"USERNAME"`
+	Password *string `koanf:"PASSWORD"`
+}
+
+type Plex struct {
+	Enabled *bool   `koanf:"ENABLED"`
+	Token   *string `koanf:"TOKEN"`
 }
 
 type Sync struct {
-	Mode      *string        `koanf:"MODE"`
-	History   *bool          `koanf:"HISTORY"`
-	Ratings   *bool          `koanf:"RATINGS"`
-	Watchlist *bool          `koanf:"WATCHLIST"`
-	Lists     *bool          `koanf:"LISTS"`
-	Timeout   *time.Duration `koanf:"TIMEOUT"`
+	Mode              *string        `koanf:"MODE"`
+	History           *bool          `koanf:"HISTORY"`
+	HistoryWindow     *time.Duration `koanf:"HISTORYWINDOW"`
+	Ratings           *bool          `koanf:"RATINGS"`
+	Watchlist         *bool          `koanf:"WATCHLIST"`
+	Lists             *bool          `koanf:"LISTS"`
+	ListLimit         *string        `koanf:"LISTLIMIT"`
+	ListModes         *[]string      `koanf:"LISTMODES"`
+	MediaTypes        *[]string      `koanf:"MEDIATYPES"`
+	Exclude           *[]string      `koanf:"EXCLUDE"`
+	Duplicates        *bool          `koanf:"DUPLICATES"`
+	Precedence        *[]string      `koanf:"PRECEDENCE"`
+	FanOut            *[]string      `koanf:"FANOUT"`
+	ItemNotes         *bool          `koanf:"ITEMNOTES"`
+	ListNameTemplate  *string        `koanf:"LISTNAMETEMPLATE"`
+	ListNameTemplates *[]string      `koanf:"LISTNAMETEMPLATES"`
+	Checkins          *bool          `koanf:"CHECKINS"`
+	CheckinList       *string        `koanf:"CHECKINLIST"`
+	Collection        *string        `koanf:"COLLECTION"`
+	CollectionMedia   *string        `koanf:"COLLECTIONMEDIA"`
+	Favorites         *string        `koanf:"FAVORITES"`
+	RatingRounding    *string        `koanf:"RATINGROUNDING"`
+	WatchedList       *string        `koanf:"WATCHEDLIST"`
+	UpcomingList      *string        `koanf:"UPCOMINGLIST"`
+	Recommendations   *string        `koanf:"RECOMMENDATIONS"`
+	Hidden            *string        `koanf:"HIDDEN"`
+	LikedLists        *[]string      `koanf:"LIKEDLISTS"`
+	ReverseLists      *[]string      `koanf:"REVERSELISTS"`
+	MappingFile       *string        `koanf:"MAPPINGFILE"`
+	SearchFallback    *bool          `koanf:"SEARCHFALLBACK"`
+	Destination       *string        `koanf:"DESTINATION"`
+	Destinations      *[]string      `koanf:"DESTINATIONS"`
+	Guardrail         *int           `koanf:"GUARDRAIL"`
+	Reviews           *bool          `koanf:"REVIEWS"`
+	Timeout           *time.Duration `koanf:"TIMEOUT"`
+	MaxDuration       *time.Duration `koanf:"MAXDURATION"`
+	RatingsConflict   *string        `koanf:"RATINGSCONFLICT"`
+	RatingsMap        *[]string      `koanf:"RATINGSMAP"`
+	SafeMode          *bool          `koanf:"SAFEMODE"`
+	SkipUnchanged     *bool          `koanf:"SKIPUNCHANGED"`
+	SkipListTTL       *time.Duration `koanf:"SKIPLISTTTL"`
+	Schedule          *string        `koanf:"SCHEDULE"`
+}
+
+type Log struct {
+	Level  *string `koanf:"LEVEL"`
+	Format *string `koanf:"FORMAT"`
+}
+
+type Notification struct {
+	Provider     *string   `koanf:"PROVIDER"`
+	URL          *string   `koanf:"URL"`
+	SMTPHost     *string   `koanf:"SMTPHOST"`
+	SMTPPort     *int      `koanf:"SMTPPORT"`
+	SMTPTLS      *string   `koanf:"SMTPTLS"`
+	SMTPUsername *string   `koanf:"SMTPUSERNAME"`
+	SMTPPassword *string   `koanf:"SMTPPASSWORD"`
+	SMTPFrom     *string   `koanf:"SMTPFROM"`
+	SMTPTo       *[]string `koanf:"SMTPTO"`
+}
+
+type Report struct {
+	Dir *string `koanf:"DIR"`
+}
+
+type Secrets struct {
+	Provider        *string `koanf:"PROVIDER"`
+	VaultAddress    *string `koanf:"VAULTADDRESS"`
+	VaultToken      *string `koanf:"VAULTTOKEN"`
+	VaultPath       *string `koanf:"VAULTPATH"`
+	SopsFile        *string `koanf:"SOPSFILE"`
+	KeychainService *string `koanf:"KEYCHAINSERVICE"`
+	Passphrase      *string `koanf:"PASSPHRASE"`
+	AgeIdentity     *string `koanf:"AGEIDENTITY"`
+	AgeRecipient    *string `koanf:"AGERECIPIENT"`
+}
+
+type HTTP struct {
+	BaseURLs        *[]string      `koanf:"BASEURLS"`
+	Timeout         *time.Duration `koanf:"TIMEOUT"`
+	Timeouts        *[]string      `koanf:"TIMEOUTS"`
+	KeepAlive       *time.Duration `koanf:"KEEPALIVE"`
+	IdleConnTimeout *time.Duration `koanf:"IDLECONNTIMEOUT"`
+	MaxIdleConns    *int           `koanf:"MAXIDLECONNS"`
+	TLSMinVersion   *string        `koanf:"TLSMINVERSION"`
+	Cache           *bool          `koanf:"CACHE"`
+	Cassette        *string        `koanf:"CASSETTE"`
+	CassetteMode    *string        `koanf:"CASSETTEMODE"`
+	Proxy           *string        `koanf:"PROXY"`
+	CACert          *string        `koanf:"CACERT"`
+}
+
+type Server struct {
+	Enabled      *bool          `koanf:"ENABLED"`
+	Address      *string        `koanf:"ADDRESS"`
+	MaxSyncAge   *time.Duration `koanf:"MAXSYNCAGE"`
+	TriggerToken *string        `koanf:"TRIGGERTOKEN"`
+}
+
+type State struct {
+	Dir *string `koanf:"DIR"`
+	URL *string `koanf:"URL"`
+}
+
+type Tracing struct {
+	Enabled     *bool    `koanf:"ENABLED"`
+	Endpoint    *string  `koanf:"ENDPOINT"`
+	SampleRatio *float64 `koanf:"SAMPLERATIO"`
 }
 
 type Config struct {
-	koanf *koanf.Koanf
-	IMDb  IMDb  `koanf:"IMDB"`
-	Trakt Trakt `koanf:"TRAKT"`
-	Sync  Sync  `koanf:"SYNC"`
+	koanf        *koanf.Koanf
+	profile      string
+	IMDb         IMDb         `koanf:"IMDB"`
+	Trakt        Trakt        `koanf:"TRAKT"`
+	Simkl        Simkl        `koanf:"SIMKL"`
+	Jellyfin     Jellyfin     `koanf:"JELLYFIN"`
+	Kodi         Kodi         `koanf:"KODI"`
+	Plex         Plex         `koanf:"PLEX"`
+	Anime        Anime        `koanf:"ANIME"`
+	HTTP         HTTP         `koanf:"HTTP"`
+	Sync         Sync         `koanf:"SYNC"`
+	Log          Log          `koanf:"LOG"`
+	Notification Notification `koanf:"NOTIFICATION"`
+	Report       Report       `koanf:"REPORT"`
+	Secrets      Secrets      `koanf:"SECRETS"`
+	Server       Server       `koanf:"SERVER"`
+	State        State        `koanf:"STATE"`
+	Tracing      Tracing      `koanf:"TRACING"`
 }
 
 const (
-	delimiter = "_"
-	prefix    = "ITS" + delimiter
-
-	IMDbAuthMethodCredentials = "credentials"
-	IMDbAuthMethodCookies     = "cookies"
-	IMDbAuthMethodNone        = "none"
-	SyncModeAddOnly           = "add-only"
-	SyncModeDryRun            = "dry-run"
-	SyncModeFull              = "full"
-	SyncTimeoutDefault        = time.Minute * 15
+	delimiter   = "_"
+	prefix      = "ITS" + delimiter
+	profilesKey = "PROFILES"
+
+	AnimeProviderAniList         = "anilist"
+	AnimeProviderMyAnimeList     = "myanimelist"
+	AnimeProviderNone            = "none"
+	CollectionMediaBluray        = "bluray"
+	CollectionMediaDigital       = "digital"
+	CollectionMediaDVD           = "dvd"
+	CollectionMediaHDDVD         = "hddvd"
+	CollectionMediaLaserDisc     = "laserdisc"
+	CollectionMediaVHS           = "vhs"
+	HTTPBaseURLServiceAniList    = "anilist"
+	HTTPBaseURLServiceAnimeLists = "animelists"
+	HTTPBaseURLServiceIMDb       = "imdb"
+	HTTPBaseURLServiceIMDbGQL    = "imdb-graphql"
+	HTTPBaseURLServiceMAL        = "myanimelist"
+	HTTPBaseURLServicePlexDisc   = "plex-discover"
+	HTTPBaseURLServicePlexMeta   = "plex-metadata"
+	HTTPBaseURLServiceSimkl      = "simkl"
+	HTTPBaseURLServiceTrakt      = "trakt"
+	HTTPBaseURLServiceTraktWeb   = "trakt-web"
+	HTTPCassetteModeOff          = "off"
+	HTTPCassetteModeRecord       = "record"
+	HTTPCassetteModeReplay       = "replay"
+	HTTPClientAnime              = "anime"
+	HTTPClientIMDb               = "imdb"
+	HTTPClientJellyfin           = "jellyfin"
+	HTTPClientKodi               = "kodi"
+	HTTPClientPlex               = "plex"
+	HTTPClientSimkl              = "simkl"
+	HTTPClientTrakt              = "trakt"
+	HTTPIdleConnTimeoutDefault   = time.Second * 90
+	HTTPKeepAliveDefault         = time.Second * 30
+	HTTPMaxIdleConnsDefault      = 10
+	HTTPTimeoutDefault           = time.Second * 30
+	HTTPTLSVersion12             = "1.2"
+	HTTPTLSVersion13             = "1.3"
+	IMDbAuthMethodCredentials    = "credentials"
+	IMDbAuthMethodCookies        = "cookies"
+	IMDbAuthMethodNone           = "none"
+	IMDbBackendBrowser           = "browser"
+	IMDbBackendGraphQL           = "graphql"
+	IMDbChartMovieMeter          = "moviemeter"
+	IMDbChartTop                 = "top"
+	IMDbChartTVMeter             = "tvmeter"
+	IMDbListsAll                 = "all"
+	IMDbRetryBackoffDefault      = time.Second * 5
+	IMDbRetryJitterDefault       = time.Second * 2
+	IMDbUserAgentDefault         = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36"
+	IMDbRetryMaxAttemptsDefault  = 3
+	ListModeDisabled             = "disabled"
+	ListWatchlist                = "watchlist"
+	ListNameTemplateDefault      = "{{.ListName}}"
+	LogFormatJSON                = "json"
+	LogFormatText                = "text"
+	LogLevelDebug                = "debug"
+	LogLevelInfo                 = "info"
+	LogLevelWarn                 = "warn"
+	LogLevelError                = "error"
+	MediaTypeEpisode             = "episode"
+	MediaTypeMovie               = "movie"
+	MediaTypePerson              = "person"
+	MediaTypeShow                = "show"
+	NotificationProviderDiscord  = "discord"
+	NotificationProviderNone     = "none"
+	NotificationProviderSlack    = "slack"
+	NotificationProviderSMTP     = "smtp"
+	NotificationProviderTelegram = "telegram"
+	NotificationSMTPPortDefault  = 587
+	NotificationSMTPTLSImplicit  = "tls"
+	NotificationSMTPTLSNone      = "none"
+	NotificationSMTPTLSStartTLS  = "starttls"
+	RatingsConflictIMDbWins      = "imdb-wins"
+	RatingsConflictNewestWins    = "newest-wins"
+	RatingsConflictSkip          = "skip-and-report"
+	RatingsConflictTraktWins     = "trakt-wins"
+	RatingsMapSkip               = "skip"
+	EncryptionMethodAge          = secrets.EncryptionMethodAge
+	EncryptionMethodPassphrase   = secrets.EncryptionMethodPassphrase
+	SecretsProviderKeychain      = secrets.ProviderKeychain
+	SecretsProviderNone          = "none"
+	SecretsProviderSops          = secrets.ProviderSops
+	SecretsProviderVault         = secrets.ProviderVault
+	ServerAddressDefault         = ":8080"
+	ServerMaxSyncAgeDefault      = time.Hour * 24
+	TracingSampleRatioDefault    = 1.0
+	StateDirDefault              = ".its"
+	SyncDestinationJellyfin      = "jellyfin"
+	SyncDestinationKodi          = "kodi"
+	SyncDestinationSimkl         = "simkl"
+	SyncDestinationTrakt         = "trakt"
+	SyncGuardrailDefault         = 50
+	SyncListLimitSkip            = "skip"
+	SyncListLimitTruncate        = "truncate"
+	SyncListLimitWarn            = "warn"
+	SyncModeAddOnly              = "add-only"
+	SyncModeDryRun               = "dry-run"
+	SyncModeFull                 = "full"
+	SyncTimeoutDefault           = time.Minute * 15
+	TraktAuthMethodCredentials   = "credentials"
+	TraktAuthMethodDevice        = "device"
+	TraktKeyringServiceDefault   = "imdb-trakt-sync"
+	TraktTokenStorageKeyring     = "keyring"
+	TraktTokenStorageState       = "state"
+	TraktListPrivacyFriends      = "friends"
+	TraktListPrivacyPrivate      = "private"
+	TraktListPrivacyPublic       = "public"
+	TraktBatchSizeDefault        = 1000
+	TraktListSortByDefault       = "rank"
+	TraktListSortHowAsc          = "asc"
+	TraktListSortHowDesc         = "desc"
 )
 
 func New(path string, includeEnv bool) (*Config, error) {
@@ -74,6 +358,12 @@ func New(path string, includeEnv bool) (*Config, error) {
 		if err := k.Load(envProvider, nil); err != nil {
 			return nil, fmt.Errorf("error loading config from environment variables: %w", err)
 		}
+		if err := decryptValues(k); err != nil {
+			return nil, fmt.Errorf("error decrypting config: %w", err)
+		}
+		if err := loadSecrets(k); err != nil {
+			return nil, fmt.Errorf("error loading config from secrets provider: %w", err)
+		}
 	}
 	conf := Config{
 		koanf: k,
@@ -85,6 +375,134 @@ func New(path string, includeEnv bool) (*Config, error) {
 	return &conf, nil
 }
 
+// NewDefault creates a config that holds the default value of every field, and an empty value for the fields that
+// have to be provided by the user, such as credentials. It is meant to scaffold a new config file.
+func NewDefault() (*Config, error) {
+	var conf Config
+	conf.applyDefaults()
+	data := make(map[string]interface{})
+	sections := reflect.ValueOf(conf)
+	for i := 0; i < sections.NumField(); i++ {
+		sectionName, ok := sections.Type().Field(i).Tag.Lookup("koanf")
+		if !ok {
+			continue
+		}
+		section := sections.Field(i)
+		for j := 0; j < section.NumField(); j++ {
+			key := sectionName + delimiter + section.Type().Field(j).Tag.Get("koanf")
+			value := section.Field(j)
+			if value.IsNil() {
+				switch value.Type().Elem().Kind() {
+				case reflect.String:
+					data[key] = ""
+				case reflect.Slice:
+					data[key] = []string{}
+				}
+				continue
+			}
+			if duration, isDuration := value.Elem().Interface().(time.Duration); isDuration {
+				data[key] = duration.String()
+				continue
+			}
+			data[key] = value.Elem().Interface()
+		}
+	}
+	return NewFromMap(data)
+}
+
+// loadSecrets overrides the sensitive config fields with the values stored in the configured secrets provider.
+func loadSecrets(k *koanf.Koanf) error {
+	provider := k.String("SECRETS_PROVIDER")
+	if provider == "" || provider == SecretsProviderNone {
+		return nil
+	}
+	p, err := secrets.NewProvider(provider, secrets.Options{
+		VaultAddress:    k.String("SECRETS_VAULTADDRESS"),
+		VaultToken:      k.String("SECRETS_VAULTTOKEN"),
+		VaultPath:       k.String("SECRETS_VAULTPATH"),
+		SopsFile:        k.String("SECRETS_SOPSFILE"),
+		KeychainService: k.String("SECRETS_KEYCHAINSERVICE"),
+	})
+	if err != nil {
+		return err
+	}
+	values, err := p.Secrets(secretKeys())
+	if err != nil {
+		return err
+	}
+	data := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		data[key] = value
+	}
+	return k.Load(confmap.Provider(data, delimiter), nil)
+}
+
+// decryptValues replaces the encrypted values of the config, including the ones of profiles, with their plaintext.
+func decryptValues(k *koanf.Koanf) error {
+	var c *secrets.Cipher
+	data := make(map[string]interface{})
+	for key, v := range k.All() {
+		value, ok := v.(string)
+		if !ok || !secrets.IsEncrypted(value) {
+			continue
+		}
+		if c == nil {
+			c = secrets.NewCipher(encryptionOptions(k))
+		}
+		plaintext, err := c.Decrypt(value)
+		if err != nil {
+			return fmt.Errorf("failure decrypting field '%s': %w", key, err)
+		}
+		data[key] = plaintext
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return k.Load(confmap.Provider(data, delimiter), nil)
+}
+
+func encryptionOptions(k *koanf.Koanf) secrets.EncryptionOptions {
+	return secrets.EncryptionOptions{
+		Passphrase:   k.String("SECRETS_PASSPHRASE"),
+		AgeIdentity:  k.String("SECRETS_AGEIDENTITY"),
+		AgeRecipient: k.String("SECRETS_AGERECIPIENT"),
+	}
+}
+
+// EncryptSecrets encrypts the credentials of the config, including the ones of profiles, that are not encrypted yet,
+// and returns how many were encrypted. The passphrase is read from the SECRETS_PASSPHRASE environment variable, since
+// it must not end up in the config file next to the values it encrypts.
+func (c *Config) EncryptSecrets(method string) (int, error) {
+	if !slices.Contains(validEncryptionMethods(), method) {
+		return 0, fmt.Errorf("encryption method must be one of: %s", strings.Join(validEncryptionMethods(), ", "))
+	}
+	opts := encryptionOptions(c.koanf)
+	opts.Passphrase = os.Getenv(prefix + "SECRETS_PASSPHRASE")
+	cipher := secrets.NewCipher(opts)
+	data := make(map[string]interface{})
+	for key, v := range c.koanf.All() {
+		value, ok := v.(string)
+		if !ok || value == "" || secrets.IsEncrypted(value) || !isEncryptableKey(key) {
+			continue
+		}
+		encrypted, err := cipher.Encrypt(method, value)
+		if err != nil {
+			return 0, fmt.Errorf("failure encrypting field '%s': %w", key, err)
+		}
+		data[key] = encrypted
+	}
+	if err := c.koanf.Load(confmap.Provider(data, delimiter), nil); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// isEncryptableKey reports whether the flattened key is a credential, leaving out the fields of the secrets section,
+// since they are needed to decrypt the other fields.
+func isEncryptableKey(key string) bool {
+	return isSecretKey(key) && !strings.Contains(delimiter+key, delimiter+"SECRETS"+delimiter)
+}
+
 func NewFromMap(data map[string]interface{}) (*Config, error) {
 	k := koanf.New(delimiter)
 	cmProvider := confmap.Provider(data, delimiter)
@@ -101,7 +519,284 @@ func NewFromMap(data map[string]interface{}) (*Config, error) {
 	return &conf, nil
 }
 
+// Profiles returns the config of each profile defined under PROFILES, such as the accounts of a household. The fields
+// of a profile override the top level fields, which are shared by every profile. Each profile keeps its state in a
+// subdirectory of STATE_DIR named after the profile, unless it sets its own STATE_DIR, and likewise under the prefix
+// of STATE_URL. The top level config is returned as the only profile when no profiles are defined.
+func (c *Config) Profiles() ([]*Config, error) {
+	if c.koanf == nil || len(c.koanf.MapKeys(profilesKey)) == 0 {
+		return []*Config{c}, nil
+	}
+	names := c.koanf.MapKeys(profilesKey)
+	profiles := make([]*Config, 0, len(names))
+	for _, name := range names {
+		overrides := c.koanf.Cut(profilesKey + delimiter + name)
+		k := c.koanf.Copy()
+		k.Delete(profilesKey)
+		if err := k.Merge(overrides); err != nil {
+			return nil, fmt.Errorf("error merging config of profile %s: %w", name, err)
+		}
+		conf := Config{
+			koanf:   k,
+			profile: name,
+		}
+		if err := k.Unmarshal("", &conf); err != nil {
+			return nil, fmt.Errorf("error unmarshalling config of profile %s: %w", name, err)
+		}
+		conf.applyDefaults()
+		if !overrides.Exists("STATE" + delimiter + "DIR") {
+			conf.State.Dir = pointer(filepath.Join(*conf.State.Dir, name))
+		}
+		if !overrides.Exists("STATE"+delimiter+"URL") && *conf.State.URL != "" {
+			if u, err := url.Parse(*conf.State.URL); err == nil {
+				u.Path = path.Join("/", u.Path, name)
+				conf.State.URL = pointer(u.String())
+			}
+		}
+		if !overrides.Exists("TRAKT" + delimiter + "KEYRINGSERVICE") {
+			conf.Trakt.KeyringService = pointer(*conf.Trakt.KeyringService + "-" + name)
+		}
+		profiles = append(profiles, &conf)
+	}
+	return profiles, nil
+}
+
+// Destinations returns the config of each destination that the imdb data is synced to, starting with
+// SYNC_DESTINATION and followed by the destinations of SYNC_DESTINATIONS.
+func (c *Config) Destinations() []*Config {
+	destinations := []*Config{c}
+	if c.Sync.Destinations == nil {
+		return destinations
+	}
+	for _, destination := range *c.Sync.Destinations {
+		destinations = append(destinations, c.ForDestination(destination))
+	}
+	return destinations
+}
+
+// DestinationConfig returns the config that syncs to the destination, which is either SYNC_DESTINATION or one of
+// SYNC_DESTINATIONS.
+func (c *Config) DestinationConfig(destination string) (*Config, error) {
+	for _, conf := range c.Destinations() {
+		if *conf.Sync.Destination == destination {
+			return conf, nil
+		}
+	}
+	return nil, fmt.Errorf("destination %s is neither SYNC_DESTINATION nor one of SYNC_DESTINATIONS", destination)
+}
+
+// ForDestination returns a copy of the config that syncs to the destination instead. The state of the copy is kept
+// apart from the state of SYNC_DESTINATION, the same way that the state of each profile is kept apart.
+func (c *Config) ForDestination(destination string) *Config {
+	conf := *c
+	conf.Sync.Destination = pointer(destination)
+	conf.Sync.Destinations = pointer(make([]string, 0))
+	if conf.State.Dir != nil {
+		conf.State.Dir = pointer(filepath.Join(*conf.State.Dir, destination))
+	}
+	if !isNilOrEmpty(conf.State.URL) {
+		if u, err := url.Parse(*conf.State.URL); err == nil {
+			u.Path = path.Join("/", u.Path, destination)
+			conf.State.URL = pointer(u.String())
+		}
+	}
+	return &conf
+}
+
+// Profile returns the name of the profile that the config belongs to, which is empty for the top level config.
+func (c *Config) Profile() string {
+	return c.profile
+}
+
 func (c *Config) Validate() error {
+	if err := c.validateIMDbAuth(); err != nil {
+		return err
+	}
+	if err := c.validateListIdentifiers(); err != nil {
+		return fmt.Errorf("field 'IMDB_LISTS' is invalid: %w", err)
+	}
+	if err := c.validateCharts(); err != nil {
+		return fmt.Errorf("field 'IMDB_CHARTS' is invalid: %w", err)
+	}
+	if c.IMDb.AllLists() && isNilOrEmpty(c.IMDb.ExportDir) && *c.IMDb.Auth == IMDbAuthMethodNone && isNilOrEmpty(c.IMDb.UserID) {
+		return fmt.Errorf("field 'IMDB_USERID' is required to discover all lists when IMDB_AUTH is %s", IMDbAuthMethodNone)
+	}
+	if !isNilOrEmpty(c.IMDb.UserID) && !regexp.MustCompile(`^ur[0-9]+$`).MatchString(*c.IMDb.UserID) {
+		return fmt.Errorf("field 'IMDB_USERID' is invalid: valid user id starts with ur and is followed by digits, but got %s", *c.IMDb.UserID)
+	}
+	if c.IMDb.RetryMaxAttempts != nil && *c.IMDb.RetryMaxAttempts < 1 {
+		return fmt.Errorf("field 'IMDB_RETRYMAXATTEMPTS' must be greater than 0")
+	}
+	if c.IMDb.RetryBackoff != nil && *c.IMDb.RetryBackoff < 0 {
+		return fmt.Errorf("field 'IMDB_RETRYBACKOFF' must not be negative")
+	}
+	if c.IMDb.RetryJitter != nil && *c.IMDb.RetryJitter < 0 {
+		return fmt.Errorf("field 'IMDB_RETRYJITTER' must not be negative")
+	}
+	if c.HTTP.Timeout != nil && *c.HTTP.Timeout <= 0 {
+		return fmt.Errorf("field 'HTTP_TIMEOUT' must be greater than 0")
+	}
+	if err := c.validateTimeouts(); err != nil {
+		return fmt.Errorf("field 'HTTP_TIMEOUTS' is invalid: %w", err)
+	}
+	if err := c.validateBaseURLs(); err != nil {
+		return fmt.Errorf("field 'HTTP_BASEURLS' is invalid: %w", err)
+	}
+	if c.HTTP.KeepAlive != nil && *c.HTTP.KeepAlive < 0 {
+		return fmt.Errorf("field 'HTTP_KEEPALIVE' must not be negative")
+	}
+	if c.HTTP.IdleConnTimeout != nil && *c.HTTP.IdleConnTimeout < 0 {
+		return fmt.Errorf("field 'HTTP_IDLECONNTIMEOUT' must not be negative")
+	}
+	if c.HTTP.MaxIdleConns != nil && *c.HTTP.MaxIdleConns < 0 {
+		return fmt.Errorf("field 'HTTP_MAXIDLECONNS' must not be negative")
+	}
+	if c.HTTP.TLSMinVersion != nil && !slices.Contains(validTLSVersions(), *c.HTTP.TLSMinVersion) {
+		return fmt.Errorf("field 'HTTP_TLSMINVERSION' must be one of: %s", strings.Join(validTLSVersions(), ", "))
+	}
+	if err := c.validateCassette(); err != nil {
+		return err
+	}
+	if err := c.validateProxy(); err != nil {
+		return err
+	}
+	if err := c.validateListNameTemplates(); err != nil {
+		return err
+	}
+	if err := c.validateListModes(); err != nil {
+		return fmt.Errorf("field 'SYNC_LISTMODES' is invalid: %w", err)
+	}
+	if err := c.validateMediaTypes(); err != nil {
+		return fmt.Errorf("field 'SYNC_MEDIATYPES' is invalid: %w", err)
+	}
+	if err := c.validateExclude(); err != nil {
+		return fmt.Errorf("field 'SYNC_EXCLUDE' is invalid: %w", err)
+	}
+	if err := c.validateFanOut(); err != nil {
+		return fmt.Errorf("field 'SYNC_FANOUT' is invalid: %w", err)
+	}
+	if err := c.validatePrecedence(); err != nil {
+		return fmt.Errorf("field 'SYNC_PRECEDENCE' is invalid: %w", err)
+	}
+	if err := c.validateLikedLists(); err != nil {
+		return fmt.Errorf("field 'SYNC_LIKEDLISTS' is invalid: %w", err)
+	}
+	if err := c.validateReverseLists(); err != nil {
+		return err
+	}
+	if err := c.validateCollection(); err != nil {
+		return err
+	}
+	if c.Sync.RatingRounding != nil && !slices.Contains(rating.Roundings(), *c.Sync.RatingRounding) {
+		return fmt.Errorf("field 'SYNC_RATINGROUNDING' must be one of: %s", strings.Join(rating.Roundings(), ", "))
+	}
+	if !isNilOrEmpty(c.Sync.Favorites) && !regexp.MustCompile(`^ls[0-9]{9}$`).MatchString(*c.Sync.Favorites) {
+		return fmt.Errorf("field 'SYNC_FAVORITES' is invalid: valid list id starts with ls and is followed by 9 digits, but got %s", *c.Sync.Favorites)
+	}
+	if err := c.validateMirrorList("SYNC_CHECKINLIST", c.Sync.CheckinList); err != nil {
+		return err
+	}
+	if err := c.validateMirrorList("SYNC_WATCHEDLIST", c.Sync.WatchedList); err != nil {
+		return err
+	}
+	if err := c.validateMirrorList("SYNC_RECOMMENDATIONS", c.Sync.Recommendations); err != nil {
+		return err
+	}
+	if err := c.validateMirrorList("SYNC_HIDDEN", c.Sync.Hidden); err != nil {
+		return err
+	}
+	if err := c.validateRatingsMap(); err != nil {
+		return fmt.Errorf("field 'SYNC_RATINGSMAP' is invalid: %w", err)
+	}
+	if err := c.validateDestination(); err != nil {
+		return err
+	}
+	if err := c.validateDestinations(); err != nil {
+		return fmt.Errorf("field 'SYNC_DESTINATIONS' is invalid: %w", err)
+	}
+	if c.Plex.Enabled != nil && *c.Plex.Enabled && isNilOrEmpty(c.Plex.Token) {
+		return fmt.Errorf("field 'PLEX_TOKEN' is required")
+	}
+	if err := c.validateAnime(); err != nil {
+		return err
+	}
+	if isNilOrEmpty(c.Sync.Mode) {
+		return fmt.Errorf("field 'SYNC_MODE' is required")
+	}
+	if !slices.Contains(validSyncModes(), *c.Sync.Mode) {
+		return fmt.Errorf("field 'SYNC_MODE' must be one of: %s", strings.Join(validSyncModes(), ", "))
+	}
+	if c.Sync.Guardrail != nil && (*c.Sync.Guardrail < 0 || *c.Sync.Guardrail > 100) {
+		return fmt.Errorf("field 'SYNC_GUARDRAIL' must be between 0 and 100")
+	}
+	if c.Sync.ListLimit != nil && !slices.Contains(validListLimitStrategies(), *c.Sync.ListLimit) {
+		return fmt.Errorf("field 'SYNC_LISTLIMIT' must be one of: %s", strings.Join(validListLimitStrategies(), ", "))
+	}
+	if c.Sync.SkipListTTL != nil && *c.Sync.SkipListTTL < 0 {
+		return fmt.Errorf("field 'SYNC_SKIPLISTTTL' must not be negative")
+	}
+	if c.Sync.HistoryWindow != nil && *c.Sync.HistoryWindow < 0 {
+		return fmt.Errorf("field 'SYNC_HISTORYWINDOW' must not be negative")
+	}
+	if c.Sync.MaxDuration != nil {
+		if *c.Sync.MaxDuration < 0 {
+			return fmt.Errorf("field 'SYNC_MAXDURATION' must not be negative")
+		}
+		if c.Sync.Timeout != nil && *c.Sync.MaxDuration > *c.Sync.Timeout {
+			return fmt.Errorf("field 'SYNC_MAXDURATION' must not exceed SYNC_TIMEOUT, which would cancel the sync before its time budget runs out")
+		}
+	}
+	if !isNilOrEmpty(c.Sync.Schedule) {
+		if _, err := cron.ParseStandard(*c.Sync.Schedule); err != nil {
+			return fmt.Errorf("field 'SYNC_SCHEDULE' is invalid: %w", err)
+		}
+	}
+	if c.Sync.RatingsConflict != nil && !slices.Contains(validRatingsConflictStrategies(), *c.Sync.RatingsConflict) {
+		return fmt.Errorf("field 'SYNC_RATINGSCONFLICT' must be one of: %s", strings.Join(validRatingsConflictStrategies(), ", "))
+	}
+	if isNilOrEmpty(c.Log.Level) {
+		return fmt.Errorf("field 'LOG_LEVEL' is required")
+	}
+	if !slices.Contains(validLogLevels(), *c.Log.Level) {
+		return fmt.Errorf("field 'LOG_LEVEL' must be one of: %s", strings.Join(validLogLevels(), ", "))
+	}
+	if isNilOrEmpty(c.Log.Format) {
+		return fmt.Errorf("field 'LOG_FORMAT' is required")
+	}
+	if !slices.Contains(validLogFormats(), *c.Log.Format) {
+		return fmt.Errorf("field 'LOG_FORMAT' must be one of: %s", strings.Join(validLogFormats(), ", "))
+	}
+	if err := c.validateNotification(); err != nil {
+		return err
+	}
+	if err := c.validateSecrets(); err != nil {
+		return err
+	}
+	if c.Server.Enabled != nil && *c.Server.Enabled && isNilOrEmpty(c.Server.Address) {
+		return fmt.Errorf("field 'SERVER_ADDRESS' is required")
+	}
+	if c.Server.MaxSyncAge != nil && *c.Server.MaxSyncAge <= 0 {
+		return fmt.Errorf("field 'SERVER_MAXSYNCAGE' must be greater than 0")
+	}
+	if isNilOrEmpty(c.State.Dir) {
+		return fmt.Errorf("field 'STATE_DIR' is required")
+	}
+	if !isNilOrEmpty(c.State.URL) {
+		if _, err := state.ParseURL(*c.State.URL); err != nil {
+			return fmt.Errorf("field 'STATE_URL' is invalid: %w", err)
+		}
+	}
+	if err := c.validateTracing(); err != nil {
+		return err
+	}
+	return c.checkDummies()
+}
+
+func (c *Config) validateIMDbAuth() error {
+	// neither the exports nor a snapshot require signing in to imdb
+	if !isNilOrEmpty(c.IMDb.ExportDir) || !isNilOrEmpty(c.IMDb.SnapshotIn) {
+		return nil
+	}
 	if isNilOrEmpty(c.IMDb.Auth) {
 		return fmt.Errorf("field 'IMDB_AUTH' is required")
 	}
@@ -120,42 +815,890 @@ func (c *Config) Validate() error {
 		if isNilOrEmpty(c.IMDb.CookieUbidMain) {
 			return fmt.Errorf("field 'IMDB_COOKIEUBIDMAIN' is required")
 		}
+		if c.IMDb.CookieRefresh != nil && *c.IMDb.CookieRefresh {
+			if isNilOrEmpty(c.IMDb.Email) {
+				return fmt.Errorf("field 'IMDB_EMAIL' is required when IMDB_COOKIEREFRESH is enabled")
+			}
+			if isNilOrEmpty(c.IMDb.Password) {
+				return fmt.Errorf("field 'IMDB_PASSWORD' is required when IMDB_COOKIEREFRESH is enabled")
+			}
+		}
 	case IMDbAuthMethodNone:
 	default:
 		return fmt.Errorf("field 'IMDB_AUTH' must be one of: %s", strings.Join(validIMDbAuthMethods(), ", "))
 	}
-	if err := c.validateListIdentifiers(); err != nil {
-		return fmt.Errorf("field 'IMDB_LISTS' is invalid: %w", err)
+	if c.IMDb.Backend == nil {
+		return nil
+	}
+	if !slices.Contains(validIMDbBackends(), *c.IMDb.Backend) {
+		return fmt.Errorf("field 'IMDB_BACKEND' must be one of: %s", strings.Join(validIMDbBackends(), ", "))
+	}
+	if *c.IMDb.Backend == IMDbBackendGraphQL && *c.IMDb.Auth == IMDbAuthMethodCredentials {
+		return fmt.Errorf("field 'IMDB_AUTH' must be one of: %s, when IMDB_BACKEND is %s", strings.Join([]string{IMDbAuthMethodCookies, IMDbAuthMethodNone}, ", "), IMDbBackendGraphQL)
+	}
+	return nil
+}
+
+func (c *Config) validateProxy() error {
+	if isNilOrEmpty(c.HTTP.Proxy) {
+		return nil
+	}
+	proxyURL, err := url.Parse(*c.HTTP.Proxy)
+	if err != nil {
+		return fmt.Errorf("field 'HTTP_PROXY' is invalid: %w", err)
+	}
+	if !slices.Contains(validProxySchemes(), proxyURL.Scheme) || proxyURL.Host == "" {
+		return fmt.Errorf("field 'HTTP_PROXY' must be a url with one of the schemes: %s", strings.Join(validProxySchemes(), ", "))
+	}
+	return nil
+}
+
+func (c *Config) validateTimeouts() error {
+	if c.HTTP.Timeouts == nil {
+		return nil
+	}
+	for _, entry := range *c.HTTP.Timeouts {
+		client, value, found := strings.Cut(entry, ":")
+		if !found || !slices.Contains(validHTTPClients(), client) {
+			return fmt.Errorf("valid timeout has format client:duration, where client is one of: %s, but got %s", strings.Join(validHTTPClients(), ", "), entry)
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("timeout must be a duration greater than 0, but got %s", value)
+		}
+	}
+	return nil
+}
+
+// ClientTimeout returns the maximum duration of a single request that the named client sends, taking per-client
+// overrides into account.
+func (h *HTTP) ClientTimeout(client string) time.Duration {
+	if h.Timeouts != nil {
+		for _, entry := range *h.Timeouts {
+			name, value, _ := strings.Cut(entry, ":")
+			if name != client {
+				continue
+			}
+			if timeout, err := time.ParseDuration(value); err == nil {
+				return timeout
+			}
+		}
+	}
+	return *h.Timeout
+}
+
+func (c *Config) validateBaseURLs() error {
+	if c.HTTP.BaseURLs == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, entry := range *c.HTTP.BaseURLs {
+		service, value, found := strings.Cut(entry, ":")
+		if !found || !slices.Contains(validHTTPBaseURLServices(), service) {
+			return fmt.Errorf("valid base url has format service:url, where service is one of: %s, but got %s", strings.Join(validHTTPBaseURLServices(), ", "), entry)
+		}
+		if seen[service] {
+			return fmt.Errorf("base url of service %s is defined more than once", service)
+		}
+		seen[service] = true
+		baseURL, err := url.Parse(value)
+		if err != nil || (baseURL.Scheme != "http" && baseURL.Scheme != "https") || baseURL.Host == "" {
+			return fmt.Errorf("base url must be an absolute http or https url, but got %s", value)
+		}
+		if baseURL.RawQuery != "" || baseURL.Fragment != "" {
+			return fmt.Errorf("base url must not have a query or a fragment, but got %s", value)
+		}
+	}
+	return nil
+}
+
+// BaseURL returns the base url that replaces the default base url of the named service, and whether one is configured.
+func (h *HTTP) BaseURL(service string) (string, bool) {
+	if h.BaseURLs == nil {
+		return "", false
+	}
+	for _, entry := range *h.BaseURLs {
+		name, value, _ := strings.Cut(entry, ":")
+		if name == service {
+			return strings.TrimSuffix(value, "/"), true
+		}
+	}
+	return "", false
+}
+
+func (c *Config) validateNotification() error {
+	if c.Notification.Provider == nil {
+		return nil
+	}
+	if !slices.Contains(validNotificationProviders(), *c.Notification.Provider) {
+		return fmt.Errorf("field 'NOTIFICATION_PROVIDER' must be one of: %s", strings.Join(validNotificationProviders(), ", "))
+	}
+	switch *c.Notification.Provider {
+	case NotificationProviderNone:
+		return nil
+	case NotificationProviderSMTP:
+		if isNilOrEmpty(c.Notification.SMTPHost) {
+			return fmt.Errorf("field 'NOTIFICATION_SMTPHOST' is required")
+		}
+		if c.Notification.SMTPPort != nil && (*c.Notification.SMTPPort < 1 || *c.Notification.SMTPPort > 65535) {
+			return fmt.Errorf("field 'NOTIFICATION_SMTPPORT' must be between 1 and 65535")
+		}
+		if c.Notification.SMTPTLS != nil && !slices.Contains(validSMTPTLSModes(), *c.Notification.SMTPTLS) {
+			return fmt.Errorf("field 'NOTIFICATION_SMTPTLS' must be one of: %s", strings.Join(validSMTPTLSModes(), ", "))
+		}
+		if isNilOrEmpty(c.Notification.SMTPFrom) {
+			return fmt.Errorf("field 'NOTIFICATION_SMTPFROM' is required")
+		}
+		if c.Notification.SMTPTo == nil || len(*c.Notification.SMTPTo) == 0 {
+			return fmt.Errorf("field 'NOTIFICATION_SMTPTO' is required")
+		}
+		return nil
+	default:
+		if isNilOrEmpty(c.Notification.URL) {
+			return fmt.Errorf("field 'NOTIFICATION_URL' is required")
+		}
+		return nil
+	}
+}
+
+func (c *Config) validateTracing() error {
+	if c.Tracing.SampleRatio != nil && (*c.Tracing.SampleRatio < 0 || *c.Tracing.SampleRatio > 1) {
+		return fmt.Errorf("field 'TRACING_SAMPLERATIO' must be between 0 and 1")
+	}
+	if isNilOrEmpty(c.Tracing.Endpoint) {
+		return nil
+	}
+	endpoint, err := url.Parse(*c.Tracing.Endpoint)
+	if err != nil {
+		return fmt.Errorf("field 'TRACING_ENDPOINT' is invalid: %w", err)
+	}
+	if (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return fmt.Errorf("field 'TRACING_ENDPOINT' must be a url with one of the schemes: http, https")
+	}
+	return nil
+}
+
+func (c *Config) validateCassette() error {
+	if c.HTTP.CassetteMode == nil || *c.HTTP.CassetteMode == HTTPCassetteModeOff {
+		return nil
+	}
+	if !slices.Contains(validCassetteModes(), *c.HTTP.CassetteMode) {
+		return fmt.Errorf("field 'HTTP_CASSETTEMODE' must be one of: %s", strings.Join(validCassetteModes(), ", "))
+	}
+	if isNilOrEmpty(c.HTTP.Cassette) {
+		return fmt.Errorf("field 'HTTP_CASSETTE' is required when HTTP_CASSETTEMODE is %s", *c.HTTP.CassetteMode)
+	}
+	// the browser does not send its requests through the http clients, so they can be neither recorded nor replayed
+	if isNilOrEmpty(c.IMDb.ExportDir) && (c.IMDb.Backend == nil || *c.IMDb.Backend != IMDbBackendGraphQL) {
+		return fmt.Errorf("field 'HTTP_CASSETTEMODE' requires IMDB_EXPORTDIR or IMDB_BACKEND %s", IMDbBackendGraphQL)
+	}
+	return nil
+}
+
+func (c *Config) validateDestination() error {
+	destination := SyncDestinationTrakt
+	if c.Sync.Destination != nil {
+		destination = *c.Sync.Destination
+	}
+	switch destination {
+	case SyncDestinationTrakt:
+		if err := c.validateTraktAuth(); err != nil {
+			return err
+		}
+		if isNilOrEmpty(c.Trakt.ClientID) {
+			return fmt.Errorf("field 'TRAKT_CLIENTID' is required")
+		}
+		if isNilOrEmpty(c.Trakt.ClientSecret) {
+			return fmt.Errorf("field 'TRAKT_CLIENTSECRET' is required")
+		}
+		if err := c.validateTraktListSettings(); err != nil {
+			return err
+		}
+	case SyncDestinationSimkl:
+		if isNilOrEmpty(c.Simkl.ClientID) {
+			return fmt.Errorf("field 'SIMKL_CLIENTID' is required")
+		}
+		if !isNilOrEmpty(c.Sync.Favorites) {
+			return fmt.Errorf("field 'SYNC_FAVORITES' is not supported with SYNC_DESTINATION %s", SyncDestinationSimkl)
+		}
+		return c.validateLibraryDestination(SyncDestinationSimkl)
+	case SyncDestinationJellyfin:
+		if err := c.validateJellyfin(); err != nil {
+			return err
+		}
+		return c.validateLibraryDestination(SyncDestinationJellyfin)
+	case SyncDestinationKodi:
+		if err := c.validateKodi(); err != nil {
+			return err
+		}
+		return c.validateLibraryDestination(SyncDestinationKodi)
+	default:
+		return fmt.Errorf("field 'SYNC_DESTINATION' must be one of: %s", strings.Join(validSyncDestinations(), ", "))
+	}
+	return nil
+}
+
+// validateDestinations makes sure that each additional destination supports the config, the same way that
+// SYNC_DESTINATION has to.
+func (c *Config) validateDestinations() error {
+	if c.Sync.Destinations == nil {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(*c.Sync.Destinations)+1)
+	if c.Sync.Destination != nil {
+		seen[*c.Sync.Destination] = struct{}{}
+	}
+	for _, destination := range *c.Sync.Destinations {
+		if !slices.Contains(validSyncDestinations(), destination) {
+			return fmt.Errorf("destination must be one of: %s, but got %s", strings.Join(validSyncDestinations(), ", "), destination)
+		}
+		if _, ok := seen[destination]; ok {
+			return fmt.Errorf("destination %s is synced more than once", destination)
+		}
+		seen[destination] = struct{}{}
+		if err := c.ForDestination(destination).validateDestination(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateLibraryDestination rejects the fields that rely on trakt features, which destinations that only keep a
+// library of titles, such as simkl and jellyfin, do not support.
+func (c *Config) validateLibraryDestination(destination string) error {
+	if c.Sync.Reviews != nil && *c.Sync.Reviews {
+		return fmt.Errorf("field 'SYNC_REVIEWS' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	if !isNilOrEmpty(c.Sync.Collection) {
+		return fmt.Errorf("field 'SYNC_COLLECTION' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	if !isNilOrEmpty(c.Sync.CheckinList) {
+		return fmt.Errorf("field 'SYNC_CHECKINLIST' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	if !isNilOrEmpty(c.Sync.WatchedList) {
+		return fmt.Errorf("field 'SYNC_WATCHEDLIST' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	if c.IMDb.Charts != nil && len(*c.IMDb.Charts) > 0 {
+		return fmt.Errorf("field 'IMDB_CHARTS' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	if !isNilOrEmpty(c.Sync.UpcomingList) {
+		return fmt.Errorf("field 'SYNC_UPCOMINGLIST' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	if !isNilOrEmpty(c.Sync.Recommendations) {
+		return fmt.Errorf("field 'SYNC_RECOMMENDATIONS' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	if !isNilOrEmpty(c.Sync.Hidden) {
+		return fmt.Errorf("field 'SYNC_HIDDEN' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	if c.Sync.ItemNotes != nil && *c.Sync.ItemNotes {
+		return fmt.Errorf("field 'SYNC_ITEMNOTES' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	if c.Sync.FanOut != nil && len(*c.Sync.FanOut) > 0 {
+		return fmt.Errorf("field 'SYNC_FANOUT' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	if c.Sync.Precedence != nil && len(*c.Sync.Precedence) > 0 {
+		return fmt.Errorf("field 'SYNC_PRECEDENCE' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	if c.Sync.LikedLists != nil && len(*c.Sync.LikedLists) > 0 {
+		return fmt.Errorf("field 'SYNC_LIKEDLISTS' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	if c.Sync.ReverseLists != nil && len(*c.Sync.ReverseLists) > 0 {
+		return fmt.Errorf("field 'SYNC_REVERSELISTS' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	if !isNilOrEmpty(c.Sync.MappingFile) {
+		return fmt.Errorf("field 'SYNC_MAPPINGFILE' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	if c.Sync.SearchFallback != nil && *c.Sync.SearchFallback {
+		return fmt.Errorf("field 'SYNC_SEARCHFALLBACK' is not supported with SYNC_DESTINATION %s", destination)
+	}
+	return nil
+}
+
+func (c *Config) validateJellyfin() error {
+	if err := validateServerURL("JELLYFIN_URL", c.Jellyfin.URL); err != nil {
+		return err
+	}
+	if isNilOrEmpty(c.Jellyfin.APIKey) {
+		return fmt.Errorf("field 'JELLYFIN_APIKEY' is required")
+	}
+	if isNilOrEmpty(c.Jellyfin.Username) {
+		return fmt.Errorf("field 'JELLYFIN_USERNAME' is required")
+	}
+	if c.Plex.Enabled != nil && *c.Plex.Enabled {
+		return fmt.Errorf("field 'PLEX_ENABLED' is not supported with SYNC_DESTINATION %s", SyncDestinationJellyfin)
+	}
+	return nil
+}
+
+func (c *Config) validateKodi() error {
+	if err := validateServerURL("KODI_URL", c.Kodi.URL); err != nil {
+		return err
+	}
+	if !isNilOrEmpty(c.Sync.Favorites) {
+		return fmt.Errorf("field 'SYNC_FAVORITES' is not supported with SYNC_DESTINATION %s", SyncDestinationKodi)
+	}
+	if c.Plex.Enabled != nil && *c.Plex.Enabled {
+		return fmt.Errorf("field 'PLEX_ENABLED' is not supported with SYNC_DESTINATION %s", SyncDestinationKodi)
+	}
+	return nil
+}
+
+// validateServerURL makes sure that a required field holds the base url of a self-hosted server.
+func validateServerURL(field string, value *string) error {
+	if isNilOrEmpty(value) {
+		return fmt.Errorf("field '%s' is required", field)
+	}
+	endpoint, err := url.Parse(*value)
+	if err != nil {
+		return fmt.Errorf("field '%s' is invalid: %w", field, err)
+	}
+	if (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return fmt.Errorf("field '%s' must be a url with one of the schemes: http, https", field)
+	}
+	return nil
+}
+
+func (c *Config) validateSecrets() error {
+	if c.Secrets.Provider == nil {
+		return nil
+	}
+	switch *c.Secrets.Provider {
+	case SecretsProviderVault:
+		if isNilOrEmpty(c.Secrets.VaultPath) {
+			return fmt.Errorf("field 'SECRETS_VAULTPATH' is required")
+		}
+	case SecretsProviderSops:
+		if isNilOrEmpty(c.Secrets.SopsFile) {
+			return fmt.Errorf("field 'SECRETS_SOPSFILE' is required")
+		}
+	case SecretsProviderKeychain:
+		if isNilOrEmpty(c.Secrets.KeychainService) {
+			return fmt.Errorf("field 'SECRETS_KEYCHAINSERVICE' is required")
+		}
+	case SecretsProviderNone:
+	default:
+		return fmt.Errorf("field 'SECRETS_PROVIDER' must be one of: %s", strings.Join(validSecretsProviders(), ", "))
+	}
+	return nil
+}
+
+func (c *Config) validateTraktListSettings() error {
+	if c.Trakt.BatchSize != nil && *c.Trakt.BatchSize < 1 {
+		return fmt.Errorf("field 'TRAKT_BATCHSIZE' must be greater than 0")
+	}
+	if !isNilOrEmpty(c.Trakt.ListPrivacy) && !slices.Contains(validTraktListPrivacies(), *c.Trakt.ListPrivacy) {
+		return fmt.Errorf("field 'TRAKT_LISTPRIVACY' must be one of: %s", strings.Join(validTraktListPrivacies(), ", "))
+	}
+	if c.Trakt.ListPrivacies != nil {
+		re := regexp.MustCompile(`^[-_a-z0-9]+$`)
+		for _, entry := range *c.Trakt.ListPrivacies {
+			slug, privacy, found := strings.Cut(entry, ":")
+			if !found || !re.MatchString(slug) {
+				return fmt.Errorf("field 'TRAKT_LISTPRIVACIES' is invalid: valid list privacy has format slug:privacy, but got %s", entry)
+			}
+			if !slices.Contains(validTraktListPrivacies(), privacy) {
+				return fmt.Errorf("field 'TRAKT_LISTPRIVACIES' is invalid: list privacy must be one of: %s, but got %s", strings.Join(validTraktListPrivacies(), ", "), privacy)
+			}
+		}
+	}
+	if c.Trakt.ListSortBy != nil && !slices.Contains(validTraktListSortBy(), *c.Trakt.ListSortBy) {
+		return fmt.Errorf("field 'TRAKT_LISTSORTBY' must be one of: %s", strings.Join(validTraktListSortBy(), ", "))
+	}
+	if c.Trakt.ListSortHow != nil && !slices.Contains(validTraktListSortHow(), *c.Trakt.ListSortHow) {
+		return fmt.Errorf("field 'TRAKT_LISTSORTHOW' must be one of: %s", strings.Join(validTraktListSortHow(), ", "))
+	}
+	return nil
+}
+
+// ListPrivacyOf returns the privacy of the trakt list with the given slug, taking per-list overrides into account,
+// and whether the privacy was set explicitly. Lists without an explicit privacy are created private, while the privacy
+// of existing lists is left as is.
+func (t *Trakt) ListPrivacyOf(slug string) (string, bool) {
+	if t.ListPrivacies != nil {
+		for _, entry := range *t.ListPrivacies {
+			if s, privacy, _ := strings.Cut(entry, ":"); s == slug {
+				return privacy, true
+			}
+		}
+	}
+	if isNilOrEmpty(t.ListPrivacy) {
+		return TraktListPrivacyPrivate, false
+	}
+	return *t.ListPrivacy, true
+}
+
+func (c *Config) validateTraktAuth() error {
+	if isNilOrEmpty(c.Trakt.Auth) {
+		return fmt.Errorf("field 'TRAKT_AUTH' is required")
+	}
+	switch *c.Trakt.Auth {
+	case TraktAuthMethodCredentials:
+		if isNilOrEmpty(c.Trakt.Email) {
+			return fmt.Errorf("field 'TRAKT_EMAIL' is required")
+		}
+		if isNilOrEmpty(c.Trakt.Password) {
+			return fmt.Errorf("field 'TRAKT_PASSWORD' is required")
+		}
+	case TraktAuthMethodDevice:
+	default:
+		return fmt.Errorf("field 'TRAKT_AUTH' must be one of: %s", strings.Join(validTraktAuthMethods(), ", "))
+	}
+	if c.Trakt.TokenStorage != nil && !slices.Contains(validTraktTokenStorages(), *c.Trakt.TokenStorage) {
+		return fmt.Errorf("field 'TRAKT_TOKENSTORAGE' must be one of: %s", strings.Join(validTraktTokenStorages(), ", "))
+	}
+	if c.Trakt.TokenStorage != nil && *c.Trakt.TokenStorage == TraktTokenStorageKeyring && isNilOrEmpty(c.Trakt.KeyringService) {
+		return fmt.Errorf("field 'TRAKT_KEYRINGSERVICE' is required")
+	}
+	return nil
+}
+
+func (c *Config) validateListIdentifiers() error {
+	if c.IMDb.AllLists() {
+		return nil
+	}
+	re := regexp.MustCompile(`^ls[0-9]{9}$`)
+	for _, id := range *c.IMDb.Lists {
+		if ok := re.MatchString(id); !ok {
+			return fmt.Errorf("valid list id starts with ls and is followed by 9 digits, but got %s", id)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateCharts() error {
+	if c.IMDb.Charts == nil || len(*c.IMDb.Charts) == 0 {
+		return nil
+	}
+	if !isNilOrEmpty(c.IMDb.ExportDir) {
+		return fmt.Errorf("charts are not available with IMDB_EXPORTDIR")
+	}
+	seen := make(map[string]bool, len(*c.IMDb.Charts))
+	for _, entry := range *c.IMDb.Charts {
+		chart, name, found := strings.Cut(entry, ":")
+		if !found || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("valid chart has format chart:trakt list name, but got %s", entry)
+		}
+		if !slices.Contains(validIMDbCharts(), chart) {
+			return fmt.Errorf("chart must be one of: %s, but got %s", strings.Join(validIMDbCharts(), ", "), chart)
+		}
+		if seen[chart] {
+			return fmt.Errorf("chart %s is configured more than once", chart)
+		}
+		seen[chart] = true
+	}
+	return nil
+}
+
+func (c *Config) validateListModes() error {
+	if c.Sync.ListModes == nil {
+		return nil
+	}
+	re := regexp.MustCompile(`^ls[0-9]{9}$`)
+	for _, entry := range *c.Sync.ListModes {
+		id, mode, found := strings.Cut(entry, ":")
+		if !found || !re.MatchString(id) {
+			return fmt.Errorf("valid list mode has format ls#########:mode, but got %s", entry)
+		}
+		if !slices.Contains(validListModes(), mode) {
+			return fmt.Errorf("list mode must be one of: %s, but got %s", strings.Join(validListModes(), ", "), mode)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateMediaTypes() error {
+	if c.Sync.MediaTypes == nil {
+		return nil
+	}
+	for _, entry := range *c.Sync.MediaTypes {
+		entity, mediaType, found := strings.Cut(entry, ":")
+		if !found || !slices.Contains(validMediaTypeEntities(), entity) {
+			return fmt.Errorf("valid media type has format entity:type, where entity is one of: %s, but got %s", strings.Join(validMediaTypeEntities(), ", "), entry)
+		}
+		if !slices.Contains(validMediaTypes(), mediaType) {
+			return fmt.Errorf("media type must be one of: %s, but got %s", strings.Join(validMediaTypes(), ", "), mediaType)
+		}
+		if mediaType == MediaTypePerson && entity != "lists" {
+			return fmt.Errorf("media type %s is only supported by lists, but got %s", MediaTypePerson, entry)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateExclude() error {
+	if c.Sync.Exclude == nil {
+		return nil
+	}
+	for _, entry := range *c.Sync.Exclude {
+		if _, err := entities.ParseFilterRule(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateFanOut() error {
+	if c.Sync.FanOut == nil {
+		return nil
+	}
+	re := regexp.MustCompile(`^ls[0-9]{9}$`)
+	for _, entry := range *c.Sync.FanOut {
+		id, rest, _ := strings.Cut(entry, ":")
+		name, filter, found := strings.Cut(rest, ":")
+		if !found || !re.MatchString(id) || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("valid fan-out rule has format ls#########:trakt list name:filter, but got %s", entry)
+		}
+		if slices.Contains(validMediaTypes(), filter) {
+			continue
+		}
+		if _, err := entities.ParseFilterRule(filter); err != nil {
+			return fmt.Errorf("filter of fan-out rule %s must be a media type or a filter rule: %w", entry, err)
+		}
+	}
+	return nil
+}
+
+// FanOutRule syncs the items of an imdb list that pass the filter to a trakt list of their own.
+type FanOutRule struct {
+	ListID   string
+	ListName string
+	Filter   entities.ItemFilter
+}
+
+// FanOutRules returns the fan-out rules of the imdb list, in the order they are configured. An imdb list with fan-out
+// rules is only synced to the trakt lists of its rules.
+func (s *Sync) FanOutRules(listID string) []FanOutRule {
+	var rules []FanOutRule
+	if s.FanOut == nil {
+		return rules
+	}
+	for _, entry := range *s.FanOut {
+		id, rest, _ := strings.Cut(entry, ":")
+		name, filter, found := strings.Cut(rest, ":")
+		if !found || id != listID {
+			continue
+		}
+		rule := FanOutRule{
+			ListID:   FanOutListID(id, strings.TrimSpace(name)),
+			ListName: strings.TrimSpace(name),
+		}
+		if slices.Contains(validMediaTypes(), filter) {
+			rule.Filter.MediaTypes = []string{filter}
+		} else if include, err := entities.ParseFilterRule(filter); err == nil {
+			rule.Filter.Include = []entities.FilterRule{include}
+		} else {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// FanOutListID returns the id that the syncer tracks the fan-out list of an imdb list under, which is made of the id of
+// the imdb list and the slug of the trakt list.
+func FanOutListID(listID, listName string) string {
+	return listID + "/" + entities.InferTraktListSlug(listName)
+}
+
+func (c *Config) validatePrecedence() error {
+	if c.Sync.Precedence == nil {
+		return nil
+	}
+	re := regexp.MustCompile(`^(ls[0-9]{9}|` + ListWatchlist + `)$`)
+	for _, entry := range *c.Sync.Precedence {
+		winner, loser, found := strings.Cut(entry, ">")
+		if !found || !re.MatchString(winner) || !re.MatchString(loser) {
+			return fmt.Errorf("valid precedence rule has format list>list, where list is an imdb list id or %s, but got %s", ListWatchlist, entry)
+		}
+		if winner == loser {
+			return fmt.Errorf("precedence rule must name two different lists, but got %s", entry)
+		}
+	}
+	return nil
+}
+
+// PrecedenceRule tells that the items of the Winner list are left out of the Loser list.
+type PrecedenceRule struct {
+	Winner string
+	Loser  string
+}
+
+// PrecedenceRules returns the precedence rules between lists, in the order they are configured.
+func (s *Sync) PrecedenceRules() []PrecedenceRule {
+	var rules []PrecedenceRule
+	if s.Precedence == nil {
+		return rules
+	}
+	for _, entry := range *s.Precedence {
+		if winner, loser, found := strings.Cut(entry, ">"); found {
+			rules = append(rules, PrecedenceRule{
+				Winner: winner,
+				Loser:  loser,
+			})
+		}
+	}
+	return rules
+}
+
+func (c *Config) validateLikedLists() error {
+	if c.Sync.LikedLists == nil {
+		return nil
+	}
+	for _, entry := range *c.Sync.LikedLists {
+		if id, err := strconv.ParseInt(entry, 10, 64); err != nil || id <= 0 {
+			return fmt.Errorf("valid list id is the numeric trakt id of the list, but got %s", entry)
+		}
+	}
+	return nil
+}
+
+// validateReverseLists validates the slugs of the trakt lists that are synced to imdb lists of their own, which are
+// created through the imdb graphql api and therefore require imdb authentication.
+func (c *Config) validateReverseLists() error {
+	if c.Sync.ReverseLists == nil || len(*c.Sync.ReverseLists) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(*c.Sync.ReverseLists))
+	for _, slug := range *c.Sync.ReverseLists {
+		if !regexp.MustCompile(`^[-_a-z0-9]+$`).MatchString(slug) {
+			return fmt.Errorf("field 'SYNC_REVERSELISTS' is invalid: valid list slug consists of lowercase letters, digits, hyphens and underscores, but got %s", slug)
+		}
+		if _, found := seen[slug]; found {
+			return fmt.Errorf("field 'SYNC_REVERSELISTS' is invalid: duplicate list slug %s", slug)
+		}
+		seen[slug] = struct{}{}
+	}
+	if !isNilOrEmpty(c.IMDb.ExportDir) {
+		return fmt.Errorf("field 'SYNC_REVERSELISTS' is not supported with IMDB_EXPORTDIR")
+	}
+	if *c.IMDb.Auth == IMDbAuthMethodNone {
+		return fmt.Errorf("field 'SYNC_REVERSELISTS' is not supported when IMDB_AUTH is %s", IMDbAuthMethodNone)
+	}
+	return nil
+}
+
+// ItemFilter returns the filter selecting the items to be synced for the given entity, made of the media types of the
+// entity and the exclusion rules, which apply to every entity.
+func (s *Sync) ItemFilter(entity string) entities.ItemFilter {
+	var filter entities.ItemFilter
+	if s.MediaTypes != nil {
+		for _, entry := range *s.MediaTypes {
+			if e, mediaType, _ := strings.Cut(entry, ":"); e == entity {
+				filter.MediaTypes = append(filter.MediaTypes, mediaType)
+			}
+		}
+	}
+	if s.Exclude != nil {
+		for _, entry := range *s.Exclude {
+			if rule, err := entities.ParseFilterRule(entry); err == nil {
+				filter.Exclude = append(filter.Exclude, rule)
+			}
+		}
+	}
+	return filter
+}
+
+func (c *Config) validateCollection() error {
+	if !isNilOrEmpty(c.Sync.Collection) && !regexp.MustCompile(`^ls[0-9]{9}$`).MatchString(*c.Sync.Collection) {
+		return fmt.Errorf("field 'SYNC_COLLECTION' is invalid: valid list id starts with ls and is followed by 9 digits, but got %s", *c.Sync.Collection)
+	}
+	if !isNilOrEmpty(c.Sync.CollectionMedia) && !slices.Contains(validCollectionMedia(), *c.Sync.CollectionMedia) {
+		return fmt.Errorf("field 'SYNC_COLLECTIONMEDIA' must be one of: %s", strings.Join(validCollectionMedia(), ", "))
+	}
+	return nil
+}
+
+// validateMirrorList makes sure an imdb list that mirrors trakt data can be edited, which requires an authenticated
+// imdb session.
+func (c *Config) validateMirrorList(field string, lid *string) error {
+	if isNilOrEmpty(lid) {
+		return nil
+	}
+	if !regexp.MustCompile(`^ls[0-9]{9}$`).MatchString(*lid) {
+		return fmt.Errorf("field '%s' is invalid: valid list id starts with ls and is followed by 9 digits, but got %s", field, *lid)
+	}
+	if !isNilOrEmpty(c.IMDb.ExportDir) {
+		return fmt.Errorf("field '%s' is not supported with IMDB_EXPORTDIR", field)
+	}
+	if *c.IMDb.Auth == IMDbAuthMethodNone {
+		return fmt.Errorf("field '%s' is not supported when IMDB_AUTH is %s", field, IMDbAuthMethodNone)
+	}
+	return nil
+}
+
+func (c *Config) validateAnime() error {
+	if c.Anime.Provider == nil || *c.Anime.Provider == AnimeProviderNone {
+		return nil
+	}
+	if !slices.Contains(validAnimeProviders(), *c.Anime.Provider) {
+		return fmt.Errorf("field 'ANIME_PROVIDER' must be one of: %s", strings.Join(validAnimeProviders(), ", "))
+	}
+	if isNilOrEmpty(c.Anime.Username) {
+		return fmt.Errorf("field 'ANIME_USERNAME' is required")
+	}
+	if *c.Anime.Provider == AnimeProviderMyAnimeList && isNilOrEmpty(c.Anime.MALClientID) {
+		return fmt.Errorf("field 'ANIME_MALCLIENTID' is required")
+	}
+	return nil
+}
+
+func (c *Config) validateRatingsMap() error {
+	if c.Sync.RatingsMap == nil {
+		return nil
+	}
+	for _, entry := range *c.Sync.RatingsMap {
+		if _, _, _, err := parseRatingsMapEntry(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseRatingsMapEntry parses a rating transformation with format imdb:trakt, where imdb is a single rating or an
+// inclusive range of ratings such as 1-4, and trakt is the rating to sync instead or skip to exclude the ratings.
+// The returned rating is 0 when the ratings are skipped.
+func parseRatingsMapEntry(entry string) (from, to, rating int, err error) {
+	source, target, found := strings.Cut(entry, ":")
+	if !found {
+		return 0, 0, 0, fmt.Errorf("valid rating mapping has format imdb:trakt, but got %s", entry)
+	}
+	low, high, isRange := strings.Cut(source, "-")
+	if from, err = parseRating(low); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid imdb rating in %s: %w", entry, err)
+	}
+	to = from
+	if isRange {
+		if to, err = parseRating(high); err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid imdb rating in %s: %w", entry, err)
+		}
+		if to < from {
+			return 0, 0, 0, fmt.Errorf("invalid imdb rating range in %s", entry)
+		}
+	}
+	if target == RatingsMapSkip {
+		return from, to, 0, nil
+	}
+	if rating, err = parseRating(target); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid trakt rating in %s: %w", entry, err)
+	}
+	return from, to, rating, nil
+}
+
+func parseRating(value string) (int, error) {
+	rating, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if rating < 1 || rating > 10 {
+		return 0, fmt.Errorf("rating must be between 1 and 10, but got %d", rating)
+	}
+	return rating, nil
+}
+
+// MapRating applies the configured rating transformations to an imdb rating. The boolean result reports whether the
+// rating should be synced at all. When several transformations match a rating, the last one wins.
+func (s *Sync) MapRating(rating int) (int, bool) {
+	if s.RatingsMap == nil {
+		return rating, true
+	}
+	mapped := rating
+	for _, entry := range *s.RatingsMap {
+		from, to, target, err := parseRatingsMapEntry(entry)
+		if err != nil || rating < from || rating > to {
+			continue
+		}
+		mapped = target
+	}
+	return mapped, mapped != 0
+}
+
+func (c *Config) validateListNameTemplates() error {
+	if c.Sync.ListNameTemplate != nil {
+		if _, err := template.New("").Parse(*c.Sync.ListNameTemplate); err != nil {
+			return fmt.Errorf("field 'SYNC_LISTNAMETEMPLATE' is invalid: %w", err)
+		}
+	}
+	if c.Sync.ListNameTemplates == nil {
+		return nil
+	}
+	re := regexp.MustCompile(`^ls[0-9]{9}$`)
+	for _, entry := range *c.Sync.ListNameTemplates {
+		id, text, found := strings.Cut(entry, ":")
+		if !found || !re.MatchString(id) {
+			return fmt.Errorf("field 'SYNC_LISTNAMETEMPLATES' is invalid: valid list name template has format ls#########:template, but got %s", entry)
+		}
+		if _, err := template.New(id).Parse(text); err != nil {
+			return fmt.Errorf("field 'SYNC_LISTNAMETEMPLATES' is invalid: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListNameTemplateData holds the values available to list name templates.
+type ListNameTemplateData struct {
+	ListID   string
+	ListName string
+	Year     int
+}
+
+// TraktListName renders the name of the trakt list that an imdb list is synced to, using the list specific
+// template if one is configured, or the global template otherwise.
+func (s *Sync) TraktListName(listID, listName string) (string, error) {
+	text := ListNameTemplateDefault
+	if s.ListNameTemplate != nil && *s.ListNameTemplate != "" {
+		text = *s.ListNameTemplate
 	}
-	if isNilOrEmpty(c.Trakt.Email) {
-		return fmt.Errorf("field 'TRAKT_EMAIL' is required")
+	if s.ListNameTemplates != nil {
+		for _, entry := range *s.ListNameTemplates {
+			if id, t, _ := strings.Cut(entry, ":"); id == listID {
+				text = t
+				break
+			}
+		}
 	}
-	if isNilOrEmpty(c.Trakt.Password) {
-		return fmt.Errorf("field 'TRAKT_PASSWORD' is required")
+	tmpl, err := template.New(listID).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failure parsing list name template: %w", err)
 	}
-	if isNilOrEmpty(c.Trakt.ClientID) {
-		return fmt.Errorf("field 'TRAKT_CLIENTID' is required")
+	var sb strings.Builder
+	data := ListNameTemplateData{
+		ListID:   listID,
+		ListName: listName,
+		Year:     time.Now().Year(),
 	}
-	if isNilOrEmpty(c.Trakt.ClientSecret) {
-		return fmt.Errorf("field 'TRAKT_CLIENTSECRET' is required")
+	if err = tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failure executing list name template: %w", err)
 	}
-	if isNilOrEmpty(c.Sync.Mode) {
-		return fmt.Errorf("field 'SYNC_MODE' is required")
+	return sb.String(), nil
+}
+
+// ListMode returns the sync mode for the given imdb list, taking per-list overrides into account.
+// Disabled lists are always skipped, while dry runs take precedence over any other override.
+func (s *Sync) ListMode(listID string) string {
+	// the fan-out lists of an imdb list follow the mode of the imdb list
+	listID, _, _ = strings.Cut(listID, "/")
+	if s.ListModes == nil {
+		return s.safeMode(*s.Mode)
 	}
-	if !slices.Contains(validSyncModes(), *c.Sync.Mode) {
-		return fmt.Errorf("field 'SYNC_MODE' must be one of: %s", strings.Join(validSyncModes(), ", "))
+	for _, entry := range *s.ListModes {
+		id, mode, _ := strings.Cut(entry, ":")
+		if id != listID {
+			continue
+		}
+		if mode == ListModeDisabled || *s.Mode != SyncModeDryRun {
+			return s.safeMode(mode)
+		}
+		break
 	}
-	return c.checkDummies()
+	return s.safeMode(*s.Mode)
 }
 
-func (c *Config) validateListIdentifiers() error {
-	re := regexp.MustCompile(`^ls[0-9]{9}$`)
-	for _, id := range *c.IMDb.Lists {
-		if ok := re.MatchString(id); !ok {
-			return fmt.Errorf("valid list id starts with ls and is followed by 9 digits, but got %s", id)
-		}
+// safeMode downgrades a full sync to add-only when safe mode is enabled, so that nothing gets removed.
+func (s *Sync) safeMode(mode string) string {
+	if mode == SyncModeFull && s.SafeMode != nil && *s.SafeMode {
+		return SyncModeAddOnly
 	}
-	return nil
+	return mode
 }
 
 func (c *Config) WriteFile(path string) error {
@@ -195,9 +1738,21 @@ func (c *Config) applyDefaults() {
 	if c.IMDb.Auth == nil {
 		c.IMDb.Auth = pointer(IMDbAuthMethodCookies)
 	}
+	if c.IMDb.Backend == nil {
+		c.IMDb.Backend = pointer(IMDbBackendBrowser)
+	}
+	if c.IMDb.CookieRefresh == nil {
+		c.IMDb.CookieRefresh = pointer(false)
+	}
+	if c.IMDb.CookieJar == nil {
+		c.IMDb.CookieJar = pointer(false)
+	}
 	if c.IMDb.Lists == nil {
 		c.IMDb.Lists = pointer(make([]string, 0))
 	}
+	if c.IMDb.Charts == nil {
+		c.IMDb.Charts = pointer(make([]string, 0))
+	}
 	if c.IMDb.Trace == nil {
 		c.IMDb.Trace = pointer(false)
 	}
@@ -207,12 +1762,117 @@ func (c *Config) applyDefaults() {
 	if c.IMDb.BrowserPath == nil {
 		c.IMDb.BrowserPath = pointer("")
 	}
+	if c.IMDb.ExportDir == nil {
+		c.IMDb.ExportDir = pointer("")
+	}
+	if c.IMDb.SnapshotIn == nil {
+		c.IMDb.SnapshotIn = pointer("")
+	}
+	if c.IMDb.SnapshotOut == nil {
+		c.IMDb.SnapshotOut = pointer("")
+	}
+	if c.IMDb.UserID == nil {
+		c.IMDb.UserID = pointer("")
+	}
+	if c.IMDb.RatingsPaginated == nil {
+		c.IMDb.RatingsPaginated = pointer(false)
+	}
+	if c.IMDb.RetryMaxAttempts == nil {
+		c.IMDb.RetryMaxAttempts = pointer(IMDbRetryMaxAttemptsDefault)
+	}
+	if c.IMDb.RetryBackoff == nil {
+		c.IMDb.RetryBackoff = pointer(IMDbRetryBackoffDefault)
+	}
+	if c.IMDb.RetryJitter == nil {
+		c.IMDb.RetryJitter = pointer(IMDbRetryJitterDefault)
+	}
+	if c.IMDb.UserAgent == nil {
+		c.IMDb.UserAgent = pointer(IMDbUserAgentDefault)
+	}
+	if c.IMDb.UserAgentRotate == nil {
+		c.IMDb.UserAgentRotate = pointer(false)
+	}
+	if c.Trakt.Auth == nil {
+		c.Trakt.Auth = pointer(TraktAuthMethodCredentials)
+	}
+	if c.Trakt.BatchSize == nil {
+		c.Trakt.BatchSize = pointer(TraktBatchSizeDefault)
+	}
+	if c.Trakt.ListPrivacies == nil {
+		c.Trakt.ListPrivacies = pointer(make([]string, 0))
+	}
+	if c.Trakt.ListDisplayNumbers == nil {
+		c.Trakt.ListDisplayNumbers = pointer(false)
+	}
+	if c.Trakt.ListAllowComments == nil {
+		c.Trakt.ListAllowComments = pointer(true)
+	}
+	if c.Trakt.ListSortBy == nil {
+		c.Trakt.ListSortBy = pointer(TraktListSortByDefault)
+	}
+	if c.Trakt.ListSortHow == nil {
+		c.Trakt.ListSortHow = pointer(TraktListSortHowAsc)
+	}
+	if c.Trakt.TokenStorage == nil {
+		c.Trakt.TokenStorage = pointer(TraktTokenStorageState)
+	}
+	if c.Trakt.TokenPassphrase == nil {
+		c.Trakt.TokenPassphrase = pointer("")
+	}
+	if c.Trakt.KeyringService == nil {
+		c.Trakt.KeyringService = pointer(TraktKeyringServiceDefault)
+	}
+	if c.Plex.Enabled == nil {
+		c.Plex.Enabled = pointer(false)
+	}
+	if c.Plex.Token == nil {
+		c.Plex.Token = pointer("")
+	}
+	if c.Simkl.ClientID == nil {
+		c.Simkl.ClientID = pointer("")
+	}
+	if c.Jellyfin.URL == nil {
+		c.Jellyfin.URL = pointer("")
+	}
+	if c.Jellyfin.APIKey == nil {
+		c.Jellyfin.APIKey = pointer("")
+	}
+	if c.Jellyfin.Username == nil {
+		c.Jellyfin.Username = pointer("")
+	}
+	if c.Kodi.URL == nil {
+		c.Kodi.URL = pointer("")
+	}
+	if c.Kodi.Username == nil {
+		c.Kodi.Username = pointer("")
+	}
+	if c.Kodi.Password == nil {
+		c.Kodi.Password = pointer("")
+	}
+	if c.Anime.Provider == nil {
+		c.Anime.Provider = pointer(AnimeProviderNone)
+	}
+	if c.Anime.Username == nil {
+		c.Anime.Username = pointer("")
+	}
+	if c.Anime.MALClientID == nil {
+		c.Anime.MALClientID = pointer("")
+	}
+	if c.Sync.Destination == nil {
+		c.Sync.Destination = pointer(SyncDestinationTrakt)
+	}
+	if c.Sync.Destinations == nil {
+		c.Sync.Destinations = pointer(make([]string, 0))
+	}
 	if c.Sync.Mode == nil {
 		c.Sync.Mode = pointer(SyncModeDryRun)
 	}
 	if c.Sync.History == nil {
 		c.Sync.History = pointer(false)
 	}
+	if c.Sync.HistoryWindow == nil {
+		c.Sync.HistoryWindow = pointer(time.Duration(0))
+	}
 	if c.Sync.Ratings == nil {
 		c.Sync.Ratings = pointer(true)
 	}
@@ -222,9 +1882,234 @@ func (c *Config) applyDefaults() {
 	if c.Sync.Lists == nil {
 		c.Sync.Lists = pointer(true)
 	}
+	if c.Sync.ListModes == nil {
+		c.Sync.ListModes = pointer(make([]string, 0))
+	}
+	if c.Sync.MediaTypes == nil {
+		c.Sync.MediaTypes = pointer(make([]string, 0))
+	}
+	if c.Sync.Exclude == nil {
+		c.Sync.Exclude = pointer(make([]string, 0))
+	}
+	if c.Sync.Duplicates == nil {
+		c.Sync.Duplicates = pointer(false)
+	}
+	if c.Sync.Precedence == nil {
+		c.Sync.Precedence = pointer(make([]string, 0))
+	}
+	if c.Sync.FanOut == nil {
+		c.Sync.FanOut = pointer(make([]string, 0))
+	}
+	if c.Sync.ItemNotes == nil {
+		c.Sync.ItemNotes = pointer(false)
+	}
+	if c.Sync.ListNameTemplate == nil {
+		c.Sync.ListNameTemplate = pointer(ListNameTemplateDefault)
+	}
+	if c.Sync.ListNameTemplates == nil {
+		c.Sync.ListNameTemplates = pointer(make([]string, 0))
+	}
+	if c.Sync.Checkins == nil {
+		c.Sync.Checkins = pointer(false)
+	}
+	if c.Sync.Reviews == nil {
+		c.Sync.Reviews = pointer(false)
+	}
+	if c.Sync.Collection == nil {
+		c.Sync.Collection = pointer("")
+	}
+	if c.Sync.CollectionMedia == nil {
+		c.Sync.CollectionMedia = pointer("")
+	}
+	if c.Sync.Favorites == nil {
+		c.Sync.Favorites = pointer("")
+	}
+	if c.Sync.RatingRounding == nil {
+		c.Sync.RatingRounding = pointer(rating.RoundingHalfStar)
+	}
+	if c.Sync.CheckinList == nil {
+		c.Sync.CheckinList = pointer("")
+	}
+	if c.Sync.WatchedList == nil {
+		c.Sync.WatchedList = pointer("")
+	}
+	if c.Sync.UpcomingList == nil {
+		c.Sync.UpcomingList = pointer("")
+	}
+	if c.Sync.Recommendations == nil {
+		c.Sync.Recommendations = pointer("")
+	}
+	if c.Sync.Hidden == nil {
+		c.Sync.Hidden = pointer("")
+	}
+	if c.Sync.LikedLists == nil {
+		c.Sync.LikedLists = pointer(make([]string, 0))
+	}
+	if c.Sync.ReverseLists == nil {
+		c.Sync.ReverseLists = pointer(make([]string, 0))
+	}
+	if c.Sync.MappingFile == nil {
+		c.Sync.MappingFile = pointer("")
+	}
+	if c.Sync.SearchFallback == nil {
+		c.Sync.SearchFallback = pointer(false)
+	}
+	if c.Sync.Guardrail == nil {
+		c.Sync.Guardrail = pointer(SyncGuardrailDefault)
+	}
+	if c.Sync.ListLimit == nil {
+		c.Sync.ListLimit = pointer(SyncListLimitWarn)
+	}
+	if c.Sync.RatingsConflict == nil {
+		c.Sync.RatingsConflict = pointer(RatingsConflictIMDbWins)
+	}
+	if c.Sync.RatingsMap == nil {
+		c.Sync.RatingsMap = pointer(make([]string, 0))
+	}
+	if c.Sync.SafeMode == nil {
+		c.Sync.SafeMode = pointer(false)
+	}
+	if c.Sync.SkipUnchanged == nil {
+		c.Sync.SkipUnchanged = pointer(false)
+	}
+	if c.Sync.SkipListTTL == nil {
+		c.Sync.SkipListTTL = pointer(time.Duration(0))
+	}
+	if c.Sync.Schedule == nil {
+		c.Sync.Schedule = pointer("")
+	}
 	if c.Sync.Timeout == nil {
 		c.Sync.Timeout = pointer(SyncTimeoutDefault)
 	}
+	if c.Sync.MaxDuration == nil {
+		c.Sync.MaxDuration = pointer(time.Duration(0))
+	}
+	if c.Log.Level == nil {
+		c.Log.Level = pointer(LogLevelInfo)
+	}
+	if c.Log.Format == nil {
+		c.Log.Format = pointer(LogFormatJSON)
+	}
+	if c.Notification.Provider == nil {
+		c.Notification.Provider = pointer(NotificationProviderNone)
+	}
+	if c.Notification.URL == nil {
+		c.Notification.URL = pointer("")
+	}
+	if c.Notification.SMTPHost == nil {
+		c.Notification.SMTPHost = pointer("")
+	}
+	if c.Notification.SMTPPort == nil {
+		c.Notification.SMTPPort = pointer(NotificationSMTPPortDefault)
+	}
+	if c.Notification.SMTPTLS == nil {
+		c.Notification.SMTPTLS = pointer(NotificationSMTPTLSStartTLS)
+	}
+	if c.Notification.SMTPUsername == nil {
+		c.Notification.SMTPUsername = pointer("")
+	}
+	if c.Notification.SMTPPassword == nil {
+		c.Notification.SMTPPassword = pointer("")
+	}
+	if c.Notification.SMTPFrom == nil {
+		c.Notification.SMTPFrom = pointer("")
+	}
+	if c.Notification.SMTPTo == nil {
+		c.Notification.SMTPTo = pointer(make([]string, 0))
+	}
+	if c.Report.Dir == nil {
+		c.Report.Dir = pointer("")
+	}
+	if c.Secrets.Provider == nil {
+		c.Secrets.Provider = pointer(SecretsProviderNone)
+	}
+	if c.Secrets.VaultAddress == nil {
+		c.Secrets.VaultAddress = pointer("")
+	}
+	if c.Secrets.VaultToken == nil {
+		c.Secrets.VaultToken = pointer("")
+	}
+	if c.Secrets.VaultPath == nil {
+		c.Secrets.VaultPath = pointer("")
+	}
+	if c.Secrets.SopsFile == nil {
+		c.Secrets.SopsFile = pointer("")
+	}
+	if c.Secrets.KeychainService == nil {
+		c.Secrets.KeychainService = pointer("")
+	}
+	if c.Secrets.Passphrase == nil {
+		c.Secrets.Passphrase = pointer("")
+	}
+	if c.Secrets.AgeIdentity == nil {
+		c.Secrets.AgeIdentity = pointer("")
+	}
+	if c.Secrets.AgeRecipient == nil {
+		c.Secrets.AgeRecipient = pointer("")
+	}
+	if c.HTTP.Cassette == nil {
+		c.HTTP.Cassette = pointer("")
+	}
+	if c.HTTP.CassetteMode == nil {
+		c.HTTP.CassetteMode = pointer(HTTPCassetteModeOff)
+	}
+	if c.HTTP.Proxy == nil {
+		c.HTTP.Proxy = pointer("")
+	}
+	if c.HTTP.CACert == nil {
+		c.HTTP.CACert = pointer("")
+	}
+	if c.HTTP.Cache == nil {
+		c.HTTP.Cache = pointer(false)
+	}
+	if c.HTTP.Timeout == nil {
+		c.HTTP.Timeout = pointer(HTTPTimeoutDefault)
+	}
+	if c.HTTP.BaseURLs == nil {
+		c.HTTP.BaseURLs = pointer(make([]string, 0))
+	}
+	if c.HTTP.Timeouts == nil {
+		c.HTTP.Timeouts = pointer(make([]string, 0))
+	}
+	if c.HTTP.KeepAlive == nil {
+		c.HTTP.KeepAlive = pointer(HTTPKeepAliveDefault)
+	}
+	if c.HTTP.IdleConnTimeout == nil {
+		c.HTTP.IdleConnTimeout = pointer(HTTPIdleConnTimeoutDefault)
+	}
+	if c.HTTP.MaxIdleConns == nil {
+		c.HTTP.MaxIdleConns = pointer(HTTPMaxIdleConnsDefault)
+	}
+	if c.HTTP.TLSMinVersion == nil {
+		c.HTTP.TLSMinVersion = pointer(HTTPTLSVersion12)
+	}
+	if c.Server.Enabled == nil {
+		c.Server.Enabled = pointer(false)
+	}
+	if c.Server.Address == nil {
+		c.Server.Address = pointer(ServerAddressDefault)
+	}
+	if c.Server.MaxSyncAge == nil {
+		c.Server.MaxSyncAge = pointer(ServerMaxSyncAgeDefault)
+	}
+	if c.Server.TriggerToken == nil {
+		c.Server.TriggerToken = pointer("")
+	}
+	if c.State.Dir == nil {
+		c.State.Dir = pointer(StateDirDefault)
+	}
+	if c.State.URL == nil {
+		c.State.URL = pointer("")
+	}
+	if c.Tracing.Enabled == nil {
+		c.Tracing.Enabled = pointer(false)
+	}
+	if c.Tracing.Endpoint == nil {
+		c.Tracing.Endpoint = pointer("")
+	}
+	if c.Tracing.SampleRatio == nil {
+		c.Tracing.SampleRatio = pointer(TracingSampleRatioDefault)
+	}
 }
 
 func pointer[T any](v T) *T {
@@ -239,6 +2124,77 @@ func validSyncModes() []string {
 	}
 }
 
+func validHTTPClients() []string {
+	return []string{
+		HTTPClientAnime,
+		HTTPClientIMDb,
+		HTTPClientJellyfin,
+		HTTPClientKodi,
+		HTTPClientPlex,
+		HTTPClientSimkl,
+		HTTPClientTrakt,
+	}
+}
+
+func validHTTPBaseURLServices() []string {
+	return []string{
+		HTTPBaseURLServiceAniList,
+		HTTPBaseURLServiceAnimeLists,
+		HTTPBaseURLServiceIMDb,
+		HTTPBaseURLServiceIMDbGQL,
+		HTTPBaseURLServiceMAL,
+		HTTPBaseURLServicePlexDisc,
+		HTTPBaseURLServicePlexMeta,
+		HTTPBaseURLServiceSimkl,
+		HTTPBaseURLServiceTrakt,
+		HTTPBaseURLServiceTraktWeb,
+	}
+}
+
+func validTLSVersions() []string {
+	return []string{
+		HTTPTLSVersion12,
+		HTTPTLSVersion13,
+	}
+}
+
+func validListModes() []string {
+	return []string{
+		SyncModeFull,
+		SyncModeAddOnly,
+		ListModeDisabled,
+	}
+}
+
+func validMediaTypes() []string {
+	return []string{
+		MediaTypeMovie,
+		MediaTypeShow,
+		MediaTypeEpisode,
+		MediaTypePerson,
+	}
+}
+
+func validMediaTypeEntities() []string {
+	return []string{
+		"watchlist",
+		"lists",
+		"ratings",
+		"history",
+		"checkins",
+		"collection",
+	}
+}
+
+func validSyncDestinations() []string {
+	return []string{
+		SyncDestinationTrakt,
+		SyncDestinationSimkl,
+		SyncDestinationJellyfin,
+		SyncDestinationKodi,
+	}
+}
+
 func validIMDbAuthMethods() []string {
 	return []string{
 		IMDbAuthMethodCredentials,
@@ -247,6 +2203,192 @@ func validIMDbAuthMethods() []string {
 	}
 }
 
+func validCassetteModes() []string {
+	return []string{
+		HTTPCassetteModeOff,
+		HTTPCassetteModeRecord,
+		HTTPCassetteModeReplay,
+	}
+}
+
+func validProxySchemes() []string {
+	return []string{
+		"http",
+		"https",
+		"socks5",
+		"socks5h",
+	}
+}
+
+func validIMDbBackends() []string {
+	return []string{
+		IMDbBackendBrowser,
+		IMDbBackendGraphQL,
+	}
+}
+
+func validIMDbCharts() []string {
+	return []string{
+		IMDbChartTop,
+		IMDbChartMovieMeter,
+		IMDbChartTVMeter,
+	}
+}
+
+func validTraktAuthMethods() []string {
+	return []string{
+		TraktAuthMethodCredentials,
+		TraktAuthMethodDevice,
+	}
+}
+
+func validEncryptionMethods() []string {
+	return []string{
+		EncryptionMethodPassphrase,
+		EncryptionMethodAge,
+	}
+}
+
+func validSecretsProviders() []string {
+	return []string{
+		SecretsProviderNone,
+		SecretsProviderVault,
+		SecretsProviderSops,
+		SecretsProviderKeychain,
+	}
+}
+
+// secretKeys lists the config fields that can be loaded from a secrets provider.
+func secretKeys() []string {
+	return []string{
+		"ANIME_MALCLIENTID",
+		"IMDB_EMAIL",
+		"IMDB_PASSWORD",
+		"IMDB_COOKIEATMAIN",
+		"IMDB_COOKIEUBIDMAIN",
+		"JELLYFIN_APIKEY",
+		"KODI_PASSWORD",
+		"NOTIFICATION_SMTPPASSWORD",
+		"NOTIFICATION_URL",
+		"PLEX_TOKEN",
+		"SIMKL_CLIENTID",
+		"TRAKT_EMAIL",
+		"TRAKT_PASSWORD",
+		"TRAKT_CLIENTID",
+		"TRAKT_CLIENTSECRET",
+		"TRAKT_TOKENPASSPHRASE",
+	}
+}
+
+func validTraktTokenStorages() []string {
+	return []string{
+		TraktTokenStorageState,
+		TraktTokenStorageKeyring,
+	}
+}
+
+func validTraktListPrivacies() []string {
+	return []string{
+		TraktListPrivacyPrivate,
+		TraktListPrivacyFriends,
+		TraktListPrivacyPublic,
+	}
+}
+
+func validTraktListSortBy() []string {
+	return []string{
+		"rank",
+		"added",
+		"title",
+		"released",
+		"runtime",
+		"popularity",
+		"percentage",
+		"votes",
+		"my_rating",
+		"random",
+		"watched",
+		"collected",
+	}
+}
+
+func validTraktListSortHow() []string {
+	return []string{
+		TraktListSortHowAsc,
+		TraktListSortHowDesc,
+	}
+}
+
+func validCollectionMedia() []string {
+	return []string{
+		CollectionMediaBluray,
+		CollectionMediaDigital,
+		CollectionMediaDVD,
+		CollectionMediaHDDVD,
+		CollectionMediaLaserDisc,
+		CollectionMediaVHS,
+	}
+}
+
+func validListLimitStrategies() []string {
+	return []string{
+		SyncListLimitWarn,
+		SyncListLimitTruncate,
+		SyncListLimitSkip,
+	}
+}
+
+func validRatingsConflictStrategies() []string {
+	return []string{
+		RatingsConflictIMDbWins,
+		RatingsConflictTraktWins,
+		RatingsConflictNewestWins,
+		RatingsConflictSkip,
+	}
+}
+
+func validLogLevels() []string {
+	return []string{
+		LogLevelDebug,
+		LogLevelInfo,
+		LogLevelWarn,
+		LogLevelError,
+	}
+}
+
+func validLogFormats() []string {
+	return []string{
+		LogFormatJSON,
+		LogFormatText,
+	}
+}
+
+func validAnimeProviders() []string {
+	return []string{
+		AnimeProviderNone,
+		AnimeProviderAniList,
+		AnimeProviderMyAnimeList,
+	}
+}
+
+func validNotificationProviders() []string {
+	return []string{
+		NotificationProviderNone,
+		NotificationProviderDiscord,
+		NotificationProviderSlack,
+		NotificationProviderTelegram,
+		NotificationProviderSMTP,
+	}
+}
+
+func validSMTPTLSModes() []string {
+	return []string{
+		NotificationSMTPTLSNone,
+		NotificationSMTPTLSStartTLS,
+		NotificationSMTPTLSImplicit,
+	}
+}
+
 func dummyValues() []string {
 	return []string{
 		"user@domain.com",
@@ -255,6 +2397,9 @@ func dummyValues() []string {
 		"301-0710501-5367639",
 		"ls000000000",
 		"ls111111111",
+		"ls000000000:add-only",
+		"ls111111111:disabled",
+		"ls000000000:imdb-{{.ListName}}",
 		"828832482dea6fffa4453f849fe873de8be54791b9acc01f6923098d0a62972d",
 		"bdf9bab88c17f3710a6394607e96cd3a21dee6e5ea0e0236e9ed06e425ed8b6f",
 	}
@@ -265,12 +2410,39 @@ func environmentVariableModifier(key string, value string) (string, any) {
 	if value == "" {
 		return key, nil
 	}
-	if strings.Contains(value, ",") {
+	if strings.Contains(value, ",") && !slices.Contains(unsplitEnvironmentVariables(), key) {
 		return key, strings.Split(value, ",")
 	}
 	return key, value
 }
 
+// unsplitEnvironmentVariables are the variables whose values may contain commas, such as user agents, and must
+// therefore not be turned into lists.
+func unsplitEnvironmentVariables() []string {
+	return []string{
+		"IMDB_USERAGENT",
+	}
+}
+
+// AllLists reports whether every list of the user should be synced, which is requested with the keyword all.
+func (i *IMDb) AllLists() bool {
+	return i.Lists != nil && len(*i.Lists) == 1 && (*i.Lists)[0] == IMDbListsAll
+}
+
+// ChartLists returns the names of the trakt lists that the configured charts are synced to, keyed by chart.
+func (i *IMDb) ChartLists() map[string]string {
+	lists := make(map[string]string)
+	if i.Charts == nil {
+		return lists
+	}
+	for _, entry := range *i.Charts {
+		if chart, name, found := strings.Cut(entry, ":"); found {
+			lists[chart] = strings.TrimSpace(name)
+		}
+	}
+	return lists
+}
+
 func isNilOrEmpty(value *string) bool {
 	return value == nil || *value == ""
 }
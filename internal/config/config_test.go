@@ -3,11 +3,17 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/knadh/koanf/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/secrets"
 )
 
 func TestNew(t *testing.T) {
@@ -84,6 +90,39 @@ SYNC:
 				assertions.NotEmpty(config.Sync.History)
 			},
 		},
+		{
+			name: "success decrypting encrypted values",
+			args: args{
+				includeEnv: true,
+			},
+			requirements: func(t *testing.T, path string) {
+				encrypted, err := secrets.NewCipher(secrets.EncryptionOptions{Passphrase: "passphrase"}).Encrypt(EncryptionMethodPassphrase, "decrypted")
+				require.Nil(t, err)
+				err = os.WriteFile(path, []byte(strings.Replace(dummyConfig, "PASSWORD: password", "PASSWORD: "+encrypted, 1)), 0644)
+				require.Nil(t, err)
+				t.Setenv("ITS_SECRETS_PASSPHRASE", "passphrase")
+			},
+			assertions: func(assertions *assert.Assertions, config *Config, err error) {
+				assertions.Nil(err)
+				assertions.Equal("decrypted", *config.Trakt.Password)
+			},
+		},
+		{
+			name: "failure decrypting encrypted values without passphrase",
+			args: args{
+				includeEnv: true,
+			},
+			requirements: func(t *testing.T, path string) {
+				encrypted, err := secrets.NewCipher(secrets.EncryptionOptions{Passphrase: "passphrase"}).Encrypt(EncryptionMethodPassphrase, "decrypted")
+				require.Nil(t, err)
+				err = os.WriteFile(path, []byte(strings.Replace(dummyConfig, "PASSWORD: password", "PASSWORD: "+encrypted, 1)), 0644)
+				require.Nil(t, err)
+			},
+			assertions: func(assertions *assert.Assertions, config *Config, err error) {
+				assertions.ErrorContains(err, "TRAKT_PASSWORD")
+				assertions.Nil(config)
+			},
+		},
 		{
 			name: "invalid config file path",
 			args: args{
@@ -133,9 +172,21 @@ func TestConfig_Validate(t *testing.T) {
 	)
 
 	type fields struct {
-		IMDb  IMDb
-		Trakt Trakt
-		Sync  Sync
+		IMDb         IMDb
+		Trakt        Trakt
+		Simkl        Simkl
+		Jellyfin     Jellyfin
+		Kodi         Kodi
+		Plex         Plex
+		Anime        Anime
+		HTTP         HTTP
+		Sync         Sync
+		Log          Log
+		Notification Notification
+		Secrets      Secrets
+		Server       Server
+		State        State
+		Tracing      Tracing
 	}
 	tests := []struct {
 		name       string
@@ -152,6 +203,37 @@ func TestConfig_Validate(t *testing.T) {
 					Lists:    &lists,
 				},
 				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Nil(err)
+			},
+		},
+		{
+			name: "skip IMDb auth validation with IMDb.ExportDir",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:      pointer("invalid"),
+					ExportDir: pointer("exports"),
+					Lists:     &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
 					Email:        &email,
 					Password:     &password,
 					ClientID:     &clientID,
@@ -160,6 +242,13 @@ func TestConfig_Validate(t *testing.T) {
 				Sync: Sync{
 					Mode: pointer(SyncModeFull),
 				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
 			},
 			assertions: func(assertions *assert.Assertions, err error) {
 				assertions.Nil(err)
@@ -246,6 +335,106 @@ func TestConfig_Validate(t *testing.T) {
 				assertions.Contains(err.Error(), "IMDB_LISTS")
 			},
 		},
+		{
+			name: "invalid IMDb.Charts",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+					Charts:   pointer([]string{"bottom:IMDb Bottom 100"}),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "IMDB_CHARTS")
+			},
+		},
+		{
+			name: "invalid IMDb.Charts without trakt list name",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+					Charts:   pointer([]string{IMDbChartTop}),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "chart:trakt list name")
+			},
+		},
+		{
+			name: "skip Trakt credentials validation with Trakt.Auth device",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodDevice),
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Nil(err)
+			},
+		},
+		{
+			name: "invalid Trakt.Auth",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth: pointer("invalid"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "TRAKT_AUTH")
+			},
+		},
+		{
+			name: "invalid Trakt.TokenStorage",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodDevice),
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+					TokenStorage: pointer("vault"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "TRAKT_TOKENSTORAGE")
+			},
+		},
 		{
 			name: "missing Trakt.Email",
 			fields: fields{
@@ -256,6 +445,7 @@ func TestConfig_Validate(t *testing.T) {
 					Lists:    &lists,
 				},
 				Trakt: Trakt{
+					Auth:  pointer(TraktAuthMethodCredentials),
 					Email: nil,
 				},
 			},
@@ -274,6 +464,7 @@ func TestConfig_Validate(t *testing.T) {
 					Lists:    &lists,
 				},
 				Trakt: Trakt{
+					Auth:     pointer(TraktAuthMethodCredentials),
 					Email:    &email,
 					Password: nil,
 				},
@@ -293,6 +484,7 @@ func TestConfig_Validate(t *testing.T) {
 					Lists:    &lists,
 				},
 				Trakt: Trakt{
+					Auth:     pointer(TraktAuthMethodCredentials),
 					Email:    &email,
 					Password: &password,
 					ClientID: nil,
@@ -313,6 +505,7 @@ func TestConfig_Validate(t *testing.T) {
 					Lists:    &lists,
 				},
 				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
 					Email:        &email,
 					Password:     &password,
 					ClientID:     &clientID,
@@ -324,6 +517,31 @@ func TestConfig_Validate(t *testing.T) {
 				assertions.Contains(err.Error(), "TRAKT_CLIENTSECRET")
 			},
 		},
+		{
+			name: "missing Plex.Token",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Plex: Plex{
+					Enabled: pointer(true),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "PLEX_TOKEN")
+			},
+		},
 		{
 			name: "missing Sync.Mode",
 			fields: fields{
@@ -334,6 +552,7 @@ func TestConfig_Validate(t *testing.T) {
 					Lists:    &lists,
 				},
 				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
 					Email:        &email,
 					Password:     &password,
 					ClientID:     &clientID,
@@ -358,6 +577,7 @@ func TestConfig_Validate(t *testing.T) {
 					Lists:    &lists,
 				},
 				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
 					Email:        &email,
 					Password:     &password,
 					ClientID:     &clientID,
@@ -372,77 +592,2601 @@ func TestConfig_Validate(t *testing.T) {
 				assertions.Contains(err.Error(), "SYNC_MODE")
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			c := &Config{
-				koanf: koanf.New("_"),
-				IMDb:  tt.fields.IMDb,
-				Trakt: tt.fields.Trakt,
-				Sync:  tt.fields.Sync,
-			}
-			tt.assertions(assert.New(t), c.Validate())
-		})
-	}
-}
-
-func Test_environmentVariableModifier(t *testing.T) {
-	type args struct {
-		key   string
-		value string
-	}
-	tests := []struct {
-		name       string
-		args       args
-		assertions func(*assert.Assertions, string, any)
-	}{
 		{
-			name: "empty value",
-			args: args{
-				key:   "key",
-				value: "",
+			name: "invalid Sync.RatingsConflict",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:            pointer(SyncModeFull),
+					RatingsConflict: pointer("invalid"),
+				},
 			},
-			assertions: func(assertions *assert.Assertions, key string, value any) {
-				assertions.Equal("key", key)
-				assertions.Nil(value)
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_RATINGSCONFLICT")
 			},
 		},
 		{
-			name: "single value",
-			args: args{
-				key:   "key",
-				value: "value1",
+			name: "invalid Sync.ListLimit",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:      pointer(SyncModeFull),
+					ListLimit: pointer("invalid"),
+				},
 			},
-			assertions: func(assertions *assert.Assertions, key string, value any) {
-				assertions.Equal("key", key)
-				assertions.Equal("value1", value)
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_LISTLIMIT")
 			},
 		},
 		{
-			name: "multiple values",
-			args: args{
-				key:   "key",
-				value: "value1,value2",
+			name: "missing Anime.Username",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Anime: Anime{
+					Provider: pointer(AnimeProviderAniList),
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
 			},
-			assertions: func(assertions *assert.Assertions, key string, value any) {
-				assertions.Equal("key", key)
-				assertions.Equal([]string{
-					"value1",
-					"value2",
-				}, value)
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "ANIME_USERNAME")
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			key, value := environmentVariableModifier(tt.args.key, tt.args.value)
-			tt.assertions(assert.New(t), key, value)
-		})
-	}
-}
-
-func TestConfig_WriteFile(t *testing.T) {
-	type fields struct {
+		{
+			name: "negative Sync.HistoryWindow",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:          pointer(SyncModeFull),
+					HistoryWindow: pointer(-time.Hour),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_HISTORYWINDOW")
+			},
+		},
+		{
+			name: "Sync.MaxDuration exceeding Sync.Timeout",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:        pointer(SyncModeFull),
+					Timeout:     pointer(time.Minute * 15),
+					MaxDuration: pointer(time.Minute * 20),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_MAXDURATION")
+			},
+		},
+		{
+			name: "negative Sync.SkipListTTL",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:        pointer(SyncModeFull),
+					SkipListTTL: pointer(-time.Hour),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_SKIPLISTTTL")
+			},
+		},
+		{
+			name: "invalid Sync.Schedule",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:     pointer(SyncModeFull),
+					Schedule: pointer("every day"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_SCHEDULE")
+			},
+		},
+		{
+			name: "invalid IMDb.Lists all without IMDb.UserID",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:  pointer(IMDbAuthMethodNone),
+					Lists: &[]string{IMDbListsAll},
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "IMDB_USERID")
+			},
+		},
+		{
+			name: "invalid IMDb.UserID",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:   pointer(IMDbAuthMethodNone),
+					Lists:  &lists,
+					UserID: pointer("ls123456789"),
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "IMDB_USERID")
+			},
+		},
+		{
+			name: "invalid Sync.CollectionMedia",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:            pointer(SyncModeFull),
+					Collection:      pointer("ls123456789"),
+					CollectionMedia: pointer("betamax"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_COLLECTIONMEDIA")
+			},
+		},
+		{
+			name: "invalid Sync.Favorites",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:      pointer(SyncModeFull),
+					Favorites: pointer("favorites"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_FAVORITES")
+			},
+		},
+		{
+			name: "invalid Sync.RatingRounding",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:           pointer(SyncModeFull),
+					RatingRounding: pointer("nearest"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_RATINGROUNDING")
+			},
+		},
+		{
+			name: "invalid Sync.CheckinList",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:        pointer(SyncModeFull),
+					CheckinList: pointer("invalid"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_CHECKINLIST")
+			},
+		},
+		{
+			name: "invalid Sync.WatchedList with imdb auth none",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:  pointer(IMDbAuthMethodNone),
+					Lists: &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:        pointer(SyncModeFull),
+					WatchedList: pointer("ls123456789"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_WATCHEDLIST")
+			},
+		},
+		{
+			name: "invalid Sync.Recommendations",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:            pointer(SyncModeFull),
+					Recommendations: pointer("recommendations"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_RECOMMENDATIONS")
+			},
+		},
+		{
+			name: "invalid Sync.Hidden",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:   pointer(SyncModeFull),
+					Hidden: pointer("hidden"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_HIDDEN")
+			},
+		},
+		{
+			name: "invalid Sync.ReverseLists",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:         pointer(SyncModeFull),
+					ReverseLists: &[]string{"Watched List"},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_REVERSELISTS")
+			},
+		},
+		{
+			name: "invalid Sync.ReverseLists with imdb auth none",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:  pointer(IMDbAuthMethodNone),
+					Lists: &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:         pointer(SyncModeFull),
+					ReverseLists: &[]string{"watched-list"},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_REVERSELISTS")
+			},
+		},
+		{
+			name: "invalid IMDb.Backend graphql with credentials",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Backend:  pointer(IMDbBackendGraphQL),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "IMDB_BACKEND is graphql")
+			},
+		},
+		{
+			name: "invalid HTTP.CassetteMode with imdb browser backend",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				HTTP: HTTP{
+					Cassette:     pointer("cassette.json"),
+					CassetteMode: pointer(HTTPCassetteModeReplay),
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "HTTP_CASSETTEMODE")
+			},
+		},
+		{
+			name: "invalid HTTP.Proxy",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				HTTP: HTTP{
+					Proxy: pointer("ftp://127.0.0.1:21"),
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "HTTP_PROXY")
+			},
+		},
+		{
+			name: "invalid HTTP.Timeout",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				HTTP: HTTP{
+					Timeout: pointer(time.Duration(0)),
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "HTTP_TIMEOUT")
+			},
+		},
+		{
+			name: "invalid HTTP.Timeouts client",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				HTTP: HTTP{
+					Timeouts: &[]string{"letterboxd:10s"},
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "HTTP_TIMEOUTS")
+			},
+		},
+		{
+			name: "invalid HTTP.Timeouts duration",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				HTTP: HTTP{
+					Timeouts: &[]string{"imdb:0s"},
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "HTTP_TIMEOUTS")
+			},
+		},
+		{
+			name: "invalid HTTP.BaseURLs service",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				HTTP: HTTP{
+					BaseURLs: &[]string{"tmdb:https://api.themoviedb.org"},
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "HTTP_BASEURLS")
+			},
+		},
+		{
+			name: "invalid HTTP.BaseURLs url",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				HTTP: HTTP{
+					BaseURLs: &[]string{"trakt:localhost:8080"},
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "HTTP_BASEURLS")
+			},
+		},
+		{
+			name: "duplicate HTTP.BaseURLs service",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				HTTP: HTTP{
+					BaseURLs: &[]string{"trakt:http://localhost:8080", "trakt:http://localhost:8081"},
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "HTTP_BASEURLS")
+			},
+		},
+		{
+			name: "invalid HTTP.TLSMinVersion",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				HTTP: HTTP{
+					TLSMinVersion: pointer("1.1"),
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "HTTP_TLSMINVERSION")
+			},
+		},
+		{
+			name: "invalid Sync.Guardrail",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:      pointer(SyncModeFull),
+					Guardrail: pointer(101),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_GUARDRAIL")
+			},
+		},
+		{
+			name: "invalid Sync.RatingsMap",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:       pointer(SyncModeFull),
+					RatingsMap: &[]string{"1-4:skip", "7:11"},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_RATINGSMAP")
+			},
+		},
+		{
+			name: "invalid Sync.MediaTypes",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:       pointer(SyncModeFull),
+					MediaTypes: &[]string{"watchlist:movie", "ratings:season"},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_MEDIATYPES")
+			},
+		},
+		{
+			name: "invalid Sync.MediaTypes person outside lists",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:       pointer(SyncModeFull),
+					MediaTypes: &[]string{"lists:person", "watchlist:person"},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "only supported by lists")
+			},
+		},
+		{
+			name: "invalid Sync.Exclude",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:    pointer(SyncModeFull),
+					Exclude: &[]string{"genre:documentary", "year:1999-1960"},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_EXCLUDE")
+			},
+		},
+		{
+			name: "invalid Log.Level",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level: pointer("invalid"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "LOG_LEVEL")
+			},
+		},
+		{
+			name: "invalid Log.Format",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelDebug),
+					Format: pointer("invalid"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "LOG_FORMAT")
+			},
+		},
+		{
+			name: "invalid Notification.Provider",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				Notification: Notification{
+					Provider: pointer("invalid"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "NOTIFICATION_PROVIDER")
+			},
+		},
+		{
+			name: "missing Notification.URL",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				Notification: Notification{
+					Provider: pointer(NotificationProviderSlack),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "NOTIFICATION_URL")
+			},
+		},
+		{
+			name: "missing Server.Address",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				Server: Server{
+					Enabled: pointer(true),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SERVER_ADDRESS")
+			},
+		},
+		{
+			name: "invalid Server.MaxSyncAge",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				Server: Server{
+					Enabled:    pointer(true),
+					Address:    pointer(ServerAddressDefault),
+					MaxSyncAge: pointer(time.Duration(0)),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SERVER_MAXSYNCAGE")
+			},
+		},
+		{
+			name: "missing State.Dir",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "STATE_DIR")
+			},
+		},
+		{
+			name: "missing Notification.SMTPTo with smtp provider",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				Notification: Notification{
+					Provider: pointer(NotificationProviderSMTP),
+					SMTPHost: pointer("smtp.example.com"),
+					SMTPFrom: pointer("sync@example.com"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "NOTIFICATION_SMTPTO")
+			},
+		},
+		{
+			name: "invalid State.URL",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+					URL: pointer("ftp://bucket/state"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "STATE_URL")
+			},
+		},
+		{
+			name: "success with Sync.Destination simkl",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Simkl: Simkl{
+					ClientID: &clientID,
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationSimkl),
+					Mode:        pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Nil(err)
+			},
+		},
+		{
+			name: "success with Sync.Destination jellyfin",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Jellyfin: Jellyfin{
+					URL:      pointer("http://localhost:8096"),
+					APIKey:   pointer("apiKey"),
+					Username: pointer("alice"),
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationJellyfin),
+					Mode:        pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Nil(err)
+			},
+		},
+		{
+			name: "invalid Jellyfin.URL",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Jellyfin: Jellyfin{
+					URL:      pointer("localhost:8096"),
+					APIKey:   pointer("apiKey"),
+					Username: pointer("alice"),
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationJellyfin),
+					Mode:        pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "JELLYFIN_URL")
+			},
+		},
+		{
+			name: "Sync.Collection with Sync.Destination jellyfin",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Jellyfin: Jellyfin{
+					URL:      pointer("http://localhost:8096"),
+					APIKey:   pointer("apiKey"),
+					Username: pointer("alice"),
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationJellyfin),
+					Mode:        pointer(SyncModeFull),
+					Collection:  pointer("ls123456789"),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_COLLECTION")
+			},
+		},
+		{
+			name: "success with Sync.Destination kodi",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Kodi: Kodi{
+					URL:      pointer("http://localhost:8080"),
+					Username: pointer("kodi"),
+					Password: pointer("kodi"),
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationKodi),
+					Mode:        pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Nil(err)
+			},
+		},
+		{
+			name: "missing Kodi.URL",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Kodi: Kodi{
+					URL:      pointer(""),
+					Username: pointer(""),
+					Password: pointer(""),
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationKodi),
+					Mode:        pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "KODI_URL")
+			},
+		},
+		{
+			name: "Sync.Favorites with Sync.Destination kodi",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Kodi: Kodi{
+					URL:      pointer("http://localhost:8080"),
+					Username: pointer("kodi"),
+					Password: pointer("kodi"),
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationKodi),
+					Mode:        pointer(SyncModeFull),
+					Favorites:   pointer("ls123456789"),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_FAVORITES")
+			},
+		},
+		{
+			name: "success with Sync.Destinations",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Kodi: Kodi{
+					URL:      pointer("http://localhost:8080"),
+					Username: pointer(""),
+					Password: pointer(""),
+				},
+				Sync: Sync{
+					Destination:  pointer(SyncDestinationTrakt),
+					Destinations: &[]string{SyncDestinationKodi},
+					Mode:         pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Nil(err)
+			},
+		},
+		{
+			name: "invalid Sync.Destinations",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Kodi: Kodi{
+					URL:      pointer("http://localhost:8080"),
+					Username: pointer(""),
+					Password: pointer(""),
+				},
+				Sync: Sync{
+					Destination:  pointer(SyncDestinationTrakt),
+					Destinations: &[]string{"plex"},
+					Mode:         pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_DESTINATIONS")
+			},
+		},
+		{
+			name: "duplicate Sync.Destinations",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Kodi: Kodi{
+					URL:      pointer("http://localhost:8080"),
+					Username: pointer(""),
+					Password: pointer(""),
+				},
+				Sync: Sync{
+					Destination:  pointer(SyncDestinationTrakt),
+					Destinations: &[]string{SyncDestinationTrakt},
+					Mode:         pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "synced more than once")
+			},
+		},
+		{
+			name: "Sync.Destinations without the config of the destination",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Kodi: Kodi{
+					URL:      pointer("http://localhost:8080"),
+					Username: pointer(""),
+					Password: pointer(""),
+				},
+				Sync: Sync{
+					Destination:  pointer(SyncDestinationTrakt),
+					Destinations: &[]string{SyncDestinationJellyfin},
+					Mode:         pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "JELLYFIN_URL")
+			},
+		},
+		{
+			name: "missing Simkl.ClientID",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationSimkl),
+					Mode:        pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SIMKL_CLIENTID")
+			},
+		},
+		{
+			name: "Sync.MappingFile with Sync.Destination simkl",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Simkl: Simkl{
+					ClientID: &clientID,
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationSimkl),
+					Mode:        pointer(SyncModeFull),
+					MappingFile: pointer("mappings.yaml"),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_MAPPINGFILE")
+			},
+		},
+		{
+			name: "Sync.UpcomingList with Sync.Destination simkl",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Simkl: Simkl{
+					ClientID: &clientID,
+				},
+				Sync: Sync{
+					Destination:  pointer(SyncDestinationSimkl),
+					Mode:         pointer(SyncModeFull),
+					UpcomingList: pointer("Upcoming"),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_UPCOMINGLIST")
+			},
+		},
+		{
+			name: "invalid Sync.ItemNotes with Simkl",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Simkl: Simkl{
+					ClientID: &clientID,
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationSimkl),
+					Mode:        pointer(SyncModeFull),
+					ItemNotes:   pointer(true),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_ITEMNOTES")
+			},
+		},
+		{
+			name: "invalid Sync.FanOut",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Simkl: Simkl{
+					ClientID: &clientID,
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationSimkl),
+					Mode:        pointer(SyncModeFull),
+					FanOut:      &[]string{"ls000000000:Favorite Movies:films"},
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_FANOUT")
+			},
+		},
+		{
+			name: "invalid Sync.Precedence",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Simkl: Simkl{
+					ClientID: &clientID,
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationSimkl),
+					Mode:        pointer(SyncModeFull),
+					Precedence:  &[]string{"watchlist>watchlist"},
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "precedence rule must name two different lists")
+			},
+		},
+		{
+			name: "invalid Sync.LikedLists",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Simkl: Simkl{
+					ClientID: &clientID,
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationSimkl),
+					Mode:        pointer(SyncModeFull),
+					LikedLists:  &[]string{"imdb-top-rated-movies"},
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_LIKEDLISTS")
+			},
+		},
+		{
+			name: "invalid Tracing.SampleRatio",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Simkl: Simkl{
+					ClientID: &clientID,
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationSimkl),
+					Mode:        pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+				Tracing: Tracing{
+					SampleRatio: pointer(1.5),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "TRACING_SAMPLERATIO")
+			},
+		},
+		{
+			name: "Sync.Reviews with Sync.Destination simkl",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Simkl: Simkl{
+					ClientID: &clientID,
+				},
+				Sync: Sync{
+					Destination: pointer(SyncDestinationSimkl),
+					Mode:        pointer(SyncModeFull),
+					Reviews:     pointer(true),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_REVIEWS")
+			},
+		},
+		{
+			name: "Sync.SearchFallback with Sync.Destination simkl",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Simkl: Simkl{
+					ClientID: &clientID,
+				},
+				Sync: Sync{
+					Destination:    pointer(SyncDestinationSimkl),
+					Mode:           pointer(SyncModeFull),
+					SearchFallback: pointer(true),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_SEARCHFALLBACK")
+			},
+		},
+		{
+			name: "invalid Sync.Destination",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Simkl: Simkl{
+					ClientID: &clientID,
+				},
+				Sync: Sync{
+					Destination: pointer("invalid"),
+					Mode:        pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_DESTINATION")
+			},
+		},
+		{
+			name: "missing IMDb.Email with IMDb.CookieRefresh",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:           pointer(IMDbAuthMethodCookies),
+					CookieAtMain:   &cookieAtMain,
+					CookieUbidMain: &cookieAtMain,
+					CookieRefresh:  pointer(true),
+					Lists:          &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "IMDB_EMAIL")
+			},
+		},
+		{
+			name: "invalid IMDb.RetryMaxAttempts",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:             pointer(IMDbAuthMethodCredentials),
+					Email:            &email,
+					Password:         &password,
+					Lists:            &lists,
+					RetryMaxAttempts: pointer(0),
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "IMDB_RETRYMAXATTEMPTS")
+			},
+		},
+		{
+			name: "success with Sync.ListModes",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:      pointer(SyncModeFull),
+					ListModes: &[]string{"ls123456789:add-only", "ls987654321:disabled"},
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.Nil(err)
+			},
+		},
+		{
+			name: "invalid Sync.ListNameTemplate",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:             pointer(SyncModeFull),
+					ListNameTemplate: pointer("{{.ListName"),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_LISTNAMETEMPLATE")
+			},
+		},
+		{
+			name: "invalid Sync.ListModes format",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:      pointer(SyncModeFull),
+					ListModes: &[]string{"ls123456789"},
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_LISTMODES")
+			},
+		},
+		{
+			name: "invalid Sync.ListModes mode",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode:      pointer(SyncModeFull),
+					ListModes: &[]string{"ls123456789:dry-run"},
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SYNC_LISTMODES")
+			},
+		},
+		{
+			name: "invalid Trakt.ListPrivacy",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+					ListPrivacy:  pointer("hidden"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "TRAKT_LISTPRIVACY")
+			},
+		},
+		{
+			name: "invalid Trakt.ListPrivacies",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:          pointer(TraktAuthMethodCredentials),
+					Email:         &email,
+					Password:      &password,
+					ClientID:      &clientID,
+					ClientSecret:  &clientSecret,
+					ListPrivacies: &[]string{"my-list:hidden"},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "TRAKT_LISTPRIVACIES")
+			},
+		},
+		{
+			name: "invalid Trakt.ListSortHow",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+					ListSortHow:  pointer("random"),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "TRAKT_LISTSORTHOW")
+			},
+		},
+		{
+			name: "invalid Secrets.Provider",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				Secrets: Secrets{
+					Provider: pointer("unknown"),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SECRETS_PROVIDER")
+			},
+		},
+		{
+			name: "missing Secrets.SopsFile",
+			fields: fields{
+				IMDb: IMDb{
+					Auth:     pointer(IMDbAuthMethodCredentials),
+					Email:    &email,
+					Password: &password,
+					Lists:    &lists,
+				},
+				Trakt: Trakt{
+					Auth:         pointer(TraktAuthMethodCredentials),
+					Email:        &email,
+					Password:     &password,
+					ClientID:     &clientID,
+					ClientSecret: &clientSecret,
+				},
+				Sync: Sync{
+					Mode: pointer(SyncModeFull),
+				},
+				Log: Log{
+					Level:  pointer(LogLevelInfo),
+					Format: pointer(LogFormatJSON),
+				},
+				Secrets: Secrets{
+					Provider: pointer(SecretsProviderSops),
+				},
+				State: State{
+					Dir: pointer(StateDirDefault),
+				},
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NotNil(err)
+				assertions.Contains(err.Error(), "SECRETS_SOPSFILE")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{
+				koanf:        koanf.New("_"),
+				IMDb:         tt.fields.IMDb,
+				Trakt:        tt.fields.Trakt,
+				Simkl:        tt.fields.Simkl,
+				Jellyfin:     tt.fields.Jellyfin,
+				Kodi:         tt.fields.Kodi,
+				Plex:         tt.fields.Plex,
+				Anime:        tt.fields.Anime,
+				HTTP:         tt.fields.HTTP,
+				Sync:         tt.fields.Sync,
+				Log:          tt.fields.Log,
+				Notification: tt.fields.Notification,
+				Secrets:      tt.fields.Secrets,
+				Server:       tt.fields.Server,
+				State:        tt.fields.State,
+				Tracing:      tt.fields.Tracing,
+			}
+			tt.assertions(assert.New(t), c.Validate())
+		})
+	}
+}
+
+func TestSync_TraktListName(t *testing.T) {
+	type fields struct {
+		listNameTemplate  *string
+		listNameTemplates *[]string
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		assertions func(*assert.Assertions, string, error)
+	}{
+		{
+			name: "default template",
+			fields: fields{
+				listNameTemplate: pointer(ListNameTemplateDefault),
+			},
+			assertions: func(assertions *assert.Assertions, name string, err error) {
+				assertions.Nil(err)
+				assertions.Equal("Watched", name)
+			},
+		},
+		{
+			name: "global template",
+			fields: fields{
+				listNameTemplate: pointer("imdb-{{.ListName}}-{{.ListID}}"),
+			},
+			assertions: func(assertions *assert.Assertions, name string, err error) {
+				assertions.Nil(err)
+				assertions.Equal("imdb-Watched-ls123456789", name)
+			},
+		},
+		{
+			name: "list template takes precedence over global template",
+			fields: fields{
+				listNameTemplate: pointer("imdb-{{.ListName}}"),
+				listNameTemplates: &[]string{
+					"ls987654321:other-{{.ListName}}",
+					"ls123456789:{{.ListName}} {{.Year}}",
+				},
+			},
+			assertions: func(assertions *assert.Assertions, name string, err error) {
+				assertions.Nil(err)
+				assertions.Equal(fmt.Sprintf("Watched %d", time.Now().Year()), name)
+			},
+		},
+		{
+			name: "failure executing template with unknown field",
+			fields: fields{
+				listNameTemplate: pointer("{{.Unknown}}"),
+			},
+			assertions: func(assertions *assert.Assertions, name string, err error) {
+				assertions.NotNil(err)
+				assertions.Empty(name)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Sync{
+				ListNameTemplate:  tt.fields.listNameTemplate,
+				ListNameTemplates: tt.fields.listNameTemplates,
+			}
+			name, err := s.TraktListName("ls123456789", "Watched")
+			tt.assertions(assert.New(t), name, err)
+		})
+	}
+}
+
+func TestSync_ListMode(t *testing.T) {
+	listModes := []string{
+		"ls123456789:add-only",
+		"ls987654321:disabled",
+		"ls000000002:full",
+	}
+	type args struct {
+		mode     string
+		listID   string
+		safeMode bool
+	}
+	tests := []struct {
+		name     string
+		args     args
+		expected string
+	}{
+		{
+			name: "list without override",
+			args: args{
+				mode:   SyncModeFull,
+				listID: "ls000000001",
+			},
+			expected: SyncModeFull,
+		},
+		{
+			name: "list with override",
+			args: args{
+				mode:   SyncModeFull,
+				listID: "ls123456789",
+			},
+			expected: SyncModeAddOnly,
+		},
+		{
+			name: "dry run takes precedence over override",
+			args: args{
+				mode:   SyncModeDryRun,
+				listID: "ls123456789",
+			},
+			expected: SyncModeDryRun,
+		},
+		{
+			name: "safe mode downgrades full override",
+			args: args{
+				mode:     SyncModeAddOnly,
+				listID:   "ls000000002",
+				safeMode: true,
+			},
+			expected: SyncModeAddOnly,
+		},
+		{
+			name: "safe mode downgrades full mode",
+			args: args{
+				mode:     SyncModeFull,
+				listID:   "ls000000001",
+				safeMode: true,
+			},
+			expected: SyncModeAddOnly,
+		},
+		{
+			name: "disabled takes precedence over dry run",
+			args: args{
+				mode:   SyncModeDryRun,
+				listID: "ls987654321",
+			},
+			expected: ListModeDisabled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Sync{
+				Mode:      &tt.args.mode,
+				ListModes: &listModes,
+				SafeMode:  &tt.args.safeMode,
+			}
+			assert.Equal(t, tt.expected, s.ListMode(tt.args.listID))
+		})
+	}
+}
+
+func TestSync_MapRating(t *testing.T) {
+	ratingsMap := []string{
+		"1-4:skip",
+		"7:8",
+		"9-10:10",
+		"10:9",
+	}
+	tests := []struct {
+		name           string
+		rating         int
+		expectedRating int
+		expectedSync   bool
+	}{
+		{
+			name:           "rating without transformation",
+			rating:         5,
+			expectedRating: 5,
+			expectedSync:   true,
+		},
+		{
+			name:           "rating mapped to another rating",
+			rating:         7,
+			expectedRating: 8,
+			expectedSync:   true,
+		},
+		{
+			name:         "rating excluded by range",
+			rating:       3,
+			expectedSync: false,
+		},
+		{
+			name:           "last matching transformation wins",
+			rating:         10,
+			expectedRating: 9,
+			expectedSync:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Sync{
+				RatingsMap: &ratingsMap,
+			}
+			rating, sync := s.MapRating(tt.rating)
+			assert.Equal(t, tt.expectedSync, sync)
+			if tt.expectedSync {
+				assert.Equal(t, tt.expectedRating, rating)
+			}
+		})
+	}
+}
+
+func TestSync_PrecedenceRules(t *testing.T) {
+	s := &Sync{
+		Precedence: &[]string{"ls000000001>watchlist", "watchlist>ls000000002"},
+	}
+	expected := []PrecedenceRule{
+		{Winner: "ls000000001", Loser: ListWatchlist},
+		{Winner: ListWatchlist, Loser: "ls000000002"},
+	}
+	assert.Equal(t, expected, s.PrecedenceRules())
+	assert.Empty(t, (&Sync{}).PrecedenceRules())
+}
+
+func TestSync_FanOutRules(t *testing.T) {
+	s := &Sync{
+		FanOut: &[]string{
+			"ls000000001:Favorite Movies:movie",
+			"ls000000001:Favorite Comedies:genre:comedy",
+			"ls000000002:Other Shows:show",
+		},
+	}
+	expected := []FanOutRule{
+		{
+			ListID:   "ls000000001/favorite-movies",
+			ListName: "Favorite Movies",
+			Filter: entities.ItemFilter{
+				MediaTypes: []string{MediaTypeMovie},
+			},
+		},
+		{
+			ListID:   "ls000000001/favorite-comedies",
+			ListName: "Favorite Comedies",
+			Filter: entities.ItemFilter{
+				Include: []entities.FilterRule{
+					{Field: entities.FilterFieldGenre, Value: "comedy"},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, s.FanOutRules("ls000000001"))
+	assert.Empty(t, s.FanOutRules("ls000000003"))
+}
+
+func TestHTTP_ClientTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts *[]string
+		client   string
+		expected time.Duration
+	}{
+		{
+			name:     "global timeout",
+			timeouts: nil,
+			client:   HTTPClientIMDb,
+			expected: HTTPTimeoutDefault,
+		},
+		{
+			name:     "client override",
+			timeouts: &[]string{"trakt:10s", "imdb:2m"},
+			client:   HTTPClientIMDb,
+			expected: time.Minute * 2,
+		},
+		{
+			name:     "override of another client",
+			timeouts: &[]string{"trakt:10s"},
+			client:   HTTPClientPlex,
+			expected: HTTPTimeoutDefault,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &HTTP{
+				Timeout:  pointer(HTTPTimeoutDefault),
+				Timeouts: tt.timeouts,
+			}
+			assert.Equal(t, tt.expected, h.ClientTimeout(tt.client))
+		})
+	}
+}
+
+func TestHTTP_BaseURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseURLs *[]string
+		service  string
+		expected string
+		found    bool
+	}{
+		{
+			name:     "no overrides",
+			baseURLs: nil,
+			service:  HTTPBaseURLServiceTrakt,
+		},
+		{
+			name:     "service override",
+			baseURLs: &[]string{"simkl:https://simkl.mirror.example", "trakt:http://localhost:8080/trakt/"},
+			service:  HTTPBaseURLServiceTrakt,
+			expected: "http://localhost:8080/trakt",
+			found:    true,
+		},
+		{
+			name:     "override of another service",
+			baseURLs: &[]string{"simkl:https://simkl.mirror.example"},
+			service:  HTTPBaseURLServiceTrakt,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &HTTP{
+				BaseURLs: tt.baseURLs,
+			}
+			baseURL, found := h.BaseURL(tt.service)
+			assert.Equal(t, tt.expected, baseURL)
+			assert.Equal(t, tt.found, found)
+		})
+	}
+}
+
+func Test_environmentVariableModifier(t *testing.T) {
+	type args struct {
+		key   string
+		value string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, string, any)
+	}{
+		{
+			name: "empty value",
+			args: args{
+				key:   "key",
+				value: "",
+			},
+			assertions: func(assertions *assert.Assertions, key string, value any) {
+				assertions.Equal("key", key)
+				assertions.Nil(value)
+			},
+		},
+		{
+			name: "single value",
+			args: args{
+				key:   "key",
+				value: "value1",
+			},
+			assertions: func(assertions *assert.Assertions, key string, value any) {
+				assertions.Equal("key", key)
+				assertions.Equal("value1", value)
+			},
+		},
+		{
+			name: "multiple values",
+			args: args{
+				key:   "key",
+				value: "value1,value2",
+			},
+			assertions: func(assertions *assert.Assertions, key string, value any) {
+				assertions.Equal("key", key)
+				assertions.Equal([]string{
+					"value1",
+					"value2",
+				}, value)
+			},
+		},
+		{
+			name: "user agent with commas",
+			args: args{
+				key:   "ITS_IMDB_USERAGENT",
+				value: "Mozilla/5.0 (KHTML, like Gecko)",
+			},
+			assertions: func(assertions *assert.Assertions, key string, value any) {
+				assertions.Equal("IMDB_USERAGENT", key)
+				assertions.Equal("Mozilla/5.0 (KHTML, like Gecko)", value)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, value := environmentVariableModifier(tt.args.key, tt.args.value)
+			tt.assertions(assert.New(t), key, value)
+		})
+	}
+}
+
+func TestConfig_WriteFile(t *testing.T) {
+	type fields struct {
 		koanf *koanf.Koanf
 	}
 	type args struct {
@@ -526,3 +3270,172 @@ func TestNewFromMap(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_Profiles(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       map[string]interface{}
+		assertions func(*assert.Assertions, []*Config, error)
+	}{
+		{
+			name: "return top level config without profiles",
+			data: map[string]interface{}{
+				"IMDB": map[string]interface{}{
+					"EMAIL": "shared@example.com",
+				},
+			},
+			assertions: func(assertions *assert.Assertions, profiles []*Config, err error) {
+				assertions.NoError(err)
+				assertions.Len(profiles, 1)
+				assertions.Equal("", profiles[0].Profile())
+				assertions.Equal(StateDirDefault, *profiles[0].State.Dir)
+			},
+		},
+		{
+			name: "keep the state of each profile under the prefix of the state url",
+			data: map[string]interface{}{
+				"STATE": map[string]interface{}{
+					"URL": "s3://bucket/state?region=eu-west-1",
+				},
+				"PROFILES": map[string]interface{}{
+					"alice": map[string]interface{}{},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, profiles []*Config, err error) {
+				assertions.NoError(err)
+				assertions.Len(profiles, 1)
+				assertions.Equal("s3://bucket/state/alice?region=eu-west-1", *profiles[0].State.URL)
+			},
+		},
+		{
+			name: "override top level config with profiles",
+			data: map[string]interface{}{
+				"IMDB": map[string]interface{}{
+					"EMAIL": "shared@example.com",
+					"LISTS": []string{"ls123456789"},
+				},
+				"PROFILES": map[string]interface{}{
+					"alice": map[string]interface{}{
+						"IMDB": map[string]interface{}{
+							"EMAIL": "alice@example.com",
+						},
+					},
+					"bob": map[string]interface{}{
+						"STATE": map[string]interface{}{
+							"DIR": "/tmp/bob",
+						},
+					},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, profiles []*Config, err error) {
+				assertions.NoError(err)
+				assertions.Len(profiles, 2)
+				assertions.Equal("alice", profiles[0].Profile())
+				assertions.Equal("alice@example.com", *profiles[0].IMDb.Email)
+				assertions.Equal([]string{"ls123456789"}, *profiles[0].IMDb.Lists)
+				assertions.Equal(filepath.Join(StateDirDefault, "alice"), *profiles[0].State.Dir)
+				assertions.Equal(TraktKeyringServiceDefault+"-alice", *profiles[0].Trakt.KeyringService)
+				assertions.Equal("bob", profiles[1].Profile())
+				assertions.Equal("shared@example.com", *profiles[1].IMDb.Email)
+				assertions.Equal("/tmp/bob", *profiles[1].State.Dir)
+				assertions.NotContains(profiles[1].Flatten(), "PROFILES_alice_IMDB_EMAIL")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf, err := NewFromMap(tt.data)
+			require.NoError(t, err)
+			profiles, err := conf.Profiles()
+			tt.assertions(assert.New(t), profiles, err)
+		})
+	}
+}
+
+func TestConfig_Destinations(t *testing.T) {
+	conf, err := NewFromMap(map[string]interface{}{
+		"SYNC": map[string]interface{}{
+			"DESTINATIONS": []string{SyncDestinationJellyfin, SyncDestinationKodi},
+		},
+		"STATE": map[string]interface{}{
+			"URL": "s3://bucket/state?region=eu-west-1",
+		},
+	})
+	require.NoError(t, err)
+	destinations := conf.Destinations()
+	assert.Len(t, destinations, 3)
+	assert.Same(t, conf, destinations[0])
+	assert.Equal(t, SyncDestinationJellyfin, *destinations[1].Sync.Destination)
+	assert.Equal(t, filepath.Join(StateDirDefault, SyncDestinationJellyfin), *destinations[1].State.Dir)
+	assert.Equal(t, "s3://bucket/state/jellyfin?region=eu-west-1", *destinations[1].State.URL)
+	assert.Empty(t, *destinations[1].Sync.Destinations)
+	assert.Equal(t, SyncDestinationKodi, *destinations[2].Sync.Destination)
+	assert.Equal(t, SyncDestinationTrakt, *conf.Sync.Destination)
+	assert.Equal(t, StateDirDefault, *conf.State.Dir)
+}
+
+func TestConfig_DestinationConfig(t *testing.T) {
+	conf, err := NewFromMap(map[string]interface{}{
+		"SYNC": map[string]interface{}{
+			"DESTINATIONS": []string{SyncDestinationSimkl},
+		},
+	})
+	require.NoError(t, err)
+	trakt, err := conf.DestinationConfig(SyncDestinationTrakt)
+	require.NoError(t, err)
+	assert.Same(t, conf, trakt)
+	simkl, err := conf.DestinationConfig(SyncDestinationSimkl)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(StateDirDefault, SyncDestinationSimkl), *simkl.State.Dir)
+	_, err = conf.DestinationConfig(SyncDestinationKodi)
+	assert.ErrorContains(t, err, SyncDestinationKodi)
+}
+
+func TestConfig_EncryptSecrets(t *testing.T) {
+	t.Setenv("ITS_SECRETS_PASSPHRASE", "passphrase")
+	conf, err := NewFromMap(map[string]interface{}{
+		"IMDB_PASSWORD":              "password",
+		"IMDB_LISTS":                 []string{"ls000000000"},
+		"PROFILES_alice_TRAKT_EMAIL": "alice@domain.com",
+		"SECRETS_PASSPHRASE":         "",
+		"SYNC_MODE":                  SyncModeFull,
+		"TRAKT_CLIENTSECRET":         "",
+	})
+	require.NoError(t, err)
+	count, err := conf.EncryptSecrets(EncryptionMethodPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	flattened := conf.Flatten()
+	assert.True(t, secrets.IsEncrypted(flattened["IMDB_PASSWORD"].(string)))
+	assert.True(t, secrets.IsEncrypted(flattened["PROFILES_alice_TRAKT_EMAIL"].(string)))
+	assert.Equal(t, SyncModeFull, flattened["SYNC_MODE"])
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, conf.WriteFile(path))
+	decrypted, err := New(path, true)
+	require.NoError(t, err)
+	assert.Equal(t, "password", *decrypted.IMDb.Password)
+	count, err = decrypted.EncryptSecrets("rot13")
+	assert.ErrorContains(t, err, "encryption method must be one of")
+	assert.Zero(t, count)
+}
+
+func TestNewDefault(t *testing.T) {
+	conf, err := NewDefault()
+	require.NoError(t, err)
+	assertions := assert.New(t)
+	assertions.Equal(IMDbAuthMethodCookies, *conf.IMDb.Auth)
+	assertions.Equal(IMDbRetryBackoffDefault, *conf.IMDb.RetryBackoff)
+	assertions.Equal(SyncModeDryRun, *conf.Sync.Mode)
+	assertions.Equal(SyncTimeoutDefault, *conf.Sync.Timeout)
+	assertions.Equal("", *conf.Trakt.ListPrivacy)
+	assertions.Equal("", *conf.Trakt.ClientID)
+	path := fmt.Sprintf("%s/%s", t.TempDir(), "config.yaml")
+	require.NoError(t, conf.WriteFile(path))
+	written, err := New(path, false)
+	require.NoError(t, err)
+	assertions.Equal(*conf.Sync.Timeout, *written.Sync.Timeout)
+	assertions.Equal(*conf.Sync.ListNameTemplate, *written.Sync.ListNameTemplate)
+	assertions.Equal(*conf.Trakt.ListSortBy, *written.Trakt.ListSortBy)
+	assertions.Empty(*written.IMDb.Lists)
+	assertions.ErrorContains(written.Validate(), "IMDB_COOKIEATMAIN")
+}
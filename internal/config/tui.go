@@ -98,6 +98,13 @@ func (m *Model) updateConfigWithFieldInput(f *field) {
 			return
 		}
 		m.conf[f.name] = b
+	case reflect.Int:
+		i, err := strconv.Atoi(f.input.Value())
+		if err != nil {
+			m.err = fmt.Errorf("error parsing integer in field %q: %w", f.name, err)
+			return
+		}
+		m.conf[f.name] = i
 	case reflect.Slice:
 		m.conf[f.name] = strings.Split(f.input.Value(), ",")
 	default:
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	watchDebounce = time.Millisecond * 500
+	redacted      = "<redacted>"
+	unset         = "<unset>"
+)
+
+// Watch calls onChange whenever the config file at path changes, until the context is cancelled. The directory of the
+// file is watched rather than the file itself, since editors and kubernetes config maps replace the file instead of
+// writing to it. Events that follow each other closely are debounced, so that saving the file calls onChange once.
+func Watch(ctx context.Context, path string, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failure creating config watcher: %w", err)
+	}
+	path = filepath.Clean(path)
+	if err = watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failure watching config file %s: %w", path, err)
+	}
+	go func() {
+		defer watcher.Close()
+		var timer *time.Timer
+		for {
+			select {
+			case <-ctx.Done():
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				// config maps swap a symlink named ..data to replace every file of the directory at once
+				if filepath.Clean(event.Name) != path && !strings.HasPrefix(filepath.Base(event.Name), "..") {
+					continue
+				}
+				if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+					continue
+				}
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.AfterFunc(watchDebounce, onChange)
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Diff describes the fields whose values differ between the previous and the current config, sorted by field. The
+// values of sensitive fields are redacted.
+func Diff(previous, current *Config) []string {
+	before, after := previous.Flatten(), current.Flatten()
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, found := before[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	changes := make([]string, 0)
+	for _, key := range keys {
+		oldValue, oldFound := before[key]
+		newValue, newFound := after[key]
+		if oldFound && newFound && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		oldText, newText := unset, unset
+		if oldFound {
+			oldText = fmt.Sprint(oldValue)
+		}
+		if newFound {
+			newText = fmt.Sprint(newValue)
+		}
+		if isSecretKey(key) {
+			oldText, newText = redactValue(oldFound), redactValue(newFound)
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s => %s", key, oldText, newText))
+	}
+	return changes
+}
+
+func isSecretKey(key string) bool {
+	// the fields of profiles are prefixed with the name of the profile
+	if strings.HasPrefix(key, "SECRETS"+delimiter) || strings.Contains(key, delimiter+"SECRETS"+delimiter) {
+		return true
+	}
+	return slices.ContainsFunc(secretKeys(), func(secretKey string) bool {
+		return key == secretKey || strings.HasSuffix(key, delimiter+secretKey)
+	})
+}
+
+func redactValue(found bool) string {
+	if found {
+		return redacted
+	}
+	return unset
+}
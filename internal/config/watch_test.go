@@ -0,0 +1,65 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	previous, err := NewFromMap(map[string]interface{}{
+		"IMDB": map[string]interface{}{
+			"LISTS":    []string{"ls123456789"},
+			"PASSWORD": "old",
+		},
+		"SYNC": map[string]interface{}{
+			"MODE": SyncModeFull,
+		},
+	})
+	require.NoError(t, err)
+	current, err := NewFromMap(map[string]interface{}{
+		"IMDB": map[string]interface{}{
+			"LISTS":    []string{"ls123456789", "ls987654321"},
+			"PASSWORD": "new",
+		},
+		"SYNC": map[string]interface{}{
+			"MODE":     SyncModeFull,
+			"SCHEDULE": "0 */6 * * *",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"IMDB_LISTS: [ls123456789] => [ls123456789 ls987654321]",
+		"IMDB_PASSWORD: <redacted> => <redacted>",
+		"SYNC_SCHEDULE: <unset> => 0 */6 * * *",
+	}, Diff(previous, current))
+	assert.Empty(t, Diff(current, current))
+}
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("SYNC:\n  MODE: full\n"), 0644))
+	changed := make(chan struct{}, 1)
+	require.NoError(t, Watch(ctx, path, func() {
+		changed <- struct{}{}
+	}))
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(path), "other.yaml"), []byte("{}"), 0644))
+	require.NoError(t, os.WriteFile(path, []byte("SYNC:\n  MODE: add-only\n"), 0644))
+	select {
+	case <-changed:
+	case <-time.After(time.Second * 5):
+		t.Fatal("config change was not observed")
+	}
+	select {
+	case <-changed:
+		t.Fatal("config change was observed more than once")
+	case <-time.After(watchDebounce * 2):
+	}
+}
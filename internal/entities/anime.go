@@ -0,0 +1,180 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/rating"
+)
+
+const (
+	AnimeStatusCompleted = "completed"
+	AnimeStatusDropped   = "dropped"
+	AnimeStatusPaused    = "paused"
+	AnimeStatusPlanned   = "planned"
+	AnimeStatusWatching  = "watching"
+
+	animeMappingTypeMovie = "MOVIE"
+)
+
+// AnimeEntry is an entry of an anime list, normalised across anilist and myanimelist. Score ranges from 1 to 10, or
+// is 0 when the anime is not scored.
+type AnimeEntry struct {
+	AniListID   int
+	MALID       int
+	Status      string
+	Score       int
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// AnimeMapping is an entry of the anime-lists project, which links the ids of an anime across sites.
+type AnimeMapping struct {
+	AniListID int    `json:"anilist_id"`
+	MALID     int    `json:"mal_id"`
+	IMDbID    string `json:"imdb_id"`
+	Type      string `json:"type"`
+}
+
+// ToIMDbItem converts the entry to an imdb item of the mapped title. The item is rated when the entry is scored, and
+// created at the time the anime was completed, so that completed entries can be synced as check-ins.
+func (e *AnimeEntry) ToIMDbItem(mapping AnimeMapping) IMDbItem {
+	item := IMDbItem{
+		ID:   mapping.IMDbID,
+		Kind: imdbItemTypeTvSeries,
+	}
+	if mapping.Type == animeMappingTypeMovie {
+		item.Kind = imdbItemTypeMovie
+	}
+	if e.Score > 0 {
+		score, ratedAt := e.Score, e.UpdatedAt
+		item.Rating, item.RatingDate = &score, &ratedAt
+	}
+	if e.Status == AnimeStatusCompleted {
+		created := e.UpdatedAt
+		if e.CompletedAt != nil {
+			created = *e.CompletedAt
+		}
+		item.Created = &created
+	}
+	return item
+}
+
+type AniListResponse struct {
+	Data struct {
+		MediaListCollection struct {
+			Lists []struct {
+				Entries []AniListEntry `json:"entries"`
+			} `json:"lists"`
+		} `json:"MediaListCollection"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type AniListEntry struct {
+	Status      string      `json:"status"`
+	Score       float64     `json:"score"`
+	UpdatedAt   int64       `json:"updatedAt"`
+	CompletedAt AniListDate `json:"completedAt"`
+	Media       struct {
+		ID    int  `json:"id"`
+		IDMal *int `json:"idMal"`
+	} `json:"media"`
+}
+
+type AniListDate struct {
+	Year  *int `json:"year"`
+	Month *int `json:"month"`
+	Day   *int `json:"day"`
+}
+
+// Entries returns the entries of every list of the collection, including custom lists, which may repeat an anime.
+// Decimal scores are converted to whole points with the rounding policy.
+func (r *AniListResponse) Entries(rounding string) []AnimeEntry {
+	var entries []AnimeEntry
+	for _, list := range r.Data.MediaListCollection.Lists {
+		for _, entry := range list.Entries {
+			animeEntry := AnimeEntry{
+				AniListID: entry.Media.ID,
+				Status:    aniListStatus(entry.Status),
+				Score:     rating.ToPoints(entry.Score, rating.Points, rounding),
+				UpdatedAt: time.Unix(entry.UpdatedAt, 0).UTC(),
+			}
+			if entry.Media.IDMal != nil {
+				animeEntry.MALID = *entry.Media.IDMal
+			}
+			if date := entry.CompletedAt; date.Year != nil && date.Month != nil && date.Day != nil {
+				completedAt := time.Date(*date.Year, time.Month(*date.Month), *date.Day, 0, 0, 0, 0, time.UTC)
+				animeEntry.CompletedAt = &completedAt
+			}
+			entries = append(entries, animeEntry)
+		}
+	}
+	return entries
+}
+
+func aniListStatus(status string) string {
+	switch status {
+	case "CURRENT":
+		return AnimeStatusWatching
+	case "PLANNING":
+		return AnimeStatusPlanned
+	case "COMPLETED", "REPEATING":
+		return AnimeStatusCompleted
+	case "PAUSED":
+		return AnimeStatusPaused
+	default:
+		return AnimeStatusDropped
+	}
+}
+
+type MALAnimeListResponse struct {
+	Data []struct {
+		Node struct {
+			ID int `json:"id"`
+		} `json:"node"`
+		ListStatus struct {
+			Status     string    `json:"status"`
+			Score      int       `json:"score"`
+			UpdatedAt  time.Time `json:"updated_at"`
+			FinishDate string    `json:"finish_date"`
+		} `json:"list_status"`
+	} `json:"data"`
+	Paging struct {
+		Next string `json:"next"`
+	} `json:"paging"`
+}
+
+// Entries returns the entries of the page.
+func (r *MALAnimeListResponse) Entries() []AnimeEntry {
+	entries := make([]AnimeEntry, 0, len(r.Data))
+	for _, entry := range r.Data {
+		animeEntry := AnimeEntry{
+			MALID:     entry.Node.ID,
+			Status:    malStatus(entry.ListStatus.Status),
+			Score:     entry.ListStatus.Score,
+			UpdatedAt: entry.ListStatus.UpdatedAt,
+		}
+		if completedAt, err := time.Parse(time.DateOnly, entry.ListStatus.FinishDate); err == nil {
+			animeEntry.CompletedAt = &completedAt
+		}
+		entries = append(entries, animeEntry)
+	}
+	return entries
+}
+
+func malStatus(status string) string {
+	switch status {
+	case "watching":
+		return AnimeStatusWatching
+	case "plan_to_watch":
+		return AnimeStatusPlanned
+	case "completed":
+		return AnimeStatusCompleted
+	case "on_hold":
+		return AnimeStatusPaused
+	default:
+		return AnimeStatusDropped
+	}
+}
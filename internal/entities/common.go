@@ -1,11 +1,162 @@
 package entities
 
 import (
+	"fmt"
+	"math"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
-func ListDifference(imdbList IMDbList, traktList TraktList) map[string]TraktItems {
+const (
+	FilterFieldDirector   = "director"
+	FilterFieldGenre      = "genre"
+	FilterFieldIMDbRating = "imdbrating"
+	FilterFieldRuntime    = "runtime"
+	FilterFieldType       = "type"
+	FilterFieldVotes      = "votes"
+	FilterFieldYear       = "year"
+)
+
+// ItemFilter selects the items to be synced, where the zero value selects every item.
+type ItemFilter struct {
+	// MediaTypes holds the trakt types of the items to be synced, where no media types allow any type.
+	MediaTypes []string
+	// Exclude holds the rules matching the imdb items that are left out of the sync.
+	Exclude []FilterRule
+	// Include holds the rules that the imdb items must all match to be synced, where no rules allow any item.
+	Include []FilterRule
+}
+
+// Allows reports whether the imdb item is synced, based on both its media type and the exclusion rules.
+func (f ItemFilter) Allows(item IMDbItem) bool {
+	if !f.AllowsType(item.toTraktItem().Type) {
+		return false
+	}
+	for _, rule := range f.Exclude {
+		if rule.Matches(item) {
+			return false
+		}
+	}
+	for _, rule := range f.Include {
+		if !rule.Matches(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowsType reports whether items of the given trakt type are synced.
+func (f ItemFilter) AllowsType(itemType string) bool {
+	return len(f.MediaTypes) == 0 || slices.Contains(f.MediaTypes, itemType)
+}
+
+// FilterRule matches imdb items by one of the columns of imdb exports. Year, runtime, imdb rating and votes rules match
+// an inclusive range, where a missing bound leaves the range open, while genre, director and type rules match a value
+// case-insensitively.
+type FilterRule struct {
+	Field string
+	Min   *float64
+	Max   *float64
+	Value string
+}
+
+// ParseFilterRule parses a rule with format field:value, such as year:-1959, runtime:90-120, imdbrating:-5.5,
+// genre:documentary, director:michael bay or type:tv episode.
+func ParseFilterRule(entry string) (FilterRule, error) {
+	field, value, found := strings.Cut(entry, ":")
+	if !found || value == "" {
+		return FilterRule{}, fmt.Errorf("valid filter rule has format field:value, but got %s", entry)
+	}
+	rule := FilterRule{
+		Field: field,
+	}
+	switch field {
+	case FilterFieldDirector, FilterFieldGenre, FilterFieldType:
+		rule.Value = value
+	case FilterFieldIMDbRating, FilterFieldRuntime, FilterFieldVotes, FilterFieldYear:
+		low, high, isRange := strings.Cut(value, "-")
+		if !isRange {
+			high = low
+		}
+		if low == "" && high == "" {
+			return FilterRule{}, fmt.Errorf("range of filter rule %s must have at least one bound", entry)
+		}
+		var err error
+		if rule.Min, err = parseBound(low); err != nil {
+			return FilterRule{}, fmt.Errorf("invalid lower bound in %s: %w", entry, err)
+		}
+		if rule.Max, err = parseBound(high); err != nil {
+			return FilterRule{}, fmt.Errorf("invalid upper bound in %s: %w", entry, err)
+		}
+		if rule.Min != nil && rule.Max != nil && *rule.Max < *rule.Min {
+			return FilterRule{}, fmt.Errorf("invalid range in %s", entry)
+		}
+	default:
+		fields := []string{FilterFieldYear, FilterFieldRuntime, FilterFieldGenre, FilterFieldType, FilterFieldIMDbRating, FilterFieldVotes, FilterFieldDirector}
+		return FilterRule{}, fmt.Errorf("filter rule field must be one of: %s, but got %s", strings.Join(fields, ", "), field)
+	}
+	return rule, nil
+}
+
+func parseBound(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	bound, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &bound, nil
+}
+
+// Matches reports whether the rule applies to the imdb item. Items without a value for the field of the rule, such as
+// items that were not read from an imdb export, are never matched.
+func (r FilterRule) Matches(item IMDbItem) bool {
+	switch r.Field {
+	case FilterFieldYear:
+		return r.inRange(toFloat(item.Year))
+	case FilterFieldRuntime:
+		return r.inRange(toFloat(item.Runtime))
+	case FilterFieldIMDbRating:
+		return r.inRange(item.IMDbRating)
+	case FilterFieldVotes:
+		return r.inRange(toFloat(item.NumVotes))
+	case FilterFieldGenre:
+		return slices.ContainsFunc(item.Genres, func(genre string) bool {
+			return strings.EqualFold(genre, r.Value)
+		})
+	case FilterFieldDirector:
+		return slices.ContainsFunc(item.Directors, func(director string) bool {
+			return strings.EqualFold(director, r.Value)
+		})
+	case FilterFieldType:
+		kind := IMDbItem{Kind: r.Value}
+		return item.Kind != "" && item.kind() == kind.kind()
+	}
+	return false
+}
+
+func (r FilterRule) inRange(value *float64) bool {
+	if value == nil {
+		return false
+	}
+	return (r.Min == nil || *value >= *r.Min) && (r.Max == nil || *value <= *r.Max)
+}
+
+func toFloat(value *int) *float64 {
+	if value == nil {
+		return nil
+	}
+	number := float64(*value)
+	return &number
+}
+
+// ListDifference returns the items to be added to and removed from the trakt list. Only items selected by the filter
+// are compared.
+func ListDifference(imdbList IMDbList, traktList TraktList, filter ItemFilter) map[string]TraktItems {
 	imdbItems := make(map[string]IMDbItem)
 	for _, item := range imdbList.ListItems {
 		imdbItems[item.ID] = item
@@ -18,12 +169,17 @@ func ListDifference(imdbList IMDbList, traktList TraktList) map[string]TraktItem
 		}
 		traktItems[*id] = item
 	}
-	return ItemsDifference(imdbItems, traktItems)
+	return ItemsDifference(imdbItems, traktItems, filter)
 }
 
-func ItemsDifference(imdbItems map[string]IMDbItem, traktItems map[string]TraktItem) map[string]TraktItems {
+// ItemsDifference returns the items to be added to and removed from trakt. Items that are not selected by the filter
+// are left out on both sides, so that they are neither added to nor removed from trakt.
+func ItemsDifference(imdbItems map[string]IMDbItem, traktItems map[string]TraktItem, filter ItemFilter) map[string]TraktItems {
 	diff := make(map[string]TraktItems)
 	for id, imdbItem := range imdbItems {
+		if !filter.Allows(imdbItem) {
+			continue
+		}
 		traktItem := imdbItem.toTraktItem()
 		if _, found := traktItems[id]; !found {
 			diff["add"] = append(diff["add"], traktItem)
@@ -35,6 +191,9 @@ func ItemsDifference(imdbItems map[string]IMDbItem, traktItems map[string]TraktI
 		}
 	}
 	for id, traktItem := range traktItems {
+		if !filter.AllowsType(traktItem.Type) {
+			continue
+		}
 		if _, found := imdbItems[id]; !found {
 			diff["remove"] = append(diff["remove"], traktItem)
 		}
@@ -42,6 +201,52 @@ func ItemsDifference(imdbItems map[string]IMDbItem, traktItems map[string]TraktI
 	return diff
 }
 
+// ListRanking returns the ids of the trakt list items, ordered by the position of the matching items in the imdb list.
+// Items without a position keep their current relative order after the positioned ones. The boolean result reports
+// whether the ranking differs from the current order of the trakt list.
+func ListRanking(imdbList IMDbList, traktList TraktList) ([]int64, bool) {
+	positions := make(map[string]int, len(imdbList.ListItems))
+	for _, item := range imdbList.ListItems {
+		if item.Position != nil {
+			positions[item.ID] = *item.Position
+		}
+	}
+	position := func(item TraktItem) int {
+		id, err := item.GetItemID()
+		if err != nil || id == nil {
+			return math.MaxInt
+		}
+		if p, found := positions[*id]; found {
+			return p
+		}
+		return math.MaxInt
+	}
+	current := slices.Clone(traktList.ListItems)
+	slices.SortStableFunc(current, func(a, b TraktItem) int {
+		return a.Rank - b.Rank
+	})
+	ranked := slices.Clone(current)
+	slices.SortStableFunc(ranked, func(a, b TraktItem) int {
+		pa, pb := position(a), position(b)
+		switch {
+		case pa < pb:
+			return -1
+		case pa > pb:
+			return 1
+		}
+		return 0
+	})
+	rank := make([]int64, len(ranked))
+	changed := false
+	for i := range ranked {
+		rank[i] = ranked[i].ID
+		if ranked[i].ID != current[i].ID {
+			changed = true
+		}
+	}
+	return rank, changed
+}
+
 func InferTraktListSlug(imdbListName string) string {
 	result := strings.ToLower(strings.Join(strings.Fields(imdbListName), "-"))
 	regex := regexp.MustCompile(`[^-_a-z0-9]+`)
@@ -58,3 +263,17 @@ func removeDuplicateAdjacentCharacters(value string, target rune) string {
 	}
 	return sb.String()
 }
+
+// parseTimestamp parses a trakt timestamp, which the syncer formats either as rfc 3339 or as the default string format
+// of go, such as the watch time of a history item.
+func parseTimestamp(timestamp *string) (time.Time, bool) {
+	if timestamp == nil {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999 -0700 MST"} {
+		if t, err := time.Parse(layout, *timestamp); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
@@ -1,26 +1,103 @@
 package entities
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
 const (
-	imdbItemTypeMovie        = "Movie"
-	imdbItemTypeTvEpisode    = "TV Episode"
-	imdbItemTypeTvMiniSeries = "TV Mini Series"
-	imdbItemTypeTvSeries     = "TV Series"
-	imdbItemTypePerson       = "Person"
+	imdbItemTypeMovie          = "movie"
+	imdbItemTypeTvEpisode      = "tvepisode"
+	imdbItemTypeTvMiniSeries   = "tvminiseries"
+	imdbItemTypeTvSeries       = "tvseries"
+	imdbItemTypePerson         = "person"
+	imdbItemTypeMusicVideo     = "musicvideo"
+	imdbItemTypePodcastSeries  = "podcastseries"
+	imdbItemTypePodcastEpisode = "podcastepisode"
+	imdbItemTypeVideoGame      = "videogame"
+
+	imdbGraphQLTypenameName = "Name"
 )
 
+// IMDbItem is a title or person of an imdb list, ratings or check-ins. Only the id and kind are always known, while the
+// metadata such as the title, imdb rating or directors is only read from the columns of imdb csv exports.
 type IMDbItem struct {
-	ID         string
-	Kind       string
-	Rating     *int
-	RatingDate *time.Time
+	ID          string
+	Kind        string
+	Title       string
+	Position    *int
+	Rating      *int
+	RatingDate  *time.Time
+	Created     *time.Time
+	ReleaseDate *time.Time
+	Year        *int
+	Runtime     *int
+	Genres      []string
+	IMDbRating  *float64
+	NumVotes    *int
+	Directors   []string
+	// Description is the note that the owner of a list wrote about the item, which is empty for anything but lists.
+	Description string
+}
+
+// kind normalises the imdb title type, since exports use human readable values such as "TV Episode",
+// while the imdb website uses camel case values such as "tvEpisode".
+func (i *IMDbItem) kind() string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.ToLower(i.Kind))
+}
+
+// IsIMDbPersonID reports whether the imdb id refers to a person rather than a title.
+func IsIMDbPersonID(id string) bool {
+	return strings.HasPrefix(id, "nm")
+}
+
+// Supported reports whether the item has a counterpart on trakt. Lists can hold items that trakt knows nothing about,
+// such as video games, podcasts or images.
+func (i *IMDbItem) Supported() bool {
+	if IsIMDbPersonID(i.ID) {
+		return true
+	}
+	if !strings.HasPrefix(i.ID, "tt") {
+		return false
+	}
+	switch i.kind() {
+	case imdbItemTypeMusicVideo, imdbItemTypePodcastSeries, imdbItemTypePodcastEpisode, imdbItemTypeVideoGame:
+		return false
+	}
+	return true
+}
+
+// Upcoming reports whether the title has not been released yet, either because its release date is in the future, or
+// because it has no release date yet and is not known to be from an earlier year.
+func (i *IMDbItem) Upcoming(now time.Time) bool {
+	if IsIMDbPersonID(i.ID) {
+		return false
+	}
+	if i.ReleaseDate != nil {
+		return i.ReleaseDate.After(now)
+	}
+	return i.Year == nil || *i.Year >= now.Year()
+}
+
+// PartitionItems splits the items into the ones that can be synced to trakt, and the ones that are not supported.
+func PartitionItems(items []IMDbItem) ([]IMDbItem, []IMDbItem) {
+	supported := make([]IMDbItem, 0, len(items))
+	var unsupported []IMDbItem
+	for _, item := range items {
+		if item.Supported() {
+			supported = append(supported, item)
+			continue
+		}
+		unsupported = append(unsupported, item)
+	}
+	return supported, unsupported
 }
 
 func (i *IMDbItem) toTraktItem() TraktItem {
-	ti := TraktItem{}
+	ti := TraktItem{
+		Notes: i.Description,
+	}
 	tiSpec := TraktItemSpec{
 		IDMeta: TraktIDMeta{
 			IMDb: i.ID,
@@ -32,7 +109,7 @@ func (i *IMDbItem) toTraktItem() TraktItem {
 		tiSpec.WatchedAt = &ratedAt
 		tiSpec.Rating = i.Rating
 	}
-	switch i.Kind {
+	switch i.kind() {
 	case imdbItemTypeMovie:
 		ti.Type = TraktItemTypeMovie
 		ti.Movie = tiSpec
@@ -55,9 +132,413 @@ func (i *IMDbItem) toTraktItem() TraktItem {
 	return ti
 }
 
+// ToTraktHistoryItem converts a check-in to a trakt history item, using the time it was checked in as watch time.
+func (i *IMDbItem) ToTraktHistoryItem() TraktItem {
+	ti := i.toTraktItem()
+	if i.Created == nil {
+		return ti
+	}
+	watchedAt := i.Created.UTC().String()
+	switch ti.Type {
+	case TraktItemTypeMovie:
+		ti.Movie.WatchedAt = &watchedAt
+	case TraktItemTypeShow:
+		ti.Show.WatchedAt = &watchedAt
+	case TraktItemTypeEpisode:
+		ti.Episode.WatchedAt = &watchedAt
+	}
+	return ti
+}
+
+// ToTraktCollectionItem converts a list item to a trakt collection item, using the time it was added to the list as
+// collection time. An empty media type leaves the media type of the item unspecified.
+func (i *IMDbItem) ToTraktCollectionItem(mediaType string) TraktItem {
+	ti := i.toTraktItem()
+	spec := ti.Spec()
+	if spec == nil {
+		return ti
+	}
+	spec.Rating, spec.RatedAt, spec.WatchedAt = nil, nil, nil
+	if i.Created != nil {
+		collectedAt := i.Created.UTC().String()
+		spec.CollectedAt = &collectedAt
+	}
+	if mediaType != "" {
+		spec.MediaType = &mediaType
+	}
+	return ti
+}
+
+// ToTraktWatchedItem converts a list item to a trakt item that only identifies the title, in order to describe the
+// items that are removed from the imdb list mirroring the trakt history.
+func (i *IMDbItem) ToTraktWatchedItem() TraktItem {
+	ti := i.toTraktItem()
+	if spec := ti.Spec(); spec != nil {
+		spec.Rating, spec.RatedAt, spec.WatchedAt = nil, nil, nil
+	}
+	return ti
+}
+
+// IMDbRatingsPage is the subset of the data embedded in the imdb ratings page, which describes a page of rated titles.
+type IMDbRatingsPage struct {
+	Props struct {
+		PageProps struct {
+			MainColumnData struct {
+				AdvancedTitleSearch IMDbTitleSearch `json:"advancedTitleSearch"`
+			} `json:"mainColumnData"`
+		} `json:"pageProps"`
+	} `json:"props"`
+}
+
+// Items converts the rated titles of the page to imdb items.
+func (p *IMDbRatingsPage) Items() ([]IMDbItem, error) {
+	return p.Props.PageProps.MainColumnData.AdvancedTitleSearch.Items()
+}
+
+// NextPaginationKey returns the key of the next page, or an empty string when this is the last page.
+func (p *IMDbRatingsPage) NextPaginationKey() string {
+	return p.Props.PageProps.MainColumnData.AdvancedTitleSearch.PageInfo.next()
+}
+
+// IMDbPageInfo describes the position of a page within a cursor paginated imdb result.
+type IMDbPageInfo struct {
+	EndCursor   string `json:"endCursor"`
+	HasNextPage bool   `json:"hasNextPage"`
+}
+
+func (p IMDbPageInfo) next() string {
+	if !p.HasNextPage {
+		return ""
+	}
+	return p.EndCursor
+}
+
+// IMDbTitleSearch is a page of rated titles, as returned by the advanced title search of imdb.
+type IMDbTitleSearch struct {
+	PageInfo IMDbPageInfo `json:"pageInfo"`
+	Edges    []struct {
+		Node struct {
+			Title struct {
+				ID        string `json:"id"`
+				TitleType struct {
+					ID string `json:"id"`
+				} `json:"titleType"`
+				UserRating struct {
+					Value int    `json:"value"`
+					Date  string `json:"date"`
+				} `json:"userRating"`
+			} `json:"title"`
+		} `json:"node"`
+	} `json:"edges"`
+}
+
+// Items converts the rated titles of the search to imdb items.
+func (s *IMDbTitleSearch) Items() ([]IMDbItem, error) {
+	items := make([]IMDbItem, len(s.Edges))
+	for i, edge := range s.Edges {
+		title := edge.Node.Title
+		ratingDate, err := time.Parse(time.RFC3339, title.UserRating.Date)
+		if err != nil {
+			return nil, fmt.Errorf("failure parsing rating date of %s: %w", title.ID, err)
+		}
+		rating := title.UserRating.Value
+		items[i] = IMDbItem{
+			ID:         title.ID,
+			Kind:       title.TitleType.ID,
+			Rating:     &rating,
+			RatingDate: &ratingDate,
+		}
+	}
+	return items, nil
+}
+
+// NextCursor returns the cursor of the next page, or an empty string when this is the last page.
+func (s *IMDbTitleSearch) NextCursor() string {
+	return s.PageInfo.next()
+}
+
+// IMDbListPage is the subset of the data embedded in an imdb list page, which is not part of the list export.
+type IMDbListPage struct {
+	Props struct {
+		PageProps struct {
+			MainColumnData struct {
+				List struct {
+					ID               string `json:"id"`
+					LastModifiedDate string `json:"lastModifiedDate"`
+					Description      struct {
+						OriginalText struct {
+							PlainText string `json:"plainText"`
+						} `json:"originalText"`
+					} `json:"description"`
+				} `json:"list"`
+			} `json:"mainColumnData"`
+		} `json:"pageProps"`
+	} `json:"props"`
+}
+
+// Description returns the description of the list, or an empty string when the list has none.
+func (p *IMDbListPage) Description() string {
+	return strings.TrimSpace(p.Props.PageProps.MainColumnData.List.Description.OriginalText.PlainText)
+}
+
+// LastModified returns the time the list was last modified, or nil when the page does not include it.
+func (p *IMDbListPage) LastModified() (*time.Time, error) {
+	date := p.Props.PageProps.MainColumnData.List.LastModifiedDate
+	if date == "" {
+		return nil, nil
+	}
+	lastModified, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return nil, fmt.Errorf("failure parsing last modified date: %w", err)
+	}
+	return &lastModified, nil
+}
+
+// IMDbGraphQLRequest is the body of a request to the imdb graphql api.
+type IMDbGraphQLRequest struct {
+	OperationName string         `json:"operationName"`
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// IMDbGraphQLResponse is the envelope of a response of the imdb graphql api, which may hold partial data and errors.
+type IMDbGraphQLResponse[T any] struct {
+	Data   T `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Err joins the errors of the response, or returns nil when the response has none.
+func (r *IMDbGraphQLResponse[T]) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	messages := make([]string, len(r.Errors))
+	for i, e := range r.Errors {
+		messages[i] = e.Message
+	}
+	return fmt.Errorf("imdb graphql api returned errors: %s", strings.Join(messages, "; "))
+}
+
+// IMDbGraphQLPredefinedList is the graphql data of a list that every imdb user has, such as the watchlist.
+type IMDbGraphQLPredefinedList struct {
+	PredefinedList *IMDbPredefinedList `json:"predefinedList"`
+}
+
+// IMDbPredefinedList identifies a list that every imdb user has, and its owner.
+type IMDbPredefinedList struct {
+	ID     string `json:"id"`
+	Author struct {
+		UserID string `json:"userId"`
+	} `json:"author"`
+}
+
+// IMDbGraphQLList is the graphql data of a page of list items.
+type IMDbGraphQLList struct {
+	List *struct {
+		ID   string `json:"id"`
+		Name struct {
+			OriginalText string `json:"originalText"`
+		} `json:"name"`
+		Description struct {
+			OriginalText struct {
+				PlainText string `json:"plainText"`
+			} `json:"originalText"`
+		} `json:"description"`
+		LastModifiedDate    string `json:"lastModifiedDate"`
+		TitleListItemSearch struct {
+			PageInfo IMDbPageInfo `json:"pageInfo"`
+			Edges    []struct {
+				CreatedDate string `json:"createdDate"`
+				Description *struct {
+					OriginalText struct {
+						PlainText string `json:"plainText"`
+					} `json:"originalText"`
+				} `json:"description"`
+				ListItem struct {
+					Typename  string `json:"__typename"`
+					ID        string `json:"id"`
+					TitleType struct {
+						ID string `json:"id"`
+					} `json:"titleType"`
+					ReleaseDate *IMDbGraphQLDate `json:"releaseDate"`
+				} `json:"listItem"`
+			} `json:"edges"`
+		} `json:"titleListItemSearch"`
+	} `json:"list"`
+}
+
+// IMDbGraphQLDate is a release date, which is only partially known for some titles, such as the ones that are still in
+// production.
+type IMDbGraphQLDate struct {
+	Year  *int `json:"year"`
+	Month *int `json:"month"`
+	Day   *int `json:"day"`
+}
+
+// date returns the release date, or nil when the exact day is not known yet.
+func (d *IMDbGraphQLDate) date() *time.Time {
+	if d == nil || d.Year == nil || d.Month == nil || d.Day == nil {
+		return nil
+	}
+	date := time.Date(*d.Year, time.Month(*d.Month), *d.Day, 0, 0, 0, 0, time.UTC)
+	return &date
+}
+
+// Items converts the list items of the page to imdb items, numbering them from the given offset.
+func (l *IMDbGraphQLList) Items(offset int) ([]IMDbItem, error) {
+	edges := l.List.TitleListItemSearch.Edges
+	items := make([]IMDbItem, len(edges))
+	for i, edge := range edges {
+		position := offset + i + 1
+		kind := edge.ListItem.TitleType.ID
+		if edge.ListItem.Typename == imdbGraphQLTypenameName {
+			// people have no title type, hence why they are given the kind of people lists exports
+			kind = "Person"
+		}
+		items[i] = IMDbItem{
+			ID:          edge.ListItem.ID,
+			Kind:        kind,
+			Position:    &position,
+			ReleaseDate: edge.ListItem.ReleaseDate.date(),
+		}
+		if edge.Description != nil {
+			items[i].Description = strings.TrimSpace(edge.Description.OriginalText.PlainText)
+		}
+		if edge.CreatedDate == "" {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339, edge.CreatedDate)
+		if err != nil {
+			return nil, fmt.Errorf("failure parsing created date of %s: %w", edge.ListItem.ID, err)
+		}
+		items[i].Created = &created
+	}
+	return items, nil
+}
+
+// NextCursor returns the cursor of the next page, or an empty string when this is the last page.
+func (l *IMDbGraphQLList) NextCursor() string {
+	return l.List.TitleListItemSearch.PageInfo.next()
+}
+
+// LastModified returns the time the list was last modified, or nil when the data does not include it.
+func (l *IMDbGraphQLList) LastModified() (*time.Time, error) {
+	if l.List.LastModifiedDate == "" {
+		return nil, nil
+	}
+	lastModified, err := time.Parse(time.RFC3339, l.List.LastModifiedDate)
+	if err != nil {
+		return nil, fmt.Errorf("failure parsing last modified date: %w", err)
+	}
+	return &lastModified, nil
+}
+
+// IMDbGraphQLListCreate is the graphql data of a created list.
+type IMDbGraphQLListCreate struct {
+	CreateList *struct {
+		ListID string `json:"listId"`
+	} `json:"createList"`
+}
+
+// IMDbGraphQLRatings is the graphql data of a page of rated titles.
+type IMDbGraphQLRatings struct {
+	AdvancedTitleSearch IMDbTitleSearch `json:"advancedTitleSearch"`
+}
+
 type IMDbList struct {
 	ListID      string
 	ListName    string
+	Description string
 	ListItems   []IMDbItem
 	IsWatchlist bool
 }
+
+type IMDbReview struct {
+	ItemID string
+	// Kind is the title type of the reviewed item, which determines the commented media type.
+	Kind    string
+	Summary string
+	Text    string
+	Spoiler bool
+}
+
+// ToTraktComment converts a review to a trakt comment on the movie, show or episode that was reviewed.
+func (r *IMDbReview) ToTraktComment() TraktComment {
+	item := IMDbItem{
+		ID:   r.ItemID,
+		Kind: r.Kind,
+	}
+	ti := item.toTraktItem()
+	comment := TraktComment{
+		Comment: r.Text,
+		Spoiler: r.Spoiler,
+	}
+	if r.Summary != "" {
+		comment.Comment = fmt.Sprintf("**%s**\n\n%s", r.Summary, r.Text)
+	}
+	switch ti.Type {
+	case TraktItemTypeShow:
+		comment.Show = &ti.Show
+	case TraktItemTypeEpisode:
+		comment.Episode = &ti.Episode
+	default:
+		comment.Movie = &ti.Movie
+	}
+	return comment
+}
+
+// IMDbChartTitles is the ranking of an imdb chart, as embedded in chart pages and returned by the graphql api.
+type IMDbChartTitles struct {
+	Edges []struct {
+		Node struct {
+			ID        string `json:"id"`
+			TitleType struct {
+				ID string `json:"id"`
+			} `json:"titleType"`
+			TitleText struct {
+				Text string `json:"text"`
+			} `json:"titleText"`
+			ReleaseDate    *IMDbGraphQLDate `json:"releaseDate"`
+			RatingsSummary struct {
+				AggregateRating *float64 `json:"aggregateRating"`
+				VoteCount       *int     `json:"voteCount"`
+			} `json:"ratingsSummary"`
+		} `json:"node"`
+	} `json:"edges"`
+}
+
+// Items converts the ranked titles of the chart to imdb items, positioned by their rank.
+func (c *IMDbChartTitles) Items() []IMDbItem {
+	items := make([]IMDbItem, len(c.Edges))
+	for i, edge := range c.Edges {
+		position := i + 1
+		items[i] = IMDbItem{
+			ID:          edge.Node.ID,
+			Kind:        edge.Node.TitleType.ID,
+			Title:       edge.Node.TitleText.Text,
+			Position:    &position,
+			ReleaseDate: edge.Node.ReleaseDate.date(),
+			IMDbRating:  edge.Node.RatingsSummary.AggregateRating,
+			NumVotes:    edge.Node.RatingsSummary.VoteCount,
+		}
+	}
+	return items
+}
+
+// IMDbChartPage is the subset of the data embedded in an imdb chart page, which describes the ranked titles.
+type IMDbChartPage struct {
+	Props struct {
+		PageProps struct {
+			PageData struct {
+				ChartTitles IMDbChartTitles `json:"chartTitles"`
+			} `json:"pageData"`
+		} `json:"pageProps"`
+	} `json:"props"`
+}
+
+// IMDbGraphQLChart is the graphql data of a chart.
+type IMDbGraphQLChart struct {
+	ChartTitles *IMDbChartTitles `json:"chartTitles"`
+}
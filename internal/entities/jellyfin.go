@@ -0,0 +1,93 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/rating"
+)
+
+const (
+	JellyfinItemTypeEpisode = "Episode"
+	JellyfinItemTypeMovie   = "Movie"
+	JellyfinItemTypeSeries  = "Series"
+
+	jellyfinProviderIMDb = "imdb"
+)
+
+type JellyfinUser struct {
+	ID   string `json:"Id"`
+	Name string `json:"Name"`
+}
+
+type JellyfinUserData struct {
+	Rating         *float64 `json:"Rating,omitempty"`
+	Played         bool     `json:"Played"`
+	IsFavorite     bool     `json:"IsFavorite"`
+	LastPlayedDate string   `json:"LastPlayedDate,omitempty"`
+}
+
+type JellyfinRatingBody struct {
+	Rating float64 `json:"Rating"`
+}
+
+type JellyfinItem struct {
+	ID          string            `json:"Id"`
+	Name        string            `json:"Name"`
+	Type        string            `json:"Type"`
+	ProviderIDs map[string]string `json:"ProviderIds"`
+	UserData    JellyfinUserData  `json:"UserData"`
+}
+
+type JellyfinItems struct {
+	Items []JellyfinItem `json:"Items"`
+}
+
+// IMDbID returns the imdb provider id of the item, which jellyfin and emby spell in different cases.
+func (i *JellyfinItem) IMDbID() string {
+	for provider, id := range i.ProviderIDs {
+		if strings.ToLower(provider) == jellyfinProviderIMDb {
+			return id
+		}
+	}
+	return ""
+}
+
+// ToTraktItem converts a jellyfin library item to a trakt item, so that it can be diffed against imdb items. Jellyfin
+// stores decimal user ratings, which are converted to whole points with the rounding policy.
+func (i *JellyfinItem) ToTraktItem(rounding string) TraktItem {
+	ti := TraktItem{
+		WatchedAt: i.UserData.LastPlayedDate,
+	}
+	if i.UserData.Rating != nil {
+		ti.Rating = rating.ToPoints(*i.UserData.Rating, rating.Points, rounding)
+	}
+	spec := TraktItemSpec{
+		IDMeta: TraktIDMeta{
+			IMDb: i.IMDbID(),
+		},
+		Title: i.Name,
+	}
+	switch i.Type {
+	case JellyfinItemTypeSeries:
+		ti.Type = TraktItemTypeShow
+		ti.Show = spec
+	case JellyfinItemTypeEpisode:
+		ti.Type = TraktItemTypeEpisode
+		ti.Episode = spec
+	default:
+		ti.Type = TraktItemTypeMovie
+		ti.Movie = spec
+	}
+	return ti
+}
+
+// JellyfinDate converts a trakt timestamp to the format that jellyfin expects. It returns an empty string when the
+// timestamp can't be parsed.
+func JellyfinDate(timestamp *string) string {
+	t, ok := parseTimestamp(timestamp)
+	if !ok {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
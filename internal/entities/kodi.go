@@ -0,0 +1,118 @@
+package entities
+
+import (
+	"fmt"
+)
+
+const (
+	KodiItemTypeEpisode = "episode"
+	KodiItemTypeMovie   = "movie"
+	KodiItemTypeTVShow  = "tvshow"
+
+	kodiDateTimeLayout = "2006-01-02 15:04:05"
+	kodiUniqueIDIMDb   = "imdb"
+)
+
+type KodiRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int64  `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type KodiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *KodiError) Error() string {
+	return fmt.Sprintf("kodi returned error %d: %s", e.Code, e.Message)
+}
+
+type KodiResponse[T any] struct {
+	Result T          `json:"result"`
+	Error  *KodiError `json:"error"`
+}
+
+type KodiItem struct {
+	MovieID    int               `json:"movieid,omitempty"`
+	TVShowID   int               `json:"tvshowid,omitempty"`
+	EpisodeID  int               `json:"episodeid,omitempty"`
+	Label      string            `json:"label"`
+	UniqueID   map[string]string `json:"uniqueid"`
+	PlayCount  int               `json:"playcount"`
+	UserRating int               `json:"userrating"`
+	LastPlayed string            `json:"lastplayed"`
+}
+
+type KodiLibrary struct {
+	Movies   []KodiItem `json:"movies"`
+	TVShows  []KodiItem `json:"tvshows"`
+	Episodes []KodiItem `json:"episodes"`
+}
+
+// Type returns the type of the item, which kodi only reveals through the kind of id that the item has.
+func (i *KodiItem) Type() string {
+	switch {
+	case i.MovieID != 0:
+		return KodiItemTypeMovie
+	case i.TVShowID != 0:
+		return KodiItemTypeTVShow
+	default:
+		return KodiItemTypeEpisode
+	}
+}
+
+// ID returns the library id of the item, along with the name of the parameter that identifies it in kodi requests.
+func (i *KodiItem) ID() (string, int) {
+	switch i.Type() {
+	case KodiItemTypeMovie:
+		return "movieid", i.MovieID
+	case KodiItemTypeTVShow:
+		return "tvshowid", i.TVShowID
+	default:
+		return "episodeid", i.EpisodeID
+	}
+}
+
+// IMDbID returns the imdb unique id of the item, which is empty when the scraper of the library did not provide one.
+func (i *KodiItem) IMDbID() string {
+	return i.UniqueID[kodiUniqueIDIMDb]
+}
+
+// ToTraktItem converts a kodi library item to a trakt item, so that it can be diffed against imdb items. Kodi rates on
+// the 10-point scale of imdb and trakt, where 0 means unrated.
+func (i *KodiItem) ToTraktItem() TraktItem {
+	ti := TraktItem{
+		Rating:    i.UserRating,
+		WatchedAt: i.LastPlayed,
+	}
+	spec := TraktItemSpec{
+		IDMeta: TraktIDMeta{
+			IMDb: i.IMDbID(),
+		},
+		Title: i.Label,
+	}
+	switch i.Type() {
+	case KodiItemTypeTVShow:
+		ti.Type = TraktItemTypeShow
+		ti.Show = spec
+	case KodiItemTypeEpisode:
+		ti.Type = TraktItemTypeEpisode
+		ti.Episode = spec
+	default:
+		ti.Type = TraktItemTypeMovie
+		ti.Movie = spec
+	}
+	return ti
+}
+
+// KodiDateTime converts a trakt timestamp to the format that kodi expects. It returns an empty string when the
+// timestamp can't be parsed.
+func KodiDateTime(timestamp *string) string {
+	t, ok := parseTimestamp(timestamp)
+	if !ok {
+		return ""
+	}
+	return t.Format(kodiDateTimeLayout)
+}
@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"strings"
+)
+
+const (
+	PlexItemTypeMovie = "movie"
+	PlexItemTypeShow  = "show"
+
+	plexGUIDPrefixIMDb = "imdb://"
+)
+
+type PlexGUID struct {
+	ID string `json:"id"`
+}
+
+type PlexItem struct {
+	RatingKey string     `json:"ratingKey"`
+	GUID      string     `json:"guid"`
+	Type      string     `json:"type"`
+	Title     string     `json:"title"`
+	GUIDs     []PlexGUID `json:"Guid"`
+}
+
+// IMDbID returns the imdb id of the item from its external guids, if present.
+func (i *PlexItem) IMDbID() string {
+	for _, guid := range i.GUIDs {
+		if id, ok := strings.CutPrefix(guid.ID, plexGUIDPrefixIMDb); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+type PlexMediaContainer struct {
+	Offset    int        `json:"offset"`
+	Size      int        `json:"size"`
+	TotalSize int        `json:"totalSize"`
+	Metadata  []PlexItem `json:"Metadata"`
+}
+
+type PlexResponse struct {
+	MediaContainer PlexMediaContainer `json:"MediaContainer"`
+}
+
+// ToPlexItemType maps an imdb item to the plex item type, or an empty string when plex does not support the item.
+func (i *IMDbItem) ToPlexItemType() string {
+	switch i.kind() {
+	case imdbItemTypeMovie:
+		return PlexItemTypeMovie
+	case imdbItemTypeTvSeries, imdbItemTypeTvMiniSeries:
+		return PlexItemTypeShow
+	default:
+		return ""
+	}
+}
+
+func PlexGUIDFromIMDbID(id string) string {
+	return plexGUIDPrefixIMDb + id
+}
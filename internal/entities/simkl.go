@@ -0,0 +1,118 @@
+package entities
+
+const (
+	SimklStatusCompleted   = "completed"
+	SimklStatusPlanToWatch = "plantowatch"
+)
+
+type SimklPinResponse struct {
+	Result          string `json:"result"`
+	UserCode        string `json:"user_code"`
+	VerificationURL string `json:"verification_url"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+type SimklTokenResponse struct {
+	Result      string `json:"result"`
+	AccessToken string `json:"access_token"`
+}
+
+type SimklIDs struct {
+	IMDb string `json:"imdb,omitempty"`
+}
+
+type SimklMedia struct {
+	Title string   `json:"title,omitempty"`
+	IDs   SimklIDs `json:"ids"`
+}
+
+type SimklAllItem struct {
+	Status      string      `json:"status"`
+	UserRating  *int        `json:"user_rating"`
+	UserRatedAt string      `json:"user_rated_at"`
+	LastWatched string      `json:"last_watched_at"`
+	Movie       *SimklMedia `json:"movie,omitempty"`
+	Show        *SimklMedia `json:"show,omitempty"`
+}
+
+// ToTraktItem converts a simkl library item to a trakt item, so that it can be diffed against imdb items.
+func (i *SimklAllItem) ToTraktItem() TraktItem {
+	ti := TraktItem{}
+	if i.UserRating != nil {
+		ti.Rating = *i.UserRating
+		ti.RatedAt = i.UserRatedAt
+	}
+	if i.Movie != nil {
+		ti.Type = TraktItemTypeMovie
+		ti.Movie = TraktItemSpec{IDMeta: TraktIDMeta{IMDb: i.Movie.IDs.IMDb}}
+		return ti
+	}
+	ti.Type = TraktItemTypeShow
+	if i.Show != nil {
+		ti.Show = TraktItemSpec{IDMeta: TraktIDMeta{IMDb: i.Show.IDs.IMDb}}
+	}
+	return ti
+}
+
+type SimklAllItems struct {
+	Movies []SimklAllItem `json:"movies"`
+	Shows  []SimklAllItem `json:"shows"`
+	Anime  []SimklAllItem `json:"anime"`
+}
+
+// Filter returns the library items that satisfy the predicate as trakt items.
+func (items *SimklAllItems) Filter(predicate func(SimklAllItem) bool) TraktItems {
+	var res TraktItems
+	for _, group := range [][]SimklAllItem{items.Movies, items.Shows, items.Anime} {
+		for _, item := range group {
+			if predicate(item) {
+				res = append(res, item.ToTraktItem())
+			}
+		}
+	}
+	return res
+}
+
+type SimklItemSpec struct {
+	IDs       SimklIDs `json:"ids"`
+	To        string   `json:"to,omitempty"`
+	Rating    *int     `json:"rating,omitempty"`
+	RatedAt   *string  `json:"rated_at,omitempty"`
+	WatchedAt *string  `json:"watched_at,omitempty"`
+}
+
+type SimklBody struct {
+	Movies []SimklItemSpec `json:"movies,omitempty"`
+	Shows  []SimklItemSpec `json:"shows,omitempty"`
+}
+
+// NewSimklBody maps trakt items to a simkl request body, skipping episodes and people that simkl does not support.
+// The to argument moves the items to the respective simkl list, when not empty.
+func NewSimklBody(items TraktItems, to string) SimklBody {
+	body := SimklBody{}
+	for _, item := range items {
+		var spec TraktItemSpec
+		switch item.Type {
+		case TraktItemTypeMovie:
+			spec = item.Movie
+		case TraktItemTypeShow:
+			spec = item.Show
+		default:
+			continue
+		}
+		simklSpec := SimklItemSpec{
+			IDs:       SimklIDs{IMDb: spec.IDMeta.IMDb},
+			To:        to,
+			Rating:    spec.Rating,
+			RatedAt:   spec.RatedAt,
+			WatchedAt: spec.WatchedAt,
+		}
+		if item.Type == TraktItemTypeMovie {
+			body.Movies = append(body.Movies, simklSpec)
+			continue
+		}
+		body.Shows = append(body.Shows, simklSpec)
+	}
+	return body
+}
@@ -2,6 +2,7 @@ package entities
 
 import (
 	"fmt"
+	"time"
 )
 
 const (
@@ -10,6 +11,9 @@ const (
 	TraktItemTypeSeason  = "season"
 	TraktItemTypeShow    = "show"
 	TraktItemTypePerson  = "person"
+
+	TraktHiddenSectionCalendar        = "calendar"
+	TraktHiddenSectionRecommendations = "recommendations"
 )
 
 type TraktAuthCodesBody struct {
@@ -17,8 +21,11 @@ type TraktAuthCodesBody struct {
 }
 
 type TraktAuthCodesResponse struct {
-	DeviceCode string `json:"device_code"`
-	UserCode   string `json:"user_code"`
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURL string `json:"verification_url"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
 }
 
 type TraktAuthTokensBody struct {
@@ -28,11 +35,28 @@ type TraktAuthTokensBody struct {
 }
 
 type TraktAuthTokensResponse struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	CreatedAt    int64  `json:"created_at"`
+}
+
+func (r *TraktAuthTokensResponse) ExpiresAt() time.Time {
+	return time.Unix(r.CreatedAt+r.ExpiresIn, 0)
+}
+
+type TraktRefreshTokenBody struct {
+	RefreshToken string `json:"refresh_token"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RedirectURI  string `json:"redirect_uri"`
+	GrantType    string `json:"grant_type"`
 }
 
 type TraktIDMeta struct {
+	Trakt    int64   `json:"trakt,omitempty"`
 	IMDb     string  `json:"imdb,omitempty"`
+	TMDB     int64   `json:"tmdb,omitempty"`
 	Slug     string  `json:"slug,omitempty"`
 	ListName *string `json:"-"`
 }
@@ -49,22 +73,35 @@ func (tidm TraktIDMetas) GetListNameFromSlug(slug string) string {
 }
 
 type TraktItemSpec struct {
-	IDMeta    TraktIDMeta `json:"ids"`
-	RatedAt   *string     `json:"rated_at,omitempty"`
-	Rating    *int        `json:"rating,omitempty"`
-	WatchedAt *string     `json:"watched_at,omitempty"`
+	IDMeta      TraktIDMeta   `json:"ids"`
+	Title       string        `json:"title,omitempty"`
+	Year        int           `json:"year,omitempty"`
+	RatedAt     *string       `json:"rated_at,omitempty"`
+	Rating      *int          `json:"rating,omitempty"`
+	WatchedAt   *string       `json:"watched_at,omitempty"`
+	CollectedAt *string       `json:"collected_at,omitempty"`
+	MediaType   *string       `json:"media_type,omitempty"`
+	Notes       *string       `json:"notes,omitempty"`
+	Seasons     []TraktSeason `json:"seasons,omitempty"`
 }
 
 type TraktItemSpecs []TraktItemSpec
 
 type TraktItem struct {
-	Type    string        `json:"type"`
-	RatedAt string        `json:"rated_at,omitempty"`
-	Rating  int           `json:"rating,omitempty"`
-	Movie   TraktItemSpec `json:"movie,omitempty"`
-	Show    TraktItemSpec `json:"show,omitempty"`
-	Episode TraktItemSpec `json:"episode,omitempty"`
-	Person  TraktItemSpec `json:"person,omitempty"`
+	ID          int64         `json:"id,omitempty"`
+	Rank        int           `json:"rank,omitempty"`
+	Type        string        `json:"type"`
+	ListedAt    string        `json:"listed_at,omitempty"`
+	RatedAt     string        `json:"rated_at,omitempty"`
+	Rating      int           `json:"rating,omitempty"`
+	WatchedAt   string        `json:"watched_at,omitempty"`
+	CollectedAt string        `json:"collected_at,omitempty"`
+	Notes       string        `json:"notes,omitempty"`
+	Seasons     []TraktSeason `json:"seasons,omitempty"`
+	Movie       TraktItemSpec `json:"movie,omitempty"`
+	Show        TraktItemSpec `json:"show,omitempty"`
+	Episode     TraktItemSpec `json:"episode,omitempty"`
+	Person      TraktItemSpec `json:"person,omitempty"`
 }
 
 type TraktItems []TraktItem
@@ -86,6 +123,88 @@ func (item *TraktItem) GetItemID() (*string, error) {
 	}
 }
 
+// Spec returns the specification of the movie, show, episode or person that the item refers to.
+func (item *TraktItem) Spec() *TraktItemSpec {
+	switch item.Type {
+	case TraktItemTypeMovie:
+		return &item.Movie
+	case TraktItemTypeShow:
+		return &item.Show
+	case TraktItemTypeEpisode:
+		return &item.Episode
+	case TraktItemTypePerson:
+		return &item.Person
+	default:
+		return nil
+	}
+}
+
+// MapSpecs returns a copy of the items, after fn has moved item level fields such as the rating into the item
+// specification, since that is where trakt expects them when adding items. Items without a specification are dropped.
+func (items TraktItems) MapSpecs(fn func(item TraktItem, spec *TraktItemSpec)) TraktItems {
+	result := make(TraktItems, 0, len(items))
+	for _, item := range items {
+		spec := item.Spec()
+		if spec == nil {
+			continue
+		}
+		fn(item, spec)
+		result = append(result, item)
+	}
+	return result
+}
+
+// WatchedWithin reports whether the history has a play within the window of the given watch time. Without a window or
+// a watch time, any play counts, and so do the plays whose watch time cannot be parsed, so that a play is never added
+// twice because of a timestamp the syncer does not understand.
+func (items TraktItems) WatchedWithin(watchedAt *string, window time.Duration) bool {
+	if len(items) == 0 {
+		return false
+	}
+	t, ok := parseTimestamp(watchedAt)
+	if window <= 0 || !ok {
+		return true
+	}
+	for _, item := range items {
+		played, ok := parseTimestamp(&item.WatchedAt)
+		if !ok || played.Sub(t).Abs() <= window {
+			return true
+		}
+	}
+	return false
+}
+
+// TraktSearchResult is a title that the text search of trakt matched, along with the relevance of the match.
+type TraktSearchResult struct {
+	Type  string        `json:"type"`
+	Score float64       `json:"score"`
+	Movie TraktItemSpec `json:"movie,omitempty"`
+	Show  TraktItemSpec `json:"show,omitempty"`
+}
+
+// Spec returns the specification of the movie or show that the search matched.
+func (r *TraktSearchResult) Spec() *TraktItemSpec {
+	switch r.Type {
+	case TraktItemTypeMovie:
+		return &r.Movie
+	case TraktItemTypeShow:
+		return &r.Show
+	default:
+		return nil
+	}
+}
+
+// TraktSeason holds the collected episodes of a show season.
+type TraktSeason struct {
+	Number   int            `json:"number"`
+	Episodes []TraktEpisode `json:"episodes,omitempty"`
+}
+
+type TraktEpisode struct {
+	Number      int    `json:"number"`
+	CollectedAt string `json:"collected_at,omitempty"`
+}
+
 type TraktListBody struct {
 	Movies   TraktItemSpecs `json:"movies,omitempty"`
 	Shows    TraktItemSpecs `json:"shows,omitempty"`
@@ -93,10 +212,37 @@ type TraktListBody struct {
 	People   TraktItemSpecs `json:"people,omitempty"`
 }
 
+// Items returns the movies, shows, episodes and people of the body as trakt items.
+func (b *TraktListBody) Items() TraktItems {
+	items := make(TraktItems, 0, len(b.Movies)+len(b.Shows)+len(b.Episodes)+len(b.People))
+	for _, spec := range b.Movies {
+		items = append(items, TraktItem{Type: TraktItemTypeMovie, Movie: spec})
+	}
+	for _, spec := range b.Shows {
+		items = append(items, TraktItem{Type: TraktItemTypeShow, Show: spec})
+	}
+	for _, spec := range b.Episodes {
+		items = append(items, TraktItem{Type: TraktItemTypeEpisode, Episode: spec})
+	}
+	for _, spec := range b.People {
+		items = append(items, TraktItem{Type: TraktItemTypePerson, Person: spec})
+	}
+	return items
+}
+
 type TraktListAddBody struct {
-	Name           string `json:"name"`
-	Description    string `json:"description"`
-	Privacy        string `json:"privacy"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	TraktListSettings
+}
+
+type TraktListUpdateBody struct {
+	Description string `json:"description,omitempty"`
+	TraktListSettings
+}
+
+type TraktListSettings struct {
+	Privacy        string `json:"privacy,omitempty"`
 	DisplayNumbers bool   `json:"display_numbers"`
 	AllowComments  bool   `json:"allow_comments"`
 	SortBy         string `json:"sort_by"`
@@ -110,6 +256,30 @@ type TraktCrudItem struct {
 	People   int `json:"people,omitempty"`
 }
 
+func (i *TraktCrudItem) merge(other *TraktCrudItem) *TraktCrudItem {
+	if other == nil {
+		return i
+	}
+	if i == nil {
+		i = &TraktCrudItem{}
+	}
+	return &TraktCrudItem{
+		Movies:   i.Movies + other.Movies,
+		Shows:    i.Shows + other.Shows,
+		Episodes: i.Episodes + other.Episodes,
+		People:   i.People + other.People,
+	}
+}
+
+type TraktListReorderBody struct {
+	Rank []int64 `json:"rank"`
+}
+
+type TraktListReorderResponse struct {
+	Updated    int     `json:"updated"`
+	SkippedIDs []int64 `json:"skipped_ids"`
+}
+
 type TraktResponse struct {
 	Added    *TraktCrudItem `json:"added,omitempty"`
 	Deleted  *TraktCrudItem `json:"deleted,omitempty"`
@@ -117,11 +287,73 @@ type TraktResponse struct {
 	NotFound *TraktListBody `json:"not_found,omitempty"`
 }
 
+// Merge adds the counts and the items that were not found of the other response to the response, in order to
+// describe the outcome of a request that was sent in batches.
+func (r *TraktResponse) Merge(other *TraktResponse) {
+	if other == nil {
+		return
+	}
+	r.Added = r.Added.merge(other.Added)
+	r.Deleted = r.Deleted.merge(other.Deleted)
+	r.Existing = r.Existing.merge(other.Existing)
+	if other.NotFound != nil {
+		if r.NotFound == nil {
+			r.NotFound = &TraktListBody{}
+		}
+		r.NotFound.Movies = append(r.NotFound.Movies, other.NotFound.Movies...)
+		r.NotFound.Shows = append(r.NotFound.Shows, other.NotFound.Shows...)
+		r.NotFound.Episodes = append(r.NotFound.Episodes, other.NotFound.Episodes...)
+		r.NotFound.People = append(r.NotFound.People, other.NotFound.People...)
+	}
+}
+
 type TraktList struct {
-	Name        *string     `json:"name,omitempty"`
-	IDMeta      TraktIDMeta `json:"ids"`
-	ListItems   TraktItems
-	IsWatchlist bool
+	Name             *string     `json:"name,omitempty"`
+	IDMeta           TraktIDMeta `json:"ids"`
+	ListItems        TraktItems
+	Description      string
+	IsWatchlist      bool
+	SettingsOutdated bool
+}
+
+// TraktLikedList is a list that the user liked, such as an official list, as returned by the likes of the user.
+type TraktLikedList struct {
+	LikedAt string    `json:"liked_at"`
+	List    TraktList `json:"list"`
+}
+
+type TraktComment struct {
+	Comment string         `json:"comment"`
+	Spoiler bool           `json:"spoiler"`
+	Movie   *TraktItemSpec `json:"movie,omitempty"`
+	Show    *TraktItemSpec `json:"show,omitempty"`
+	Episode *TraktItemSpec `json:"episode,omitempty"`
+}
+
+// TraktCheckin is the body of a check-in, which refers to either a movie or an episode.
+type TraktCheckin struct {
+	Movie   *TraktItemSpec `json:"movie,omitempty"`
+	Episode *TraktItemSpec `json:"episode,omitempty"`
+}
+
+type TraktUserSettings struct {
+	User   TraktUserInfo `json:"user"`
+	Limits TraktLimits   `json:"limits"`
+}
+
+// TraktLimits are the caps of the account, which are lower for accounts without vip. A zero value means no cap.
+type TraktLimits struct {
+	List struct {
+		Count     int `json:"count"`
+		ItemCount int `json:"item_count"`
+	} `json:"list"`
+	Watchlist struct {
+		ItemCount int `json:"item_count"`
+	} `json:"watchlist"`
+	// ListsOwned is the number of personal lists that the user already has, which counts towards the list cap.
+	ListsOwned int `json:"-"`
+	// Vip reports whether the account has vip, which also unlocks notes on list and watchlist items.
+	Vip bool `json:"-"`
 }
 
 type TraktUserInfo struct {
@@ -0,0 +1,265 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+const (
+	dirLists      = "lists"
+	fileRatings   = "ratings.csv"
+	fileWatchlist = "watchlist.csv"
+
+	imdbTitleTypeMovie     = "Movie"
+	imdbTitleTypeTvEpisode = "TV Episode"
+	imdbTitleTypeTvSeries  = "TV Series"
+	imdbTitleURL           = "https://www.imdb.com/title/%s/"
+)
+
+var (
+	// headerTitles and headerRatings match the headers of the csv files exported by imdb,
+	// so that the output can be imported back into imdb or synced with the imdb export dir option.
+	headerTitles = []string{
+		"Position",
+		"Const",
+		"Created",
+		"Modified",
+		"Description",
+		"Title",
+		"Original Title",
+		"URL",
+		"Title Type",
+		"IMDb Rating",
+		"Runtime (mins)",
+		"Year",
+		"Genres",
+		"Num Votes",
+		"Release Date",
+		"Directors",
+		"Your Rating",
+		"Date Rated",
+	}
+	headerRatings = []string{
+		"Const",
+		"Your Rating",
+		"Date Rated",
+		"Title",
+		"Original Title",
+		"URL",
+		"Title Type",
+		"IMDb Rating",
+		"Runtime (mins)",
+		"Year",
+		"Genres",
+		"Num Votes",
+		"Release Date",
+		"Directors",
+	}
+)
+
+// Exporter writes the trakt ratings, watchlist and lists of the user to imdb compatible csv files.
+type Exporter struct {
+	logger *slog.Logger
+	client client.TraktClientInterface
+	dir    string
+}
+
+type title struct {
+	id        string
+	name      string
+	titleType string
+	year      string
+}
+
+func NewExporter(traktClient client.TraktClientInterface, dir string, logger *slog.Logger) *Exporter {
+	return &Exporter{
+		logger: logger,
+		client: traktClient,
+		dir:    dir,
+	}
+}
+
+func (e *Exporter) Export() error {
+	if err := os.MkdirAll(filepath.Join(e.dir, dirLists), os.ModePerm); err != nil {
+		return fmt.Errorf("failure creating export directory: %w", err)
+	}
+	if err := e.exportRatings(); err != nil {
+		return fmt.Errorf("failure exporting ratings: %w", err)
+	}
+	if err := e.exportWatchlist(); err != nil {
+		return fmt.Errorf("failure exporting watchlist: %w", err)
+	}
+	if err := e.exportLists(); err != nil {
+		return fmt.Errorf("failure exporting lists: %w", err)
+	}
+	return nil
+}
+
+func (e *Exporter) exportRatings() error {
+	ratings, err := e.client.RatingsGet()
+	if err != nil {
+		return err
+	}
+	records := make([][]string, 0, len(ratings))
+	for _, item := range ratings {
+		t, ok := toTitle(item)
+		if !ok {
+			continue
+		}
+		records = append(records, []string{
+			t.id,
+			strconv.Itoa(item.Rating),
+			formatDate(item.RatedAt),
+			t.name,
+			t.name,
+			fmt.Sprintf(imdbTitleURL, t.id),
+			t.titleType,
+			"",
+			"",
+			t.year,
+			"",
+			"",
+			"",
+			"",
+		})
+	}
+	path := filepath.Join(e.dir, fileRatings)
+	if err = writeFile(path, headerRatings, records); err != nil {
+		return err
+	}
+	e.logExported(path, len(records), len(ratings))
+	return nil
+}
+
+func (e *Exporter) exportWatchlist() error {
+	watchlist, err := e.client.WatchlistGet()
+	if err != nil {
+		return err
+	}
+	return e.exportTitles(filepath.Join(e.dir, fileWatchlist), watchlist.ListItems)
+}
+
+func (e *Exporter) exportLists() error {
+	lists, err := e.client.UserListsGet()
+	if err != nil {
+		return err
+	}
+	for _, summary := range lists {
+		slug := summary.IDMeta.Slug
+		list, err := e.client.ListGet(slug)
+		if err != nil {
+			return fmt.Errorf("failure getting list %s: %w", slug, err)
+		}
+		if err = e.exportTitles(filepath.Join(e.dir, dirLists, slug+".csv"), list.ListItems); err != nil {
+			return fmt.Errorf("failure exporting list %s: %w", slug, err)
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) exportTitles(path string, items entities.TraktItems) error {
+	records := make([][]string, 0, len(items))
+	for _, item := range items {
+		t, ok := toTitle(item)
+		if !ok {
+			continue
+		}
+		listedAt := formatDate(item.ListedAt)
+		records = append(records, []string{
+			strconv.Itoa(len(records) + 1),
+			t.id,
+			listedAt,
+			listedAt,
+			"",
+			t.name,
+			t.name,
+			fmt.Sprintf(imdbTitleURL, t.id),
+			t.titleType,
+			"",
+			"",
+			t.year,
+			"",
+			"",
+			"",
+			"",
+			"",
+			"",
+		})
+	}
+	if err := writeFile(path, headerTitles, records); err != nil {
+		return err
+	}
+	e.logExported(path, len(records), len(items))
+	return nil
+}
+
+func (e *Exporter) logExported(path string, exported, total int) {
+	e.logger.Info("exported trakt items", slog.String("path", path), slog.Int("count", exported))
+	if skipped := total - exported; skipped > 0 {
+		e.logger.Warn("skipped trakt items without an imdb title", slog.String("path", path), slog.Int("count", skipped))
+	}
+}
+
+// toTitle maps a trakt item to an imdb title, reporting false for items that imdb cannot import,
+// such as seasons, people or titles without an imdb id.
+func toTitle(item entities.TraktItem) (*title, bool) {
+	var (
+		spec      entities.TraktItemSpec
+		titleType string
+	)
+	switch item.Type {
+	case entities.TraktItemTypeMovie:
+		spec, titleType = item.Movie, imdbTitleTypeMovie
+	case entities.TraktItemTypeShow:
+		spec, titleType = item.Show, imdbTitleTypeTvSeries
+	case entities.TraktItemTypeEpisode:
+		spec, titleType = item.Episode, imdbTitleTypeTvEpisode
+	default:
+		return nil, false
+	}
+	if spec.IDMeta.IMDb == "" {
+		return nil, false
+	}
+	t := &title{
+		id:        spec.IDMeta.IMDb,
+		name:      spec.Title,
+		titleType: titleType,
+	}
+	if spec.Year != 0 {
+		t.year = strconv.Itoa(spec.Year)
+	}
+	return t, true
+}
+
+// formatDate converts a trakt timestamp to the date format used by imdb, falling back to the current date.
+func formatDate(timestamp string) string {
+	date, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		date = time.Now()
+	}
+	return date.UTC().Format(time.DateOnly)
+}
+
+func writeFile(path string, header []string, records [][]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failure creating file %s: %w", path, err)
+	}
+	defer file.Close()
+	writer := csv.NewWriter(file)
+	if err = writer.Write(header); err != nil {
+		return fmt.Errorf("failure writing csv header to %s: %w", path, err)
+	}
+	if err = writer.WriteAll(records); err != nil {
+		return fmt.Errorf("failure writing csv records to %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package exporter
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+type mockTraktClient struct {
+	client.TraktClientInterface
+	ratings   entities.TraktItems
+	watchlist entities.TraktItems
+	lists     map[string]entities.TraktItems
+	err       error
+}
+
+func (m *mockTraktClient) RatingsGet() (entities.TraktItems, error) {
+	return m.ratings, m.err
+}
+
+func (m *mockTraktClient) WatchlistGet() (*entities.TraktList, error) {
+	return &entities.TraktList{ListItems: m.watchlist, IsWatchlist: true}, nil
+}
+
+func (m *mockTraktClient) UserListsGet() ([]entities.TraktList, error) {
+	lists := make([]entities.TraktList, 0, len(m.lists))
+	for slug := range m.lists {
+		lists = append(lists, entities.TraktList{IDMeta: entities.TraktIDMeta{Slug: slug}})
+	}
+	return lists, nil
+}
+
+func (m *mockTraktClient) ListGet(listID string) (*entities.TraktList, error) {
+	return &entities.TraktList{IDMeta: entities.TraktIDMeta{Slug: listID}, ListItems: m.lists[listID]}, nil
+}
+
+func TestExporter_Export(t *testing.T) {
+	movie := entities.TraktItem{
+		Type:     entities.TraktItemTypeMovie,
+		RatedAt:  "2023-12-31T15:20:50.000Z",
+		Rating:   8,
+		ListedAt: "2024-01-02T10:00:00.000Z",
+		Movie: entities.TraktItemSpec{
+			IDMeta: entities.TraktIDMeta{IMDb: "tt5013056"},
+			Title:  "Dunkirk",
+			Year:   2017,
+		},
+	}
+	show := entities.TraktItem{
+		Type:     entities.TraktItemTypeShow,
+		RatedAt:  "2024-01-01T08:00:00.000Z",
+		Rating:   9,
+		ListedAt: "2024-01-03T10:00:00.000Z",
+		Show: entities.TraktItemSpec{
+			IDMeta: entities.TraktIDMeta{IMDb: "tt0903747"},
+			Title:  "Breaking Bad, Remastered",
+			Year:   2008,
+		},
+	}
+	season := entities.TraktItem{
+		Type:   entities.TraktItemTypeSeason,
+		Rating: 7,
+	}
+	tests := []struct {
+		name       string
+		client     *mockTraktClient
+		assertions func(*assert.Assertions, string, error)
+	}{
+		{
+			name: "successfully export ratings, watchlist and lists",
+			client: &mockTraktClient{
+				ratings:   entities.TraktItems{movie, season, show},
+				watchlist: entities.TraktItems{show},
+				lists: map[string]entities.TraktItems{
+					"favourites": {season, movie, show},
+				},
+			},
+			assertions: func(assertions *assert.Assertions, dir string, err error) {
+				assertions.NoError(err)
+				ratings, err := os.ReadFile(filepath.Join(dir, fileRatings))
+				assertions.NoError(err)
+				assertions.Equal(
+					"Const,Your Rating,Date Rated,Title,Original Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors\n"+
+						"tt5013056,8,2023-12-31,Dunkirk,Dunkirk,https://www.imdb.com/title/tt5013056/,Movie,,,2017,,,,\n"+
+						"tt0903747,9,2024-01-01,\"Breaking Bad, Remastered\",\"Breaking Bad, Remastered\",https://www.imdb.com/title/tt0903747/,TV Series,,,2008,,,,\n",
+					string(ratings),
+				)
+				watchlist, err := os.ReadFile(filepath.Join(dir, fileWatchlist))
+				assertions.NoError(err)
+				assertions.Contains(string(watchlist), "1,tt0903747,2024-01-03,2024-01-03,,")
+				list, err := os.ReadFile(filepath.Join(dir, dirLists, "favourites.csv"))
+				assertions.NoError(err)
+				assertions.Contains(string(list), "1,tt5013056,2024-01-02,2024-01-02,,Dunkirk,")
+				assertions.Contains(string(list), "2,tt0903747,2024-01-03,2024-01-03,,")
+			},
+		},
+		{
+			name: "failure getting ratings",
+			client: &mockTraktClient{
+				err: errors.New("unexpected status code"),
+			},
+			assertions: func(assertions *assert.Assertions, dir string, err error) {
+				assertions.ErrorContains(err, "failure exporting ratings")
+				assertions.NoFileExists(filepath.Join(dir, fileWatchlist))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			exporter := NewExporter(tt.client, dir, logger.NewLogger(io.Discard))
+			err := exporter.Export()
+			tt.assertions(assert.New(t), dir, err)
+		})
+	}
+}
@@ -0,0 +1,402 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+const (
+	OperationCollectionAdd    = "collection-add"
+	OperationCollectionRemove = "collection-remove"
+	OperationFavoritesAdd     = "favorites-add"
+	OperationFavoritesRemove  = "favorites-remove"
+	OperationHiddenAdd        = "hidden-add"
+	OperationHiddenRemove     = "hidden-remove"
+	OperationHistoryAdd       = "history-add"
+	OperationHistoryRemove    = "history-remove"
+	OperationListAdd          = "list-add"
+	OperationListItemsAdd     = "list-items-add"
+	OperationListItemsRemove  = "list-items-remove"
+	OperationListLike         = "list-like"
+	OperationListUnlike       = "list-unlike"
+	OperationRatingsAdd       = "ratings-add"
+	OperationRatingsRemove    = "ratings-remove"
+	OperationWatchlistAdd     = "watchlist-add"
+	OperationWatchlistRemove  = "watchlist-remove"
+
+	stateKey = "sync-journal"
+)
+
+// Journal records the mutating operations of a sync run, so that they can be reverted afterwards.
+type Journal struct {
+	StartedAt  time.Time   `json:"started_at"`
+	Operations []Operation `json:"operations"`
+}
+
+// Operation is a single mutation of the destination. Previous holds the state that the operation replaced,
+// such as the ratings that were overwritten or the history entries that were removed.
+type Operation struct {
+	Kind     string              `json:"kind"`
+	ListID   string              `json:"list_id,omitempty"`
+	Items    entities.TraktItems `json:"items,omitempty"`
+	Previous entities.TraktItems `json:"previous,omitempty"`
+}
+
+// Client wraps a destination client and records every successful mutation in a journal.
+// The ratings and history that the wrapped client returns are remembered, so that overwritten
+// ratings and removed history entries can be restored when the journal is undone.
+type Client struct {
+	client.DestinationClientInterface
+	journal *Journal
+	ratings map[string]entities.TraktItem
+	history map[string]entities.TraktItems
+}
+
+func NewClient(destination client.DestinationClientInterface) *Client {
+	return &Client{
+		DestinationClientInterface: destination,
+		journal: &Journal{
+			StartedAt: time.Now().UTC(),
+		},
+		ratings: make(map[string]entities.TraktItem),
+		history: make(map[string]entities.TraktItems),
+	}
+}
+
+// Journal returns the operations recorded so far.
+func (c *Client) Journal() *Journal {
+	return c.journal
+}
+
+func (c *Client) record(operation Operation) {
+	c.journal.Operations = append(c.journal.Operations, operation)
+}
+
+// applied returns the items that the destination changed, which are only some of them when a request that is sent in
+// several batches fails midway, since the batches that were applied are not rolled back.
+func applied(items entities.TraktItems, err error) (entities.TraktItems, bool) {
+	if err == nil {
+		return items, true
+	}
+	var partial *client.PartialError
+	if errors.As(err, &partial) {
+		return partial.Applied, true
+	}
+	return nil, false
+}
+
+func (c *Client) WatchlistItemsAdd(items entities.TraktItems) error {
+	err := c.DestinationClientInterface.WatchlistItemsAdd(items)
+	if items, ok := applied(items, err); ok {
+		c.record(Operation{Kind: OperationWatchlistAdd, Items: items})
+	}
+	return err
+}
+
+func (c *Client) WatchlistItemsRemove(items entities.TraktItems) error {
+	err := c.DestinationClientInterface.WatchlistItemsRemove(items)
+	if items, ok := applied(items, err); ok {
+		c.record(Operation{Kind: OperationWatchlistRemove, Items: items})
+	}
+	return err
+}
+
+func (c *Client) ListAdd(listID, listName, description string) error {
+	if err := c.DestinationClientInterface.ListAdd(listID, listName, description); err != nil {
+		return err
+	}
+	c.record(Operation{Kind: OperationListAdd, ListID: listID})
+	return nil
+}
+
+func (c *Client) ListItemsAdd(listID string, items entities.TraktItems) error {
+	err := c.DestinationClientInterface.ListItemsAdd(listID, items)
+	if items, ok := applied(items, err); ok {
+		c.record(Operation{Kind: OperationListItemsAdd, ListID: listID, Items: items})
+	}
+	return err
+}
+
+func (c *Client) ListItemsRemove(listID string, items entities.TraktItems) error {
+	err := c.DestinationClientInterface.ListItemsRemove(listID, items)
+	if items, ok := applied(items, err); ok {
+		c.record(Operation{Kind: OperationListItemsRemove, ListID: listID, Items: items})
+	}
+	return err
+}
+
+func (c *Client) RatingsGet() (entities.TraktItems, error) {
+	items, err := c.DestinationClientInterface.RatingsGet()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if id, err := item.GetItemID(); err == nil && id != nil {
+			c.ratings[*id] = item
+		}
+	}
+	return items, nil
+}
+
+func (c *Client) RatingsAdd(items entities.TraktItems) error {
+	err := c.DestinationClientInterface.RatingsAdd(items)
+	items, ok := applied(items, err)
+	if !ok {
+		return err
+	}
+	var previous entities.TraktItems
+	for _, item := range items {
+		id, err := item.GetItemID()
+		if err != nil || id == nil {
+			continue
+		}
+		if rating, ok := c.ratings[*id]; ok {
+			previous = append(previous, rating)
+		}
+	}
+	c.record(Operation{Kind: OperationRatingsAdd, Items: items, Previous: previous})
+	return err
+}
+
+func (c *Client) RatingsRemove(items entities.TraktItems) error {
+	err := c.DestinationClientInterface.RatingsRemove(items)
+	if items, ok := applied(items, err); ok {
+		c.record(Operation{Kind: OperationRatingsRemove, Items: items})
+	}
+	return err
+}
+
+func (c *Client) HistoryGet(itemType, itemID string) (entities.TraktItems, error) {
+	items, err := c.DestinationClientInterface.HistoryGet(itemType, itemID)
+	if err != nil {
+		return nil, err
+	}
+	c.history[itemType+itemID] = items
+	return items, nil
+}
+
+func (c *Client) HistoryAdd(items entities.TraktItems) error {
+	err := c.DestinationClientInterface.HistoryAdd(items)
+	if items, ok := applied(items, err); ok {
+		c.record(Operation{Kind: OperationHistoryAdd, Items: items})
+	}
+	return err
+}
+
+func (c *Client) HistoryRemove(items entities.TraktItems) error {
+	err := c.DestinationClientInterface.HistoryRemove(items)
+	items, ok := applied(items, err)
+	if !ok {
+		return err
+	}
+	var previous entities.TraktItems
+	for _, item := range items {
+		id, err := item.GetItemID()
+		if err != nil || id == nil {
+			continue
+		}
+		previous = append(previous, c.history[item.Type+*id]...)
+	}
+	c.record(Operation{Kind: OperationHistoryRemove, Items: items, Previous: previous})
+	return err
+}
+
+func (c *Client) CollectionAdd(items entities.TraktItems) error {
+	err := c.DestinationClientInterface.CollectionAdd(items)
+	if items, ok := applied(items, err); ok {
+		c.record(Operation{Kind: OperationCollectionAdd, Items: items})
+	}
+	return err
+}
+
+func (c *Client) CollectionRemove(items entities.TraktItems) error {
+	err := c.DestinationClientInterface.CollectionRemove(items)
+	if items, ok := applied(items, err); ok {
+		c.record(Operation{Kind: OperationCollectionRemove, Items: items})
+	}
+	return err
+}
+
+func (c *Client) FavoritesAdd(items entities.TraktItems) error {
+	err := c.DestinationClientInterface.FavoritesAdd(items)
+	if items, ok := applied(items, err); ok {
+		c.record(Operation{Kind: OperationFavoritesAdd, Items: items})
+	}
+	return err
+}
+
+func (c *Client) FavoritesRemove(items entities.TraktItems) error {
+	err := c.DestinationClientInterface.FavoritesRemove(items)
+	if items, ok := applied(items, err); ok {
+		c.record(Operation{Kind: OperationFavoritesRemove, Items: items})
+	}
+	return err
+}
+
+// HiddenAdd records the section that the items were hidden from as the list id of the operation.
+func (c *Client) HiddenAdd(section string, items entities.TraktItems) error {
+	err := c.DestinationClientInterface.HiddenAdd(section, items)
+	if items, ok := applied(items, err); ok {
+		c.record(Operation{Kind: OperationHiddenAdd, ListID: section, Items: items})
+	}
+	return err
+}
+
+func (c *Client) HiddenRemove(section string, items entities.TraktItems) error {
+	err := c.DestinationClientInterface.HiddenRemove(section, items)
+	if items, ok := applied(items, err); ok {
+		c.record(Operation{Kind: OperationHiddenRemove, ListID: section, Items: items})
+	}
+	return err
+}
+
+func (c *Client) ListLike(listID string) error {
+	if err := c.DestinationClientInterface.ListLike(listID); err != nil {
+		return err
+	}
+	c.record(Operation{Kind: OperationListLike, ListID: listID})
+	return nil
+}
+
+func (c *Client) ListUnlike(listID string) error {
+	if err := c.DestinationClientInterface.ListUnlike(listID); err != nil {
+		return err
+	}
+	c.record(Operation{Kind: OperationListUnlike, ListID: listID})
+	return nil
+}
+
+// Load returns the journal of the last sync run that changed any data.
+func Load(store state.Store) (*Journal, error) {
+	var journal Journal
+	if err := store.Load(stateKey, &journal); err != nil {
+		return nil, err
+	}
+	return &journal, nil
+}
+
+// Save stores the journal, unless no operations were recorded, so that the journal of the
+// last sync run that changed any data is kept.
+func Save(store state.Store, journal *Journal) error {
+	if len(journal.Operations) == 0 {
+		return nil
+	}
+	return store.Save(stateKey, journal)
+}
+
+// Undo applies the inverse of the journal operations to the destination in reverse order,
+// then deletes the journal from the store so that it cannot be reverted twice.
+func Undo(destination client.DestinationClientInterface, store state.Store, journal *Journal, logger *slog.Logger) error {
+	for _, operation := range slices.Backward(journal.Operations) {
+		if err := undo(destination, operation); err != nil {
+			return fmt.Errorf("failure undoing operation %s: %w", operation.Kind, err)
+		}
+		logger.Info("reverted operation", slog.String("kind", operation.Kind), slog.String("listID", operation.ListID), slog.Int("count", len(operation.Items)))
+	}
+	if err := store.Delete(stateKey); err != nil && !errors.Is(err, state.ErrNotFound) {
+		return fmt.Errorf("failure deleting journal: %w", err)
+	}
+	return nil
+}
+
+func undo(destination client.DestinationClientInterface, operation Operation) error {
+	switch operation.Kind {
+	case OperationWatchlistAdd:
+		return destination.WatchlistItemsRemove(operation.Items)
+	case OperationWatchlistRemove:
+		return destination.WatchlistItemsAdd(operation.Items)
+	case OperationListAdd:
+		return destination.ListRemove(operation.ListID)
+	case OperationListItemsAdd:
+		return destination.ListItemsRemove(operation.ListID, operation.Items)
+	case OperationListItemsRemove:
+		return destination.ListItemsAdd(operation.ListID, operation.Items)
+	case OperationListLike:
+		return destination.ListUnlike(operation.ListID)
+	case OperationListUnlike:
+		return destination.ListLike(operation.ListID)
+	case OperationRatingsAdd:
+		return undoRatingsAdd(destination, operation)
+	case OperationRatingsRemove:
+		return destination.RatingsAdd(ratingItems(operation.Items))
+	case OperationHistoryAdd:
+		return destination.HistoryRemove(operation.Items)
+	case OperationHistoryRemove:
+		previous := operation.Previous
+		if len(previous) == 0 {
+			previous = operation.Items
+		}
+		return destination.HistoryAdd(previous.MapSpecs(func(item entities.TraktItem, spec *entities.TraktItemSpec) {
+			if item.WatchedAt != "" {
+				spec.WatchedAt = &item.WatchedAt
+			} else if spec.WatchedAt == nil && item.RatedAt != "" {
+				spec.WatchedAt = &item.RatedAt
+			}
+		}))
+	case OperationCollectionAdd:
+		return destination.CollectionRemove(operation.Items)
+	case OperationCollectionRemove:
+		return destination.CollectionAdd(operation.Items.MapSpecs(func(item entities.TraktItem, spec *entities.TraktItemSpec) {
+			if item.CollectedAt != "" {
+				spec.CollectedAt = &item.CollectedAt
+			}
+		}))
+	case OperationFavoritesAdd:
+		return destination.FavoritesRemove(operation.Items)
+	case OperationFavoritesRemove:
+		return destination.FavoritesAdd(operation.Items)
+	case OperationHiddenAdd:
+		return destination.HiddenRemove(operation.ListID, operation.Items)
+	case OperationHiddenRemove:
+		return destination.HiddenAdd(operation.ListID, operation.Items)
+	default:
+		return fmt.Errorf("unknown operation %s", operation.Kind)
+	}
+}
+
+// undoRatingsAdd removes the added ratings, except for the ones that overwrote a previous rating, which is restored instead.
+func undoRatingsAdd(destination client.DestinationClientInterface, operation Operation) error {
+	overwritten := make(map[string]struct{}, len(operation.Previous))
+	for _, item := range operation.Previous {
+		if id, err := item.GetItemID(); err == nil && id != nil {
+			overwritten[*id] = struct{}{}
+		}
+	}
+	var added entities.TraktItems
+	for _, item := range operation.Items {
+		id, err := item.GetItemID()
+		if err != nil || id == nil {
+			continue
+		}
+		if _, ok := overwritten[*id]; !ok {
+			added = append(added, item)
+		}
+	}
+	if len(added) > 0 {
+		if err := destination.RatingsRemove(added); err != nil {
+			return err
+		}
+	}
+	if len(operation.Previous) > 0 {
+		return destination.RatingsAdd(ratingItems(operation.Previous))
+	}
+	return nil
+}
+
+// ratingItems moves the rating of items returned by trakt into their specification, so that they can be rated again.
+func ratingItems(items entities.TraktItems) entities.TraktItems {
+	return items.MapSpecs(func(item entities.TraktItem, spec *entities.TraktItemSpec) {
+		if item.Rating == 0 {
+			return
+		}
+		spec.Rating = &item.Rating
+		spec.RatedAt = &item.RatedAt
+	})
+}
@@ -0,0 +1,348 @@
+package journal
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+type call struct {
+	method string
+	listID string
+	items  entities.TraktItems
+}
+
+type mockDestinationClient struct {
+	client.DestinationClientInterface
+	ratings entities.TraktItems
+	history entities.TraktItems
+	err     error
+	calls   []call
+}
+
+func (m *mockDestinationClient) do(method, listID string, items entities.TraktItems) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.calls = append(m.calls, call{method: method, listID: listID, items: items})
+	return nil
+}
+
+func (m *mockDestinationClient) WatchlistItemsAdd(items entities.TraktItems) error {
+	return m.do("WatchlistItemsAdd", "", items)
+}
+
+func (m *mockDestinationClient) WatchlistItemsRemove(items entities.TraktItems) error {
+	return m.do("WatchlistItemsRemove", "", items)
+}
+
+func (m *mockDestinationClient) ListAdd(listID, listName, description string) error {
+	return m.do("ListAdd", listID, nil)
+}
+
+func (m *mockDestinationClient) ListRemove(listID string) error {
+	return m.do("ListRemove", listID, nil)
+}
+
+func (m *mockDestinationClient) ListItemsAdd(listID string, items entities.TraktItems) error {
+	return m.do("ListItemsAdd", listID, items)
+}
+
+func (m *mockDestinationClient) ListItemsRemove(listID string, items entities.TraktItems) error {
+	return m.do("ListItemsRemove", listID, items)
+}
+
+func (m *mockDestinationClient) RatingsGet() (entities.TraktItems, error) {
+	return m.ratings, nil
+}
+
+func (m *mockDestinationClient) RatingsAdd(items entities.TraktItems) error {
+	return m.do("RatingsAdd", "", items)
+}
+
+func (m *mockDestinationClient) RatingsRemove(items entities.TraktItems) error {
+	return m.do("RatingsRemove", "", items)
+}
+
+func (m *mockDestinationClient) HistoryGet(itemType, itemID string) (entities.TraktItems, error) {
+	return m.history, nil
+}
+
+func (m *mockDestinationClient) HistoryAdd(items entities.TraktItems) error {
+	return m.do("HistoryAdd", "", items)
+}
+
+func (m *mockDestinationClient) HistoryRemove(items entities.TraktItems) error {
+	return m.do("HistoryRemove", "", items)
+}
+
+func (m *mockDestinationClient) CollectionAdd(items entities.TraktItems) error {
+	return m.do("CollectionAdd", "", items)
+}
+
+func (m *mockDestinationClient) CollectionRemove(items entities.TraktItems) error {
+	return m.do("CollectionRemove", "", items)
+}
+
+func (m *mockDestinationClient) HiddenAdd(section string, items entities.TraktItems) error {
+	return m.do("HiddenAdd", section, items)
+}
+
+func (m *mockDestinationClient) HiddenRemove(section string, items entities.TraktItems) error {
+	return m.do("HiddenRemove", section, items)
+}
+
+func (m *mockDestinationClient) ListLike(listID string) error {
+	return m.do("ListLike", listID, nil)
+}
+
+func (m *mockDestinationClient) ListUnlike(listID string) error {
+	return m.do("ListUnlike", listID, nil)
+}
+
+func movie(id string, rating int) entities.TraktItem {
+	item := entities.TraktItem{
+		Type: entities.TraktItemTypeMovie,
+		Movie: entities.TraktItemSpec{
+			IDMeta: entities.TraktIDMeta{IMDb: id},
+		},
+	}
+	if rating > 0 {
+		item.Movie.Rating = &rating
+	}
+	return item
+}
+
+func TestClient(t *testing.T) {
+	tests := []struct {
+		name       string
+		client     *mockDestinationClient
+		run        func(*Client) error
+		assertions func(*assert.Assertions, *Journal, error)
+	}{
+		{
+			name: "record operations with previous ratings and history",
+			client: &mockDestinationClient{
+				ratings: entities.TraktItems{{
+					Type:    entities.TraktItemTypeMovie,
+					Rating:  6,
+					RatedAt: "2023-12-31T15:20:50.000Z",
+					Movie: entities.TraktItemSpec{
+						IDMeta: entities.TraktIDMeta{IMDb: "tt1"},
+					},
+				}},
+				history: entities.TraktItems{{
+					Type:      entities.TraktItemTypeMovie,
+					WatchedAt: "2024-01-30T00:00:00.000Z",
+					Movie: entities.TraktItemSpec{
+						IDMeta: entities.TraktIDMeta{IMDb: "tt3"},
+					},
+				}},
+			},
+			run: func(c *Client) error {
+				if _, err := c.RatingsGet(); err != nil {
+					return err
+				}
+				if _, err := c.HistoryGet(entities.TraktItemTypeMovie, "tt3"); err != nil {
+					return err
+				}
+				if err := c.ListAdd("watched", "Watched", ""); err != nil {
+					return err
+				}
+				if err := c.RatingsAdd(entities.TraktItems{movie("tt1", 8), movie("tt2", 7)}); err != nil {
+					return err
+				}
+				return c.HistoryRemove(entities.TraktItems{movie("tt3", 0)})
+			},
+			assertions: func(assertions *assert.Assertions, journal *Journal, err error) {
+				assertions.NoError(err)
+				assertions.Equal(3, len(journal.Operations))
+				assertions.Equal(OperationListAdd, journal.Operations[0].Kind)
+				assertions.Equal("watched", journal.Operations[0].ListID)
+				assertions.Equal(OperationRatingsAdd, journal.Operations[1].Kind)
+				assertions.Equal(1, len(journal.Operations[1].Previous))
+				assertions.Equal(6, journal.Operations[1].Previous[0].Rating)
+				assertions.Equal(OperationHistoryRemove, journal.Operations[2].Kind)
+				assertions.Equal("2024-01-30T00:00:00.000Z", journal.Operations[2].Previous[0].WatchedAt)
+			},
+		},
+		{
+			name: "skip failed operations",
+			client: &mockDestinationClient{
+				err: errors.New("unexpected status code"),
+			},
+			run: func(c *Client) error {
+				return c.WatchlistItemsAdd(entities.TraktItems{movie("tt1", 0)})
+			},
+			assertions: func(assertions *assert.Assertions, journal *Journal, err error) {
+				assertions.Error(err)
+				assertions.Empty(journal.Operations)
+			},
+		},
+		{
+			name: "record the items that were applied before a later batch failed",
+			client: &mockDestinationClient{
+				ratings: entities.TraktItems{{
+					Type:   entities.TraktItemTypeMovie,
+					Rating: 6,
+					Movie: entities.TraktItemSpec{
+						IDMeta: entities.TraktIDMeta{IMDb: "tt2"},
+					},
+				}},
+				err: &client.PartialError{
+					Applied: entities.TraktItems{movie("tt1", 8)},
+					Err:     errors.New("unexpected status code"),
+				},
+			},
+			run: func(c *Client) error {
+				if _, err := c.RatingsGet(); err != nil {
+					return err
+				}
+				return c.RatingsAdd(entities.TraktItems{movie("tt1", 8), movie("tt2", 7)})
+			},
+			assertions: func(assertions *assert.Assertions, journal *Journal, err error) {
+				assertions.Error(err)
+				assertions.Equal(1, len(journal.Operations))
+				assertions.Equal(OperationRatingsAdd, journal.Operations[0].Kind)
+				assertions.Equal(entities.TraktItems{movie("tt1", 8)}, journal.Operations[0].Items)
+				assertions.Empty(journal.Operations[0].Previous)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(tt.client)
+			err := tt.run(c)
+			tt.assertions(assert.New(t), c.Journal(), err)
+		})
+	}
+}
+
+func TestUndo(t *testing.T) {
+	previousRating := entities.TraktItem{
+		Type:    entities.TraktItemTypeMovie,
+		Rating:  6,
+		RatedAt: "2023-12-31T15:20:50.000Z",
+		Movie: entities.TraktItemSpec{
+			IDMeta: entities.TraktIDMeta{IMDb: "tt1"},
+		},
+	}
+	tests := []struct {
+		name       string
+		journal    *Journal
+		client     *mockDestinationClient
+		assertions func(*assert.Assertions, *mockDestinationClient, state.Store, error)
+	}{
+		{
+			name: "revert operations in reverse order",
+			journal: &Journal{
+				Operations: []Operation{
+					{Kind: OperationListAdd, ListID: "watched"},
+					{Kind: OperationListItemsAdd, ListID: "watched", Items: entities.TraktItems{movie("tt1", 0)}},
+					{Kind: OperationWatchlistRemove, Items: entities.TraktItems{movie("tt2", 0)}},
+					{Kind: OperationRatingsAdd, Items: entities.TraktItems{movie("tt1", 8), movie("tt2", 7)}, Previous: entities.TraktItems{previousRating}},
+				},
+			},
+			client: &mockDestinationClient{},
+			assertions: func(assertions *assert.Assertions, c *mockDestinationClient, store state.Store, err error) {
+				assertions.NoError(err)
+				methods := make([]string, 0, len(c.calls))
+				for _, call := range c.calls {
+					methods = append(methods, call.method)
+				}
+				assertions.Equal([]string{"RatingsRemove", "RatingsAdd", "WatchlistItemsAdd", "ListItemsRemove", "ListRemove"}, methods)
+				assertions.Equal("tt2", c.calls[0].items[0].Movie.IDMeta.IMDb)
+				assertions.Equal(6, *c.calls[1].items[0].Movie.Rating)
+				_, err = Load(store)
+				assertions.ErrorIs(err, state.ErrNotFound)
+			},
+		},
+		{
+			name: "revert collection changes",
+			journal: &Journal{
+				Operations: []Operation{
+					{Kind: OperationCollectionAdd, Items: entities.TraktItems{movie("tt1", 0)}},
+					{Kind: OperationCollectionRemove, Items: entities.TraktItems{{
+						Type:        entities.TraktItemTypeMovie,
+						CollectedAt: "2024-01-30T00:00:00.000Z",
+						Movie: entities.TraktItemSpec{
+							IDMeta: entities.TraktIDMeta{IMDb: "tt2"},
+						},
+					}}},
+				},
+			},
+			client: &mockDestinationClient{},
+			assertions: func(assertions *assert.Assertions, c *mockDestinationClient, store state.Store, err error) {
+				assertions.NoError(err)
+				assertions.Equal(2, len(c.calls))
+				assertions.Equal("CollectionAdd", c.calls[0].method)
+				assertions.Equal("2024-01-30T00:00:00.000Z", *c.calls[0].items[0].Movie.CollectedAt)
+				assertions.Equal("CollectionRemove", c.calls[1].method)
+				assertions.Equal("tt1", c.calls[1].items[0].Movie.IDMeta.IMDb)
+			},
+		},
+		{
+			name: "revert hidden items",
+			journal: &Journal{
+				Operations: []Operation{
+					{Kind: OperationHiddenAdd, ListID: "recommendations", Items: entities.TraktItems{movie("tt1", 0)}},
+					{Kind: OperationHiddenAdd, ListID: "calendar", Items: entities.TraktItems{movie("tt1", 0)}},
+				},
+			},
+			client: &mockDestinationClient{},
+			assertions: func(assertions *assert.Assertions, c *mockDestinationClient, store state.Store, err error) {
+				assertions.NoError(err)
+				assertions.Equal(2, len(c.calls))
+				assertions.Equal("HiddenRemove", c.calls[0].method)
+				assertions.Equal("calendar", c.calls[0].listID)
+				assertions.Equal("recommendations", c.calls[1].listID)
+			},
+		},
+		{
+			name: "revert liked lists",
+			journal: &Journal{
+				Operations: []Operation{
+					{Kind: OperationListLike, ListID: "2"},
+					{Kind: OperationListUnlike, ListID: "3"},
+				},
+			},
+			client: &mockDestinationClient{},
+			assertions: func(assertions *assert.Assertions, c *mockDestinationClient, store state.Store, err error) {
+				assertions.NoError(err)
+				assertions.Equal([]call{{method: "ListLike", listID: "3"}, {method: "ListUnlike", listID: "2"}}, c.calls)
+			},
+		},
+		{
+			name: "failure reverting operation",
+			journal: &Journal{
+				Operations: []Operation{
+					{Kind: OperationWatchlistAdd, Items: entities.TraktItems{movie("tt1", 0)}},
+				},
+			},
+			client: &mockDestinationClient{
+				err: errors.New("unexpected status code"),
+			},
+			assertions: func(assertions *assert.Assertions, c *mockDestinationClient, store state.Store, err error) {
+				assertions.ErrorContains(err, "failure undoing operation watchlist-add")
+				_, err = Load(store)
+				assertions.NoError(err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := state.NewFileStore(t.TempDir())
+			assert.NoError(t, err)
+			assert.NoError(t, Save(store, tt.journal))
+			err = Undo(tt.client, store, tt.journal, logger.NewLogger(io.Discard))
+			tt.assertions(assert.New(t), tt.client, store, err)
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package mapping
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/progress"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+// Mapping points an imdb id to the trakt or tmdb id of the title that it should be synced as. The type restricts the
+// mapping to the trakt items of that type, since trakt and tmdb ids are only unique per type.
+type Mapping struct {
+	Type  string `yaml:"type"`
+	Trakt int64  `yaml:"trakt"`
+	TMDB  int64  `yaml:"tmdb"`
+}
+
+// Mappings are the mappings keyed by imdb id.
+type Mappings map[string]Mapping
+
+// Load reads the mappings from a yaml file, which maps each imdb id to the ids of the title on the destination:
+//
+//	tt0000001:
+//	  type: movie
+//	  trakt: 12345
+//	tt0000002:
+//	  tmdb: 67890
+func Load(path string) (Mappings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading mapping file: %w", err)
+	}
+	var mappings Mappings
+	if err = yaml.Unmarshal(data, &mappings); err != nil {
+		return nil, fmt.Errorf("failure parsing mapping file: %w", err)
+	}
+	for imdbID, mapping := range mappings {
+		if !strings.HasPrefix(imdbID, "tt") && !entities.IsIMDbPersonID(imdbID) {
+			return nil, fmt.Errorf("mapping file contains invalid imdb id %s", imdbID)
+		}
+		if mapping.Type != "" && !slices.Contains(validTypes(), mapping.Type) {
+			return nil, fmt.Errorf("mapping of imdb id %s has type %s, which must be one of: %s", imdbID, mapping.Type, strings.Join(validTypes(), ", "))
+		}
+		if mapping.Trakt == 0 && mapping.TMDB == 0 {
+			return nil, fmt.Errorf("mapping of imdb id %s requires a trakt or tmdb id", imdbID)
+		}
+	}
+	return mappings, nil
+}
+
+func validTypes() []string {
+	return []string{
+		entities.TraktItemTypeMovie,
+		entities.TraktItemTypeShow,
+		entities.TraktItemTypeEpisode,
+		entities.TraktItemTypePerson,
+	}
+}
+
+// Client wraps a destination client and applies the mappings to the items that go through it. Outgoing items that
+// have a mapping are identified by their mapped ids instead of their imdb id, while incoming items that match a mapping
+// are given the imdb id that they were mapped from, so that they are matched with their imdb counterparts.
+type Client struct {
+	client.DestinationClientInterface
+	mappings Mappings
+}
+
+// NewClient wraps the destination, unless there are no mappings to apply.
+func NewClient(destination client.DestinationClientInterface, mappings Mappings) client.DestinationClientInterface {
+	if len(mappings) == 0 {
+		return destination
+	}
+	return &Client{
+		DestinationClientInterface: destination,
+		mappings:                   mappings,
+	}
+}
+
+// SetProgress forwards the progress to the wrapped destination, if it reports any.
+func (c *Client) SetProgress(p *progress.Progress) {
+	if destination, ok := c.DestinationClientInterface.(interface{ SetProgress(*progress.Progress) }); ok {
+		destination.SetProgress(p)
+	}
+}
+
+// outgoing returns a copy of the items, where the items that have a mapping are identified by the mapped ids only.
+func (c *Client) outgoing(items entities.TraktItems) entities.TraktItems {
+	mapped := slices.Clone(items)
+	for i := range mapped {
+		id, err := mapped[i].GetItemID()
+		if err != nil || id == nil {
+			continue
+		}
+		mapping, found := c.mappings[*id]
+		if !found || (mapping.Type != "" && mapping.Type != mapped[i].Type) {
+			continue
+		}
+		if spec := mapped[i].Spec(); spec != nil {
+			spec.IDMeta = entities.TraktIDMeta{
+				Trakt: mapping.Trakt,
+				TMDB:  mapping.TMDB,
+			}
+		}
+	}
+	return mapped
+}
+
+// incoming replaces the imdb ids of the items that match a mapping with the imdb id that they were mapped from.
+func (c *Client) incoming(items entities.TraktItems) entities.TraktItems {
+	for i := range items {
+		spec := items[i].Spec()
+		if spec == nil {
+			continue
+		}
+		for imdbID, mapping := range c.mappings {
+			if mapping.Type != "" && mapping.Type != items[i].Type {
+				continue
+			}
+			if (mapping.Trakt != 0 && mapping.Trakt == spec.IDMeta.Trakt) || (mapping.TMDB != 0 && mapping.TMDB == spec.IDMeta.TMDB) {
+				spec.IDMeta.IMDb = imdbID
+				break
+			}
+		}
+	}
+	return items
+}
+
+func (c *Client) incomingList(list *entities.TraktList, err error) (*entities.TraktList, error) {
+	if err != nil {
+		return nil, err
+	}
+	list.ListItems = c.incoming(list.ListItems)
+	return list, nil
+}
+
+// partial reports the items that were applied before a partial failure as they were passed in, rather than mapped.
+func partial(items entities.TraktItems, err error) error {
+	var partialErr *client.PartialError
+	if errors.As(err, &partialErr) {
+		partialErr.Applied = items[:min(len(partialErr.Applied), len(items))]
+	}
+	return err
+}
+
+func (c *Client) incomingItems(items entities.TraktItems, err error) (entities.TraktItems, error) {
+	if err != nil {
+		return nil, err
+	}
+	return c.incoming(items), nil
+}
+
+func (c *Client) WatchlistGet() (*entities.TraktList, error) {
+	return c.incomingList(c.DestinationClientInterface.WatchlistGet())
+}
+
+func (c *Client) WatchlistItemsAdd(items entities.TraktItems) error {
+	return partial(items, c.DestinationClientInterface.WatchlistItemsAdd(c.outgoing(items)))
+}
+
+func (c *Client) WatchlistItemsRemove(items entities.TraktItems) error {
+	return partial(items, c.DestinationClientInterface.WatchlistItemsRemove(c.outgoing(items)))
+}
+
+func (c *Client) ListGet(listID string) (*entities.TraktList, error) {
+	return c.incomingList(c.DestinationClientInterface.ListGet(listID))
+}
+
+func (c *Client) ListsGet(idMeta entities.TraktIDMetas) ([]entities.TraktList, []error) {
+	lists, errs := c.DestinationClientInterface.ListsGet(idMeta)
+	for i := range lists {
+		lists[i].ListItems = c.incoming(lists[i].ListItems)
+	}
+	return lists, errs
+}
+
+func (c *Client) ListItemsAdd(listID string, items entities.TraktItems) error {
+	return partial(items, c.DestinationClientInterface.ListItemsAdd(listID, c.outgoing(items)))
+}
+
+func (c *Client) ListItemsRemove(listID string, items entities.TraktItems) error {
+	return partial(items, c.DestinationClientInterface.ListItemsRemove(listID, c.outgoing(items)))
+}
+
+func (c *Client) RatingsGet() (entities.TraktItems, error) {
+	return c.incomingItems(c.DestinationClientInterface.RatingsGet())
+}
+
+func (c *Client) RatingsAdd(items entities.TraktItems) error {
+	return partial(items, c.DestinationClientInterface.RatingsAdd(c.outgoing(items)))
+}
+
+func (c *Client) RatingsRemove(items entities.TraktItems) error {
+	return partial(items, c.DestinationClientInterface.RatingsRemove(c.outgoing(items)))
+}
+
+func (c *Client) HistoryAdd(items entities.TraktItems) error {
+	return partial(items, c.DestinationClientInterface.HistoryAdd(c.outgoing(items)))
+}
+
+func (c *Client) HistoryRemove(items entities.TraktItems) error {
+	return partial(items, c.DestinationClientInterface.HistoryRemove(c.outgoing(items)))
+}
+
+func (c *Client) HistoryGetAll() (entities.TraktItems, error) {
+	return c.incomingItems(c.DestinationClientInterface.HistoryGetAll())
+}
+
+func (c *Client) RecommendationsGet() (entities.TraktItems, error) {
+	return c.incomingItems(c.DestinationClientInterface.RecommendationsGet())
+}
+
+func (c *Client) HiddenGet(section string) (entities.TraktItems, error) {
+	return c.incomingItems(c.DestinationClientInterface.HiddenGet(section))
+}
+
+func (c *Client) HiddenAdd(section string, items entities.TraktItems) error {
+	return partial(items, c.DestinationClientInterface.HiddenAdd(section, c.outgoing(items)))
+}
+
+func (c *Client) HiddenRemove(section string, items entities.TraktItems) error {
+	return partial(items, c.DestinationClientInterface.HiddenRemove(section, c.outgoing(items)))
+}
+
+func (c *Client) CollectionGet() (entities.TraktItems, error) {
+	return c.incomingItems(c.DestinationClientInterface.CollectionGet())
+}
+
+func (c *Client) CollectionAdd(items entities.TraktItems) error {
+	return partial(items, c.DestinationClientInterface.CollectionAdd(c.outgoing(items)))
+}
+
+func (c *Client) CollectionRemove(items entities.TraktItems) error {
+	return partial(items, c.DestinationClientInterface.CollectionRemove(c.outgoing(items)))
+}
+
+func (c *Client) FavoritesGet() (entities.TraktItems, error) {
+	return c.incomingItems(c.DestinationClientInterface.FavoritesGet())
+}
+
+func (c *Client) FavoritesAdd(items entities.TraktItems) error {
+	return partial(items, c.DestinationClientInterface.FavoritesAdd(c.outgoing(items)))
+}
+
+func (c *Client) FavoritesRemove(items entities.TraktItems) error {
+	return partial(items, c.DestinationClientInterface.FavoritesRemove(c.outgoing(items)))
+}
+
+func (c *Client) Checkin(item entities.TraktItem) error {
+	return c.DestinationClientInterface.Checkin(c.outgoing(entities.TraktItems{item})[0])
+}
+
+func (c *Client) NotFound() entities.TraktItems {
+	return c.incoming(c.DestinationClientInterface.NotFound())
+}
@@ -0,0 +1,150 @@
+package mapping
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+type mockDestinationClient struct {
+	client.DestinationClientInterface
+	ratings entities.TraktItems
+	added   entities.TraktItems
+	err     error
+}
+
+func (m *mockDestinationClient) RatingsGet() (entities.TraktItems, error) {
+	return m.ratings, nil
+}
+
+func (m *mockDestinationClient) RatingsAdd(items entities.TraktItems) error {
+	m.added = items
+	return m.err
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		assertions func(*assert.Assertions, Mappings, error)
+	}{
+		{
+			name: "valid mappings",
+			content: `tt0000001:
+  type: movie
+  trakt: 12345
+tt0000002:
+  tmdb: 67890
+`,
+			assertions: func(assertions *assert.Assertions, mappings Mappings, err error) {
+				assertions.NoError(err)
+				assertions.Equal(Mappings{
+					"tt0000001": {Type: entities.TraktItemTypeMovie, Trakt: 12345},
+					"tt0000002": {TMDB: 67890},
+				}, mappings)
+			},
+		},
+		{
+			name:    "invalid imdb id",
+			content: "0000001:\n  trakt: 12345\n",
+			assertions: func(assertions *assert.Assertions, mappings Mappings, err error) {
+				assertions.ErrorContains(err, "invalid imdb id 0000001")
+			},
+		},
+		{
+			name:    "invalid type",
+			content: "tt0000001:\n  type: season\n  trakt: 12345\n",
+			assertions: func(assertions *assert.Assertions, mappings Mappings, err error) {
+				assertions.ErrorContains(err, "has type season")
+			},
+		},
+		{
+			name:    "missing ids",
+			content: "tt0000001:\n  type: movie\n",
+			assertions: func(assertions *assert.Assertions, mappings Mappings, err error) {
+				assertions.ErrorContains(err, "requires a trakt or tmdb id")
+			},
+		},
+		{
+			name:    "invalid yaml",
+			content: "tt0000001: [",
+			assertions: func(assertions *assert.Assertions, mappings Mappings, err error) {
+				assertions.ErrorContains(err, "failure parsing mapping file")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "mappings.yaml")
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+			mappings, err := Load(path)
+			tt.assertions(assert.New(t), mappings, err)
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	destination := &mockDestinationClient{}
+	assert.Equal(t, destination, NewClient(destination, nil))
+	assert.IsType(t, &Client{}, NewClient(destination, Mappings{"tt0000001": {Trakt: 1}}))
+}
+
+func TestClient_RatingsAdd(t *testing.T) {
+	destination := &mockDestinationClient{}
+	c := NewClient(destination, Mappings{
+		"tt0000001": {Type: entities.TraktItemTypeMovie, Trakt: 12345},
+		"tt0000002": {Type: entities.TraktItemTypeShow, TMDB: 67890},
+	})
+	items := entities.TraktItems{
+		{Type: entities.TraktItemTypeMovie, Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt0000001"}}},
+		{Type: entities.TraktItemTypeMovie, Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt0000002"}}},
+		{Type: entities.TraktItemTypeMovie, Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt0000003"}}},
+	}
+	assert.NoError(t, c.RatingsAdd(items))
+	assert.Equal(t, entities.TraktIDMeta{Trakt: 12345}, destination.added[0].Movie.IDMeta)
+	assert.Equal(t, entities.TraktIDMeta{IMDb: "tt0000002"}, destination.added[1].Movie.IDMeta)
+	assert.Equal(t, entities.TraktIDMeta{IMDb: "tt0000003"}, destination.added[2].Movie.IDMeta)
+	assert.Equal(t, "tt0000001", items[0].Movie.IDMeta.IMDb)
+}
+
+func TestClient_RatingsAdd_partialError(t *testing.T) {
+	destination := &mockDestinationClient{
+		err: &client.PartialError{
+			Applied: entities.TraktItems{{Type: entities.TraktItemTypeMovie, Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{Trakt: 12345}}}},
+			Err:     errors.New("unexpected status code"),
+		},
+	}
+	c := NewClient(destination, Mappings{"tt0000001": {Type: entities.TraktItemTypeMovie, Trakt: 12345}})
+	items := entities.TraktItems{
+		{Type: entities.TraktItemTypeMovie, Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt0000001"}}},
+		{Type: entities.TraktItemTypeMovie, Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt0000002"}}},
+	}
+	var partialError *client.PartialError
+	assert.ErrorAs(t, c.RatingsAdd(items), &partialError)
+	assert.Equal(t, items[:1], partialError.Applied)
+}
+
+func TestClient_RatingsGet(t *testing.T) {
+	destination := &mockDestinationClient{
+		ratings: entities.TraktItems{
+			{Type: entities.TraktItemTypeMovie, Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt9999999", Trakt: 12345}}},
+			{Type: entities.TraktItemTypeShow, Show: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{TMDB: 67890}}},
+			{Type: entities.TraktItemTypeMovie, Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{TMDB: 67890}}},
+		},
+	}
+	c := NewClient(destination, Mappings{
+		"tt0000001": {Type: entities.TraktItemTypeMovie, Trakt: 12345},
+		"tt0000002": {Type: entities.TraktItemTypeShow, TMDB: 67890},
+	})
+	ratings, err := c.RatingsGet()
+	assert.NoError(t, err)
+	assert.Equal(t, "tt0000001", ratings[0].Movie.IDMeta.IMDb)
+	assert.Equal(t, "tt0000002", ratings[1].Show.IDMeta.IMDb)
+	assert.Empty(t, ratings[2].Movie.IDMeta.IMDb)
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	namespace = "its"
+
+	OutcomeFailure = "failure"
+	OutcomeSuccess = "success"
+)
+
+var (
+	itemsSynced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "items_synced_total",
+		Help:      "Number of items synced per entity and operation.",
+	}, []string{"entity", "operation"})
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "client_requests_total",
+		Help:      "Number of requests sent per client and outcome.",
+	}, []string{"client", "outcome"})
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "client_request_duration_seconds",
+		Help:      "Latency of the requests sent per client.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"client"})
+	errorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "errors_total",
+		Help:      "Number of errors encountered per entity.",
+	}, []string{"entity"})
+	lastSuccessfulSync = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_successful_sync_timestamp_seconds",
+		Help:      "Unix timestamp of the last successful sync.",
+	})
+)
+
+func ObserveItemsSynced(entity, operation string, count int) {
+	itemsSynced.WithLabelValues(entity, operation).Add(float64(count))
+}
+
+func ObserveRequest(client string, duration time.Duration, success bool) {
+	outcome := OutcomeSuccess
+	if !success {
+		outcome = OutcomeFailure
+	}
+	requestsTotal.WithLabelValues(client, outcome).Inc()
+	requestDuration.WithLabelValues(client).Observe(duration.Seconds())
+}
+
+func ObserveError(entity string) {
+	errorsTotal.WithLabelValues(entity).Inc()
+}
+
+func ObserveSuccessfulSync() {
+	lastSuccessfulSync.SetToCurrentTime()
+}
+
+type instrumentedTransport struct {
+	client string
+	next   http.RoundTripper
+}
+
+func InstrumentTransport(client string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &instrumentedTransport{
+		client: client,
+		next:   next,
+	}
+}
+
+func (t *instrumentedTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	start := time.Now()
+	response, err := t.next.RoundTrip(request)
+	ObserveRequest(t.client, time.Since(start), err == nil && response.StatusCode < http.StatusBadRequest)
+	return response, err
+}
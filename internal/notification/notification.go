@@ -0,0 +1,85 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+)
+
+const (
+	telegramQueryKeyChatID = "chat_id"
+)
+
+type Notifier interface {
+	Notify(ctx context.Context, message string) error
+}
+
+// webhookNotifier posts messages as json to a webhook url, using a provider specific payload.
+type webhookNotifier struct {
+	client  *http.Client
+	url     string
+	payload func(message string) any
+}
+
+func NewNotifier(provider, webhookURL string) (Notifier, error) {
+	n := &webhookNotifier{
+		client: &http.Client{
+			Timeout: time.Second * 10,
+		},
+		url: webhookURL,
+	}
+	switch provider {
+	case appconfig.NotificationProviderDiscord:
+		n.payload = func(message string) any {
+			return map[string]string{"content": message}
+		}
+	case appconfig.NotificationProviderSlack:
+		n.payload = func(message string) any {
+			return map[string]string{"text": message}
+		}
+	case appconfig.NotificationProviderTelegram:
+		u, err := url.Parse(webhookURL)
+		if err != nil {
+			return nil, fmt.Errorf("failure parsing telegram url: %w", err)
+		}
+		chatID := u.Query().Get(telegramQueryKeyChatID)
+		if chatID == "" {
+			return nil, fmt.Errorf("telegram url is missing the %s query parameter", telegramQueryKeyChatID)
+		}
+		u.RawQuery = ""
+		n.url = u.String()
+		n.payload = func(message string) any {
+			return map[string]string{"chat_id": chatID, "text": message}
+		}
+	default:
+		return nil, fmt.Errorf("unknown notification provider %s", provider)
+	}
+	return n, nil
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, message string) error {
+	body, err := json.Marshal(n.payload(message))
+	if err != nil {
+		return fmt.Errorf("failure marshalling notification payload: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failure creating notification request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := n.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failure sending notification request: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("notification request returned unexpected status code %d", response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+)
+
+func TestNotifier_Notify(t *testing.T) {
+	type args struct {
+		provider   string
+		query      string
+		statusCode int
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, map[string]string, error, error)
+	}{
+		{
+			name: "successfully notify discord",
+			args: args{
+				provider:   appconfig.NotificationProviderDiscord,
+				statusCode: http.StatusNoContent,
+			},
+			assertions: func(assertions *assert.Assertions, payload map[string]string, newErr, notifyErr error) {
+				assertions.NoError(newErr)
+				assertions.NoError(notifyErr)
+				assertions.Equal("message", payload["content"])
+			},
+		},
+		{
+			name: "successfully notify slack",
+			args: args{
+				provider:   appconfig.NotificationProviderSlack,
+				statusCode: http.StatusOK,
+			},
+			assertions: func(assertions *assert.Assertions, payload map[string]string, newErr, notifyErr error) {
+				assertions.NoError(newErr)
+				assertions.NoError(notifyErr)
+				assertions.Equal("message", payload["text"])
+			},
+		},
+		{
+			name: "successfully notify telegram",
+			args: args{
+				provider:   appconfig.NotificationProviderTelegram,
+				query:      "?chat_id=123",
+				statusCode: http.StatusOK,
+			},
+			assertions: func(assertions *assert.Assertions, payload map[string]string, newErr, notifyErr error) {
+				assertions.NoError(newErr)
+				assertions.NoError(notifyErr)
+				assertions.Equal("message", payload["text"])
+				assertions.Equal("123", payload["chat_id"])
+			},
+		},
+		{
+			name: "failure creating telegram notifier without chat id",
+			args: args{
+				provider: appconfig.NotificationProviderTelegram,
+			},
+			assertions: func(assertions *assert.Assertions, payload map[string]string, newErr, notifyErr error) {
+				assertions.ErrorContains(newErr, "chat_id")
+			},
+		},
+		{
+			name: "failure creating notifier with unknown provider",
+			args: args{
+				provider: "unknown",
+			},
+			assertions: func(assertions *assert.Assertions, payload map[string]string, newErr, notifyErr error) {
+				assertions.ErrorContains(newErr, "unknown notification provider")
+			},
+		},
+		{
+			name: "failure handling unexpected status code",
+			args: args{
+				provider:   appconfig.NotificationProviderSlack,
+				statusCode: http.StatusForbidden,
+			},
+			assertions: func(assertions *assert.Assertions, payload map[string]string, newErr, notifyErr error) {
+				assertions.NoError(newErr)
+				assertions.ErrorContains(notifyErr, "unexpected status code 403")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the handler runs on the goroutine of the server, so it hands the payload over instead of sharing it
+			payloads := make(chan map[string]string, 1)
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var payload map[string]string
+				assert.Empty(t, r.URL.RawQuery)
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+				payloads <- payload
+				w.WriteHeader(tt.args.statusCode)
+			}))
+			defer testServer.Close()
+			notifier, newErr := NewNotifier(tt.args.provider, testServer.URL+tt.args.query)
+			var notifyErr error
+			if newErr == nil {
+				notifyErr = notifier.Notify(context.Background(), "message")
+			}
+			var payload map[string]string
+			select {
+			case payload = <-payloads:
+			default:
+			}
+			tt.assertions(assert.New(t), payload, newErr, notifyErr)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+)
+
+const (
+	smtpSubjectPrefix = "IMDb Trakt Sync: "
+	smtpTimeout       = time.Second * 30
+)
+
+// DetailsNotifier is implemented by the notifiers that have room for the details of a sync next to its summary
+// message, such as the full sync report.
+type DetailsNotifier interface {
+	NotifyDetails(ctx context.Context, message, details string) error
+}
+
+// SMTPOptions configures the mail server that the summary messages are sent through.
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	TLS      string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// smtpNotifier emails the summary message to the recipients, along with the details of the sync.
+type smtpNotifier struct {
+	options   SMTPOptions
+	tlsConfig *tls.Config
+	now       func() time.Time
+}
+
+func NewSMTPNotifier(options SMTPOptions) (Notifier, error) {
+	switch options.TLS {
+	case appconfig.NotificationSMTPTLSImplicit, appconfig.NotificationSMTPTLSNone, appconfig.NotificationSMTPTLSStartTLS:
+	default:
+		return nil, fmt.Errorf("unknown smtp tls mode %s", options.TLS)
+	}
+	if len(options.To) == 0 {
+		return nil, fmt.Errorf("smtp notifier requires at least one recipient")
+	}
+	return &smtpNotifier{
+		options: options,
+		tlsConfig: &tls.Config{
+			ServerName: options.Host,
+			MinVersion: tls.VersionTLS12,
+		},
+		now: time.Now,
+	}, nil
+}
+
+func (n *smtpNotifier) Notify(ctx context.Context, message string) error {
+	return n.NotifyDetails(ctx, message, "")
+}
+
+func (n *smtpNotifier) NotifyDetails(ctx context.Context, message, details string) error {
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+	address := net.JoinHostPort(n.options.Host, strconv.Itoa(n.options.Port))
+	var (
+		conn net.Conn
+		err  error
+	)
+	if n.options.TLS == appconfig.NotificationSMTPTLSImplicit {
+		dialer := &tls.Dialer{Config: n.tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	} else {
+		dialer := &net.Dialer{}
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return fmt.Errorf("failure connecting to smtp server: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, n.options.Host)
+	if err != nil {
+		return fmt.Errorf("failure greeting smtp server: %w", err)
+	}
+	defer c.Close()
+	if n.options.TLS == appconfig.NotificationSMTPTLSStartTLS {
+		if err = c.StartTLS(n.tlsConfig); err != nil {
+			return fmt.Errorf("failure starting tls with smtp server: %w", err)
+		}
+	}
+	if n.options.Username != "" {
+		if err = c.Auth(smtp.PlainAuth("", n.options.Username, n.options.Password, n.options.Host)); err != nil {
+			return fmt.Errorf("failure authenticating with smtp server: %w", err)
+		}
+	}
+	if err = c.Mail(n.options.From); err != nil {
+		return fmt.Errorf("failure setting smtp sender: %w", err)
+	}
+	for _, to := range n.options.To {
+		if err = c.Rcpt(to); err != nil {
+			return fmt.Errorf("failure setting smtp recipient %s: %w", to, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("failure starting smtp message: %w", err)
+	}
+	if _, err = w.Write(n.email(message, details)); err != nil {
+		return fmt.Errorf("failure writing smtp message: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("failure sending smtp message: %w", err)
+	}
+	return c.Quit()
+}
+
+// email builds a plain text email, whose subject is the first sentence of the message, e.g. Sync completed.
+func (n *smtpNotifier) email(message, details string) []byte {
+	subject, _, _ := strings.Cut(message, ". ")
+	if prefix, _, found := strings.Cut(subject, ": "); found {
+		subject = prefix
+	}
+	body := message
+	if details != "" {
+		body += "\n\n" + details
+	}
+	var sb strings.Builder
+	sb.WriteString("From: " + n.options.From + "\r\n")
+	sb.WriteString("To: " + strings.Join(n.options.To, ", ") + "\r\n")
+	sb.WriteString("Subject: " + smtpSubjectPrefix + subject + "\r\n")
+	sb.WriteString("Date: " + n.now().Format(time.RFC1123Z) + "\r\n")
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	sb.WriteString("\r\n")
+	return []byte(sb.String())
+}
@@ -0,0 +1,124 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+)
+
+// smtpServer accepts a single session, and records the commands and the message that it receives.
+type smtpServer struct {
+	listener net.Listener
+	commands []string
+	message  string
+	done     chan struct{}
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &smtpServer{
+		listener: listener,
+		done:     make(chan struct{}),
+	}
+	go s.serve()
+	t.Cleanup(func() {
+		listener.Close()
+	})
+	return s
+}
+
+func (s *smtpServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpServer) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) {
+		_, _ = conn.Write([]byte(line + "\r\n"))
+	}
+	reply("220 localhost ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimRight(line, "\r\n")
+		s.commands = append(s.commands, command)
+		switch verb, _, _ := strings.Cut(command, " "); strings.ToUpper(verb) {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			reply("235 authenticated")
+		case "DATA":
+			reply("354 go ahead")
+			var sb strings.Builder
+			for {
+				line, err = reader.ReadString('\n')
+				if err != nil || line == ".\r\n" {
+					break
+				}
+				sb.WriteString(line)
+			}
+			s.message = sb.String()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func TestSMTPNotifier_NotifyDetails(t *testing.T) {
+	server := newSMTPServer(t)
+	notifier, err := NewSMTPNotifier(SMTPOptions{
+		Host:     "localhost",
+		Port:     server.port(),
+		TLS:      appconfig.NotificationSMTPTLSNone,
+		Username: "user",
+		Password: "password",
+		From:     "sync@example.com",
+		To:       []string{"alice@example.com", "bob@example.com"},
+	})
+	require.NoError(t, err)
+	notifier.(*smtpNotifier).now = func() time.Time {
+		return time.Date(2025, time.January, 2, 3, 4, 5, 0, time.UTC)
+	}
+	err = notifier.(DetailsNotifier).NotifyDetails(context.Background(), "Sync failed: failure adding ratings. Synced 2 ratings", "# Sync report\n\n- **Status:** failed")
+	require.NoError(t, err)
+	<-server.done
+	assert.Contains(t, server.commands, "MAIL FROM:<sync@example.com>")
+	assert.Contains(t, server.commands, "RCPT TO:<alice@example.com>")
+	assert.Contains(t, server.commands, "RCPT TO:<bob@example.com>")
+	assert.True(t, strings.HasPrefix(server.commands[1], "AUTH PLAIN"))
+	assert.Contains(t, server.message, "Subject: IMDb Trakt Sync: Sync failed\r\n")
+	assert.Contains(t, server.message, "To: alice@example.com, bob@example.com\r\n")
+	assert.Contains(t, server.message, "Date: Thu, 02 Jan 2025 03:04:05 +0000\r\n")
+	assert.Contains(t, server.message, "\r\n\r\nSync failed: failure adding ratings. Synced 2 ratings\r\n\r\n# Sync report\r\n\r\n- **Status:** failed\r\n")
+}
+
+func TestNewSMTPNotifier(t *testing.T) {
+	_, err := NewSMTPNotifier(SMTPOptions{Host: "localhost", Port: 587, TLS: "ssl", To: []string{"alice@example.com"}})
+	assert.ErrorContains(t, err, "unknown smtp tls mode")
+	_, err = NewSMTPNotifier(SMTPOptions{Host: "localhost", Port: 587, TLS: appconfig.NotificationSMTPTLSStartTLS})
+	assert.ErrorContains(t, err, "at least one recipient")
+	_, err = NewSMTPNotifier(SMTPOptions{Host: "localhost", Port: 587, TLS: appconfig.NotificationSMTPTLSStartTLS, To: []string{"alice@example.com"}})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,104 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+)
+
+const (
+	barWidth    = 30
+	logInterval = time.Second * 10
+)
+
+// Progress renders progress bars when the output is a terminal, and falls back to logging the progress periodically
+// otherwise, such as when the output is redirected to a file. A nil progress reports nothing.
+type Progress struct {
+	mu       sync.Mutex
+	out      io.Writer
+	tty      bool
+	logger   *slog.Logger
+	interval time.Duration
+}
+
+// New returns a progress that renders bars to the output file if it is a terminal, or logs the progress otherwise.
+func New(out *os.File, logger *slog.Logger) *Progress {
+	return &Progress{
+		out:      out,
+		tty:      term.IsTerminal(out.Fd()),
+		logger:   logger,
+		interval: logInterval,
+	}
+}
+
+// Track starts reporting the progress of a task made of the given number of steps, such as the batches of a request.
+func (p *Progress) Track(name string, total int) *Bar {
+	if p == nil || total <= 0 {
+		return nil
+	}
+	bar := &Bar{
+		progress: p,
+		name:     name,
+		total:    total,
+		logged:   time.Now(),
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	bar.report(false)
+	return bar
+}
+
+// Bar is the progress of a single task. A nil bar ignores every step.
+type Bar struct {
+	progress *Progress
+	name     string
+	total    int
+	current  int
+	logged   time.Time
+}
+
+// Add marks the given number of steps as completed.
+func (b *Bar) Add(n int) {
+	if b == nil {
+		return
+	}
+	b.progress.mu.Lock()
+	defer b.progress.mu.Unlock()
+	b.current = min(b.current+n, b.total)
+	b.report(false)
+}
+
+// Done completes the task, even if some of its steps were skipped.
+func (b *Bar) Done() {
+	if b == nil {
+		return
+	}
+	b.progress.mu.Lock()
+	defer b.progress.mu.Unlock()
+	b.current = b.total
+	b.report(true)
+}
+
+func (b *Bar) report(done bool) {
+	p := b.progress
+	if p.tty {
+		filled := barWidth * b.current / b.total
+		bar := strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled)
+		_, _ = fmt.Fprintf(p.out, "\r%s [%s] %d/%d", b.name, bar, b.current, b.total)
+		if done {
+			_, _ = fmt.Fprintln(p.out)
+		}
+		return
+	}
+	if !done && time.Since(b.logged) < p.interval {
+		return
+	}
+	b.logged = time.Now()
+	p.logger.Info(fmt.Sprintf("progress of %s", b.name), slog.Int("current", b.current), slog.Int("total", b.total))
+}
@@ -0,0 +1,69 @@
+package progress
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress_Track(t *testing.T) {
+	tests := []struct {
+		name       string
+		tty        bool
+		interval   time.Duration
+		assertions func(*assert.Assertions, string, string)
+	}{
+		{
+			name: "render bar on terminal",
+			tty:  true,
+			assertions: func(assertions *assert.Assertions, out string, logs string) {
+				assertions.Contains(out, "\rbatches [                              ] 0/4")
+				assertions.Contains(out, "\rbatches [===============               ] 2/4")
+				assertions.Contains(out, "\rbatches [==============================] 4/4\n")
+				assertions.Empty(logs)
+			},
+		},
+		{
+			name:     "log progress periodically without terminal",
+			interval: time.Hour,
+			assertions: func(assertions *assert.Assertions, out string, logs string) {
+				assertions.Empty(out)
+				assertions.Equal(1, bytes.Count([]byte(logs), []byte("progress of batches")))
+				assertions.Contains(logs, "current=4 total=4")
+			},
+		},
+		{
+			name: "log every step without interval",
+			assertions: func(assertions *assert.Assertions, out string, logs string) {
+				assertions.Equal(4, bytes.Count([]byte(logs), []byte("progress of batches")))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, logs bytes.Buffer
+			p := &Progress{
+				out:      &out,
+				tty:      tt.tty,
+				logger:   slog.New(slog.NewTextHandler(&logs, nil)),
+				interval: tt.interval,
+			}
+			bar := p.Track("batches", 4)
+			bar.Add(2)
+			bar.Add(1)
+			bar.Done()
+			tt.assertions(assert.New(t), out.String(), logs.String())
+		})
+	}
+}
+
+func TestProgress_Nil(t *testing.T) {
+	var p *Progress
+	bar := p.Track("batches", 4)
+	assert.Nil(t, bar)
+	bar.Add(1)
+	bar.Done()
+}
@@ -0,0 +1,68 @@
+package rating
+
+import (
+	"math"
+)
+
+const (
+	RoundingCeil     = "ceil"
+	RoundingFloor    = "floor"
+	RoundingHalfStar = "half-star"
+
+	// Points is the maximum rating of the 10-point scale of imdb and trakt.
+	Points = 10
+	// Stars is the maximum rating of the 5-star scale, where each star is worth two points.
+	Stars = 5
+
+	// precision discards the floating point error of a conversion, so that e.g. 0.7 on a scale of 1 converts to
+	// exactly 7 points instead of rounding up to 8.
+	precision = 1e6
+)
+
+// Roundings returns the rounding policies that decide how a rating that falls between two steps of the target scale
+// is converted.
+func Roundings() []string {
+	return []string{
+		RoundingCeil,
+		RoundingFloor,
+		RoundingHalfStar,
+	}
+}
+
+// ToPoints converts a rating on a scale from 0 to scale, such as 5 stars or 100 points, to the 10-point scale. A rating
+// between two points is rounded down by floor, up by ceil, and to the nearest point by half-star, since a point is half
+// a star. A rating that is not positive is unrated and converts to 0, while any other rating converts to at least 1.
+func ToPoints(value, scale float64, rounding string) int {
+	if value <= 0 || scale <= 0 {
+		return 0
+	}
+	return int(min(max(round(value*Points/scale, 1, rounding), 1), Points))
+}
+
+// ToStars converts a rating on the 10-point scale to the 5-star scale. The half-star policy keeps half stars, which
+// converts each point exactly, while floor and ceil round odd points down or up to whole stars. A rating that is not
+// positive is unrated and converts to 0, while any other rating converts to at least the lowest step of the policy.
+func ToStars(points int, rounding string) float64 {
+	if points <= 0 {
+		return 0
+	}
+	step := 1.0
+	if rounding != RoundingFloor && rounding != RoundingCeil {
+		step = 0.5
+	}
+	return min(max(round(float64(points)*Stars/Points, step, rounding), step), Stars)
+}
+
+// round rounds the value to a multiple of the step, following the rounding policy.
+func round(value, step float64, rounding string) float64 {
+	steps := math.Round(value/step*precision) / precision
+	switch rounding {
+	case RoundingFloor:
+		steps = math.Floor(steps)
+	case RoundingCeil:
+		steps = math.Ceil(steps)
+	default:
+		steps = math.Round(steps)
+	}
+	return steps * step
+}
@@ -0,0 +1,61 @@
+package rating
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToStars(t *testing.T) {
+	// each row converts the points from 1 to 10
+	tests := map[string][Points]float64{
+		RoundingHalfStar: {0.5, 1, 1.5, 2, 2.5, 3, 3.5, 4, 4.5, 5},
+		RoundingFloor:    {1, 1, 1, 2, 2, 3, 3, 4, 4, 5},
+		RoundingCeil:     {1, 1, 2, 2, 3, 3, 4, 4, 5, 5},
+	}
+	for rounding, stars := range tests {
+		for i, expected := range stars {
+			points := i + 1
+			t.Run(fmt.Sprintf("%s %d points", rounding, points), func(t *testing.T) {
+				assert.Equal(t, expected, ToStars(points, rounding))
+			})
+		}
+	}
+	assert.Zero(t, ToStars(0, RoundingHalfStar))
+}
+
+func TestToPoints(t *testing.T) {
+	// each row converts the stars from 0.5 to 5 in half star steps, which are exact on the 10-point scale
+	for _, rounding := range Roundings() {
+		for points := 1; points <= Points; points++ {
+			stars := float64(points) / 2
+			t.Run(fmt.Sprintf("%s %.1f stars", rounding, stars), func(t *testing.T) {
+				assert.Equal(t, points, ToPoints(stars, Stars, rounding))
+			})
+		}
+	}
+	tests := []struct {
+		value    float64
+		scale    float64
+		rounding string
+		expected int
+	}{
+		{value: 3.3, scale: Stars, rounding: RoundingHalfStar, expected: 7},
+		{value: 3.3, scale: Stars, rounding: RoundingFloor, expected: 6},
+		{value: 3.3, scale: Stars, rounding: RoundingCeil, expected: 7},
+		{value: 7.5, scale: Points, rounding: RoundingHalfStar, expected: 8},
+		{value: 7.5, scale: Points, rounding: RoundingFloor, expected: 7},
+		{value: 7.5, scale: Points, rounding: RoundingCeil, expected: 8},
+		{value: 0.7, scale: 1, rounding: RoundingCeil, expected: 7},
+		{value: 85, scale: 100, rounding: RoundingFloor, expected: 8},
+		{value: 3, scale: 100, rounding: RoundingFloor, expected: 1},
+		{value: 0, scale: Points, rounding: RoundingCeil, expected: 0},
+		{value: 12, scale: Points, rounding: RoundingHalfStar, expected: 10},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %v of %v", tt.rounding, tt.value, tt.scale), func(t *testing.T) {
+			assert.Equal(t, tt.expected, ToPoints(tt.value, tt.scale, tt.rounding))
+		})
+	}
+}
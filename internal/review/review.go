@@ -0,0 +1,180 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+const (
+	helpMessage = "\n—— ↑/↓ move —— SPACE toggle —— A toggle all —— ENTER confirm —— ESC abort ——\n"
+	pageSize    = 15
+)
+
+var (
+	focusedStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
+		Light: "#6200EE",
+		Dark:  "#BB86FC",
+	})
+	rejectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Strikethrough(true)
+	helpStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+)
+
+// Model lists the items of a change and lets the user approve or reject each of them. All items are approved initially.
+type Model struct {
+	change   syncer.Change
+	cursor   int
+	approved []bool
+	err      error
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	message, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch message.String() {
+	case "esc", "ctrl+c":
+		m.err = config.ErrUserAborted
+		return m, tea.Quit
+	case "enter":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.approved)-1 {
+			m.cursor++
+		}
+	case " ":
+		m.approved[m.cursor] = !m.approved[m.cursor]
+	case "a":
+		approve := !m.allApproved()
+		for i := range m.approved {
+			m.approved[i] = approve
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) View() string {
+	var sb strings.Builder
+	sb.WriteString(m.header() + "\n\n")
+	start := max(0, min(m.cursor-pageSize/2, len(m.approved)-pageSize))
+	end := min(len(m.approved), start+pageSize)
+	for i := start; i < end; i++ {
+		checkbox := "[ ]"
+		if m.approved[i] {
+			checkbox = "[x]"
+		}
+		line := checkbox + " " + describe(m.change.Items[i])
+		if !m.approved[i] {
+			line = rejectedStyle.Render(line)
+		}
+		if i == m.cursor {
+			sb.WriteString(focusedStyle.Render("> ") + line + "\n")
+			continue
+		}
+		sb.WriteString("  " + line + "\n")
+	}
+	sb.WriteString(helpStyle.Render(helpMessage))
+	return sb.String()
+}
+
+func (m *Model) Err() error {
+	return m.err
+}
+
+// Approved returns the items that were approved by the user.
+func (m *Model) Approved() entities.TraktItems {
+	items := make(entities.TraktItems, 0, len(m.approved))
+	for i, approved := range m.approved {
+		if approved {
+			items = append(items, m.change.Items[i])
+		}
+	}
+	return items
+}
+
+func (m *Model) header() string {
+	if m.change.Operation == "remove" {
+		return fmt.Sprintf("Remove %d item(s) from %s (%d approved)", len(m.approved), m.change.Target, m.approvedCount())
+	}
+	return fmt.Sprintf("Add %d item(s) to %s (%d approved)", len(m.approved), m.change.Target, m.approvedCount())
+}
+
+func (m *Model) allApproved() bool {
+	return m.approvedCount() == len(m.approved)
+}
+
+func (m *Model) approvedCount() int {
+	var count int
+	for _, approved := range m.approved {
+		if approved {
+			count++
+		}
+	}
+	return count
+}
+
+func NewTeaProgram(change syncer.Change, opts ...tea.ProgramOption) *tea.Program {
+	return tea.NewProgram(newModel(change), opts...)
+}
+
+func newModel(change syncer.Change) *Model {
+	approved := make([]bool, len(change.Items))
+	for i := range approved {
+		approved[i] = true
+	}
+	return &Model{
+		change:   change,
+		approved: approved,
+	}
+}
+
+// NewReviewer returns a reviewer that prompts the user to approve the items of each change in the terminal.
+func NewReviewer(opts ...tea.ProgramOption) syncer.Reviewer {
+	return func(change syncer.Change) (entities.TraktItems, error) {
+		teaModel, err := NewTeaProgram(change, opts...).Run()
+		if err != nil {
+			return nil, fmt.Errorf("failure running text-based user interface: %w", err)
+		}
+		model := teaModel.(*Model)
+		if err = model.Err(); err != nil {
+			return nil, err
+		}
+		return model.Approved(), nil
+	}
+}
+
+func describe(item entities.TraktItem) string {
+	spec := item.Spec()
+	if spec == nil {
+		return item.Type
+	}
+	var sb strings.Builder
+	sb.WriteString(item.Type + " " + spec.IDMeta.IMDb)
+	if spec.Title != "" {
+		sb.WriteString(" " + spec.Title)
+		if spec.Year != 0 {
+			sb.WriteString(fmt.Sprintf(" (%d)", spec.Year))
+		}
+	}
+	if spec.Rating != nil {
+		sb.WriteString(fmt.Sprintf(" rated %d", *spec.Rating))
+	} else if item.Rating != 0 {
+		sb.WriteString(fmt.Sprintf(" rated %d", item.Rating))
+	}
+	return sb.String()
+}
@@ -0,0 +1,122 @@
+package review
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
+)
+
+func buildTestModel() *Model {
+	rating := 8
+	return newModel(syncer.Change{
+		Entity:    "ratings",
+		Operation: "add",
+		Target:    "ratings",
+		Items: entities.TraktItems{
+			{
+				Type: entities.TraktItemTypeMovie,
+				Movie: entities.TraktItemSpec{
+					IDMeta: entities.TraktIDMeta{IMDb: "tt5013056"},
+					Title:  "Dunkirk",
+					Year:   2017,
+					Rating: &rating,
+				},
+			},
+			{
+				Type: entities.TraktItemTypeShow,
+				Show: entities.TraktItemSpec{
+					IDMeta: entities.TraktIDMeta{IMDb: "tt0903747"},
+				},
+			},
+		},
+	})
+}
+
+func TestModel_Update(t *testing.T) {
+	tests := []struct {
+		name       string
+		keys       []tea.KeyMsg
+		assertions func(*assert.Assertions, *Model, tea.Cmd)
+	}{
+		{
+			name: "confirm without changes approves all items",
+			keys: []tea.KeyMsg{
+				{Type: tea.KeyEnter},
+			},
+			assertions: func(a *assert.Assertions, m *Model, cmd tea.Cmd) {
+				a.NoError(m.Err())
+				a.Equal(2, len(m.Approved()))
+				a.IsType(tea.QuitMsg{}, cmd())
+			},
+		},
+		{
+			name: "reject the focused item",
+			keys: []tea.KeyMsg{
+				{Type: tea.KeyDown},
+				{Type: tea.KeySpace, Runes: []rune{' '}},
+				{Type: tea.KeyEnter},
+			},
+			assertions: func(a *assert.Assertions, m *Model, cmd tea.Cmd) {
+				a.Equal(1, len(m.Approved()))
+				a.Equal("tt5013056", m.Approved()[0].Movie.IDMeta.IMDb)
+			},
+		},
+		{
+			name: "toggle all items",
+			keys: []tea.KeyMsg{
+				{Type: tea.KeyRunes, Runes: []rune{'a'}},
+			},
+			assertions: func(a *assert.Assertions, m *Model, cmd tea.Cmd) {
+				a.Empty(m.Approved())
+				a.Nil(cmd)
+			},
+		},
+		{
+			name: "cursor stays within the items",
+			keys: []tea.KeyMsg{
+				{Type: tea.KeyUp},
+				{Type: tea.KeyDown},
+				{Type: tea.KeyDown},
+				{Type: tea.KeyDown},
+			},
+			assertions: func(a *assert.Assertions, m *Model, cmd tea.Cmd) {
+				a.Equal(1, m.cursor)
+			},
+		},
+		{
+			name: "escape button pressed",
+			keys: []tea.KeyMsg{
+				{Type: tea.KeyEsc},
+			},
+			assertions: func(a *assert.Assertions, m *Model, cmd tea.Cmd) {
+				a.ErrorIs(m.Err(), config.ErrUserAborted)
+				a.IsType(tea.QuitMsg{}, cmd())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := buildTestModel()
+			var cmd tea.Cmd
+			for _, key := range tt.keys {
+				_, cmd = m.Update(key)
+			}
+			tt.assertions(assert.New(t), m, cmd)
+		})
+	}
+}
+
+func TestModel_View(t *testing.T) {
+	m := buildTestModel()
+	m.approved[1] = false
+	view := m.View()
+	a := assert.New(t)
+	a.Contains(view, "Add 2 item(s) to ratings (1 approved)")
+	a.Contains(view, "[x] movie tt5013056 Dunkirk (2017) rated 8")
+	a.Contains(view, "[ ] show tt0903747")
+}
@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+type Schedule interface {
+	Next(time.Time) time.Time
+}
+
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+func NewIntervalSchedule(interval time.Duration) (Schedule, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be a positive duration, but got %s", interval)
+	}
+	return intervalSchedule{
+		interval: interval,
+	}, nil
+}
+
+func NewCronSchedule(expression string) (Schedule, error) {
+	schedule, err := cron.ParseStandard(expression)
+	if err != nil {
+		return nil, fmt.Errorf("failure parsing cron expression %q: %w", expression, err)
+	}
+	return schedule, nil
+}
+
+type Job func(ctx context.Context) error
+
+type Scheduler struct {
+	schedule   Schedule
+	jitter     time.Duration
+	logger     *slog.Logger
+	trigger    chan struct{}
+	reschedule chan struct{}
+	mu         sync.Mutex
+}
+
+func NewScheduler(schedule Schedule, jitter time.Duration, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		schedule:   schedule,
+		jitter:     jitter,
+		logger:     logger,
+		trigger:    make(chan struct{}, 1),
+		reschedule: make(chan struct{}, 1),
+	}
+}
+
+// SetSchedule replaces the schedule of the job. The next execution is rescheduled right away when the job is waiting,
+// or once the job completes otherwise.
+func (s *Scheduler) SetSchedule(schedule Schedule) {
+	s.mu.Lock()
+	s.schedule = schedule
+	s.mu.Unlock()
+	select {
+	case s.reschedule <- struct{}{}:
+	default:
+	}
+}
+
+// Trigger requests the job to be executed immediately, instead of waiting for the next tick of the schedule. When the
+// job is running, it is executed again once it completes. It returns false if a triggered execution is already pending.
+func (s *Scheduler) Trigger() bool {
+	select {
+	case s.trigger <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Run executes the job once immediately and then on every tick of the schedule or trigger, until the context is
+// cancelled. Job failures are logged and do not stop the scheduler.
+func (s *Scheduler) Run(ctx context.Context, job Job) error {
+	s.logger.Info("daemon started")
+	for {
+		if err := job(ctx); err != nil {
+			s.logger.Error("failure running scheduled job", logger.Error(err))
+		}
+		if !s.wait(ctx) {
+			s.logger.Info("daemon stopped")
+			return nil
+		}
+	}
+}
+
+// wait blocks until the next tick of the schedule or trigger, and returns false if the context was cancelled instead.
+func (s *Scheduler) wait(ctx context.Context) bool {
+	timer := time.NewTimer(time.Until(s.next()))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-s.trigger:
+			s.logger.Info("sync triggered")
+			return true
+		case <-s.reschedule:
+			timer.Reset(time.Until(s.next()))
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+func (s *Scheduler) next() time.Time {
+	s.mu.Lock()
+	schedule := s.schedule
+	s.mu.Unlock()
+	next := schedule.Next(time.Now()).Add(s.randomJitter())
+	s.logger.Info("scheduled next sync", slog.Time("at", next))
+	return next
+}
+
+func (s *Scheduler) randomJitter() time.Duration {
+	if s.jitter <= 0 {
+		return 0
+	}
+	return rand.N(s.jitter)
+}
@@ -0,0 +1,155 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+func TestNewIntervalSchedule(t *testing.T) {
+	type args struct {
+		interval time.Duration
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, Schedule, error)
+	}{
+		{
+			name: "success",
+			args: args{
+				interval: time.Hour,
+			},
+			assertions: func(assertions *assert.Assertions, schedule Schedule, err error) {
+				assertions.Nil(err)
+				now := time.Now()
+				assertions.Equal(now.Add(time.Hour), schedule.Next(now))
+			},
+		},
+		{
+			name: "failure with non-positive interval",
+			args: args{
+				interval: 0,
+			},
+			assertions: func(assertions *assert.Assertions, schedule Schedule, err error) {
+				assertions.Nil(schedule)
+				assertions.ErrorContains(err, "positive duration")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := NewIntervalSchedule(tt.args.interval)
+			tt.assertions(assert.New(t), schedule, err)
+		})
+	}
+}
+
+func TestNewCronSchedule(t *testing.T) {
+	type args struct {
+		expression string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, Schedule, error)
+	}{
+		{
+			name: "success",
+			args: args{
+				expression: "0 */6 * * *",
+			},
+			assertions: func(assertions *assert.Assertions, schedule Schedule, err error) {
+				assertions.Nil(err)
+				from := time.Date(2024, time.January, 1, 1, 30, 0, 0, time.UTC)
+				assertions.Equal(time.Date(2024, time.January, 1, 6, 0, 0, 0, time.UTC), schedule.Next(from))
+			},
+		},
+		{
+			name: "failure parsing expression",
+			args: args{
+				expression: "invalid",
+			},
+			assertions: func(assertions *assert.Assertions, schedule Schedule, err error) {
+				assertions.Nil(schedule)
+				assertions.ErrorContains(err, "failure parsing cron expression")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := NewCronSchedule(tt.args.expression)
+			tt.assertions(assert.New(t), schedule, err)
+		})
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		triggers   int
+		assertions func(*assert.Assertions, int, error)
+	}{
+		{
+			name: "stops when context is cancelled",
+			assertions: func(assertions *assert.Assertions, runs int, err error) {
+				assertions.Nil(err)
+				assertions.Equal(1, runs)
+			},
+		},
+		{
+			name:     "runs again when triggered",
+			triggers: 1,
+			assertions: func(assertions *assert.Assertions, runs int, err error) {
+				assertions.Nil(err)
+				assertions.Equal(2, runs)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			schedule, err := NewIntervalSchedule(time.Hour)
+			assert.Nil(t, err)
+			var runs int
+			s := NewScheduler(schedule, time.Minute, logger.NewLogger(io.Discard))
+			err = s.Run(ctx, func(context.Context) error {
+				runs++
+				if runs <= tt.triggers {
+					s.Trigger()
+					return nil
+				}
+				cancel()
+				return nil
+			})
+			tt.assertions(assert.New(t), runs, err)
+		})
+	}
+}
+
+func TestScheduler_SetSchedule(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	schedule, err := NewIntervalSchedule(time.Hour)
+	assert.Nil(t, err)
+	rescheduled, err := NewIntervalSchedule(time.Millisecond)
+	assert.Nil(t, err)
+	var runs int
+	s := NewScheduler(schedule, 0, logger.NewLogger(io.Discard))
+	err = s.Run(ctx, func(context.Context) error {
+		runs++
+		if runs == 1 {
+			s.SetSchedule(rescheduled)
+			return nil
+		}
+		cancel()
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, runs)
+}
@@ -0,0 +1,310 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/mapping"
+	"github.com/cecobask/imdb-trakt-sync/internal/progress"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+const (
+	stateKey      = "trakt-search-matches"
+	maxCandidates = 5
+)
+
+// Searcher finds the titles of a type by their title, which trakt supports on top of looking titles up by their id.
+type Searcher interface {
+	Search(itemType, query string, year int) ([]entities.TraktSearchResult, error)
+}
+
+// Candidate is a title that the search found for an item without being confident enough to sync the item as it, which
+// is reported so that the item can be mapped manually.
+type Candidate struct {
+	Trakt int64  `json:"trakt"`
+	Title string `json:"title"`
+	Year  int    `json:"year,omitempty"`
+}
+
+type title struct {
+	name string
+	year int
+}
+
+// Load returns the matches that the search found by previous syncs, keyed by imdb id.
+func Load(store state.Store) (mapping.Mappings, error) {
+	matches := make(mapping.Mappings)
+	if err := store.Load(stateKey, &matches); err != nil && !errors.Is(err, state.ErrNotFound) {
+		return nil, fmt.Errorf("failure loading trakt search matches: %w", err)
+	}
+	return matches, nil
+}
+
+// Save replaces the matches that the search found.
+func Save(store state.Store, matches mapping.Mappings) error {
+	return store.Save(stateKey, matches)
+}
+
+// Client wraps a destination client and looks up the movies and shows that the destination could not find by their
+// imdb id with a search by title and year instead. Items with a single exact match are synced as that title, and the
+// match is kept, so that later syncs identify the item by it. Items with several or inexact matches are left not found,
+// and their candidates are reported for manual mapping.
+type Client struct {
+	client.DestinationClientInterface
+	searcher   Searcher
+	logger     *slog.Logger
+	titles     map[string]title
+	matches    mapping.Mappings
+	candidates map[string][]Candidate
+	searched   map[string]struct{}
+	notFound   entities.TraktItems
+	mu         sync.Mutex
+}
+
+// NewClient wraps the destination with the search of the searcher, starting from the matches of previous syncs.
+func NewClient(destination client.DestinationClientInterface, searcher Searcher, matches mapping.Mappings, log *slog.Logger) *Client {
+	if matches == nil {
+		matches = make(mapping.Mappings)
+	}
+	return &Client{
+		DestinationClientInterface: destination,
+		searcher:                   searcher,
+		logger:                     log,
+		titles:                     make(map[string]title),
+		matches:                    matches,
+		candidates:                 make(map[string][]Candidate),
+		searched:                   make(map[string]struct{}),
+	}
+}
+
+// SetProgress forwards the progress to the wrapped destination, if it reports any.
+func (c *Client) SetProgress(p *progress.Progress) {
+	if destination, ok := c.DestinationClientInterface.(interface{ SetProgress(*progress.Progress) }); ok {
+		destination.SetProgress(p)
+	}
+}
+
+// Remember keeps the title and year of the imdb items, which the items are searched by when they are not found.
+func (c *Client) Remember(items ...entities.IMDbItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, item := range items {
+		if item.Title == "" {
+			continue
+		}
+		t := title{
+			name: item.Title,
+		}
+		if item.Year != nil {
+			t.year = *item.Year
+		}
+		c.titles[item.ID] = t
+	}
+}
+
+// Matches returns the matches of previous syncs, along with the matches that were found since.
+func (c *Client) Matches() mapping.Mappings {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	matches := make(mapping.Mappings, len(c.matches))
+	for imdbID, match := range c.matches {
+		matches[imdbID] = match
+	}
+	return matches
+}
+
+// Candidates returns the titles that the search found for an item that is still not found.
+func (c *Client) Candidates(imdbID string) []Candidate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.candidates[imdbID]
+}
+
+// NotFound returns the items that neither the destination nor the search could find.
+func (c *Client) NotFound() entities.TraktItems {
+	notFound := c.DestinationClientInterface.NotFound()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	notFound = append(c.notFound, notFound...)
+	c.notFound = nil
+	return notFound
+}
+
+// resolve searches the items that the destination could not find, and adds the items that were matched once more,
+// identified by the trakt id of their match. Only the items that were just sent are resolved, so that items which
+// are not found while syncing another target concurrently are never added to the wrong target.
+func (c *Client) resolve(items entities.TraktItems, add func(entities.TraktItems) error) error {
+	notFound := c.DestinationClientInterface.NotFound()
+	if len(notFound) == 0 {
+		return nil
+	}
+	sent := make(map[string]entities.TraktItem, len(items))
+	for _, item := range items {
+		if id, err := item.GetItemID(); err == nil && id != nil && *id != "" {
+			sent[*id] = item
+		}
+	}
+	var resolved, unresolved entities.TraktItems
+	for _, item := range notFound {
+		id, err := item.GetItemID()
+		if err != nil || id == nil {
+			unresolved = append(unresolved, item)
+			continue
+		}
+		original, found := sent[*id]
+		if !found {
+			unresolved = append(unresolved, item)
+			continue
+		}
+		traktID, matched := c.match(original.Type, *id)
+		if !matched {
+			unresolved = append(unresolved, item)
+			continue
+		}
+		if spec := original.Spec(); spec != nil {
+			spec.IDMeta = entities.TraktIDMeta{
+				Trakt: traktID,
+			}
+		}
+		resolved = append(resolved, original)
+	}
+	c.mu.Lock()
+	c.notFound = append(c.notFound, unresolved...)
+	c.mu.Unlock()
+	if len(resolved) == 0 {
+		return nil
+	}
+	if err := add(resolved); err != nil {
+		return fmt.Errorf("failure adding items matched by title: %w", err)
+	}
+	return nil
+}
+
+// match returns the trakt id of the title that the item was matched with, searching for the item unless it was
+// matched or searched before. A match has to be the only result of the same type with the same title and year.
+func (c *Client) match(itemType, imdbID string) (int64, bool) {
+	if itemType != entities.TraktItemTypeMovie && itemType != entities.TraktItemTypeShow {
+		return 0, false
+	}
+	c.mu.Lock()
+	if match, found := c.matches[imdbID]; found && match.Type == itemType && match.Trakt != 0 {
+		c.mu.Unlock()
+		return match.Trakt, true
+	}
+	t, found := c.titles[imdbID]
+	_, searched := c.searched[imdbID]
+	c.searched[imdbID] = struct{}{}
+	c.mu.Unlock()
+	if !found || searched {
+		return 0, false
+	}
+	results, err := c.searcher.Search(itemType, t.name, t.year)
+	if err != nil {
+		c.logger.Warn(fmt.Sprintf("failure searching trakt for %s %s", itemType, imdbID), logger.Error(err))
+		return 0, false
+	}
+	var exact []int64
+	candidates := make([]Candidate, 0, maxCandidates)
+	for _, result := range results {
+		spec := result.Spec()
+		if spec == nil || result.Type != itemType || spec.IDMeta.Trakt == 0 {
+			continue
+		}
+		if normalize(spec.Title) == normalize(t.name) && (t.year == 0 || spec.Year == t.year) {
+			exact = append(exact, spec.IDMeta.Trakt)
+		}
+		if len(candidates) < maxCandidates {
+			candidates = append(candidates, Candidate{
+				Trakt: spec.IDMeta.Trakt,
+				Title: spec.Title,
+				Year:  spec.Year,
+			})
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(exact) == 1 {
+		c.matches[imdbID] = mapping.Mapping{
+			Type:  itemType,
+			Trakt: exact[0],
+		}
+		c.logger.Info(fmt.Sprintf("matched %s %s to trakt id %d by its title", itemType, imdbID, exact[0]), slog.String("title", t.name))
+		return exact[0], true
+	}
+	if len(candidates) > 0 {
+		c.candidates[imdbID] = candidates
+		c.logger.Warn(fmt.Sprintf("found %d ambiguous trakt match(es) for %s %s, which has to be mapped manually", len(candidates), itemType, imdbID), slog.String("title", t.name))
+	}
+	return 0, false
+}
+
+// normalize leaves out the case, punctuation and spacing of a title, which the titles of imdb and trakt differ in.
+func normalize(t string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, t)
+}
+
+func (c *Client) WatchlistItemsAdd(items entities.TraktItems) error {
+	if err := c.DestinationClientInterface.WatchlistItemsAdd(items); err != nil {
+		return err
+	}
+	return c.resolve(items, c.DestinationClientInterface.WatchlistItemsAdd)
+}
+
+func (c *Client) ListItemsAdd(listID string, items entities.TraktItems) error {
+	if err := c.DestinationClientInterface.ListItemsAdd(listID, items); err != nil {
+		return err
+	}
+	return c.resolve(items, func(resolved entities.TraktItems) error {
+		return c.DestinationClientInterface.ListItemsAdd(listID, resolved)
+	})
+}
+
+func (c *Client) RatingsAdd(items entities.TraktItems) error {
+	if err := c.DestinationClientInterface.RatingsAdd(items); err != nil {
+		return err
+	}
+	return c.resolve(items, c.DestinationClientInterface.RatingsAdd)
+}
+
+func (c *Client) HistoryAdd(items entities.TraktItems) error {
+	if err := c.DestinationClientInterface.HistoryAdd(items); err != nil {
+		return err
+	}
+	return c.resolve(items, c.DestinationClientInterface.HistoryAdd)
+}
+
+func (c *Client) HiddenAdd(section string, items entities.TraktItems) error {
+	if err := c.DestinationClientInterface.HiddenAdd(section, items); err != nil {
+		return err
+	}
+	return c.resolve(items, func(resolved entities.TraktItems) error {
+		return c.DestinationClientInterface.HiddenAdd(section, resolved)
+	})
+}
+
+func (c *Client) CollectionAdd(items entities.TraktItems) error {
+	if err := c.DestinationClientInterface.CollectionAdd(items); err != nil {
+		return err
+	}
+	return c.resolve(items, c.DestinationClientInterface.CollectionAdd)
+}
+
+func (c *Client) FavoritesAdd(items entities.TraktItems) error {
+	if err := c.DestinationClientInterface.FavoritesAdd(items); err != nil {
+		return err
+	}
+	return c.resolve(items, c.DestinationClientInterface.FavoritesAdd)
+}
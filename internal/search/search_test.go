@@ -0,0 +1,107 @@
+package search
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/mapping"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+type mockDestinationClient struct {
+	client.DestinationClientInterface
+	added    []entities.TraktItems
+	notFound entities.TraktItems
+}
+
+func (m *mockDestinationClient) RatingsAdd(items entities.TraktItems) error {
+	m.added = append(m.added, items)
+	var notFound entities.TraktItems
+	for _, item := range items {
+		if item.Movie.IDMeta.Trakt == 0 && item.Movie.IDMeta.IMDb != "tt0000001" {
+			notFound = append(notFound, item)
+		}
+	}
+	m.notFound = append(m.notFound, notFound...)
+	return nil
+}
+
+func (m *mockDestinationClient) NotFound() entities.TraktItems {
+	notFound := m.notFound
+	m.notFound = nil
+	return notFound
+}
+
+type mockSearcher struct {
+	queries []string
+	results map[string][]entities.TraktSearchResult
+}
+
+func (m *mockSearcher) Search(_, query string, _ int) ([]entities.TraktSearchResult, error) {
+	m.queries = append(m.queries, query)
+	return m.results[query], nil
+}
+
+func movie(imdbID string) entities.TraktItem {
+	return entities.TraktItem{
+		Type:  entities.TraktItemTypeMovie,
+		Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: imdbID}},
+	}
+}
+
+func result(trakt int64, title string, year int) entities.TraktSearchResult {
+	return entities.TraktSearchResult{
+		Type:  entities.TraktItemTypeMovie,
+		Movie: entities.TraktItemSpec{Title: title, Year: year, IDMeta: entities.TraktIDMeta{Trakt: trakt}},
+	}
+}
+
+func TestClient(t *testing.T) {
+	year := 2001
+	destination := &mockDestinationClient{}
+	searcher := &mockSearcher{
+		results: map[string][]entities.TraktSearchResult{
+			"Exact":     {result(2, "exact!", 2001), result(3, "Exact Sequel", 2001)},
+			"Ambiguous": {result(4, "Ambiguous", 2001), result(5, "Ambiguous", 2001)},
+		},
+	}
+	c := NewClient(destination, searcher, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	c.Remember(
+		entities.IMDbItem{ID: "tt0000002", Title: "Exact", Year: &year},
+		entities.IMDbItem{ID: "tt0000003", Title: "Ambiguous", Year: &year},
+	)
+
+	assert.NoError(t, c.RatingsAdd(entities.TraktItems{movie("tt0000001"), movie("tt0000002"), movie("tt0000003"), movie("tt0000004")}))
+	assert.Len(t, destination.added, 2)
+	matched := destination.added[1]
+	assert.Len(t, matched, 1)
+	assert.Equal(t, int64(2), matched[0].Movie.IDMeta.Trakt)
+
+	notFound := c.NotFound()
+	assert.Len(t, notFound, 2)
+	assert.Equal(t, mapping.Mappings{"tt0000002": {Type: entities.TraktItemTypeMovie, Trakt: 2}}, c.Matches())
+	assert.Len(t, c.Candidates("tt0000003"), 2)
+	assert.Empty(t, c.Candidates("tt0000004"))
+
+	// items are searched once per sync
+	assert.NoError(t, c.RatingsAdd(entities.TraktItems{movie("tt0000003")}))
+	assert.Equal(t, []string{"Exact", "Ambiguous"}, searcher.queries)
+}
+
+func TestLoad(t *testing.T) {
+	store, err := state.NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	matches, err := Load(store)
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+	matches["tt0000002"] = mapping.Mapping{Type: entities.TraktItemTypeMovie, Trakt: 2}
+	assert.NoError(t, Save(store, matches))
+	loaded, err := Load(store)
+	assert.NoError(t, err)
+	assert.Equal(t, matches, loaded)
+}
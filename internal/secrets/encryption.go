@@ -0,0 +1,189 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+const (
+	EncryptionMethodAge        = "age"
+	EncryptionMethodPassphrase = "passphrase"
+
+	encryptedPrefix      = "enc:"
+	passphraseIterations = 600000
+	passphraseSaltSize   = 16
+	passphraseKeySize    = 32
+)
+
+// EncryptionOptions holds the keys that config values are encrypted and decrypted with. The passphrase is used for
+// both, while age encrypts to the recipient and decrypts with the identity file.
+type EncryptionOptions struct {
+	Passphrase   string
+	AgeIdentity  string
+	AgeRecipient string
+}
+
+// inputRunner executes a command with the input on its standard input, and returns its standard output.
+type inputRunner func(input []byte, name string, args ...string) ([]byte, error)
+
+// Cipher encrypts single config values, such as passwords, so that a config file can be shared or committed without
+// leaking its credentials. Encrypted values have the form enc:method:ciphertext, and are decrypted when the config is
+// loaded.
+type Cipher struct {
+	opts EncryptionOptions
+	run  inputRunner
+	mu   sync.Mutex
+	salt []byte
+	keys map[string][]byte
+}
+
+func NewCipher(opts EncryptionOptions) *Cipher {
+	return &Cipher{
+		opts: opts,
+		run:  execInputRunner,
+		keys: make(map[string][]byte),
+	}
+}
+
+// IsEncrypted reports whether the value was encrypted by a cipher.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+func (c *Cipher) Encrypt(method, plaintext string) (string, error) {
+	var (
+		ciphertext []byte
+		err        error
+	)
+	switch method {
+	case EncryptionMethodPassphrase:
+		ciphertext, err = c.passphraseEncrypt([]byte(plaintext))
+	case EncryptionMethodAge:
+		if c.opts.AgeRecipient == "" {
+			return "", fmt.Errorf("age encryption requires a recipient")
+		}
+		ciphertext, err = c.run([]byte(plaintext), "age", "--encrypt", "--recipient", c.opts.AgeRecipient)
+	default:
+		return "", fmt.Errorf("unknown encryption method %s", method)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failure encrypting value with %s: %w", method, err)
+	}
+	return encryptedPrefix + method + ":" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func (c *Cipher) Decrypt(value string) (string, error) {
+	method, encoded, found := strings.Cut(strings.TrimPrefix(value, encryptedPrefix), ":")
+	if !IsEncrypted(value) || !found {
+		return "", fmt.Errorf("value is not encrypted")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failure decoding encrypted value: %w", err)
+	}
+	var plaintext []byte
+	switch method {
+	case EncryptionMethodPassphrase:
+		plaintext, err = c.passphraseDecrypt(ciphertext)
+	case EncryptionMethodAge:
+		if c.opts.AgeIdentity == "" {
+			return "", fmt.Errorf("age decryption requires an identity file")
+		}
+		plaintext, err = c.run(ciphertext, "age", "--decrypt", "--identity", c.opts.AgeIdentity)
+	default:
+		return "", fmt.Errorf("unknown encryption method %s", method)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failure decrypting value with %s: %w", method, err)
+	}
+	return string(plaintext), nil
+}
+
+// passphraseEncrypt seals the plaintext with a key derived from the passphrase, and prepends the salt of the key and
+// the nonce. The values encrypted by the same cipher share the salt, so that the costly key derivation runs once.
+func (c *Cipher) passphraseEncrypt(plaintext []byte) ([]byte, error) {
+	c.mu.Lock()
+	if c.salt == nil {
+		salt := make([]byte, passphraseSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			c.mu.Unlock()
+			return nil, fmt.Errorf("failure generating salt: %w", err)
+		}
+		c.salt = salt
+	}
+	salt := c.salt
+	c.mu.Unlock()
+	aead, err := c.passphraseAEAD(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failure generating nonce: %w", err)
+	}
+	sealed := append(bytes.Clone(salt), nonce...)
+	return aead.Seal(sealed, nonce, plaintext, nil), nil
+}
+
+func (c *Cipher) passphraseDecrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < passphraseSaltSize {
+		return nil, fmt.Errorf("data is too short")
+	}
+	aead, err := c.passphraseAEAD(ciphertext[:passphraseSaltSize])
+	if err != nil {
+		return nil, err
+	}
+	ciphertext = ciphertext[passphraseSaltSize:]
+	size := aead.NonceSize()
+	if len(ciphertext) < size {
+		return nil, fmt.Errorf("data is too short")
+	}
+	plaintext, err := aead.Open(nil, ciphertext[:size], ciphertext[size:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("wrong passphrase or corrupted value: %w", err)
+	}
+	return plaintext, nil
+}
+
+// passphraseAEAD returns the cipher of the key derived from the passphrase and the salt, deriving each key once.
+func (c *Cipher) passphraseAEAD(salt []byte) (cipher.AEAD, error) {
+	if c.opts.Passphrase == "" {
+		return nil, fmt.Errorf("passphrase encryption requires a passphrase")
+	}
+	c.mu.Lock()
+	key, ok := c.keys[string(salt)]
+	if !ok {
+		var err error
+		if key, err = deriveKey(c.opts.Passphrase, salt, passphraseIterations, passphraseKeySize); err != nil {
+			c.mu.Unlock()
+			return nil, fmt.Errorf("failure deriving key from passphrase: %w", err)
+		}
+		c.keys[string(salt)] = key
+	}
+	c.mu.Unlock()
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// deriveKey derives a key from the passphrase with pbkdf2 and hmac-sha256, as specified by rfc 8018.
+func deriveKey(passphrase string, salt []byte, iterations, keySize int) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, passphrase, salt, iterations, keySize)
+}
+
+func execInputRunner(input []byte, name string, args ...string) ([]byte, error) {
+	command := exec.Command(name, args...)
+	command.Stdin = bytes.NewReader(input)
+	return command.Output()
+}
@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_deriveKey(t *testing.T) {
+	// test vector of rfc 7914, section 11
+	key, err := deriveKey("passwd", []byte("salt"), 1, 64)
+	assert.NoError(t, err)
+	assert.Equal(t, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783", hex.EncodeToString(key))
+}
+
+func TestCipher(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		encrypt    EncryptionOptions
+		decrypt    EncryptionOptions
+		assertions func(*assert.Assertions, string, error)
+	}{
+		{
+			name:    "success with passphrase",
+			method:  EncryptionMethodPassphrase,
+			encrypt: EncryptionOptions{Passphrase: "passphrase"},
+			decrypt: EncryptionOptions{Passphrase: "passphrase"},
+			assertions: func(assertions *assert.Assertions, plaintext string, err error) {
+				assertions.NoError(err)
+				assertions.Equal("password123", plaintext)
+			},
+		},
+		{
+			name:    "success with age",
+			method:  EncryptionMethodAge,
+			encrypt: EncryptionOptions{AgeRecipient: "age1recipient"},
+			decrypt: EncryptionOptions{AgeIdentity: "key.txt"},
+			assertions: func(assertions *assert.Assertions, plaintext string, err error) {
+				assertions.NoError(err)
+				assertions.Equal("password123", plaintext)
+			},
+		},
+		{
+			name:    "failure with wrong passphrase",
+			method:  EncryptionMethodPassphrase,
+			encrypt: EncryptionOptions{Passphrase: "passphrase"},
+			decrypt: EncryptionOptions{Passphrase: "wrong"},
+			assertions: func(assertions *assert.Assertions, plaintext string, err error) {
+				assertions.Empty(plaintext)
+				assertions.ErrorContains(err, "wrong passphrase")
+			},
+		},
+		{
+			name:    "failure with age missing identity",
+			method:  EncryptionMethodAge,
+			encrypt: EncryptionOptions{AgeRecipient: "age1recipient"},
+			assertions: func(assertions *assert.Assertions, plaintext string, err error) {
+				assertions.Empty(plaintext)
+				assertions.ErrorContains(err, "requires an identity file")
+			},
+		},
+	}
+	// the fake age reverses the input, so that the ciphertext differs from the plaintext
+	run := func(input []byte, name string, args ...string) ([]byte, error) {
+		if name != "age" || len(args) != 3 {
+			return nil, errors.New("unexpected command")
+		}
+		output := bytes.Clone(input)
+		for i, j := 0, len(output)-1; i < j; i, j = i+1, j-1 {
+			output[i], output[j] = output[j], output[i]
+		}
+		return output, nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertions := assert.New(t)
+			encrypter := NewCipher(tt.encrypt)
+			encrypter.run = run
+			encrypted, err := encrypter.Encrypt(tt.method, "password123")
+			assertions.NoError(err)
+			assertions.True(IsEncrypted(encrypted))
+			assertions.NotContains(encrypted, "password123")
+			decrypter := NewCipher(tt.decrypt)
+			decrypter.run = run
+			plaintext, err := decrypter.Decrypt(encrypted)
+			tt.assertions(assertions, plaintext, err)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	ProviderKeychain = "keychain"
+	ProviderSops     = "sops"
+	ProviderVault    = "vault"
+
+	vaultEnvAddress = "VAULT_ADDR"
+	vaultEnvToken   = "VAULT_TOKEN"
+	vaultHeaderKey  = "X-Vault-Token"
+)
+
+// Provider loads secret values from a secrets backend, keyed by their flattened config field name, e.g. IMDB_PASSWORD.
+type Provider interface {
+	Secrets(keys []string) (map[string]string, error)
+}
+
+type Options struct {
+	VaultAddress    string
+	VaultToken      string
+	VaultPath       string
+	SopsFile        string
+	KeychainService string
+}
+
+// runner executes a command and returns its standard output, so that tests can replace external binaries.
+type runner func(name string, args ...string) ([]byte, error)
+
+func NewProvider(provider string, opts Options) (Provider, error) {
+	switch provider {
+	case ProviderVault:
+		if opts.VaultAddress == "" {
+			opts.VaultAddress = os.Getenv(vaultEnvAddress)
+		}
+		if opts.VaultToken == "" {
+			opts.VaultToken = os.Getenv(vaultEnvToken)
+		}
+		if opts.VaultAddress == "" || opts.VaultToken == "" || opts.VaultPath == "" {
+			return nil, fmt.Errorf("vault secrets provider requires an address, token and path")
+		}
+		return &vaultProvider{
+			client: &http.Client{
+				Timeout: time.Second * 30,
+			},
+			address: strings.TrimSuffix(opts.VaultAddress, "/"),
+			token:   opts.VaultToken,
+			path:    strings.Trim(opts.VaultPath, "/"),
+		}, nil
+	case ProviderSops:
+		if opts.SopsFile == "" {
+			return nil, fmt.Errorf("sops secrets provider requires a file")
+		}
+		return &sopsProvider{
+			run:  execRunner,
+			file: opts.SopsFile,
+		}, nil
+	case ProviderKeychain:
+		if opts.KeychainService == "" {
+			return nil, fmt.Errorf("keychain secrets provider requires a service")
+		}
+		return &keychainProvider{
+			run:     execRunner,
+			goos:    runtime.GOOS,
+			service: opts.KeychainService,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown secrets provider %s", provider)
+	}
+}
+
+// vaultProvider reads the secrets from a single hashicorp vault secret, using the kv secrets engine.
+type vaultProvider struct {
+	client  *http.Client
+	address string
+	token   string
+	path    string
+}
+
+func (p *vaultProvider) Secrets(keys []string) (map[string]string, error) {
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/%s", p.address, p.path), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating vault request: %w", err)
+	}
+	request.Header.Set(vaultHeaderKey, p.token)
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failure sending vault request: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault responded with status code %d for secret %s", response.StatusCode, p.path)
+	}
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err = json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failure decoding vault response: %w", err)
+	}
+	data := body.Data
+	// version 2 of the kv secrets engine nests the secret data alongside its metadata
+	if nested, ok := data["data"].(map[string]any); ok {
+		data = nested
+	}
+	return pick(data, keys), nil
+}
+
+// sopsProvider decrypts a sops encrypted yaml or json file, which holds the secrets as top level keys.
+type sopsProvider struct {
+	run  runner
+	file string
+}
+
+func (p *sopsProvider) Secrets(keys []string) (map[string]string, error) {
+	output, err := p.run("sops", "--decrypt", "--output-type", "json", p.file)
+	if err != nil {
+		return nil, fmt.Errorf("failure decrypting sops file %s: %w", p.file, err)
+	}
+	var data map[string]any
+	if err = json.Unmarshal(output, &data); err != nil {
+		return nil, fmt.Errorf("failure unmarshalling decrypted sops file %s: %w", p.file, err)
+	}
+	return pick(data, keys), nil
+}
+
+// keychainProvider looks up each secret as a generic password of the os keychain, using the key as account name.
+type keychainProvider struct {
+	run     runner
+	goos    string
+	service string
+}
+
+func (p *keychainProvider) Secrets(keys []string) (map[string]string, error) {
+	secrets := make(map[string]string, len(keys))
+	for _, key := range keys {
+		var (
+			output []byte
+			err    error
+		)
+		switch p.goos {
+		case "darwin":
+			output, err = p.run("security", "find-generic-password", "-s", p.service, "-a", key, "-w")
+		case "linux":
+			output, err = p.run("secret-tool", "lookup", "service", p.service, "account", key)
+		default:
+			return nil, fmt.Errorf("keychain secrets provider is not supported on %s", p.goos)
+		}
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				// both tools exit with a non-zero status code when the secret does not exist
+				continue
+			}
+			return nil, fmt.Errorf("failure looking up keychain secret %s: %w", key, err)
+		}
+		if value := strings.TrimSpace(string(output)); value != "" {
+			secrets[key] = value
+		}
+	}
+	return secrets, nil
+}
+
+func execRunner(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// pick returns the values of the requested keys that are present in the data, ignoring any other keys.
+func pick(data map[string]any, keys []string) map[string]string {
+	secrets := make(map[string]string, len(keys))
+	for _, key := range keys {
+		value, ok := data[key]
+		if !ok || value == nil {
+			continue
+		}
+		secrets[key] = fmt.Sprintf("%v", value)
+	}
+	return secrets
+}
@@ -0,0 +1,259 @@
+package secrets
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProvider(t *testing.T) {
+	type args struct {
+		provider string
+		opts     Options
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, Provider, error)
+	}{
+		{
+			name: "success with vault",
+			args: args{
+				provider: ProviderVault,
+				opts: Options{
+					VaultAddress: "http://localhost:8200/",
+					VaultToken:   "token",
+					VaultPath:    "/secret/data/its",
+				},
+			},
+			assertions: func(assertions *assert.Assertions, provider Provider, err error) {
+				assertions.NoError(err)
+				vault, ok := provider.(*vaultProvider)
+				assertions.True(ok)
+				assertions.Equal("http://localhost:8200", vault.address)
+				assertions.Equal("secret/data/its", vault.path)
+			},
+		},
+		{
+			name: "failure with vault missing path",
+			args: args{
+				provider: ProviderVault,
+				opts: Options{
+					VaultAddress: "http://localhost:8200",
+					VaultToken:   "token",
+				},
+			},
+			assertions: func(assertions *assert.Assertions, provider Provider, err error) {
+				assertions.Nil(provider)
+				assertions.ErrorContains(err, "requires an address, token and path")
+			},
+		},
+		{
+			name: "failure with sops missing file",
+			args: args{
+				provider: ProviderSops,
+			},
+			assertions: func(assertions *assert.Assertions, provider Provider, err error) {
+				assertions.Nil(provider)
+				assertions.ErrorContains(err, "requires a file")
+			},
+		},
+		{
+			name: "failure with keychain missing service",
+			args: args{
+				provider: ProviderKeychain,
+			},
+			assertions: func(assertions *assert.Assertions, provider Provider, err error) {
+				assertions.Nil(provider)
+				assertions.ErrorContains(err, "requires a service")
+			},
+		},
+		{
+			name: "failure with unknown provider",
+			args: args{
+				provider: "unknown",
+			},
+			assertions: func(assertions *assert.Assertions, provider Provider, err error) {
+				assertions.Nil(provider)
+				assertions.ErrorContains(err, "unknown secrets provider")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewProvider(tt.args.provider, tt.args.opts)
+			tt.assertions(assert.New(t), provider, err)
+		})
+	}
+}
+
+func TestVaultProvider_Secrets(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		assertions func(*assert.Assertions, map[string]string, error)
+	}{
+		{
+			name: "success with kv version 2",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get(vaultHeaderKey) != "token" || r.URL.Path != "/v1/secret/data/its" {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				_, _ = w.Write([]byte(`{"data":{"data":{"IMDB_COOKIEATMAIN":"atMain","UNRELATED":"value"},"metadata":{"version":1}}}`))
+			},
+			assertions: func(assertions *assert.Assertions, secrets map[string]string, err error) {
+				assertions.NoError(err)
+				assertions.Equal(map[string]string{"IMDB_COOKIEATMAIN": "atMain"}, secrets)
+			},
+		},
+		{
+			name: "success with kv version 1",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"data":{"IMDB_COOKIEATMAIN":"atMain","TRAKT_CLIENTSECRET":"secret"}}`))
+			},
+			assertions: func(assertions *assert.Assertions, secrets map[string]string, err error) {
+				assertions.NoError(err)
+				assertions.Equal(map[string]string{"IMDB_COOKIEATMAIN": "atMain", "TRAKT_CLIENTSECRET": "secret"}, secrets)
+			},
+		},
+		{
+			name: "failure with unexpected status code",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			assertions: func(assertions *assert.Assertions, secrets map[string]string, err error) {
+				assertions.Nil(secrets)
+				assertions.ErrorContains(err, "status code 403")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+			provider, err := NewProvider(ProviderVault, Options{
+				VaultAddress: server.URL,
+				VaultToken:   "token",
+				VaultPath:    "secret/data/its",
+			})
+			assert.NoError(t, err)
+			secrets, err := provider.Secrets([]string{"IMDB_COOKIEATMAIN", "TRAKT_CLIENTSECRET"})
+			tt.assertions(assert.New(t), secrets, err)
+		})
+	}
+}
+
+func TestSopsProvider_Secrets(t *testing.T) {
+	tests := []struct {
+		name       string
+		run        runner
+		assertions func(*assert.Assertions, map[string]string, error)
+	}{
+		{
+			name: "success",
+			run: func(name string, args ...string) ([]byte, error) {
+				return []byte(`{"IMDB_COOKIEATMAIN":"atMain","sops":{"version":"3.9.0"}}`), nil
+			},
+			assertions: func(assertions *assert.Assertions, secrets map[string]string, err error) {
+				assertions.NoError(err)
+				assertions.Equal(map[string]string{"IMDB_COOKIEATMAIN": "atMain"}, secrets)
+			},
+		},
+		{
+			name: "failure decrypting file",
+			run: func(name string, args ...string) ([]byte, error) {
+				return nil, errors.New("no key could decrypt the data")
+			},
+			assertions: func(assertions *assert.Assertions, secrets map[string]string, err error) {
+				assertions.Nil(secrets)
+				assertions.ErrorContains(err, "failure decrypting sops file")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &sopsProvider{
+				run:  tt.run,
+				file: "secrets.enc.yaml",
+			}
+			secrets, err := provider.Secrets([]string{"IMDB_COOKIEATMAIN"})
+			tt.assertions(assert.New(t), secrets, err)
+		})
+	}
+}
+
+func TestKeychainProvider_Secrets(t *testing.T) {
+	tests := []struct {
+		name       string
+		goos       string
+		run        runner
+		assertions func(*assert.Assertions, map[string]string, error)
+	}{
+		{
+			name: "success on darwin skipping missing secrets",
+			goos: "darwin",
+			run: func(name string, args ...string) ([]byte, error) {
+				if name != "security" {
+					return nil, errors.New("unexpected command")
+				}
+				if args[4] == "IMDB_COOKIEATMAIN" {
+					return []byte("atMain\n"), nil
+				}
+				return nil, &exec.ExitError{}
+			},
+			assertions: func(assertions *assert.Assertions, secrets map[string]string, err error) {
+				assertions.NoError(err)
+				assertions.Equal(map[string]string{"IMDB_COOKIEATMAIN": "atMain"}, secrets)
+			},
+		},
+		{
+			name: "success on linux",
+			goos: "linux",
+			run: func(name string, args ...string) ([]byte, error) {
+				if name != "secret-tool" {
+					return nil, errors.New("unexpected command")
+				}
+				return []byte(args[4]), nil
+			},
+			assertions: func(assertions *assert.Assertions, secrets map[string]string, err error) {
+				assertions.NoError(err)
+				assertions.Equal(map[string]string{"IMDB_COOKIEATMAIN": "IMDB_COOKIEATMAIN", "TRAKT_CLIENTSECRET": "TRAKT_CLIENTSECRET"}, secrets)
+			},
+		},
+		{
+			name: "failure running command",
+			goos: "linux",
+			run: func(name string, args ...string) ([]byte, error) {
+				return nil, exec.ErrNotFound
+			},
+			assertions: func(assertions *assert.Assertions, secrets map[string]string, err error) {
+				assertions.Nil(secrets)
+				assertions.ErrorIs(err, exec.ErrNotFound)
+			},
+		},
+		{
+			name: "failure with unsupported os",
+			goos: "windows",
+			assertions: func(assertions *assert.Assertions, secrets map[string]string, err error) {
+				assertions.Nil(secrets)
+				assertions.ErrorContains(err, "not supported on windows")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &keychainProvider{
+				run:     tt.run,
+				goos:    tt.goos,
+				service: "its",
+			}
+			secrets, err := provider.Secrets([]string{"IMDB_COOKIEATMAIN", "TRAKT_CLIENTSECRET"})
+			tt.assertions(assert.New(t), secrets, err)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	pathHealth    = "/healthz"
+	pathReadiness = "/readyz"
+
+	healthStatusOK        = "ok"
+	healthStatusUnhealthy = "unhealthy"
+
+	profileDefault = "default"
+)
+
+// Health tracks the outcome of the syncs of each profile, in order to report it on the health endpoints.
+// A nil health ignores every observation.
+type Health struct {
+	mu         sync.Mutex
+	started    time.Time
+	maxSyncAge time.Duration
+	profiles   map[string]*profileHealth
+}
+
+type profileHealth struct {
+	CredentialsValid bool       `json:"credentialsValid"`
+	LastSuccess      *time.Time `json:"lastSuccess,omitempty"`
+	SinceLastSuccess string     `json:"sinceLastSuccess,omitempty"`
+}
+
+type healthResponse struct {
+	Status   string                    `json:"status"`
+	Profiles map[string]*profileHealth `json:"profiles"`
+}
+
+// NewHealth returns a health that reports the syncer as unhealthy once no sync succeeded for longer than the max
+// sync age.
+func NewHealth(maxSyncAge time.Duration) *Health {
+	return &Health{
+		started:    time.Now(),
+		maxSyncAge: maxSyncAge,
+		profiles:   make(map[string]*profileHealth),
+	}
+}
+
+// ObserveCredentials records whether the clients of the profile managed to authenticate when its sync started.
+func (h *Health) ObserveCredentials(profile string, valid bool) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.profile(profile).CredentialsValid = valid
+}
+
+// ObserveSuccess records a successful sync of the profile.
+func (h *Health) ObserveSuccess(profile string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	h.profile(profile).LastSuccess = &now
+}
+
+func (h *Health) profile(name string) *profileHealth {
+	if name == "" {
+		name = profileDefault
+	}
+	p, ok := h.profiles[name]
+	if !ok {
+		p = &profileHealth{}
+		h.profiles[name] = p
+	}
+	return p
+}
+
+// handleHealth reports whether every profile synced successfully within the max sync age, counting from the start of
+// the process for profiles that have not synced successfully yet. A stuck syncer fails this check, so that it can be
+// restarted.
+func (h *Health) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	response := h.response()
+	for _, p := range h.profiles {
+		since := h.started
+		if p.LastSuccess != nil {
+			since = *p.LastSuccess
+		}
+		if time.Since(since) > h.maxSyncAge {
+			response.Status = healthStatusUnhealthy
+		}
+	}
+	writeHealth(w, response)
+}
+
+// handleReadiness reports whether a sync has started for every profile, and the credentials of each were accepted.
+func (h *Health) handleReadiness(w http.ResponseWriter, _ *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	response := h.response()
+	if len(h.profiles) == 0 {
+		response.Status = healthStatusUnhealthy
+	}
+	for _, p := range h.profiles {
+		if !p.CredentialsValid {
+			response.Status = healthStatusUnhealthy
+		}
+	}
+	writeHealth(w, response)
+}
+
+func (h *Health) response() healthResponse {
+	response := healthResponse{
+		Status:   healthStatusOK,
+		Profiles: make(map[string]*profileHealth, len(h.profiles)),
+	}
+	for name, p := range h.profiles {
+		snapshot := *p
+		if p.LastSuccess != nil {
+			snapshot.SinceLastSuccess = time.Since(*p.LastSuccess).Round(time.Second).String()
+		}
+		response.Profiles[name] = &snapshot
+	}
+	return response
+}
+
+func writeHealth(w http.ResponseWriter, response healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if response.Status != healthStatusOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// Probe requests the health endpoint of the server listening on the address, and returns an error unless it reports
+// the syncer as healthy. It is meant for container health checks, which run next to the server.
+func Probe(ctx context.Context, address string) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("failure parsing server address %s: %w", address, err)
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	healthURL := "http://" + net.JoinHostPort(host, port) + pathHealth
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("error creating http request %s: %w", healthURL, err)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("error sending http request %s: %w", healthURL, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		var health healthResponse
+		_ = json.NewDecoder(response.Body).Decode(&health)
+		return fmt.Errorf("syncer is %s, health endpoint responded with status code %d", cmp.Or(health.Status, healthStatusUnhealthy), response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+func TestHealth(t *testing.T) {
+	tests := []struct {
+		name       string
+		observe    func(*Health)
+		path       string
+		statusCode int
+	}{
+		{
+			name:       "healthy before the first sync",
+			observe:    func(*Health) {},
+			path:       pathHealth,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "not ready before the first sync",
+			observe:    func(*Health) {},
+			path:       pathReadiness,
+			statusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name: "ready once the credentials of the first sync were accepted",
+			observe: func(h *Health) {
+				h.ObserveCredentials("", true)
+			},
+			path:       pathReadiness,
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "not ready when the credentials of a profile were rejected",
+			observe: func(h *Health) {
+				h.ObserveCredentials("", true)
+				h.ObserveCredentials("family", false)
+			},
+			path:       pathReadiness,
+			statusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name: "unhealthy when the first sync did not succeed within the max sync age",
+			observe: func(h *Health) {
+				h.ObserveCredentials("", true)
+				h.started = time.Now().Add(-time.Hour * 2)
+			},
+			path:       pathHealth,
+			statusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name: "healthy after a successful sync",
+			observe: func(h *Health) {
+				h.ObserveCredentials("", true)
+				h.started = time.Now().Add(-time.Hour * 2)
+				h.ObserveSuccess("")
+			},
+			path:       pathHealth,
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "unhealthy when the last successful sync is older than the max sync age",
+			observe: func(h *Health) {
+				h.ObserveSuccess("")
+				lastSuccess := time.Now().Add(-time.Hour * 2)
+				h.profiles[profileDefault].LastSuccess = &lastSuccess
+			},
+			path:       pathHealth,
+			statusCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health := NewHealth(time.Hour)
+			tt.observe(health)
+			s := NewServer(":0", health, logger.NewLogger(io.Discard))
+			recorder := httptest.NewRecorder()
+			s.mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, http.NoBody))
+			assertions := assert.New(t)
+			assertions.Equal(tt.statusCode, recorder.Code)
+			var response healthResponse
+			assertions.NoError(json.NewDecoder(recorder.Body).Decode(&response))
+			if tt.statusCode == http.StatusOK {
+				assertions.Equal(healthStatusOK, response.Status)
+			} else {
+				assertions.Equal(healthStatusUnhealthy, response.Status)
+			}
+		})
+	}
+}
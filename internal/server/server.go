@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+const (
+	pathMetrics = "/metrics"
+	pathTrigger = "/trigger"
+)
+
+type Server struct {
+	server *http.Server
+	mux    *http.ServeMux
+	logger *slog.Logger
+}
+
+// NewServer returns a server exposing the metrics, along with the health endpoints when a health is provided.
+func NewServer(address string, health *Health, logger *slog.Logger) *Server {
+	mux := http.NewServeMux()
+	mux.Handle(pathMetrics, promhttp.Handler())
+	if health != nil {
+		mux.HandleFunc(pathHealth, health.handleHealth)
+		mux.HandleFunc(pathReadiness, health.handleReadiness)
+	}
+	return &Server{
+		server: &http.Server{
+			Addr:              address,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		mux:    mux,
+		logger: logger,
+	}
+}
+
+// HandleTrigger exposes an endpoint that runs the trigger function on POST requests bearing the token, so that
+// external automations can kick off a sync. The trigger function reports whether the sync was scheduled.
+func (s *Server) HandleTrigger(token string, trigger func() bool) {
+	s.mux.HandleFunc(pathTrigger, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		bearer, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			s.logger.Warn("rejected unauthorized sync trigger", slog.String("remoteAddress", r.RemoteAddr))
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if !trigger() {
+			http.Error(w, "a triggered sync is already pending", http.StatusConflict)
+			return
+		}
+		s.logger.Info("accepted sync trigger", slog.String("remoteAddress", r.RemoteAddr))
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+func (s *Server) Start() {
+	go func() {
+		s.logger.Info("started http server", slog.String("address", s.server.Addr))
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("failure serving http requests", logger.Error(err))
+		}
+	}()
+}
+
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.logger.Error("failure shutting down http server", logger.Error(err))
+		return
+	}
+	s.logger.Info("stopped http server")
+}
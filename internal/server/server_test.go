@@ -0,0 +1,96 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+func TestServer_HandleTrigger(t *testing.T) {
+	const token = "secret-token"
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		pending       bool
+		assertions    func(*assert.Assertions, *httptest.ResponseRecorder, int)
+	}{
+		{
+			name:          "reject wrong method",
+			method:        http.MethodGet,
+			authorization: "Bearer " + token,
+			assertions: func(assertions *assert.Assertions, recorder *httptest.ResponseRecorder, triggers int) {
+				assertions.Equal(http.StatusMethodNotAllowed, recorder.Code)
+				assertions.Equal(http.MethodPost, recorder.Header().Get("Allow"))
+				assertions.Zero(triggers)
+			},
+		},
+		{
+			name:   "reject missing token",
+			method: http.MethodPost,
+			assertions: func(assertions *assert.Assertions, recorder *httptest.ResponseRecorder, triggers int) {
+				assertions.Equal(http.StatusUnauthorized, recorder.Code)
+				assertions.Zero(triggers)
+			},
+		},
+		{
+			name:          "reject wrong token",
+			method:        http.MethodPost,
+			authorization: "Bearer wrong-token",
+			assertions: func(assertions *assert.Assertions, recorder *httptest.ResponseRecorder, triggers int) {
+				assertions.Equal(http.StatusUnauthorized, recorder.Code)
+				assertions.Zero(triggers)
+			},
+		},
+		{
+			name:          "reject token without bearer scheme",
+			method:        http.MethodPost,
+			authorization: token,
+			assertions: func(assertions *assert.Assertions, recorder *httptest.ResponseRecorder, triggers int) {
+				assertions.Equal(http.StatusUnauthorized, recorder.Code)
+				assertions.Zero(triggers)
+			},
+		},
+		{
+			name:          "conflict when a sync is already pending",
+			method:        http.MethodPost,
+			authorization: "Bearer " + token,
+			pending:       true,
+			assertions: func(assertions *assert.Assertions, recorder *httptest.ResponseRecorder, triggers int) {
+				assertions.Equal(http.StatusConflict, recorder.Code)
+				assertions.Equal(1, triggers)
+			},
+		},
+		{
+			name:          "accept trigger",
+			method:        http.MethodPost,
+			authorization: "Bearer " + token,
+			assertions: func(assertions *assert.Assertions, recorder *httptest.ResponseRecorder, triggers int) {
+				assertions.Equal(http.StatusAccepted, recorder.Code)
+				assertions.Equal(1, triggers)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(":0", nil, logger.NewLogger(io.Discard))
+			triggers := 0
+			s.HandleTrigger(token, func() bool {
+				triggers++
+				return !tt.pending
+			})
+			request := httptest.NewRequest(tt.method, pathTrigger, http.NoBody)
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			s.mux.ServeHTTP(recorder, request)
+			tt.assertions(assert.New(t), recorder, triggers)
+		})
+	}
+}
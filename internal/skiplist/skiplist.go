@@ -0,0 +1,227 @@
+package skiplist
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/progress"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+const stateKey = "trakt-skip-list"
+
+// Entry describes an item that the destination could not find, which is skipped until it is due to be retried.
+type Entry struct {
+	IMDb          string    `json:"imdb"`
+	Type          string    `json:"type"`
+	Misses        int       `json:"misses"`
+	FirstMissedAt time.Time `json:"firstMissedAt"`
+	LastMissedAt  time.Time `json:"lastMissedAt"`
+}
+
+// RetryAt returns when the item is retried, which is once the ttl has passed since the item was last missed.
+func (e Entry) RetryAt(ttl time.Duration) time.Time {
+	return e.LastMissedAt.Add(ttl)
+}
+
+// Load returns the skipped items, sorted by imdb id.
+func Load(store state.Store) ([]Entry, error) {
+	var entries []Entry
+	if err := store.Load(stateKey, &entries); err != nil && !errors.Is(err, state.ErrNotFound) {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Save replaces the skipped items.
+func Save(store state.Store, entries []Entry) error {
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return strings.Compare(a.IMDb, b.IMDb)
+	})
+	return store.Save(stateKey, entries)
+}
+
+// Clear removes the given imdb ids from the skip-list, or every item when no imdb ids are given. It returns the number
+// of items that were removed.
+func Clear(store state.Store, imdbIDs ...string) (int, error) {
+	entries, err := Load(store)
+	if err != nil {
+		return 0, err
+	}
+	if len(imdbIDs) == 0 {
+		return len(entries), Save(store, make([]Entry, 0))
+	}
+	kept := slices.DeleteFunc(slices.Clone(entries), func(entry Entry) bool {
+		return slices.Contains(imdbIDs, entry.IMDb)
+	})
+	return len(entries) - len(kept), Save(store, kept)
+}
+
+// Client wraps a destination client and leaves out the items of the skip-list that are not due to be retried yet, so
+// that titles which the destination consistently fails to find do not waste requests on every run. The items that the
+// destination could not find are added to the skip-list, while retried items that were found are removed from it.
+type Client struct {
+	client.DestinationClientInterface
+	logger  *slog.Logger
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]Entry
+	retried map[string]struct{}
+	missed  map[string]struct{}
+	mu      sync.Mutex
+}
+
+// NewClient wraps the destination with the skipped items, which are retried once the ttl has passed.
+func NewClient(destination client.DestinationClientInterface, entries []Entry, ttl time.Duration, log *slog.Logger) *Client {
+	c := &Client{
+		DestinationClientInterface: destination,
+		logger:                     log,
+		ttl:                        ttl,
+		now:                        time.Now,
+		entries:                    make(map[string]Entry, len(entries)),
+		retried:                    make(map[string]struct{}),
+		missed:                     make(map[string]struct{}),
+	}
+	for _, entry := range entries {
+		c.entries[entry.IMDb] = entry
+	}
+	return c
+}
+
+// SetProgress forwards the progress to the wrapped destination, if it reports any.
+func (c *Client) SetProgress(p *progress.Progress) {
+	if destination, ok := c.DestinationClientInterface.(interface{ SetProgress(*progress.Progress) }); ok {
+		destination.SetProgress(p)
+	}
+}
+
+// Entries returns the skip-list as it stands after the items that were sent so far.
+func (c *Client) Entries() []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := make([]Entry, 0, len(c.entries))
+	for imdbID, entry := range c.entries {
+		if _, retried := c.retried[imdbID]; retried {
+			if _, missed := c.missed[imdbID]; !missed {
+				continue
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// NotFound records the items that the destination could not find in the skip-list, before returning them.
+func (c *Client) NotFound() entities.TraktItems {
+	notFound := c.DestinationClientInterface.NotFound()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for _, item := range notFound {
+		id, err := item.GetItemID()
+		if err != nil || id == nil || *id == "" {
+			continue
+		}
+		if _, missed := c.missed[*id]; missed {
+			continue
+		}
+		c.missed[*id] = struct{}{}
+		entry, found := c.entries[*id]
+		if !found {
+			entry = Entry{
+				IMDb:          *id,
+				Type:          item.Type,
+				FirstMissedAt: now,
+			}
+		}
+		entry.Misses++
+		entry.LastMissedAt = now
+		c.entries[*id] = entry
+	}
+	return notFound
+}
+
+// filter returns the items that are not skipped, and marks the skipped items that are due to be retried.
+func (c *Client) filter(items entities.TraktItems) entities.TraktItems {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	filtered := make(entities.TraktItems, 0, len(items))
+	for _, item := range items {
+		id, err := item.GetItemID()
+		if err != nil || id == nil {
+			filtered = append(filtered, item)
+			continue
+		}
+		entry, found := c.entries[*id]
+		if !found {
+			filtered = append(filtered, item)
+			continue
+		}
+		if now.Before(entry.RetryAt(c.ttl)) {
+			continue
+		}
+		c.retried[*id] = struct{}{}
+		filtered = append(filtered, item)
+	}
+	if skipped := len(items) - len(filtered); skipped > 0 {
+		c.logger.Debug(fmt.Sprintf("skipping %d item(s) that were recently not found", skipped))
+	}
+	return filtered
+}
+
+func (c *Client) WatchlistItemsAdd(items entities.TraktItems) error {
+	if items = c.filter(items); len(items) == 0 {
+		return nil
+	}
+	return c.DestinationClientInterface.WatchlistItemsAdd(items)
+}
+
+func (c *Client) ListItemsAdd(listID string, items entities.TraktItems) error {
+	if items = c.filter(items); len(items) == 0 {
+		return nil
+	}
+	return c.DestinationClientInterface.ListItemsAdd(listID, items)
+}
+
+func (c *Client) RatingsAdd(items entities.TraktItems) error {
+	if items = c.filter(items); len(items) == 0 {
+		return nil
+	}
+	return c.DestinationClientInterface.RatingsAdd(items)
+}
+
+func (c *Client) HistoryAdd(items entities.TraktItems) error {
+	if items = c.filter(items); len(items) == 0 {
+		return nil
+	}
+	return c.DestinationClientInterface.HistoryAdd(items)
+}
+
+func (c *Client) HiddenAdd(section string, items entities.TraktItems) error {
+	if items = c.filter(items); len(items) == 0 {
+		return nil
+	}
+	return c.DestinationClientInterface.HiddenAdd(section, items)
+}
+
+func (c *Client) CollectionAdd(items entities.TraktItems) error {
+	if items = c.filter(items); len(items) == 0 {
+		return nil
+	}
+	return c.DestinationClientInterface.CollectionAdd(items)
+}
+
+func (c *Client) FavoritesAdd(items entities.TraktItems) error {
+	if items = c.filter(items); len(items) == 0 {
+		return nil
+	}
+	return c.DestinationClientInterface.FavoritesAdd(items)
+}
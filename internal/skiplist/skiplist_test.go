@@ -0,0 +1,100 @@
+package skiplist
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+type mockDestinationClient struct {
+	client.DestinationClientInterface
+	added    entities.TraktItems
+	notFound entities.TraktItems
+}
+
+func (m *mockDestinationClient) RatingsAdd(items entities.TraktItems) error {
+	m.added = items
+	return nil
+}
+
+func (m *mockDestinationClient) NotFound() entities.TraktItems {
+	notFound := m.notFound
+	m.notFound = nil
+	return notFound
+}
+
+func movie(imdbID string) entities.TraktItem {
+	return entities.TraktItem{
+		Type:  entities.TraktItemTypeMovie,
+		Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: imdbID}},
+	}
+}
+
+func TestClient(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	destination := &mockDestinationClient{}
+	c := NewClient(destination, []Entry{
+		{IMDb: "tt0000001", Type: entities.TraktItemTypeMovie, Misses: 1, FirstMissedAt: now.Add(-time.Hour), LastMissedAt: now.Add(-time.Hour)},
+		{IMDb: "tt0000002", Type: entities.TraktItemTypeMovie, Misses: 2, FirstMissedAt: now.Add(-time.Hour * 72), LastMissedAt: now.Add(-time.Hour * 48)},
+		{IMDb: "tt0000003", Type: entities.TraktItemTypeMovie, Misses: 1, FirstMissedAt: now.Add(-time.Hour * 48), LastMissedAt: now.Add(-time.Hour * 48)},
+	}, time.Hour*24, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	c.now = func() time.Time { return now }
+
+	assert.NoError(t, c.RatingsAdd(entities.TraktItems{movie("tt0000001"), movie("tt0000002"), movie("tt0000003"), movie("tt0000004")}))
+	assert.Equal(t, entities.TraktItems{movie("tt0000002"), movie("tt0000003"), movie("tt0000004")}, destination.added)
+
+	destination.notFound = entities.TraktItems{movie("tt0000002"), movie("tt0000004")}
+	assert.Len(t, c.NotFound(), 2)
+
+	entries := c.Entries()
+	assert.Len(t, entries, 3)
+	byID := make(map[string]Entry)
+	for _, entry := range entries {
+		byID[entry.IMDb] = entry
+	}
+	assert.Equal(t, 1, byID["tt0000001"].Misses)
+	assert.Equal(t, 3, byID["tt0000002"].Misses)
+	assert.Equal(t, now, byID["tt0000002"].LastMissedAt)
+	assert.Equal(t, Entry{IMDb: "tt0000004", Type: entities.TraktItemTypeMovie, Misses: 1, FirstMissedAt: now, LastMissedAt: now}, byID["tt0000004"])
+	assert.NotContains(t, byID, "tt0000003")
+}
+
+func TestClient_skipsEmptyRequests(t *testing.T) {
+	now := time.Now()
+	destination := &mockDestinationClient{}
+	c := NewClient(destination, []Entry{
+		{IMDb: "tt0000001", Type: entities.TraktItemTypeMovie, Misses: 1, FirstMissedAt: now, LastMissedAt: now},
+	}, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assert.NoError(t, c.RatingsAdd(entities.TraktItems{movie("tt0000001")}))
+	assert.Nil(t, destination.added)
+}
+
+func TestClear(t *testing.T) {
+	store, err := state.NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	assert.NoError(t, Save(store, []Entry{{IMDb: "tt0000002"}, {IMDb: "tt0000001"}, {IMDb: "tt0000003"}}))
+	entries, err := Load(store)
+	assert.NoError(t, err)
+	assert.Equal(t, []Entry{{IMDb: "tt0000001"}, {IMDb: "tt0000002"}, {IMDb: "tt0000003"}}, entries)
+
+	cleared, err := Clear(store, "tt0000002", "tt0000009")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cleared)
+	entries, err = Load(store)
+	assert.NoError(t, err)
+	assert.Equal(t, []Entry{{IMDb: "tt0000001"}, {IMDb: "tt0000003"}}, entries)
+
+	cleared, err = Clear(store)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cleared)
+	entries, err = Load(store)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,216 @@
+package state
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+var errKeyringUnsupported = errors.New("os keyring is not supported")
+
+// runner executes a command with the given standard input and returns its standard output, so that tests can replace
+// external binaries.
+type runner func(stdin []byte, name string, args ...string) ([]byte, error)
+
+// KeyringStore persists each key as a generic password of the os keyring, using the key as account name. When the
+// keyring is not available, such as on headless machines without a secret service, the fallback store is used.
+type KeyringStore struct {
+	run      runner
+	goos     string
+	service  string
+	fallback Store
+}
+
+// NewKeyringStore returns a store that keeps the values in the os keyring under the given service. The fallback store
+// can be nil, in which case the keyring must be available.
+func NewKeyringStore(service string, fallback Store) *KeyringStore {
+	return &KeyringStore{
+		run:      execRunner,
+		goos:     runtime.GOOS,
+		service:  service,
+		fallback: fallback,
+	}
+}
+
+func (s *KeyringStore) Load(key string, v any) error {
+	data, err := s.lookup(key)
+	if err != nil {
+		// values are saved to the fallback store while the keyring is not available
+		if s.fallback != nil {
+			return s.fallback.Load(key, v)
+		}
+		return err
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failure unmarshalling state %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *KeyringStore) Save(key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failure marshalling state %s: %w", key, err)
+	}
+	if err = s.store(key, data); err != nil {
+		if s.fallback == nil {
+			return fmt.Errorf("failure saving state %s to os keyring: %w", key, err)
+		}
+		return s.fallback.Save(key, v)
+	}
+	if s.fallback != nil {
+		// the keyring takes precedence, hence why stale values of the fallback store are no longer needed
+		return s.fallback.Delete(key)
+	}
+	return nil
+}
+
+func (s *KeyringStore) Delete(key string) error {
+	if err := s.remove(key); err != nil && s.fallback == nil {
+		return fmt.Errorf("failure deleting state %s from os keyring: %w", key, err)
+	}
+	if s.fallback != nil {
+		return s.fallback.Delete(key)
+	}
+	return nil
+}
+
+func (s *KeyringStore) lookup(key string) ([]byte, error) {
+	var (
+		output []byte
+		err    error
+	)
+	switch s.goos {
+	case "darwin":
+		output, err = s.run(nil, "security", "find-generic-password", "-s", s.service, "-a", key, "-w")
+	case "linux":
+		output, err = s.run(nil, "secret-tool", "lookup", "service", s.service, "account", key)
+	default:
+		return nil, errKeyringUnsupported
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// both tools exit with a non-zero status code when the secret does not exist
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failure reading state %s from os keyring: %w", key, err)
+	}
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return nil, ErrNotFound
+	}
+	return output, nil
+}
+
+func (s *KeyringStore) store(key string, data []byte) error {
+	var err error
+	switch s.goos {
+	case "darwin":
+		_, err = s.run(nil, "security", "add-generic-password", "-U", "-s", s.service, "-a", key, "-w", string(data))
+	case "linux":
+		label := fmt.Sprintf("%s %s", s.service, key)
+		_, err = s.run(data, "secret-tool", "store", "--label", label, "service", s.service, "account", key)
+	default:
+		return errKeyringUnsupported
+	}
+	return err
+}
+
+func (s *KeyringStore) remove(key string) error {
+	var err error
+	switch s.goos {
+	case "darwin":
+		_, err = s.run(nil, "security", "delete-generic-password", "-s", s.service, "-a", key)
+	case "linux":
+		_, err = s.run(nil, "secret-tool", "clear", "service", s.service, "account", key)
+	default:
+		return errKeyringUnsupported
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// a missing secret has nothing to delete
+		return nil
+	}
+	return err
+}
+
+func execRunner(stdin []byte, name string, args ...string) ([]byte, error) {
+	command := exec.Command(name, args...)
+	if stdin != nil {
+		command.Stdin = bytes.NewReader(stdin)
+	}
+	return command.Output()
+}
+
+// EncryptedStore encrypts each value with a key derived from a passphrase, before persisting it to the next store.
+type EncryptedStore struct {
+	next Store
+	aead cipher.AEAD
+}
+
+func NewEncryptedStore(next Store, passphrase string) (*EncryptedStore, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("encrypted state requires a passphrase")
+	}
+	key := sha256.Sum256([]byte(passphrase))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failure creating state cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating state cipher: %w", err)
+	}
+	return &EncryptedStore{
+		next: next,
+		aead: aead,
+	}, nil
+}
+
+func (s *EncryptedStore) Load(key string, v any) error {
+	var data []byte
+	if err := s.next.Load(s.key(key), &data); err != nil {
+		return err
+	}
+	size := s.aead.NonceSize()
+	if len(data) < size {
+		return fmt.Errorf("failure decrypting state %s: data is too short", key)
+	}
+	plaintext, err := s.aead.Open(nil, data[:size], data[size:], []byte(key))
+	if err != nil {
+		return fmt.Errorf("failure decrypting state %s: %w", key, err)
+	}
+	if err = json.Unmarshal(plaintext, v); err != nil {
+		return fmt.Errorf("failure unmarshalling state %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *EncryptedStore) Save(key string, v any) error {
+	plaintext, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failure marshalling state %s: %w", key, err)
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return fmt.Errorf("failure generating nonce for state %s: %w", key, err)
+	}
+	return s.next.Save(s.key(key), s.aead.Seal(nonce, nonce, plaintext, []byte(key)))
+}
+
+func (s *EncryptedStore) Delete(key string) error {
+	return s.next.Delete(s.key(key))
+}
+
+// key keeps the encrypted values apart from plain values of the same key, which the next store may already hold.
+func (s *EncryptedStore) key(key string) string {
+	return key + "-encrypted"
+}
@@ -0,0 +1,349 @@
+package state
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	URLSchemeAzure = "azblob"
+	URLSchemeGCS   = "gs"
+	URLSchemeS3    = "s3"
+
+	remoteTimeout = time.Second * 30
+	s3Algorithm   = "AWS4-HMAC-SHA256"
+	s3DateFormat  = "20060102T150405Z"
+	s3Service     = "s3"
+)
+
+// NewStore returns a store that persists the state to the object storage of rawURL, or to the local directory when no
+// url is given.
+func NewStore(dir, rawURL string) (Store, error) {
+	if rawURL == "" {
+		return NewFileStore(dir)
+	}
+	return NewRemoteStore(rawURL)
+}
+
+// ParseURL parses the url of an object storage, which has the format scheme://bucket/prefix. The bucket of an azure
+// url is the blob container.
+func ParseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failure parsing url: %w", err)
+	}
+	if !slices.Contains(validURLSchemes(), u.Scheme) {
+		return nil, fmt.Errorf("url scheme must be one of: %s, but got %s", strings.Join(validURLSchemes(), ", "), u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("url must have the format %s://bucket/prefix, but got %s", u.Scheme, rawURL)
+	}
+	return u, nil
+}
+
+// backend builds the authorised requests for the objects of an object storage.
+type backend interface {
+	request(method, key string, body []byte) (*http.Request, error)
+}
+
+// RemoteStore persists each key as a json object in an object storage, such as s3, gcs or azure blob storage, so that
+// the state survives runs in environments without a persistent disk.
+type RemoteStore struct {
+	httpClient *http.Client
+	backend    backend
+	prefix     string
+}
+
+// NewRemoteStore returns a store for the object storage of rawURL. The credentials are read from the environment
+// variables that the cloud providers use by convention.
+func NewRemoteStore(rawURL string) (*RemoteStore, error) {
+	u, err := ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	var b backend
+	switch u.Scheme {
+	case URLSchemeS3:
+		b, err = newS3Backend(u)
+	case URLSchemeGCS:
+		b, err = newGCSBackend(u)
+	case URLSchemeAzure:
+		b, err = newAzureBackend(u)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &RemoteStore{
+		httpClient: &http.Client{
+			Timeout: remoteTimeout,
+		},
+		backend: b,
+		prefix:  strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+func (s *RemoteStore) Load(key string, v any) error {
+	response, err := s.do(http.MethodGet, key, nil)
+	if err != nil {
+		return fmt.Errorf("failure reading state %s: %w", key, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failure reading state %s: %w", key, statusError(response))
+	}
+	if err = json.NewDecoder(response.Body).Decode(v); err != nil {
+		return fmt.Errorf("failure unmarshalling state %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *RemoteStore) Save(key string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failure marshalling state %s: %w", key, err)
+	}
+	response, err := s.do(http.MethodPut, key, data)
+	if err != nil {
+		return fmt.Errorf("failure writing state %s: %w", key, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failure writing state %s: %w", key, statusError(response))
+	}
+	return nil
+}
+
+func (s *RemoteStore) Delete(key string) error {
+	response, err := s.do(http.MethodDelete, key, nil)
+	if err != nil {
+		return fmt.Errorf("failure deleting state %s: %w", key, err)
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("failure deleting state %s: %w", key, statusError(response))
+	}
+}
+
+func (s *RemoteStore) do(method, key string, body []byte) (*http.Response, error) {
+	request, err := s.backend.request(method, path.Join(s.prefix, key+".json"), body)
+	if err != nil {
+		return nil, err
+	}
+	return s.httpClient.Do(request)
+}
+
+func statusError(response *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+	return fmt.Errorf("unexpected status code %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// s3Backend signs its requests with aws signature version 4. The endpoint can be overridden with the endpoint query
+// parameter of the url, in which case path-style requests are sent, as s3 compatible storages expect.
+type s3Backend struct {
+	endpoint     *url.URL
+	bucket       string
+	region       string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	pathStyle    bool
+	now          func() time.Time
+}
+
+func newS3Backend(u *url.URL) (*s3Backend, error) {
+	b := &s3Backend{
+		bucket:       u.Host,
+		region:       firstNonEmpty(u.Query().Get("region"), os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"), "us-east-1"),
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		now:          time.Now,
+	}
+	if b.accessKey == "" || b.secretKey == "" {
+		return nil, fmt.Errorf("environment variables AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required for %s urls", URLSchemeS3)
+	}
+	endpoint := fmt.Sprintf("https://%s.s3.%s.amazonaws.com", b.bucket, b.region)
+	if custom := u.Query().Get("endpoint"); custom != "" {
+		endpoint, b.pathStyle = custom, true
+	}
+	var err error
+	if b.endpoint, err = url.Parse(endpoint); err != nil {
+		return nil, fmt.Errorf("failure parsing s3 endpoint: %w", err)
+	}
+	return b, nil
+}
+
+func (b *s3Backend) request(method, key string, body []byte) (*http.Request, error) {
+	objectPath := "/" + key
+	if b.pathStyle {
+		objectPath = "/" + b.bucket + objectPath
+	}
+	request, err := newRequest(method, b.endpoint, objectPath, "", body)
+	if err != nil {
+		return nil, err
+	}
+	if b.sessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", b.sessionToken)
+	}
+	b.sign(request, body)
+	return request, nil
+}
+
+// sign adds the authorization header of aws signature version 4 to the request, which signs every header of the
+// request along with the host.
+func (b *s3Backend) sign(request *http.Request, body []byte) {
+	now := b.now().UTC()
+	payloadHash := sha256.Sum256(body)
+	request.Header.Set("X-Amz-Date", now.Format(s3DateFormat))
+	request.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	headers := map[string]string{
+		"host": request.URL.Host,
+	}
+	for name, values := range request.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	date := now.Format("20060102")
+	scope := strings.Join([]string{date, b.region, s3Service, "aws4_request"}, "/")
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s3Algorithm, now.Format(s3DateFormat), scope, hex.EncodeToString(canonicalHash[:])}, "\n")
+	signingKey := hmacSHA256([]byte("AWS4"+b.secretKey), date)
+	for _, part := range []string{b.region, s3Service, "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", s3Algorithm, b.accessKey, scope, signedHeaders, signature))
+}
+
+// gcsBackend authorises its requests with an oauth access token, such as the one that the google-github-actions/auth
+// action exports.
+type gcsBackend struct {
+	endpoint *url.URL
+	bucket   string
+	token    string
+}
+
+func newGCSBackend(u *url.URL) (*gcsBackend, error) {
+	token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("environment variable GOOGLE_OAUTH_ACCESS_TOKEN is required for %s urls", URLSchemeGCS)
+	}
+	return &gcsBackend{
+		endpoint: &url.URL{Scheme: "https", Host: "storage.googleapis.com"},
+		bucket:   u.Host,
+		token:    token,
+	}, nil
+}
+
+func (b *gcsBackend) request(method, key string, body []byte) (*http.Request, error) {
+	request, err := newRequest(method, b.endpoint, "/"+b.bucket+"/"+key, "", body)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+b.token)
+	return request, nil
+}
+
+// azureBackend authorises its requests with a shared access signature of the storage account.
+type azureBackend struct {
+	endpoint  *url.URL
+	container string
+	sasToken  string
+}
+
+func newAzureBackend(u *url.URL) (*azureBackend, error) {
+	account, sasToken := os.Getenv("AZURE_STORAGE_ACCOUNT"), strings.TrimPrefix(os.Getenv("AZURE_STORAGE_SAS_TOKEN"), "?")
+	if account == "" || sasToken == "" {
+		return nil, fmt.Errorf("environment variables AZURE_STORAGE_ACCOUNT and AZURE_STORAGE_SAS_TOKEN are required for %s urls", URLSchemeAzure)
+	}
+	return &azureBackend{
+		endpoint:  &url.URL{Scheme: "https", Host: account + ".blob.core.windows.net"},
+		container: u.Host,
+		sasToken:  sasToken,
+	}, nil
+}
+
+func (b *azureBackend) request(method, key string, body []byte) (*http.Request, error) {
+	request, err := newRequest(method, b.endpoint, "/"+b.container+"/"+key, b.sasToken, body)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("X-Ms-Version", "2021-08-06")
+	if method == http.MethodPut {
+		request.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+	}
+	return request, nil
+}
+
+func newRequest(method string, endpoint *url.URL, objectPath, rawQuery string, body []byte) (*http.Request, error) {
+	u := *endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + objectPath
+	u.RawPath = ""
+	u.RawQuery = rawQuery
+	request, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failure creating %s request: %w", method, err)
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	return request, nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func validURLSchemes() []string {
+	return []string{
+		URLSchemeAzure,
+		URLSchemeGCS,
+		URLSchemeS3,
+	}
+}
@@ -0,0 +1,72 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+var ErrNotFound = errors.New("state not found")
+
+type Store interface {
+	Load(key string, v any) error
+	Save(key string, v any) error
+	Delete(key string) error
+}
+
+// FileStore persists each key as a json file in a local directory.
+type FileStore struct {
+	dir string
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failure creating state directory %s: %w", dir, err)
+	}
+	return &FileStore{
+		dir: dir,
+	}, nil
+}
+
+func (s *FileStore) Load(key string, v any) error {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failure reading state %s: %w", key, err)
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failure unmarshalling state %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *FileStore) Save(key string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failure marshalling state %s: %w", key, err)
+	}
+	tmp := s.path(key) + ".tmp"
+	if err = os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failure writing state %s: %w", key, err)
+	}
+	if err = os.Rename(tmp, s.path(key)); err != nil {
+		return fmt.Errorf("failure replacing state %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *FileStore) Delete(key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failure deleting state %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *FileStore) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
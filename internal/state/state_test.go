@@ -0,0 +1,172 @@
+package state
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dummyState struct {
+	Value string `json:"value"`
+}
+
+func TestFileStore(t *testing.T) {
+	tests := []struct {
+		name       string
+		assertions func(*assert.Assertions, *FileStore)
+	}{
+		{
+			name: "save and load",
+			assertions: func(assertions *assert.Assertions, store *FileStore) {
+				assertions.Nil(store.Save("key", dummyState{Value: "value"}))
+				var loaded dummyState
+				assertions.Nil(store.Load("key", &loaded))
+				assertions.Equal("value", loaded.Value)
+			},
+		},
+		{
+			name: "load missing key",
+			assertions: func(assertions *assert.Assertions, store *FileStore) {
+				var loaded dummyState
+				assertions.ErrorIs(store.Load("missing", &loaded), ErrNotFound)
+			},
+		},
+		{
+			name: "delete key",
+			assertions: func(assertions *assert.Assertions, store *FileStore) {
+				assertions.Nil(store.Save("key", dummyState{Value: "value"}))
+				assertions.Nil(store.Delete("key"))
+				assertions.Nil(store.Delete("key"))
+				var loaded dummyState
+				assertions.ErrorIs(store.Load("key", &loaded), ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewFileStore(filepath.Join(t.TempDir(), "state"))
+			require.Nil(t, err)
+			tt.assertions(assert.New(t), store)
+		})
+	}
+}
+
+func TestKeyringStore(t *testing.T) {
+	tests := []struct {
+		name       string
+		goos       string
+		assertions func(*assert.Assertions, *KeyringStore, *FileStore)
+	}{
+		{
+			name: "save and load from keyring",
+			goos: "linux",
+			assertions: func(assertions *assert.Assertions, store *KeyringStore, fallback *FileStore) {
+				assertions.Nil(store.Save("key", dummyState{Value: "value"}))
+				var loaded dummyState
+				assertions.Nil(store.Load("key", &loaded))
+				assertions.Equal("value", loaded.Value)
+				assertions.ErrorIs(fallback.Load("key", &loaded), ErrNotFound)
+			},
+		},
+		{
+			name: "save and load from fallback when keyring is not supported",
+			goos: "windows",
+			assertions: func(assertions *assert.Assertions, store *KeyringStore, fallback *FileStore) {
+				assertions.Nil(store.Save("key", dummyState{Value: "value"}))
+				var loaded dummyState
+				assertions.Nil(store.Load("key", &loaded))
+				assertions.Equal("value", loaded.Value)
+				assertions.Nil(fallback.Load("key", &loaded))
+			},
+		},
+		{
+			name: "delete key",
+			goos: "darwin",
+			assertions: func(assertions *assert.Assertions, store *KeyringStore, fallback *FileStore) {
+				assertions.Nil(store.Save("key", dummyState{Value: "value"}))
+				assertions.Nil(store.Delete("key"))
+				var loaded dummyState
+				assertions.ErrorIs(store.Load("key", &loaded), ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fallback, err := NewFileStore(filepath.Join(t.TempDir(), "state"))
+			require.Nil(t, err)
+			store := NewKeyringStore("its", fallback)
+			store.goos = tt.goos
+			store.run = fakeKeyring(make(map[string]string))
+			tt.assertions(assert.New(t), store, fallback)
+		})
+	}
+}
+
+// fakeKeyring emulates the keyring tools, keeping the secrets in memory by account name.
+func fakeKeyring(secrets map[string]string) runner {
+	return func(stdin []byte, name string, args ...string) ([]byte, error) {
+		var (
+			command = args[0]
+			account = args[len(args)-1]
+		)
+		switch command {
+		case "add-generic-password":
+			secrets[args[len(args)-3]] = args[len(args)-1]
+		case "store":
+			secrets[account] = string(stdin)
+		case "find-generic-password", "lookup":
+			if command == "find-generic-password" {
+				account = args[len(args)-2]
+			}
+			if secret, ok := secrets[account]; ok {
+				return []byte(secret), nil
+			}
+			return nil, &exec.ExitError{}
+		case "delete-generic-password", "clear":
+			delete(secrets, account)
+		}
+		return nil, nil
+	}
+}
+
+func TestEncryptedStore(t *testing.T) {
+	tests := []struct {
+		name       string
+		assertions func(*assert.Assertions, *EncryptedStore, *FileStore)
+	}{
+		{
+			name: "save and load",
+			assertions: func(assertions *assert.Assertions, store *EncryptedStore, next *FileStore) {
+				assertions.Nil(store.Save("key", dummyState{Value: "value"}))
+				var loaded dummyState
+				assertions.Nil(store.Load("key", &loaded))
+				assertions.Equal("value", loaded.Value)
+				var raw []byte
+				assertions.Nil(next.Load("key-encrypted", &raw))
+				assertions.NotContains(string(raw), "value")
+			},
+		},
+		{
+			name: "failure decrypting with another passphrase",
+			assertions: func(assertions *assert.Assertions, store *EncryptedStore, next *FileStore) {
+				assertions.Nil(store.Save("key", dummyState{Value: "value"}))
+				other, err := NewEncryptedStore(next, "other")
+				assertions.Nil(err)
+				var loaded dummyState
+				assertions.ErrorContains(other.Load("key", &loaded), "failure decrypting state")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := NewFileStore(filepath.Join(t.TempDir(), "state"))
+			require.Nil(t, err)
+			store, err := NewEncryptedStore(next, "passphrase")
+			require.Nil(t, err)
+			tt.assertions(assert.New(t), store, next)
+		})
+	}
+}
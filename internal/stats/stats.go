@@ -0,0 +1,163 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSON  = "json"
+	FormatTable = "table"
+
+	operationAdd    = "add"
+	operationRemove = "remove"
+
+	historyLimit = 1000
+	stateKey     = "sync-stats"
+)
+
+// Run holds the statistics of a single sync run.
+type Run struct {
+	StartedAt   time.Time                 `json:"startedAt"`
+	Duration    time.Duration             `json:"duration"`
+	Mode        string                    `json:"mode"`
+	Status      string                    `json:"status"`
+	Items       map[string]map[string]int `json:"items"`
+	Failures    int                       `json:"failures"`
+	NotFound    int                       `json:"notFound"`
+	Unsupported int                       `json:"unsupported"`
+}
+
+// Added returns the number of items that the run added to the destination, across all entities.
+func (r Run) Added() int {
+	return r.count(operationAdd)
+}
+
+// Removed returns the number of items that the run removed from the destination, across all entities.
+func (r Run) Removed() int {
+	return r.count(operationRemove)
+}
+
+func (r Run) count(operation string) int {
+	var count int
+	for _, operations := range r.Items {
+		count += operations[operation]
+	}
+	return count
+}
+
+// Load returns the statistics of the previous sync runs, from the oldest to the most recent.
+func Load(store state.Store) ([]Run, error) {
+	var runs []Run
+	if err := store.Load(stateKey, &runs); err != nil && !errors.Is(err, state.ErrNotFound) {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// Append adds the statistics of a sync run to the history, dropping the oldest runs once the history is full.
+func Append(store state.Store, run Run) error {
+	runs, err := Load(store)
+	if err != nil {
+		return err
+	}
+	runs = append(runs, run)
+	if len(runs) > historyLimit {
+		runs = runs[len(runs)-historyLimit:]
+	}
+	return store.Save(stateKey, runs)
+}
+
+// Write prints the runs in the given format, which is either a table for reading or csv and json for graphing.
+func Write(w io.Writer, runs []Run, format string) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, runs)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if runs == nil {
+			runs = make([]Run, 0)
+		}
+		return encoder.Encode(runs)
+	case FormatTable:
+		return writeTable(w, runs)
+	default:
+		return fmt.Errorf("unsupported format %s, must be one of: %s", format, strings.Join(Formats(), ", "))
+	}
+}
+
+// Formats returns the formats that the runs can be printed in.
+func Formats() []string {
+	return []string{
+		FormatTable,
+		FormatCSV,
+		FormatJSON,
+	}
+}
+
+func writeCSV(w io.Writer, runs []Run) error {
+	writer := csv.NewWriter(w)
+	records := [][]string{
+		{"started_at", "mode", "status", "duration_seconds", "added", "removed", "failures", "not_found", "unsupported"},
+	}
+	for _, run := range runs {
+		records = append(records, []string{
+			run.StartedAt.UTC().Format(time.RFC3339),
+			run.Mode,
+			run.Status,
+			strconv.FormatFloat(run.Duration.Seconds(), 'f', 0, 64),
+			strconv.Itoa(run.Added()),
+			strconv.Itoa(run.Removed()),
+			strconv.Itoa(run.Failures),
+			strconv.Itoa(run.NotFound),
+			strconv.Itoa(run.Unsupported),
+		})
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("failure writing csv: %w", err)
+	}
+	return nil
+}
+
+func writeTable(w io.Writer, runs []Run) error {
+	if len(runs) == 0 {
+		_, err := fmt.Fprintln(w, "No sync runs recorded yet")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "STARTED\tMODE\tSTATUS\tDURATION\tADDED\tREMOVED\tFAILURES\tNOT FOUND")
+	var added, removed, failed int
+	for _, run := range runs {
+		added, removed = added+run.Added(), removed+run.Removed()
+		if run.Failures > 0 {
+			failed++
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\n",
+			run.StartedAt.Local().Format(time.DateTime),
+			run.Mode,
+			run.Status,
+			run.Duration.Round(time.Second),
+			run.Added(),
+			run.Removed(),
+			run.Failures,
+			run.NotFound,
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failure writing table: %w", err)
+	}
+	_, err := fmt.Fprintf(w, "\n%d run(s) since %s: %d item(s) added, %d item(s) removed, %d run(s) with failures\n",
+		len(runs), runs[0].StartedAt.Local().Format(time.DateTime), added, removed, failed)
+	return err
+}
@@ -0,0 +1,114 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+)
+
+func TestAppend(t *testing.T) {
+	store, err := state.NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	runs, err := Load(store)
+	assert.NoError(t, err)
+	assert.Empty(t, runs)
+	assert.NoError(t, store.Save(stateKey, make([]Run, historyLimit)))
+	assert.NoError(t, Append(store, Run{Mode: "full"}))
+	runs, err = Load(store)
+	assert.NoError(t, err)
+	assert.Len(t, runs, historyLimit)
+	assert.Equal(t, "full", runs[len(runs)-1].Mode)
+}
+
+func TestWrite(t *testing.T) {
+	startedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	runs := []Run{
+		{
+			StartedAt: startedAt,
+			Duration:  time.Second * 90,
+			Mode:      "full",
+			Status:    "completed",
+			Items: map[string]map[string]int{
+				"lists":   {"add": 3, "remove": 1},
+				"ratings": {"add": 2},
+			},
+		},
+		{
+			StartedAt: startedAt.Add(time.Hour),
+			Mode:      "full",
+			Status:    "partially failed",
+			Failures:  1,
+			NotFound:  4,
+		},
+	}
+	tests := []struct {
+		name       string
+		runs       []Run
+		format     string
+		assertions func(*assert.Assertions, string, error)
+	}{
+		{
+			name:   "csv",
+			runs:   runs,
+			format: FormatCSV,
+			assertions: func(assertions *assert.Assertions, out string, err error) {
+				assertions.NoError(err)
+				expected := "started_at,mode,status,duration_seconds,added,removed,failures,not_found,unsupported\n" +
+					"2025-01-02T03:04:05Z,full,completed,90,5,1,0,0,0\n" +
+					"2025-01-02T04:04:05Z,full,partially failed,0,0,0,1,4,0\n"
+				assertions.Equal(expected, out)
+			},
+		},
+		{
+			name:   "json",
+			runs:   runs,
+			format: FormatJSON,
+			assertions: func(assertions *assert.Assertions, out string, err error) {
+				assertions.NoError(err)
+				var decoded []Run
+				assertions.NoError(json.Unmarshal([]byte(out), &decoded))
+				assertions.Len(decoded, 2)
+				assertions.Equal(5, decoded[0].Added())
+			},
+		},
+		{
+			name:   "table",
+			runs:   runs,
+			format: FormatTable,
+			assertions: func(assertions *assert.Assertions, out string, err error) {
+				assertions.NoError(err)
+				assertions.Contains(out, "STARTED")
+				assertions.Contains(out, "partially failed")
+				assertions.Contains(out, "2 run(s) since")
+				assertions.Contains(out, "5 item(s) added, 1 item(s) removed, 1 run(s) with failures")
+			},
+		},
+		{
+			name:   "empty table",
+			format: FormatTable,
+			assertions: func(assertions *assert.Assertions, out string, err error) {
+				assertions.NoError(err)
+				assertions.Equal("No sync runs recorded yet\n", out)
+			},
+		},
+		{
+			name:   "unsupported format",
+			format: "xml",
+			assertions: func(assertions *assert.Assertions, out string, err error) {
+				assertions.ErrorContains(err, "unsupported format xml")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := Write(&out, tt.runs, tt.format)
+			tt.assertions(assert.New(t), out.String(), err)
+		})
+	}
+}
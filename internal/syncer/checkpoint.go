@@ -0,0 +1,119 @@
+package syncer
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/budget"
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+const stateKeyCheckpoint = "sync-checkpoint"
+
+// checkpoint records the progress of a sync that ran out of its time budget, so that the next sync resumes where it
+// stopped, rather than starting over with the steps that were already synced.
+type checkpoint struct {
+	CreatedAt time.Time `json:"createdAt"`
+	// Mode is the sync mode of the sync that saved the checkpoint, which is only resumed by a sync of the same mode.
+	Mode string `json:"mode"`
+	// Steps are the names of the steps that were synced, and Lists are the ids of the imdb lists that the lists step
+	// synced before the budget ran out.
+	Steps []string `json:"steps"`
+	Lists []string `json:"lists"`
+}
+
+// loadCheckpoint returns the checkpoint of the previous sync, or an empty checkpoint when the previous sync completed.
+func (s *Syncer) loadCheckpoint() (*checkpoint, error) {
+	var cp checkpoint
+	if err := s.store.Load(stateKeyCheckpoint, &cp); err != nil {
+		if errors.Is(err, state.ErrNotFound) {
+			return &checkpoint{}, nil
+		}
+		return nil, fmt.Errorf("failure loading sync checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+// resumeCheckpoint continues from the checkpoint of the previous sync. Dry runs change nothing, so they neither resume
+// a checkpoint, nor leave one behind, and a checkpoint that was saved by a sync of another mode is discarded.
+func (s *Syncer) resumeCheckpoint() {
+	s.checkpoint = &checkpoint{}
+	mode := *s.conf.Mode
+	if mode == appconfig.SyncModeDryRun {
+		return
+	}
+	cp, err := s.loadCheckpoint()
+	switch {
+	case err != nil:
+		s.logger.Warn("failure loading sync checkpoint, starting over", logger.Error(err))
+	case cp.CreatedAt.IsZero():
+	case cp.Mode != mode:
+		s.logger.Info(fmt.Sprintf("discarding the checkpoint of a sync in mode %s, since this sync is in mode %s", cp.Mode, mode))
+	default:
+		s.logger.Info("resuming the sync that ran out of its time budget", slog.Time("checkpoint", cp.CreatedAt))
+		s.checkpoint = cp
+	}
+}
+
+// finishCheckpoint saves the checkpoint of a sync that ran out of its time budget, and deletes the checkpoint once a
+// sync ran every step. A sync that was aborted otherwise keeps the checkpoint of the previous sync.
+func (s *Syncer) finishCheckpoint(exhausted, completed bool) {
+	if exhausted {
+		s.summary.Paused = true
+	}
+	if *s.conf.Mode == appconfig.SyncModeDryRun {
+		return
+	}
+	switch {
+	case exhausted:
+		if err := s.saveCheckpoint(); err != nil {
+			s.logger.Warn("failure saving sync checkpoint", logger.Error(err))
+		}
+		s.logger.Warn("sync ran out of its time budget, the next sync resumes where it stopped")
+	case completed:
+		if err := s.clearCheckpoint(); err != nil {
+			s.logger.Warn("failure clearing sync checkpoint", logger.Error(err))
+		}
+	}
+}
+
+func (s *Syncer) saveCheckpoint() error {
+	s.checkpoint.CreatedAt = time.Now().UTC()
+	s.checkpoint.Mode = *s.conf.Mode
+	if err := s.store.Save(stateKeyCheckpoint, s.checkpoint); err != nil {
+		return fmt.Errorf("failure saving sync checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (s *Syncer) clearCheckpoint() error {
+	if err := s.store.Delete(stateKeyCheckpoint); err != nil {
+		return fmt.Errorf("failure deleting sync checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (c *checkpoint) hasStep(name string) bool {
+	return slices.Contains(c.Steps, name)
+}
+
+func (c *checkpoint) hasList(lid string) bool {
+	return slices.Contains(c.Lists, lid)
+}
+
+// withoutExhausted leaves out the errors of the targets that were not synced since the time budget ran out, which are
+// not failures, and returns the remaining errors.
+func withoutExhausted(err error) error {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	return errors.Join(slices.DeleteFunc(slices.Clone(errs), func(err error) bool {
+		return errors.Is(err, budget.ErrExhausted)
+	})...)
+}
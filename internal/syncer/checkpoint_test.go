@@ -0,0 +1,102 @@
+package syncer
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+)
+
+func buildTestSyncer(t *testing.T, store state.Store, mode string) *Syncer {
+	t.Helper()
+	return &Syncer{
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+		store:      store,
+		conf:       appconfig.Sync{Mode: &mode},
+		summary:    newSummary(),
+		checkpoint: &checkpoint{},
+	}
+}
+
+func TestSyncer_checkpoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		saveMode   string
+		resumeMode string
+		exhausted  bool
+		completed  bool
+		assertions func(*assert.Assertions, *Syncer, state.Store)
+	}{
+		{
+			name:       "resume the checkpoint of an exhausted sync",
+			saveMode:   appconfig.SyncModeFull,
+			resumeMode: appconfig.SyncModeFull,
+			exhausted:  true,
+			assertions: func(assertions *assert.Assertions, s *Syncer, _ state.Store) {
+				assertions.Equal(appconfig.SyncModeFull, s.checkpoint.Mode)
+				assertions.Equal([]string{"watchlist"}, s.checkpoint.Steps)
+				assertions.Equal([]string{"ls000000001"}, s.checkpoint.Lists)
+				assertions.True(s.checkpoint.hasStep("watchlist"))
+			},
+		},
+		{
+			name:       "discard the checkpoint of a sync in another mode",
+			saveMode:   appconfig.SyncModeAddOnly,
+			resumeMode: appconfig.SyncModeFull,
+			exhausted:  true,
+			assertions: func(assertions *assert.Assertions, s *Syncer, _ state.Store) {
+				assertions.Empty(s.checkpoint.Steps)
+				assertions.Empty(s.checkpoint.Lists)
+			},
+		},
+		{
+			name:       "do not save the checkpoint of a dry run",
+			saveMode:   appconfig.SyncModeDryRun,
+			resumeMode: appconfig.SyncModeFull,
+			exhausted:  true,
+			assertions: func(assertions *assert.Assertions, s *Syncer, store state.Store) {
+				assertions.Empty(s.checkpoint.Steps)
+				assertions.ErrorIs(store.Load(stateKeyCheckpoint, &checkpoint{}), state.ErrNotFound)
+			},
+		},
+		{
+			name:       "do not resume a checkpoint in a dry run",
+			saveMode:   appconfig.SyncModeFull,
+			resumeMode: appconfig.SyncModeDryRun,
+			exhausted:  true,
+			assertions: func(assertions *assert.Assertions, s *Syncer, store state.Store) {
+				assertions.Empty(s.checkpoint.Steps)
+				assertions.NoError(store.Load(stateKeyCheckpoint, &checkpoint{}))
+			},
+		},
+		{
+			name:       "clear the checkpoint of a completed sync",
+			saveMode:   appconfig.SyncModeFull,
+			resumeMode: appconfig.SyncModeFull,
+			completed:  true,
+			assertions: func(assertions *assert.Assertions, s *Syncer, store state.Store) {
+				assertions.Empty(s.checkpoint.Steps)
+				assertions.ErrorIs(store.Load(stateKeyCheckpoint, &checkpoint{}), state.ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := state.NewFileStore(t.TempDir())
+			assert.NoError(t, err)
+			saver := buildTestSyncer(t, store, tt.saveMode)
+			saver.resumeCheckpoint()
+			saver.checkpoint.Steps = append(saver.checkpoint.Steps, "watchlist")
+			saver.checkpoint.Lists = append(saver.checkpoint.Lists, "ls000000001")
+			saver.finishCheckpoint(tt.exhausted, tt.completed)
+			assert.Equal(t, tt.exhausted, saver.summary.Paused)
+			resumer := buildTestSyncer(t, store, tt.resumeMode)
+			resumer.resumeCheckpoint()
+			tt.assertions(assert.New(t), resumer, store)
+		})
+	}
+}
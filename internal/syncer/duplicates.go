@@ -0,0 +1,92 @@
+package syncer
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+)
+
+// resolveDuplicates reports the items that appear in more than one imdb list when SYNC_DUPLICATES is enabled, and then
+// leaves the items of each list out of the lists that it takes precedence over, so that syncing the lists removes them
+// from the trakt counterparts of the latter.
+func (s *Syncer) resolveDuplicates() {
+	if *s.conf.Duplicates {
+		s.reportDuplicates()
+	}
+	for _, rule := range s.conf.PrecedenceRules() {
+		winner, found := s.precedenceList(rule.Winner)
+		if !found {
+			s.logger.Info(fmt.Sprintf("skipping precedence rule %s>%s since imdb list %s is not synced", rule.Winner, rule.Loser, rule.Winner))
+			continue
+		}
+		loser, found := s.precedenceList(rule.Loser)
+		if !found {
+			s.logger.Info(fmt.Sprintf("skipping precedence rule %s>%s since imdb list %s is not synced", rule.Winner, rule.Loser, rule.Loser))
+			continue
+		}
+		ids := make(map[string]struct{}, len(winner.ListItems))
+		for _, item := range winner.ListItems {
+			ids[item.ID] = struct{}{}
+		}
+		kept := make([]entities.IMDbItem, 0, len(loser.ListItems))
+		for _, item := range loser.ListItems {
+			if _, ok := ids[item.ID]; !ok {
+				kept = append(kept, item)
+			}
+		}
+		if left := len(loser.ListItems) - len(kept); left > 0 {
+			s.logger.Info(fmt.Sprintf("leaving %d item(s) of imdb list %s out of imdb list %s", left, winner.ListID, loser.ListID))
+		}
+		loser.ListItems = kept
+		s.user.imdbLists[loser.ListID] = loser
+	}
+}
+
+// precedenceList finds the imdb list that a precedence rule refers to, which is either a list id or the watchlist.
+func (s *Syncer) precedenceList(ref string) (entities.IMDbList, bool) {
+	if ref != appconfig.ListWatchlist {
+		list, found := s.user.imdbLists[ref]
+		return list, found
+	}
+	for _, list := range s.user.imdbLists {
+		if list.IsWatchlist {
+			return list, true
+		}
+	}
+	return entities.IMDbList{}, false
+}
+
+func (s *Syncer) reportDuplicates() {
+	lists := make(map[string][]string)
+	titles := make(map[string]string)
+	for _, lid := range slices.Sorted(maps.Keys(s.user.imdbLists)) {
+		for _, item := range s.user.imdbLists[lid].ListItems {
+			if !slices.Contains(lists[item.ID], lid) {
+				lists[item.ID] = append(lists[item.ID], lid)
+			}
+			if item.Title != "" {
+				titles[item.ID] = item.Title
+			}
+		}
+	}
+	var count int
+	for _, id := range slices.Sorted(maps.Keys(lists)) {
+		if len(lists[id]) < 2 {
+			continue
+		}
+		count++
+		s.summary.addDuplicate(DuplicateItem{
+			IMDb:  id,
+			Title: titles[id],
+			Lists: lists[id],
+		})
+		s.logger.Debug(fmt.Sprintf("item %s appears in more than one imdb list", id), slog.Any("lists", lists[id]))
+	}
+	if count > 0 {
+		s.logger.Warn(fmt.Sprintf("found %d item(s) that appear in more than one imdb list", count))
+	}
+}
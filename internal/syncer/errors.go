@@ -0,0 +1,115 @@
+package syncer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+const (
+	exitCodeFailure = 1
+
+	errorCategoryAuth      = "auth"
+	errorCategoryRateLimit = "rate-limit"
+	errorCategoryNotFound  = "not-found"
+	errorCategoryTransient = "transient"
+)
+
+// exitCodes maps each entity to a distinct bit of the exit code, so that scripts can tell which entities failed to
+// sync. Entities that write to the same trakt data share a bit, since an exit code has no more than 8 bits.
+var exitCodes = map[string]int{
+	entityRatings:         1 << 1,
+	entityWatchlist:       1 << 2,
+	entityPlex:            1 << 2,
+	entityLists:           1 << 3,
+	entityHistory:         1 << 4,
+	entityCheckins:        1 << 4,
+	entityCollection:      1 << 5,
+	entityFavorites:       1 << 5,
+	entityReviews:         1 << 6,
+	entityWatchedList:     1 << 7,
+	entityRecommendations: 1 << 7,
+	entityHidden:          1 << 7,
+	entityReverseLists:    1 << 7,
+}
+
+// PartialFailureError is returned by a sync that failed to sync some entities, while the remaining entities were
+// synced successfully.
+type PartialFailureError struct {
+	Entities []string
+	Err      error
+}
+
+func (e *PartialFailureError) Error() string {
+	return fmt.Sprintf("failure syncing %s: %s", strings.Join(e.Entities, ", "), e.Err)
+}
+
+func (e *PartialFailureError) Unwrap() error {
+	return e.Err
+}
+
+// targetError attributes the failure of a single target of a sync step, such as a list, to an entity.
+type targetError struct {
+	entity string
+	target string
+	err    error
+}
+
+func (e *targetError) Error() string {
+	return e.err.Error()
+}
+
+func (e *targetError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode returns the exit code that describes the error returned by one or more syncs. Syncs that failed to sync
+// some entities set the bits of the failed entities, while any other failure results in a generic failure.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var code int
+		for _, err := range joined.Unwrap() {
+			code |= ExitCode(err)
+		}
+		return code
+	}
+	var partialErr *PartialFailureError
+	if !errors.As(err, &partialErr) {
+		return exitCodeFailure
+	}
+	var code int
+	for _, entity := range partialErr.Entities {
+		code |= exitCodes[entity]
+	}
+	if code == 0 {
+		return exitCodeFailure
+	}
+	return code
+}
+
+// errorCategory returns the category of a client error, so that the report tells failures that need fixing, such as
+// rejected credentials, apart from the ones that are likely to go away on the next sync. Other errors have no category.
+func errorCategory(err error) string {
+	var (
+		authErr      *client.AuthError
+		rateLimitErr *client.RateLimitError
+		notFoundErr  *client.NotFoundError
+		transientErr *client.TransientError
+	)
+	switch {
+	case errors.As(err, &authErr):
+		return errorCategoryAuth
+	case errors.As(err, &rateLimitErr):
+		return errorCategoryRateLimit
+	case errors.As(err, &notFoundErr):
+		return errorCategoryNotFound
+	case errors.As(err, &transientErr):
+		return errorCategoryTransient
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package syncer
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+)
+
+const (
+	stateKeyItemCounts = "imdb-item-counts"
+
+	// guardrailMinItems is the number of items a source must have had on the previous sync for the guardrail to apply,
+	// so that small lists can be emptied without having to force the sync.
+	guardrailMinItems = 10
+)
+
+// ErrGuardrail is returned when the imdb data is considered unreliable, since it shrank more than allowed.
+var ErrGuardrail = errors.New("guardrail triggered")
+
+// SetForce disables the guardrail, so that the sync proceeds even if the imdb data shrank unexpectedly.
+func (s *Syncer) SetForce(force bool) {
+	s.force = force
+	for _, peer := range s.peers {
+		peer.SetForce(force)
+	}
+}
+
+// checkGuardrail compares the number of imdb items against the previous sync and aborts when any source shrank by
+// more than the configured percentage, which usually indicates a broken imdb export rather than a genuine change.
+func (s *Syncer) checkGuardrail() error {
+	threshold := *s.conf.Guardrail
+	if threshold == 0 {
+		return nil
+	}
+	previous := make(map[string]int)
+	if err := s.store.Load(stateKeyItemCounts, &previous); err != nil {
+		if errors.Is(err, state.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failure loading imdb item counts: %w", err)
+	}
+	var violations int
+	for source, count := range s.user.imdbCounts {
+		last, ok := previous[source]
+		if !ok || last < guardrailMinItems || count >= last {
+			continue
+		}
+		drop := (last - count) * 100 / last
+		if drop <= threshold {
+			continue
+		}
+		violations++
+		s.logger.Warn(
+			fmt.Sprintf("imdb %s shrank by %d%% since the previous sync", source, drop),
+			slog.Int("previous", last),
+			slog.Int("current", count),
+		)
+	}
+	if violations == 0 {
+		return nil
+	}
+	if s.force {
+		s.logger.Warn("proceeding with the sync despite the guardrail, since it was forced")
+		return nil
+	}
+	return fmt.Errorf("%w: %d imdb source(s) shrank by more than %d%%, use --force to sync anyway", ErrGuardrail, violations, threshold)
+}
+
+// saveItemCounts records the number of imdb items of each source, keeping the counts of the sources that were not
+// fetched during this sync.
+func (s *Syncer) saveItemCounts() error {
+	counts := make(map[string]int)
+	if err := s.store.Load(stateKeyItemCounts, &counts); err != nil && !errors.Is(err, state.ErrNotFound) {
+		return fmt.Errorf("failure loading imdb item counts: %w", err)
+	}
+	maps.Copy(counts, s.user.imdbCounts)
+	return s.store.Save(stateKeyItemCounts, counts)
+}
@@ -0,0 +1,46 @@
+package syncer
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+)
+
+// Change is a set of items that a sync step is about to add to or remove from the destination.
+type Change struct {
+	Entity    string
+	Operation string
+	Target    string
+	Items     entities.TraktItems
+}
+
+// Reviewer lets the user approve the items of a change before it is applied and returns the approved items.
+type Reviewer func(change Change) (entities.TraktItems, error)
+
+// SetReviewer enables the interactive review of every change before it is applied to the destination.
+func (s *Syncer) SetReviewer(reviewer Reviewer) {
+	s.reviewer = reviewer
+	for _, peer := range s.peers {
+		peer.SetReviewer(reviewer)
+	}
+}
+
+func (s *Syncer) review(entity, operation, target string, items entities.TraktItems) (entities.TraktItems, error) {
+	if s.reviewer == nil || len(items) == 0 {
+		return items, nil
+	}
+	approved, err := s.reviewer(Change{
+		Entity:    entity,
+		Operation: operation,
+		Target:    target,
+		Items:     items,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failure reviewing %s %s changes: %w", entity, operation, err)
+	}
+	if rejected := len(items) - len(approved); rejected > 0 {
+		s.logger.Info(fmt.Sprintf("rejected %d %s item(s) to %s", rejected, entity, operation), slog.String("target", target))
+	}
+	return approved, nil
+}
@@ -0,0 +1,282 @@
+package syncer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+var (
+	errSnapshotMissing  = errors.New("imdb data is missing from the snapshot")
+	errSnapshotReadOnly = errors.New("imdb can't be changed while replaying a snapshot")
+)
+
+// snapshot shares the imdb data between the syncers of several destinations, so that imdb is scraped once per sync, no
+// matter how many destinations the data is synced to. Each call is made once per distinct set of arguments, and its
+// outcome is handed to every later caller. Calls that change imdb are passed through as they are.
+//
+// The imdb client is not safe for concurrent use, since it drives a single browser tab, so every call that is forwarded
+// to it is made while holding a lock, including the calls that destination syncers make at the same time with
+// different arguments.
+//
+// The outcome of the calls can be saved to a file, which a later sync replays instead of fetching the data from imdb,
+// so that a sync can be reproduced from the exact same imdb data.
+type snapshot struct {
+	client.IMDbClientInterface
+	mu        sync.Mutex
+	clientMu  sync.Mutex
+	calls     map[string]*snapshotCall
+	replay    map[string]json.RawMessage
+	closeOnce sync.Once
+}
+
+type snapshotCall struct {
+	once  sync.Once
+	value any
+	err   error
+}
+
+type snapshotFile struct {
+	CreatedAt time.Time                  `json:"createdAt"`
+	Calls     map[string]json.RawMessage `json:"calls"`
+}
+
+func newSnapshot(imdbClient client.IMDbClientInterface) *snapshot {
+	return &snapshot{
+		IMDbClientInterface: imdbClient,
+		calls:               make(map[string]*snapshotCall),
+	}
+}
+
+// loadSnapshot reads a snapshot that was saved by an earlier sync, and replays it without connecting to imdb.
+func loadSnapshot(path string) (*snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading imdb snapshot: %w", err)
+	}
+	var file snapshotFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failure unmarshalling imdb snapshot: %w", err)
+	}
+	s := newSnapshot(nil)
+	s.replay = file.Calls
+	if s.replay == nil {
+		s.replay = make(map[string]json.RawMessage)
+	}
+	return s, nil
+}
+
+// save writes the outcome of the successful calls to the file.
+func (s *snapshot) save(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file := snapshotFile{
+		CreatedAt: time.Now().UTC(),
+		Calls:     make(map[string]json.RawMessage, len(s.calls)),
+	}
+	for key, c := range s.calls {
+		if c.err != nil {
+			continue
+		}
+		data, err := json.Marshal(c.value)
+		if err != nil {
+			return fmt.Errorf("failure marshalling imdb snapshot call %s: %w", key, err)
+		}
+		file.Calls[key] = data
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failure marshalling imdb snapshot: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failure creating imdb snapshot directory: %w", err)
+		}
+	}
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failure writing imdb snapshot: %w", err)
+	}
+	return nil
+}
+
+// remember makes the call the first time that the key is seen, and returns its outcome every time after. Concurrent
+// callers of the same key wait for the first call to return. A replayed snapshot looks the outcome up in the snapshot
+// instead.
+func remember[T any](s *snapshot, key string, call func() (T, error)) (T, error) {
+	s.mu.Lock()
+	c, ok := s.calls[key]
+	if !ok {
+		c = &snapshotCall{}
+		s.calls[key] = c
+	}
+	s.mu.Unlock()
+	c.once.Do(func() {
+		if s.replay == nil {
+			s.clientMu.Lock()
+			defer s.clientMu.Unlock()
+			c.value, c.err = call()
+			return
+		}
+		raw, found := s.replay[key]
+		if !found {
+			c.err = fmt.Errorf("%w: %s", errSnapshotMissing, key)
+			return
+		}
+		var value T
+		c.err = json.Unmarshal(raw, &value)
+		c.value = value
+	})
+	value, _ := c.value.(T)
+	return value, c.err
+}
+
+// snapshotKey identifies a call by its method and arguments. The arguments are sorted, since the ids of the lists to
+// fetch are collected from a map, and their order does not change the outcome of the call.
+func snapshotKey(method string, args ...string) string {
+	return method + ":" + strings.Join(slices.Sorted(slices.Values(args)), ",")
+}
+
+func (s *snapshot) ListsExport(ids ...string) error {
+	_, err := remember(s, snapshotKey("ListsExport", ids...), func() (struct{}, error) {
+		return struct{}{}, s.IMDbClientInterface.ListsExport(ids...)
+	})
+	return err
+}
+
+func (s *snapshot) ListsGet(ids ...string) ([]entities.IMDbList, error) {
+	lists, err := remember(s, snapshotKey("ListsGet", ids...), func() ([]entities.IMDbList, error) {
+		return s.IMDbClientInterface.ListsGet(ids...)
+	})
+	return cloneLists(lists), err
+}
+
+func (s *snapshot) ListsGetAll() ([]entities.IMDbList, error) {
+	lists, err := remember(s, snapshotKey("ListsGetAll"), s.IMDbClientInterface.ListsGetAll)
+	return cloneLists(lists), err
+}
+
+func (s *snapshot) ListsModified(ids ...string) (map[string]time.Time, error) {
+	modified, err := remember(s, snapshotKey("ListsModified", ids...), func() (map[string]time.Time, error) {
+		return s.IMDbClientInterface.ListsModified(ids...)
+	})
+	return maps.Clone(modified), err
+}
+
+func (s *snapshot) WatchlistExport() error {
+	_, err := remember(s, snapshotKey("WatchlistExport"), func() (struct{}, error) {
+		return struct{}{}, s.IMDbClientInterface.WatchlistExport()
+	})
+	return err
+}
+
+func (s *snapshot) WatchlistGet() (*entities.IMDbList, error) {
+	list, err := remember(s, snapshotKey("WatchlistGet"), s.IMDbClientInterface.WatchlistGet)
+	return cloneList(list), err
+}
+
+func (s *snapshot) RatingsExport() error {
+	_, err := remember(s, snapshotKey("RatingsExport"), func() (struct{}, error) {
+		return struct{}{}, s.IMDbClientInterface.RatingsExport()
+	})
+	return err
+}
+
+func (s *snapshot) RatingsGet() ([]entities.IMDbItem, error) {
+	items, err := remember(s, snapshotKey("RatingsGet"), s.IMDbClientInterface.RatingsGet)
+	return slices.Clone(items), err
+}
+
+func (s *snapshot) CheckinsExport() error {
+	_, err := remember(s, snapshotKey("CheckinsExport"), func() (struct{}, error) {
+		return struct{}{}, s.IMDbClientInterface.CheckinsExport()
+	})
+	return err
+}
+
+func (s *snapshot) CheckinsGet() (*entities.IMDbList, error) {
+	list, err := remember(s, snapshotKey("CheckinsGet"), s.IMDbClientInterface.CheckinsGet)
+	return cloneList(list), err
+}
+
+func (s *snapshot) ChartGet(chart string) (*entities.IMDbList, error) {
+	list, err := remember(s, snapshotKey("ChartGet", chart), func() (*entities.IMDbList, error) {
+		return s.IMDbClientInterface.ChartGet(chart)
+	})
+	return cloneList(list), err
+}
+
+func (s *snapshot) ListCreate(name, description string) (string, error) {
+	if s.replay != nil {
+		return "", errSnapshotReadOnly
+	}
+	s.clientMu.Lock()
+	defer s.clientMu.Unlock()
+	return s.IMDbClientInterface.ListCreate(name, description)
+}
+
+func (s *snapshot) ListItemsAdd(id string, itemIDs ...string) error {
+	if s.replay != nil {
+		return errSnapshotReadOnly
+	}
+	s.clientMu.Lock()
+	defer s.clientMu.Unlock()
+	return s.IMDbClientInterface.ListItemsAdd(id, itemIDs...)
+}
+
+func (s *snapshot) ListItemsRemove(id string, itemIDs ...string) error {
+	if s.replay != nil {
+		return errSnapshotReadOnly
+	}
+	s.clientMu.Lock()
+	defer s.clientMu.Unlock()
+	return s.IMDbClientInterface.ListItemsRemove(id, itemIDs...)
+}
+
+func (s *snapshot) ReviewsGet() ([]entities.IMDbReview, error) {
+	reviews, err := remember(s, snapshotKey("ReviewsGet"), s.IMDbClientInterface.ReviewsGet)
+	return slices.Clone(reviews), err
+}
+
+// Close closes the imdb client once, since every syncer that shares the snapshot closes it. A replayed snapshot has
+// no imdb client to close.
+func (s *snapshot) Close() {
+	if s.IMDbClientInterface == nil {
+		return
+	}
+	s.closeOnce.Do(func() {
+		s.clientMu.Lock()
+		defer s.clientMu.Unlock()
+		s.IMDbClientInterface.Close()
+	})
+}
+
+// cloneList copies the items of the list, so that a syncer can rearrange them without affecting the other syncers.
+func cloneList(list *entities.IMDbList) *entities.IMDbList {
+	if list == nil {
+		return nil
+	}
+	clone := *list
+	clone.ListItems = slices.Clone(list.ListItems)
+	return &clone
+}
+
+func cloneLists(lists []entities.IMDbList) []entities.IMDbList {
+	if lists == nil {
+		return nil
+	}
+	clones := make([]entities.IMDbList, len(lists))
+	for i := range lists {
+		clones[i] = *cloneList(&lists[i])
+	}
+	return clones
+}
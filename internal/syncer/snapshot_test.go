@@ -0,0 +1,71 @@
+package syncer
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+// mockIMDbClient fails the calls that are made while another call is in flight, like a single browser tab would.
+type mockIMDbClient struct {
+	client.IMDbClientInterface
+	busy       atomic.Bool
+	concurrent atomic.Bool
+	listsGets  atomic.Int32
+}
+
+func (m *mockIMDbClient) use() {
+	if !m.busy.CompareAndSwap(false, true) {
+		m.concurrent.Store(true)
+		return
+	}
+	time.Sleep(time.Millisecond * 5)
+	m.busy.Store(false)
+}
+
+func (m *mockIMDbClient) ListsGet(ids ...string) ([]entities.IMDbList, error) {
+	m.use()
+	m.listsGets.Add(1)
+	lists := make([]entities.IMDbList, len(ids))
+	for i, id := range ids {
+		lists[i] = entities.IMDbList{ListID: id}
+	}
+	return lists, nil
+}
+
+func (m *mockIMDbClient) ListItemsAdd(string, ...string) error {
+	m.use()
+	return nil
+}
+
+func TestSnapshot(t *testing.T) {
+	imdbClient := &mockIMDbClient{}
+	s := newSnapshot(imdbClient)
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			ids := []string{"ls000000001", "ls000000002"}
+			if i%2 == 0 {
+				ids = []string{"ls000000002", "ls000000001"}
+			}
+			lists, err := s.ListsGet(ids...)
+			assert.NoError(t, err)
+			assert.Len(t, lists, 2)
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, s.ListItemsAdd("ls000000003", "tt0000001"))
+		}()
+	}
+	wg.Wait()
+	assert.False(t, imdbClient.concurrent.Load())
+	assert.Equal(t, int32(1), imdbClient.listsGets.Load())
+}
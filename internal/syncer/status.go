@@ -0,0 +1,87 @@
+package syncer
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+)
+
+// Drift describes how far the destination is out of sync with imdb for a single entity.
+type Drift struct {
+	Entity string
+	// Missing is the number of imdb items that the destination does not have.
+	Missing int
+	// Extra is the number of destination items that imdb does not have.
+	Extra int
+	// Differ is the number of items that both have, but with a different value, such as the rating.
+	Differ int
+}
+
+func (d Drift) String() string {
+	var parts []string
+	if d.Differ > 0 {
+		parts = append(parts, fmt.Sprintf("%d differ", d.Differ))
+	}
+	if d.Missing > 0 {
+		parts = append(parts, fmt.Sprintf("%d missing on trakt", d.Missing))
+	}
+	if d.Extra > 0 {
+		parts = append(parts, fmt.Sprintf("%d extra", d.Extra))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "in sync")
+	}
+	return fmt.Sprintf("%s: %s", d.Entity, strings.Join(parts, ", "))
+}
+
+// Status fetches the imdb and destination data like a sync would, and returns the drift of the watchlist, lists and
+// ratings without syncing anything. The sync mode is forced to dry-run, so that missing trakt lists are not created.
+func (s *Syncer) Status() ([]Drift, error) {
+	mode, skipUnchanged := appconfig.SyncModeDryRun, false
+	s.conf.Mode, s.conf.SkipUnchanged = &mode, &skipUnchanged
+	if err := s.hydrate(); err != nil {
+		return nil, err
+	}
+	var drifts []Drift
+	lists := Drift{Entity: entityLists}
+	for _, lid := range slices.Sorted(maps.Keys(s.user.imdbLists)) {
+		list := s.user.imdbLists[lid]
+		diff := entities.ListDifference(list, s.user.traktLists[lid], s.conf.ItemFilter(listEntity(list)))
+		if list.IsWatchlist {
+			drifts = append(drifts, Drift{
+				Entity:  entityWatchlist,
+				Missing: len(diff["add"]),
+				Extra:   len(diff["remove"]),
+			})
+			continue
+		}
+		lists.Missing += len(diff["add"])
+		lists.Extra += len(diff["remove"])
+	}
+	if *s.conf.Lists {
+		drifts = append(drifts, lists)
+	}
+	if *s.conf.Ratings && !s.authless {
+		imdbRatings, traktRatings := s.mapRatings()
+		diff := entities.ItemsDifference(imdbRatings, traktRatings, s.conf.ItemFilter(entityRatings))
+		ratings := Drift{
+			Entity: entityRatings,
+			Extra:  len(diff["remove"]),
+		}
+		for _, item := range diff["add"] {
+			if id, err := item.GetItemID(); err == nil && id != nil {
+				if _, found := traktRatings[*id]; found {
+					ratings.Differ++
+					continue
+				}
+			}
+			ratings.Missing++
+		}
+		drifts = append(drifts, ratings)
+	}
+	return drifts, nil
+}
@@ -0,0 +1,486 @@
+package syncer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/actions"
+	"github.com/cecobask/imdb-trakt-sync/internal/search"
+	"github.com/cecobask/imdb-trakt-sync/internal/stats"
+)
+
+const (
+	reportFileJSON      = "report.json"
+	reportFileNotFound  = "not_found.json"
+	reportFileMarkdown  = "report.md"
+	reportTitle         = "Sync report"
+	reportStatusOK      = "completed"
+	reportStatusFailed  = "failed"
+	reportStatusPartial = "partially failed"
+	reportStatusPaused  = "paused"
+)
+
+// Summary describes the outcome of a single sync run.
+type Summary struct {
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	Items       map[string]map[string]int
+	Failures    []Failure
+	NotFound    []NotFoundItem
+	Unsupported []UnsupportedItem
+	Duplicates  []DuplicateItem
+	// RatingChanges is the change log of the ratings that the sync added, overwrote or removed on the destination.
+	RatingChanges []RatingChange
+	// Destinations is the outcome of each destination, when the imdb data is synced to several destinations at once.
+	Destinations []DestinationStatus
+	// Paused reports that the sync ran out of its time budget, and that the next sync resumes where it stopped.
+	Paused bool
+}
+
+// DestinationStatus describes the outcome of the sync to one of several destinations.
+type DestinationStatus struct {
+	Destination string                    `json:"destination"`
+	Status      string                    `json:"status"`
+	Items       map[string]map[string]int `json:"items"`
+	Failures    int                       `json:"failures"`
+	NotFound    int                       `json:"notFound"`
+}
+
+// Failure describes an error that occurred while syncing an entity.
+type Failure struct {
+	Destination string `json:"destination,omitempty"`
+	Entity      string `json:"entity"`
+	Reason      string `json:"reason"`
+	Category    string `json:"category,omitempty"`
+}
+
+// NotFoundItem describes an item that the destination could not find, which usually means that its imdb id is not
+// linked to any title of the destination, and has to be fixed manually.
+type NotFoundItem struct {
+	Destination string `json:"destination,omitempty"`
+	Entity      string `json:"entity"`
+	Target      string `json:"target,omitempty"`
+	Type        string `json:"type"`
+	IMDb        string `json:"imdb"`
+	// Candidates are the titles that the search fallback found for the item, which were too ambiguous to sync the item
+	// as any of them, and can be used to map the item manually.
+	Candidates []search.Candidate `json:"candidates,omitempty"`
+}
+
+// UnsupportedItem describes an item of an imdb list that has no counterpart on the destination, such as a video game,
+// which is therefore left out of the sync.
+type UnsupportedItem struct {
+	Entity string `json:"entity"`
+	Target string `json:"target,omitempty"`
+	Kind   string `json:"kind"`
+	IMDb   string `json:"imdb"`
+	Title  string `json:"title,omitempty"`
+}
+
+// DuplicateItem describes an item that appears in more than one imdb list, which is only reported when SYNC_DUPLICATES
+// is enabled.
+type DuplicateItem struct {
+	IMDb  string   `json:"imdb"`
+	Title string   `json:"title,omitempty"`
+	Lists []string `json:"lists"`
+}
+
+// RatingChange describes a rating that the sync changed on the destination. Old is nil for a new rating and New is
+// nil for a removed rating. Source tells where the new rating comes from, which is the imdb rating itself, or the
+// imdb rating transformed by SYNC_RATINGSMAP. Outcome is skipped for the conflicts that SYNC_RATINGSCONFLICT left as
+// they are, in which case Old is the rating that was kept.
+type RatingChange struct {
+	Destination string `json:"destination,omitempty"`
+	IMDb        string `json:"imdb"`
+	Title       string `json:"title,omitempty"`
+	Type        string `json:"type"`
+	Old         *int   `json:"old"`
+	New         *int   `json:"new"`
+	Source      string `json:"source"`
+	Outcome     string `json:"outcome"`
+}
+
+type report struct {
+	Status        string                    `json:"status"`
+	StartedAt     time.Time                 `json:"startedAt"`
+	FinishedAt    time.Time                 `json:"finishedAt"`
+	Duration      string                    `json:"duration"`
+	Items         map[string]map[string]int `json:"items"`
+	Failures      []Failure                 `json:"failures"`
+	Unsupported   []UnsupportedItem         `json:"unsupported"`
+	Duplicates    []DuplicateItem           `json:"duplicates"`
+	RatingChanges []RatingChange            `json:"ratingChanges"`
+	Destinations  []DestinationStatus       `json:"destinations,omitempty"`
+}
+
+func newSummary() *Summary {
+	return &Summary{
+		StartedAt:     time.Now(),
+		Items:         make(map[string]map[string]int),
+		Failures:      make([]Failure, 0),
+		NotFound:      make([]NotFoundItem, 0),
+		Unsupported:   make([]UnsupportedItem, 0),
+		Duplicates:    make([]DuplicateItem, 0),
+		RatingChanges: make([]RatingChange, 0),
+	}
+}
+
+func (s *Summary) addItems(entity, operation string, count int) {
+	if s.Items[entity] == nil {
+		s.Items[entity] = make(map[string]int)
+	}
+	s.Items[entity][operation] += count
+}
+
+func (s *Summary) addNotFound(item NotFoundItem) {
+	s.NotFound = append(s.NotFound, item)
+}
+
+func (s *Summary) addUnsupported(item UnsupportedItem) {
+	s.Unsupported = append(s.Unsupported, item)
+}
+
+func (s *Summary) addDuplicate(item DuplicateItem) {
+	s.Duplicates = append(s.Duplicates, item)
+}
+
+func (s *Summary) addRatingChange(change RatingChange) {
+	s.RatingChanges = append(s.RatingChanges, change)
+}
+
+func (s *Summary) addFailure(entity string, err error) {
+	s.Failures = append(s.Failures, Failure{
+		Entity:   entity,
+		Reason:   err.Error(),
+		Category: errorCategory(err),
+	})
+}
+
+// merge adds the summary of the sync to one of several destinations, and records the outcome of the destination.
+// The unsupported and duplicate items come from the imdb data, which every destination shares, so they are only kept
+// once.
+func (s *Summary) merge(destination string, other *Summary) {
+	if s.Destinations == nil || other.StartedAt.Before(s.StartedAt) {
+		s.StartedAt = other.StartedAt
+	}
+	if other.FinishedAt.After(s.FinishedAt) {
+		s.FinishedAt = other.FinishedAt
+	}
+	s.Paused = s.Paused || other.Paused
+	s.Destinations = append(s.Destinations, DestinationStatus{
+		Destination: destination,
+		Status:      other.status(),
+		Items:       other.Items,
+		Failures:    len(other.Failures),
+		NotFound:    len(other.NotFound),
+	})
+	for entity, operations := range other.Items {
+		for operation, count := range operations {
+			s.addItems(entity, operation, count)
+		}
+	}
+	for _, failure := range other.Failures {
+		failure.Destination = destination
+		s.Failures = append(s.Failures, failure)
+	}
+	for _, item := range other.NotFound {
+		item.Destination = destination
+		s.addNotFound(item)
+	}
+	for _, change := range other.RatingChanges {
+		change.Destination = destination
+		s.addRatingChange(change)
+	}
+	for _, item := range other.Unsupported {
+		if !slices.Contains(s.Unsupported, item) {
+			s.addUnsupported(item)
+		}
+	}
+	for _, item := range other.Duplicates {
+		if !slices.ContainsFunc(s.Duplicates, func(duplicate DuplicateItem) bool { return duplicate.IMDb == item.IMDb }) {
+			s.addDuplicate(item)
+		}
+	}
+}
+
+// Duration returns how long the sync run took.
+func (s *Summary) Duration() time.Duration {
+	if s.FinishedAt.IsZero() {
+		return time.Since(s.StartedAt)
+	}
+	return s.FinishedAt.Sub(s.StartedAt)
+}
+
+// status reports a sync that was aborted before syncing any entity as failed, and a sync that failed to sync some
+// entities as partially failed. A sync to several destinations has only failed when every destination failed. A sync
+// that ran out of its time budget without failing is paused.
+func (s *Summary) status() string {
+	if len(s.Failures) == 0 {
+		if s.Paused {
+			return reportStatusPaused
+		}
+		return reportStatusOK
+	}
+	if len(s.Destinations) > 0 {
+		for _, destination := range s.Destinations {
+			if destination.Status != reportStatusFailed {
+				return reportStatusPartial
+			}
+		}
+		return reportStatusFailed
+	}
+	for _, failure := range s.Failures {
+		if failure.Entity == entityHydrate {
+			return reportStatusFailed
+		}
+	}
+	return reportStatusPartial
+}
+
+// run returns the statistics of the sync run, which are kept to show the history of the syncs.
+func (s *Summary) run(mode string) stats.Run {
+	return stats.Run{
+		StartedAt:   s.StartedAt.UTC(),
+		Duration:    s.Duration(),
+		Mode:        mode,
+		Status:      s.status(),
+		Items:       s.Items,
+		Failures:    len(s.Failures),
+		NotFound:    len(s.NotFound),
+		Unsupported: len(s.Unsupported),
+	}
+}
+
+func (s *Summary) String() string {
+	var synced []string
+	for _, l := range summaryLabels() {
+		var count int
+		for _, c := range s.Items[l.entity] {
+			count += c
+		}
+		if count > 0 {
+			synced = append(synced, fmt.Sprintf("%d %s", count, l.label))
+		}
+	}
+	message := "Synced nothing"
+	if len(synced) > 0 {
+		message = "Synced " + strings.Join(synced, ", ")
+	}
+	if len(s.NotFound) > 0 {
+		message += fmt.Sprintf(". %d item(s) were not found", len(s.NotFound))
+	}
+	if len(s.Unsupported) > 0 {
+		message += fmt.Sprintf(". %d item(s) are not supported", len(s.Unsupported))
+	}
+	if len(s.Duplicates) > 0 {
+		message += fmt.Sprintf(". %d item(s) appear in more than one list", len(s.Duplicates))
+	}
+	if len(s.Destinations) > 0 {
+		statuses := make([]string, len(s.Destinations))
+		for i, destination := range s.Destinations {
+			statuses[i] = fmt.Sprintf("%s %s", destination.Destination, destination.Status)
+		}
+		message += fmt.Sprintf(". Destinations: %s", strings.Join(statuses, ", "))
+	}
+	if len(s.Failures) > 0 {
+		reasons := make([]string, len(s.Failures))
+		for i, failure := range s.Failures {
+			reasons[i] = failure.Reason
+			if failure.Destination != "" {
+				reasons[i] = fmt.Sprintf("%s: %s", failure.Destination, failure.Reason)
+			}
+		}
+		if s.status() == reportStatusPartial {
+			return fmt.Sprintf("Sync partially failed: %s. %s", strings.Join(reasons, "; "), message)
+		}
+		return fmt.Sprintf("Sync failed: %s. %s", strings.Join(reasons, "; "), message)
+	}
+	if s.Paused {
+		return "Sync paused, since it ran out of its time budget. The next sync resumes where it stopped. " + message
+	}
+	return "Sync completed. " + message
+}
+
+// WriteReport writes the summary to the given directory as json and markdown files.
+func (s *Summary) WriteReport(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failure creating report directory: %w", err)
+	}
+	data, err := json.MarshalIndent(report{
+		Status:        s.status(),
+		StartedAt:     s.StartedAt.UTC(),
+		FinishedAt:    s.FinishedAt.UTC(),
+		Duration:      s.Duration().Round(time.Second).String(),
+		Items:         s.Items,
+		Failures:      s.Failures,
+		Unsupported:   s.Unsupported,
+		Duplicates:    s.Duplicates,
+		RatingChanges: s.RatingChanges,
+		Destinations:  s.Destinations,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failure marshalling json report: %w", err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, reportFileJSON), data, 0644); err != nil {
+		return fmt.Errorf("failure writing json report: %w", err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, reportFileMarkdown), []byte(s.Markdown(reportTitle)), 0644); err != nil {
+		return fmt.Errorf("failure writing markdown report: %w", err)
+	}
+	if data, err = json.MarshalIndent(s.NotFound, "", "  "); err != nil {
+		return fmt.Errorf("failure marshalling not found report: %w", err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, reportFileNotFound), data, 0644); err != nil {
+		return fmt.Errorf("failure writing not found report: %w", err)
+	}
+	return nil
+}
+
+// Markdown renders the summary as a markdown document with the given title, with a table of the changes per entity.
+func (s *Summary) Markdown(title string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s\n\n", title))
+	sb.WriteString(fmt.Sprintf("- **Status:** %s\n", s.status()))
+	sb.WriteString(fmt.Sprintf("- **Started at:** %s\n", s.StartedAt.UTC().Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("- **Duration:** %s\n\n", s.Duration().Round(time.Second)))
+	sb.WriteString("| Entity | Added | Removed |\n")
+	sb.WriteString("| --- | ---: | ---: |\n")
+	for _, l := range summaryLabels() {
+		sb.WriteString(fmt.Sprintf("| %s | %d | %d |\n", l.label, s.Items[l.entity][operationAdd], s.Items[l.entity][operationRemove]))
+	}
+	if len(s.Destinations) > 0 {
+		sb.WriteString("\n## Destinations\n\n")
+		sb.WriteString("| Destination | Status | Added | Removed | Failures | Not found |\n")
+		sb.WriteString("| --- | --- | ---: | ---: | ---: | ---: |\n")
+		for _, destination := range s.Destinations {
+			var added, removed int
+			for _, operations := range destination.Items {
+				added += operations[operationAdd]
+				removed += operations[operationRemove]
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %d | %d | %d | %d |\n", destination.Destination, destination.Status, added, removed, destination.Failures, destination.NotFound))
+		}
+	}
+	if len(s.NotFound) > 0 {
+		sb.WriteString("\n## Not found\n\n")
+		for _, item := range s.NotFound {
+			sb.WriteString(fmt.Sprintf("- **%s**: %s %s%s\n", qualified(item.Destination, item.Entity), item.Type, item.IMDb, formatCandidates(item.Candidates)))
+		}
+	}
+	if len(s.Unsupported) > 0 {
+		sb.WriteString("\n## Not supported\n\n")
+		for _, item := range s.Unsupported {
+			sb.WriteString(fmt.Sprintf("- **%s**: %s %s\n", item.Entity, item.Kind, titled(item.IMDb, item.Title)))
+		}
+	}
+	if len(s.Duplicates) > 0 {
+		sb.WriteString("\n## Duplicates\n\n")
+		for _, item := range s.Duplicates {
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", titled(item.IMDb, item.Title), strings.Join(item.Lists, ", ")))
+		}
+	}
+	if len(s.RatingChanges) > 0 {
+		sb.WriteString("\n## Rating changes\n\n")
+		sb.WriteString("| IMDb | Type | Old | New | Source | Outcome |\n")
+		sb.WriteString("| --- | --- | ---: | ---: | --- | --- |\n")
+		for _, change := range s.RatingChanges {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n", qualified(change.Destination, titled(change.IMDb, change.Title)), change.Type, formatRating(change.Old), formatRating(change.New), change.Source, change.Outcome))
+		}
+	}
+	if len(s.Failures) > 0 {
+		sb.WriteString("\n## Failures\n\n")
+		for _, failure := range s.Failures {
+			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", qualified(failure.Destination, failure.Entity), failure.Reason))
+		}
+	}
+	return sb.String()
+}
+
+// Annotate writes a workflow command for each failure of the sync, and another one with the outcome of the sync, so
+// that they stand out on the summary page of a GitHub Actions workflow run. The title names the sync, such as the
+// profile that was synced.
+func (s *Summary) Annotate(w io.Writer, title string) {
+	for _, failure := range s.Failures {
+		actions.Annotate(w, actions.LevelError, fmt.Sprintf("%s: %s failed", title, qualified(failure.Destination, failure.Entity)), failure.Reason)
+	}
+	if len(s.NotFound) > 0 {
+		ids := make([]string, len(s.NotFound))
+		for i, item := range s.NotFound {
+			ids[i] = item.IMDb
+		}
+		actions.Annotate(w, actions.LevelWarning, fmt.Sprintf("%s: items not found", title), fmt.Sprintf("%d item(s) were not found: %s", len(ids), strings.Join(ids, ", ")))
+	}
+	level := actions.LevelNotice
+	switch s.status() {
+	case reportStatusFailed:
+		level = actions.LevelError
+	case reportStatusPartial, reportStatusPaused:
+		level = actions.LevelWarning
+	}
+	actions.Annotate(w, level, title, s.String())
+}
+
+// qualified prefixes the entity with the destination it was synced to, when the sync had several destinations.
+func qualified(destination, entity string) string {
+	if destination == "" {
+		return entity
+	}
+	return destination + " " + entity
+}
+
+// titled appends the title to the imdb id, when the title is known from the imdb exports.
+func titled(id, title string) string {
+	if title == "" {
+		return id
+	}
+	return fmt.Sprintf("%s (%s)", id, title)
+}
+
+// formatCandidates lists the trakt ids of the candidates of an item that was not found, to be copied to the mapping file.
+func formatCandidates(candidates []search.Candidate) string {
+	if len(candidates) == 0 {
+		return ""
+	}
+	formatted := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		formatted[i] = fmt.Sprintf("%s (%d, trakt %d)", candidate.Title, candidate.Year, candidate.Trakt)
+	}
+	return " - candidates: " + strings.Join(formatted, ", ")
+}
+
+func formatRating(rating *int) string {
+	if rating == nil {
+		return "-"
+	}
+	return strconv.Itoa(*rating)
+}
+
+type summaryLabel struct {
+	entity string
+	label  string
+}
+
+func summaryLabels() []summaryLabel {
+	return []summaryLabel{
+		{entity: entityRatings, label: "ratings"},
+		{entity: entityWatchlist, label: "watchlist items"},
+		{entity: entityPlex, label: "plex watchlist items"},
+		{entity: entityLists, label: "list items"},
+		{entity: entityHistory, label: "history items"},
+		{entity: entityCheckins, label: "check-ins"},
+		{entity: entityFavorites, label: "favorites"},
+		{entity: entityReviews, label: "reviews"},
+		{entity: entityWatchedList, label: "imdb watched list items"},
+		{entity: entityRecommendations, label: "imdb recommendations list items"},
+		{entity: entityHidden, label: "hidden items"},
+		{entity: entityLikedLists, label: "liked lists"},
+		{entity: entityReverseLists, label: "imdb reverse list items"},
+	}
+}
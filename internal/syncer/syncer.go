@@ -5,52 +5,312 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"os"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/budget"
 	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
 	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/journal"
+	"github.com/cecobask/imdb-trakt-sync/internal/mapping"
+	"github.com/cecobask/imdb-trakt-sync/internal/metrics"
+	"github.com/cecobask/imdb-trakt-sync/internal/progress"
+	"github.com/cecobask/imdb-trakt-sync/internal/search"
+	"github.com/cecobask/imdb-trakt-sync/internal/skiplist"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/internal/stats"
+	"github.com/cecobask/imdb-trakt-sync/internal/tracing"
+	"github.com/cecobask/imdb-trakt-sync/internal/wal"
 	"github.com/cecobask/imdb-trakt-sync/pkg/client"
 	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
 )
 
+const (
+	entityCheckinList     = "checkinlist"
+	entityCheckins        = "checkins"
+	entityCollection      = "collection"
+	entityFavorites       = "favorites"
+	entityHidden          = "hidden"
+	entityHistory         = "history"
+	entityHydrate         = "hydrate"
+	entityLikedLists      = "likedlists"
+	entityLists           = "lists"
+	entityPlex            = "plex"
+	entityRatings         = "ratings"
+	entityRecommendations = "recommendations"
+	entityReverseLists    = "reverselists"
+	entityReviews         = "reviews"
+	entityWatchedList     = "watchedlist"
+	entityWatchlist       = "watchlist"
+	operationAdd          = "add"
+	operationRemove       = "remove"
+
+	traktCommentMinWords = 5
+
+	stateKeyLikedLists    = "trakt-liked-lists"
+	stateKeyListsModified = "imdb-lists-modified"
+	stateKeyNotFound      = "trakt-not-found"
+	stateKeyReverseLists  = "imdb-reverse-lists"
+
+	chartListIDPrefix       = "chart-"
+	upcomingListID          = "upcoming"
+	upcomingListDescription = "Titles of the IMDb watchlist that have not been released yet"
+
+	ratingSourceIMDb       = "imdb"
+	ratingSourceRatingsMap = "ratings-map"
+
+	ratingOutcomeApplied = "applied"
+	ratingOutcomeSkipped = "skipped"
+)
+
 type Syncer struct {
-	logger      *slog.Logger
-	imdbClient  client.IMDbClientInterface
-	traktClient client.TraktClientInterface
-	user        *user
-	conf        appconfig.Sync
-	authless    bool
+	ctx             context.Context
+	logger          *slog.Logger
+	imdbClient      client.IMDbClientInterface
+	destination     client.DestinationClientInterface
+	plexClient      client.PlexClientInterface
+	journal         *journal.Client
+	wal             *wal.Client
+	skipList        *skiplist.Client
+	search          *search.Client
+	reviewer        Reviewer
+	store           state.Store
+	user            *user
+	conf            appconfig.Sync
+	charts          map[string]string
+	authless        bool
+	publicWatchlist bool
+	force           bool
+	progress        *progress.Progress
+	summary         *Summary
+	checkpoint      *checkpoint
+	destinationName string
+	snapshotOut     string
+	// peers sync the same imdb snapshot to the destinations of SYNC_DESTINATIONS, alongside this syncer.
+	peers []*Syncer
+	// failedPeers are the destinations of SYNC_DESTINATIONS whose syncer could not be built.
+	failedPeers []failedPeer
+}
+
+type failedPeer struct {
+	destination string
+	err         error
 }
 
 type user struct {
-	imdbLists    map[string]entities.IMDbList
-	imdbRatings  map[string]entities.IMDbItem
-	traktLists   map[string]entities.TraktList
-	traktRatings map[string]entities.TraktItem
+	imdbCheckinList         []entities.IMDbItem
+	imdbCheckins            []entities.IMDbItem
+	imdbCollection          []entities.IMDbItem
+	imdbCounts              map[string]int
+	imdbFavorites           []entities.IMDbItem
+	imdbHidden              []entities.IMDbItem
+	imdbLists               map[string]entities.IMDbList
+	imdbListsModified       map[string]time.Time
+	imdbListsSyncedModified map[string]time.Time
+	imdbRatings             map[string]entities.IMDbItem
+	imdbReviews             []entities.IMDbReview
+	imdbRecommendations     []entities.IMDbItem
+	imdbWatchedList         []entities.IMDbItem
+	traktCollection         map[string]entities.TraktItem
+	traktComments           map[string]struct{}
+	traktFavorites          map[string]entities.TraktItem
+	traktHidden             map[string]map[string]entities.TraktItem
+	traktLimits             *entities.TraktLimits
+	traktListNames          map[string]string
+	traktLists              map[string]entities.TraktList
+	traktRatings            map[string]entities.TraktItem
+	traktRecommendations    map[string]entities.TraktItem
+	traktWatched            map[string]entities.TraktItem
+	traktWatchlist          *entities.TraktList
+}
+
+func NewSyncer(ctx context.Context, conf *appconfig.Config, log *slog.Logger) (*Syncer, error) {
+	log = logger.WithSyncID(log, logger.NewSyncID())
+	store, err := state.NewStore(*conf.State.Dir, *conf.State.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failure initialising state store: %w", err)
+	}
+	imdbClient, err := newSourceClient(ctx, conf, store, log)
+	if err != nil {
+		return nil, err
+	}
+	destinations := conf.Destinations()
+	if len(destinations) == 1 && *conf.IMDb.SnapshotOut == "" {
+		syncer, err := newDestinationSyncer(ctx, conf, imdbClient, store, log)
+		if err != nil {
+			imdbClient.Close()
+			return nil, err
+		}
+		return syncer, nil
+	}
+	if _, ok := imdbClient.(*snapshot); !ok {
+		imdbClient = newSnapshot(imdbClient)
+	}
+	if len(destinations) > 1 {
+		log = log.With(slog.String("destination", *conf.Sync.Destination))
+	}
+	syncer, err := newDestinationSyncer(ctx, conf, imdbClient, store, log)
+	if err != nil {
+		imdbClient.Close()
+		return nil, err
+	}
+	syncer.snapshotOut = *conf.IMDb.SnapshotOut
+	syncer.addPeers(destinations[1:], func(destinationConf *appconfig.Config) (*Syncer, error) {
+		destinationStore, err := state.NewStore(*destinationConf.State.Dir, *destinationConf.State.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failure initialising state store of %s: %w", *destinationConf.Sync.Destination, err)
+		}
+		return newDestinationSyncer(ctx, destinationConf, imdbClient, destinationStore, log.With(slog.String("destination", *destinationConf.Sync.Destination)))
+	})
+	return syncer, nil
+}
+
+// addPeers builds the syncers of the additional destinations. A destination whose clients cannot be built, such as one
+// that is unreachable or not authorised yet, is reported as failed by the sync, rather than preventing the sync of the
+// other destinations.
+func (s *Syncer) addPeers(destinations []*appconfig.Config, build func(*appconfig.Config) (*Syncer, error)) {
+	for _, destinationConf := range destinations {
+		destination := *destinationConf.Sync.Destination
+		peer, err := build(destinationConf)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("failure initialising the sync to %s", destination), logger.Error(err))
+			s.failedPeers = append(s.failedPeers, failedPeer{destination: destination, err: err})
+			continue
+		}
+		s.peers = append(s.peers, peer)
+	}
 }
 
-func NewSyncer(ctx context.Context, conf *appconfig.Config) (*Syncer, error) {
-	log := logger.NewLogger(os.Stdout)
-	imdbClient, err := client.NewIMDbClient(ctx, &conf.IMDb, log)
+// newSourceClient creates the client that the imdb data is fetched with, which replays a snapshot of the imdb data
+// instead when one is provided.
+func newSourceClient(ctx context.Context, conf *appconfig.Config, store state.Store, log *slog.Logger) (client.IMDbClientInterface, error) {
+	if *conf.IMDb.SnapshotIn != "" {
+		log.Info("replaying imdb snapshot instead of fetching imdb data", slog.String("path", *conf.IMDb.SnapshotIn))
+		return loadSnapshot(*conf.IMDb.SnapshotIn)
+	}
+	imdbClient, err := newIMDbClient(ctx, conf, store, log)
 	if err != nil {
 		return nil, fmt.Errorf("failure initialising imdb client: %w", err)
 	}
-	traktClient, err := client.NewTraktClient(conf.Trakt, log)
+	if *conf.Anime.Provider != appconfig.AnimeProviderNone {
+		animeClient, err := client.NewAnimeClient(ctx, imdbClient, conf.Anime, conf.HTTP, *conf.Sync.RatingRounding, store, log)
+		if err != nil {
+			imdbClient.Close()
+			return nil, fmt.Errorf("failure initialising %s client: %w", *conf.Anime.Provider, err)
+		}
+		return animeClient, nil
+	}
+	return imdbClient, nil
+}
+
+// newDestinationSyncer creates a syncer that syncs the imdb data to the destination of the config.
+func newDestinationSyncer(ctx context.Context, conf *appconfig.Config, imdbClient client.IMDbClientInterface, store state.Store, log *slog.Logger) (*Syncer, error) {
+	baseClient, err := newDestinationClient(ctx, conf, store, log)
+	if err != nil {
+		return nil, fmt.Errorf("failure initialising %s client: %w", *conf.Sync.Destination, err)
+	}
+	var plexClient client.PlexClientInterface
+	if *conf.Plex.Enabled {
+		if plexClient, err = client.NewPlexClient(ctx, conf.Plex, conf.HTTP, log); err != nil {
+			return nil, fmt.Errorf("failure initialising plex client: %w", err)
+		}
+	}
+	mappings, err := loadMappings(conf)
+	if err != nil {
+		return nil, err
+	}
+	walEntries, err := wal.Load(store)
 	if err != nil {
-		return nil, fmt.Errorf("failure initialising trakt client: %w", err)
+		return nil, fmt.Errorf("failure loading write-ahead log: %w", err)
+	}
+	searchClient, err := newSearchClient(conf, baseClient, store, log)
+	if err != nil {
+		return nil, err
+	}
+	var mappedClient = baseClient
+	if searchClient != nil {
+		// the mapping file takes precedence over the matches that the search found
+		for imdbID, match := range searchClient.Matches() {
+			if _, found := mappings[imdbID]; !found {
+				if mappings == nil {
+					mappings = make(mapping.Mappings)
+				}
+				mappings[imdbID] = match
+			}
+		}
+		mappedClient = searchClient
+	}
+	journalClient := journal.NewClient(mapping.NewClient(mappedClient, mappings))
+	walClient := wal.NewClient(journalClient, store, walEntries, log)
+	var destinationClient client.DestinationClientInterface = walClient
+	var skipListClient *skiplist.Client
+	if *conf.Sync.SkipListTTL > 0 {
+		entries, err := skiplist.Load(store)
+		if err != nil {
+			return nil, fmt.Errorf("failure loading skip-list: %w", err)
+		}
+		skipListClient = skiplist.NewClient(walClient, entries, *conf.Sync.SkipListTTL, log)
+		destinationClient = skipListClient
 	}
 	syncer := &Syncer{
+		ctx:         ctx,
 		logger:      log,
 		imdbClient:  imdbClient,
-		traktClient: traktClient,
+		destination: destinationClient,
+		plexClient:  plexClient,
+		journal:     journalClient,
+		wal:         walClient,
+		skipList:    skipListClient,
+		search:      searchClient,
+		store:       store,
 		user: &user{
-			imdbLists:    make(map[string]entities.IMDbList, len(*conf.IMDb.Lists)),
-			imdbRatings:  make(map[string]entities.IMDbItem),
-			traktLists:   make(map[string]entities.TraktList, len(*conf.IMDb.Lists)),
-			traktRatings: make(map[string]entities.TraktItem),
+			imdbCounts:           make(map[string]int),
+			imdbLists:            make(map[string]entities.IMDbList, len(*conf.IMDb.Lists)),
+			imdbRatings:          make(map[string]entities.IMDbItem),
+			traktCollection:      make(map[string]entities.TraktItem),
+			traktComments:        make(map[string]struct{}),
+			traktFavorites:       make(map[string]entities.TraktItem),
+			traktHidden:          make(map[string]map[string]entities.TraktItem),
+			traktListNames:       make(map[string]string, len(*conf.IMDb.Lists)),
+			traktLists:           make(map[string]entities.TraktList, len(*conf.IMDb.Lists)),
+			traktRatings:         make(map[string]entities.TraktItem),
+			traktRecommendations: make(map[string]entities.TraktItem),
+			traktWatched:         make(map[string]entities.TraktItem),
 		},
-		conf:     conf.Sync,
-		authless: *conf.IMDb.Auth == appconfig.IMDbAuthMethodNone,
+		conf:            conf.Sync,
+		charts:          conf.IMDb.ChartLists(),
+		authless:        *conf.IMDb.Auth == appconfig.IMDbAuthMethodNone && *conf.IMDb.ExportDir == "",
+		publicWatchlist: *conf.IMDb.UserID != "",
+		summary:         newSummary(),
+		checkpoint:      &checkpoint{},
+		destinationName: *conf.Sync.Destination,
+	}
+	if *conf.Sync.SafeMode && *conf.Sync.Mode == appconfig.SyncModeFull {
+		log.Info(fmt.Sprintf("safe mode is enabled, falling back to sync mode %s", appconfig.SyncModeAddOnly))
+		syncMode := appconfig.SyncModeAddOnly
+		syncer.conf.Mode = &syncMode
+	}
+	switch *conf.Sync.Destination {
+	case appconfig.SyncDestinationSimkl:
+		log.Warn("skipping imdb lists since simkl does not support custom lists")
+		return syncer, nil
+	case appconfig.SyncDestinationJellyfin:
+		log.Warn("skipping imdb watchlist and lists since jellyfin does not support them")
+		watchlist := false
+		syncer.conf.Watchlist = &watchlist
+		return syncer, nil
+	case appconfig.SyncDestinationKodi:
+		log.Warn("skipping imdb watchlist and lists since kodi does not support them")
+		watchlist := false
+		syncer.conf.Watchlist = &watchlist
+		return syncer, nil
 	}
 	for _, lid := range *conf.IMDb.Lists {
 		syncer.user.imdbLists[lid] = entities.IMDbList{ListID: lid}
@@ -58,41 +318,388 @@ func NewSyncer(ctx context.Context, conf *appconfig.Config) (*Syncer, error) {
 	return syncer, nil
 }
 
+// Sync syncs the imdb data to the destination, and to the destinations of SYNC_DESTINATIONS at the same time. A failing
+// destination does not prevent the other destinations from being synced, and the summary reports the outcome of each.
 func (s *Syncer) Sync() error {
+	defer s.saveSnapshot()
+	if len(s.peers) == 0 && len(s.failedPeers) == 0 {
+		return s.syncDestination()
+	}
+	syncers := append([]*Syncer{s}, s.peers...)
+	errs := make([]error, len(syncers))
+	run := func(i int) {
+		if err := syncers[i].syncDestination(); err != nil {
+			errs[i] = fmt.Errorf("failure syncing to %s: %w", syncers[i].destinationName, err)
+		}
+	}
+	if s.reviewer != nil {
+		// the reviewer can only review the changes of one destination at a time
+		for i := range syncers {
+			run(i)
+		}
+	} else {
+		var wg sync.WaitGroup
+		for i := range syncers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				run(i)
+			}()
+		}
+		wg.Wait()
+	}
+	summary := newSummary()
+	for _, syncer := range syncers {
+		summary.merge(syncer.destinationName, syncer.summary)
+	}
+	s.summary = summary
+	return errors.Join(append(errs, s.mergeFailedPeers()...)...)
+}
+
+// mergeFailedPeers reports the destinations whose syncer could not be built as failed in the summary, and returns
+// their errors.
+func (s *Syncer) mergeFailedPeers() []error {
+	errs := make([]error, 0, len(s.failedPeers))
+	for _, peer := range s.failedPeers {
+		summary := newSummary()
+		summary.StartedAt = s.summary.StartedAt
+		summary.FinishedAt = time.Now()
+		summary.addFailure(entityHydrate, peer.err)
+		s.summary.merge(peer.destination, summary)
+		errs = append(errs, fmt.Errorf("failure syncing to %s: %w", peer.destination, peer.err))
+	}
+	return errs
+}
+
+// saveSnapshot saves the imdb data that the sync fetched, when requested, so that the sync can be reproduced later.
+func (s *Syncer) saveSnapshot() {
+	snap, ok := s.imdbClient.(*snapshot)
+	if s.snapshotOut == "" || !ok {
+		return
+	}
+	if err := snap.save(s.snapshotOut); err != nil {
+		s.logger.Warn("failure saving imdb snapshot", logger.Error(err))
+		return
+	}
+	s.logger.Info("saved imdb snapshot", slog.String("path", s.snapshotOut))
+}
+
+// syncDestination syncs the imdb data to the destination of the syncer.
+func (s *Syncer) syncDestination() error {
 	s.logger.Info("sync started")
-	if err := s.hydrate(); err != nil {
+	defer func() {
+		s.summary.FinishedAt = time.Now()
+		if err := journal.Save(s.store, s.journal.Journal()); err != nil {
+			s.logger.Warn("failure saving sync journal", logger.Error(err))
+		}
+		if err := s.wal.Prune(); err != nil {
+			s.logger.Warn("failure pruning write-ahead log", logger.Error(err))
+		}
+		if err := s.store.Save(stateKeyNotFound, s.summary.NotFound); err != nil {
+			s.logger.Warn("failure saving trakt not found items", logger.Error(err))
+		}
+		if s.skipList != nil {
+			if err := skiplist.Save(s.store, s.skipList.Entries()); err != nil {
+				s.logger.Warn("failure saving skip-list", logger.Error(err))
+			}
+		}
+		if s.search != nil {
+			if err := search.Save(s.store, s.search.Matches()); err != nil {
+				s.logger.Warn("failure saving trakt search matches", logger.Error(err))
+			}
+		}
+		if err := stats.Append(s.store, s.summary.run(*s.conf.Mode)); err != nil {
+			s.logger.Warn("failure saving sync statistics", logger.Error(err))
+		}
+	}()
+	_, endSpan := tracing.Start(s.ctx, "hydrate")
+	err := s.hydrate()
+	endSpan(err)
+	if err != nil {
 		s.logger.Error("failure hydrating imdb client", logger.Error(err))
+		s.observeError(entityHydrate, err)
 		return err
 	}
-	if err := s.syncLists(); err != nil {
-		s.logger.Error("failure syncing lists", logger.Error(err))
+	s.rememberTitles()
+	if err := s.checkGuardrail(); err != nil {
+		s.logger.Error("failure passing sync guardrail", logger.Error(err))
+		s.observeError(entityHydrate, err)
 		return err
 	}
-	if err := s.syncRatings(); err != nil {
-		s.logger.Error("failure syncing ratings", logger.Error(err))
-		return err
+	s.resumeCheckpoint()
+	timeBudget := budget.FromContext(s.ctx)
+	// each step is synced independently, so that a failing step does not prevent the remaining steps from syncing
+	steps := []struct {
+		entity string
+		name   string
+		run    func() error
+	}{
+		{entity: entityLists, name: "lists", run: s.syncLists},
+		{entity: entityPlex, name: "plex watchlist", run: s.syncPlexWatchlist},
+		{entity: entityRatings, name: "ratings", run: s.syncRatings},
+		{entity: entityHistory, name: "history", run: s.syncHistory},
+		{entity: entityCheckins, name: "check-ins", run: s.syncCheckins},
+		{entity: entityCheckinList, name: "imdb check-in list", run: s.syncCheckinList},
+		{entity: entityCollection, name: "collection", run: s.syncCollection},
+		{entity: entityFavorites, name: "favorites", run: s.syncFavorites},
+		{entity: entityWatchedList, name: "imdb watched list", run: s.syncWatchedList},
+		{entity: entityRecommendations, name: "imdb recommendations list", run: s.syncRecommendations},
+		{entity: entityHidden, name: "hidden items", run: s.syncHidden},
+		{entity: entityLikedLists, name: "trakt liked lists", run: s.syncLikedLists},
+		{entity: entityReverseLists, name: "imdb reverse lists", run: s.syncReverseLists},
+		{entity: entityReviews, name: "reviews", run: s.syncReviews},
 	}
-	if err := s.syncHistory(); err != nil {
-		s.logger.Error("failure syncing history", logger.Error(err))
-		return err
+	var (
+		failures  []error
+		exhausted bool
+		completed = true
+	)
+	failed := make(map[string]struct{})
+	for _, step := range steps {
+		if s.checkpoint.hasStep(step.name) {
+			s.logger.Info(fmt.Sprintf("skipping %s, which the previous sync already synced", step.name))
+			continue
+		}
+		if exhausted = timeBudget.Exhausted(); exhausted {
+			break
+		}
+		err := s.runStep(step.entity, step.name, step.run, failed)
+		if err == nil {
+			s.checkpoint.Steps = append(s.checkpoint.Steps, step.name)
+			continue
+		}
+		if exhausted = errors.Is(err, budget.ErrExhausted); exhausted {
+			if err = withoutExhausted(err); err != nil {
+				failures = append(failures, err)
+			}
+			break
+		}
+		failures = append(failures, err)
+		if errors.Is(err, appconfig.ErrUserAborted) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			completed = false
+			break
+		}
+		// the remaining steps would be rejected the same way, so there is no point in running them
+		var authErr *client.AuthError
+		if errors.As(err, &authErr) {
+			s.logger.Error("aborting the remaining sync steps, since the credentials were rejected")
+			completed = false
+			break
+		}
+	}
+	s.finishCheckpoint(exhausted, completed)
+	if len(failures) > 0 {
+		failedEntities := make([]string, 0, len(failed))
+		for entity := range failed {
+			failedEntities = append(failedEntities, entity)
+		}
+		slices.Sort(failedEntities)
+		return &PartialFailureError{
+			Entities: failedEntities,
+			Err:      errors.Join(failures...),
+		}
+	}
+	if exhausted {
+		return nil
 	}
+	if err := s.saveItemCounts(); err != nil {
+		s.logger.Warn("failure saving imdb item counts", logger.Error(err))
+	}
+	metrics.ObserveSuccessfulSync()
 	s.logger.Info("sync completed")
 	return nil
 }
 
+// runStep runs a sync step and records its failures. A step that syncs several targets, such as the lists, returns
+// the joined failures of its targets, which are attributed to the entity of each target.
+func (s *Syncer) runStep(entity, name string, run func() error, failed map[string]struct{}) error {
+	_, endSpan := tracing.Start(s.ctx, name, attribute.String("its.entity", entity))
+	err := run()
+	endSpan(err)
+	s.observeNotFound(entity, "")
+	if err == nil {
+		return nil
+	}
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	for _, err := range errs {
+		// the targets that were left for the next sync, since the time budget ran out, have not failed
+		if errors.Is(err, budget.ErrExhausted) {
+			continue
+		}
+		failedEntity := entity
+		var targetErr *targetError
+		if errors.As(err, &targetErr) {
+			failedEntity = targetErr.entity
+		}
+		s.logger.Error(fmt.Sprintf("failure syncing %s", name), logger.Error(err))
+		s.observeError(failedEntity, err)
+		failed[failedEntity] = struct{}{}
+	}
+	return err
+}
+
+// SetProgress reports the progress of the lists, as well as of the requests that the destination sends in batches.
+func (s *Syncer) SetProgress(p *progress.Progress) {
+	s.progress = p
+	if destination, ok := s.journal.DestinationClientInterface.(interface{ SetProgress(*progress.Progress) }); ok {
+		destination.SetProgress(p)
+	}
+	for _, peer := range s.peers {
+		peer.SetProgress(p)
+	}
+}
+
+// Summary returns the outcome of the last sync run.
+func (s *Syncer) Summary() Summary {
+	return *s.summary
+}
+
+func (s *Syncer) observeItemsSynced(entity, operation string, count int) {
+	metrics.ObserveItemsSynced(entity, operation, count)
+	s.summary.addItems(entity, operation, count)
+}
+
+// observeNotFound records the items that the destination could not find while syncing the target of the entity.
+func (s *Syncer) observeNotFound(entity, target string) {
+	for _, item := range s.destination.NotFound() {
+		id, err := item.GetItemID()
+		if err != nil || id == nil {
+			continue
+		}
+		notFound := NotFoundItem{
+			Entity: entity,
+			Target: target,
+			Type:   item.Type,
+			IMDb:   *id,
+		}
+		if s.search != nil {
+			notFound.Candidates = s.search.Candidates(*id)
+		}
+		s.summary.addNotFound(notFound)
+	}
+}
+
+// rememberTitles hands the titles of the imdb items to the search fallback, which searches the items that the
+// destination could not find by their title and year.
+func (s *Syncer) rememberTitles() {
+	if s.search == nil {
+		return
+	}
+	for _, list := range s.user.imdbLists {
+		s.search.Remember(list.ListItems...)
+	}
+	for _, rating := range s.user.imdbRatings {
+		s.search.Remember(rating)
+	}
+	s.search.Remember(s.user.imdbCheckins...)
+	s.search.Remember(s.user.imdbCollection...)
+	s.search.Remember(s.user.imdbFavorites...)
+	s.search.Remember(s.user.imdbHidden...)
+}
+
+func (s *Syncer) observeError(entity string, err error) {
+	metrics.ObserveError(entity)
+	s.summary.addFailure(entity, err)
+}
+
+func newIMDbClient(ctx context.Context, conf *appconfig.Config, store state.Store, log *slog.Logger) (client.IMDbClientInterface, error) {
+	if *conf.IMDb.ExportDir != "" {
+		return client.NewIMDbFileClient(&conf.IMDb, log)
+	}
+	if *conf.IMDb.Backend == appconfig.IMDbBackendGraphQL {
+		return client.NewIMDbGraphQLClient(ctx, &conf.IMDb, conf.HTTP, store, log)
+	}
+	return client.NewIMDbClient(ctx, &conf.IMDb, conf.HTTP, store, log)
+}
+
+func newDestinationClient(ctx context.Context, conf *appconfig.Config, store state.Store, log *slog.Logger) (client.DestinationClientInterface, error) {
+	switch *conf.Sync.Destination {
+	case appconfig.SyncDestinationSimkl:
+		return client.NewSimklClient(ctx, conf.Simkl, conf.HTTP, store, log)
+	case appconfig.SyncDestinationJellyfin:
+		return client.NewJellyfinClient(ctx, conf.Jellyfin, conf.HTTP, *conf.Sync.RatingRounding, store, log)
+	case appconfig.SyncDestinationKodi:
+		return client.NewKodiClient(ctx, conf.Kodi, conf.HTTP, store, log)
+	}
+	return client.NewTraktClient(ctx, conf.Trakt, conf.HTTP, store, log)
+}
+
+// newSearchClient wraps the destination with the search fallback, when it is enabled and the destination supports it.
+func newSearchClient(conf *appconfig.Config, destination client.DestinationClientInterface, store state.Store, log *slog.Logger) (*search.Client, error) {
+	searcher, ok := destination.(search.Searcher)
+	if !*conf.Sync.SearchFallback || !ok {
+		return nil, nil
+	}
+	matches, err := search.Load(store)
+	if err != nil {
+		return nil, err
+	}
+	return search.NewClient(destination, searcher, matches, log), nil
+}
+
+func loadMappings(conf *appconfig.Config) (mapping.Mappings, error) {
+	if *conf.Sync.MappingFile == "" {
+		return nil, nil
+	}
+	mappings, err := mapping.Load(*conf.Sync.MappingFile)
+	if err != nil {
+		return nil, fmt.Errorf("failure loading title mappings: %w", err)
+	}
+	return mappings, nil
+}
+
+func (s *Syncer) Close() {
+	s.imdbClient.Close()
+}
+
 func (s *Syncer) hydrate() error {
-	lids := make([]string, len(s.user.imdbLists))
-	var i int
+	reverseLists, err := s.loadReverseLists()
+	if err != nil {
+		return err
+	}
+	lids := make([]string, 0, len(s.user.imdbLists))
+	var skipped int
 	for lid := range s.user.imdbLists {
-		lids[i] = lid
-		i++
+		if s.conf.ListMode(lid) == appconfig.ListModeDisabled {
+			s.logger.Info(fmt.Sprintf("skipping disabled imdb list %s", lid))
+			delete(s.user.imdbLists, lid)
+			skipped++
+			continue
+		}
+		if isReverseList(reverseLists, lid) {
+			s.logger.Info(fmt.Sprintf("skipping imdb list %s since it mirrors a trakt list", lid))
+			delete(s.user.imdbLists, lid)
+			skipped++
+			continue
+		}
+		lids = append(lids, lid)
+	}
+	// the ids come out of a map, so sort them for the calls to be the same for every destination that shares the imdb data
+	slices.Sort(lids)
+	// an empty slice of list ids means all lists, so make sure not to fetch anything when every configured list is skipped
+	fetchLists := *s.conf.Lists && (len(lids) > 0 || skipped == 0)
+	if fetchLists && *s.conf.SkipUnchanged && len(lids) > 0 {
+		changed, err := s.skipUnchangedLists(lids)
+		if err != nil {
+			return fmt.Errorf("failure detecting unchanged imdb lists: %w", err)
+		}
+		lids, fetchLists = changed, len(changed) > 0
+	}
+	if err := s.hydrateTrakt(); err != nil {
+		return err
+	}
+	if *s.conf.ItemNotes && s.user.traktLimits != nil && !s.user.traktLimits.Vip {
+		s.logger.Warn("skipping trakt item notes since they require trakt vip")
 	}
 	if *s.conf.Ratings {
 		if err := s.imdbClient.RatingsExport(); err != nil {
 			return fmt.Errorf("failure exporting imdb ratings: %w", err)
 		}
 	}
-	if *s.conf.Lists {
+	if fetchLists {
 		if err := s.imdbClient.ListsExport(lids...); err != nil {
 			return fmt.Errorf("failure exporting imdb lists: %w", err)
 		}
@@ -102,31 +709,66 @@ func (s *Syncer) hydrate() error {
 			return fmt.Errorf("failure exporting imdb watchlist: %w", err)
 		}
 	}
-	if *s.conf.Lists {
+	if *s.conf.Checkins {
+		if err := s.imdbClient.CheckinsExport(); err != nil {
+			return fmt.Errorf("failure exporting imdb check-ins: %w", err)
+		}
+	}
+	if fetchLists {
 		imdbLists, err := s.imdbClient.ListsGet(lids...)
 		if err != nil {
 			return fmt.Errorf("failure fetching imdb lists: %w", err)
 		}
 		traktIDMetas := make(entities.TraktIDMetas, 0, len(imdbLists))
+		descriptions := make(map[string]string, len(imdbLists))
+		listIDs := make(map[string]string, len(imdbLists))
 		for _, imdbList := range imdbLists {
+			if s.conf.ListMode(imdbList.ListID) == appconfig.ListModeDisabled {
+				s.logger.Info(fmt.Sprintf("skipping disabled imdb list %s", imdbList.ListID))
+				continue
+			}
+			if isReverseList(reverseLists, imdbList.ListID) {
+				s.logger.Info(fmt.Sprintf("skipping imdb list %s since it mirrors a trakt list", imdbList.ListID))
+				continue
+			}
+			imdbList = s.partitionList(imdbList)
+			if len(s.conf.FanOutRules(imdbList.ListID)) > 0 {
+				// lists that fan out are not synced to a trakt list of their own
+				s.user.imdbLists[imdbList.ListID] = imdbList
+				s.user.imdbCounts[imdbList.ListID] = len(imdbList.ListItems)
+				continue
+			}
+			traktListName, err := s.conf.TraktListName(imdbList.ListID, imdbList.ListName)
+			if err != nil {
+				return fmt.Errorf("failure naming trakt list for imdb list %s: %w", imdbList.ListID, err)
+			}
 			s.user.imdbLists[imdbList.ListID] = imdbList
+			s.user.imdbCounts[imdbList.ListID] = len(imdbList.ListItems)
+			s.user.traktListNames[imdbList.ListID] = traktListName
+			traktListSlug := entities.InferTraktListSlug(traktListName)
+			descriptions[traktListSlug] = imdbList.Description
+			listIDs[traktListSlug] = imdbList.ListID
 			traktIDMetas = append(traktIDMetas, entities.TraktIDMeta{
 				IMDb:     imdbList.ListID,
-				Slug:     entities.InferTraktListSlug(imdbList.ListName),
-				ListName: &imdbList.ListName,
+				Slug:     traktListSlug,
+				ListName: &traktListName,
 			})
 		}
-		traktLists, delegatedErrors := s.traktClient.ListsGet(traktIDMetas)
+		traktLists, delegatedErrors := s.destination.ListsGet(traktIDMetas)
 		for _, delegatedErr := range delegatedErrors {
 			var notFoundError *client.TraktListNotFoundError
 			if errors.As(delegatedErr, &notFoundError) {
 				listName := traktIDMetas.GetListNameFromSlug(notFoundError.Slug)
+				if !s.allowListCreation(notFoundError.Slug) {
+					delete(s.user.imdbLists, listIDs[notFoundError.Slug])
+					continue
+				}
 				if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun {
 					msg := fmt.Sprintf("sync mode %s would have created trakt list %s to backfill imdb list %s", syncMode, notFoundError.Slug, listName)
 					s.logger.Info(msg)
 					continue
 				}
-				if err = s.traktClient.ListAdd(notFoundError.Slug, listName); err != nil {
+				if err = s.destination.ListAdd(notFoundError.Slug, listName, descriptions[notFoundError.Slug]); err != nil {
 					return fmt.Errorf("failure creating trakt list: %w", err)
 				}
 				continue
@@ -137,35 +779,43 @@ func (s *Syncer) hydrate() error {
 			s.user.traktLists[traktList.IDMeta.IMDb] = traktList
 		}
 	}
-	if s.authless {
-		return nil
+	if err := s.hydrateCollection(); err != nil {
+		return err
 	}
-	if *s.conf.Watchlist {
+	if err := s.hydrateFavorites(); err != nil {
+		return err
+	}
+	// a public watchlist can be synced without imdb auth, as long as the id of its owner is known
+	if *s.conf.Watchlist && (!s.authless || s.publicWatchlist) {
 		imdbWatchlist, err := s.imdbClient.WatchlistGet()
 		if err != nil {
 			return fmt.Errorf("failure fetching imdb watchlist: %w", err)
 		}
-		s.user.imdbLists[imdbWatchlist.ListID] = *imdbWatchlist
-		traktWatchlist, err := s.traktClient.WatchlistGet()
-		if err != nil {
-			return fmt.Errorf("failure fetching trakt watchlist: %w", err)
+		if s.conf.ListMode(imdbWatchlist.ListID) == appconfig.ListModeDisabled {
+			s.logger.Info(fmt.Sprintf("skipping disabled imdb watchlist %s", imdbWatchlist.ListID))
+		} else {
+			watchlist := s.partitionList(*imdbWatchlist)
+			// the guardrail counts the whole watchlist, since titles move from the upcoming list back to it once released
+			s.user.imdbCounts[watchlist.ListID] = len(watchlist.ListItems)
+			if watchlist, err = s.hydrateUpcomingList(watchlist); err != nil {
+				return err
+			}
+			s.user.imdbLists[watchlist.ListID] = watchlist
+			s.user.traktLists[watchlist.ListID] = *s.user.traktWatchlist
 		}
-		s.user.traktLists[imdbWatchlist.ListID] = *traktWatchlist
+	}
+	if err := s.hydrateCharts(); err != nil {
+		return err
+	}
+	s.resolveDuplicates()
+	if err := s.hydrateFanOut(); err != nil {
+		return err
+	}
+	s.enforceListLimits()
+	if s.authless {
+		return nil
 	}
 	if *s.conf.Ratings {
-		traktRatings, err := s.traktClient.RatingsGet()
-		if err != nil {
-			return fmt.Errorf("failure fetching trakt ratings: %w", err)
-		}
-		for _, traktRating := range traktRatings {
-			id, err := traktRating.GetItemID()
-			if err != nil {
-				return fmt.Errorf("failure fetching trakt item id: %w", err)
-			}
-			if id != nil {
-				s.user.traktRatings[*id] = traktRating
-			}
-		}
 		imdbRatings, err := s.imdbClient.RatingsGet()
 		if err != nil {
 			return fmt.Errorf("failure fetching imdb ratings: %w", err)
@@ -173,85 +823,685 @@ func (s *Syncer) hydrate() error {
 		for _, imdbRating := range imdbRatings {
 			s.user.imdbRatings[imdbRating.ID] = imdbRating
 		}
+		s.user.imdbCounts[entityRatings] = len(imdbRatings)
+	}
+	if *s.conf.Checkins {
+		imdbCheckins, err := s.imdbClient.CheckinsGet()
+		if err != nil {
+			return fmt.Errorf("failure fetching imdb check-ins: %w", err)
+		}
+		s.user.imdbCheckins = imdbCheckins.ListItems
+		s.user.imdbCounts[entityCheckins] = len(imdbCheckins.ListItems)
+	}
+	if err := s.hydrateCheckinList(); err != nil {
+		return err
+	}
+	if err := s.hydrateWatchedList(); err != nil {
+		return err
+	}
+	if err := s.hydrateRecommendations(); err != nil {
+		return err
+	}
+	if err := s.hydrateHidden(); err != nil {
+		return err
+	}
+	if *s.conf.Reviews {
+		imdbReviews, err := s.imdbClient.ReviewsGet()
+		if err != nil {
+			return fmt.Errorf("failure fetching imdb reviews: %w", err)
+		}
+		s.user.imdbReviews = imdbReviews
 	}
 	return nil
 }
 
-func (s *Syncer) syncLists() error {
-	if !*s.conf.Watchlist {
-		s.logger.Info("skipping watchlist sync")
+// hydrateUpcomingList moves the titles of the watchlist that have not been released yet to a trakt list of their own,
+// so that the trakt calendar shows them apart from the rest of the watchlist. Titles move back to the watchlist on
+// the first sync after their release.
+func (s *Syncer) hydrateUpcomingList(watchlist entities.IMDbList) (entities.IMDbList, error) {
+	name := *s.conf.UpcomingList
+	if name == "" {
+		return watchlist, nil
+	}
+	slug := entities.InferTraktListSlug(name)
+	if ok, err := s.hydrateTraktList(upcomingListID, name, upcomingListDescription, "upcoming titles"); err != nil || !ok {
+		return watchlist, err
+	}
+	now := time.Now()
+	released := make([]entities.IMDbItem, 0, len(watchlist.ListItems))
+	var upcoming []entities.IMDbItem
+	for _, item := range watchlist.ListItems {
+		if item.Upcoming(now) {
+			upcoming = append(upcoming, item)
+			continue
+		}
+		released = append(released, item)
+	}
+	s.logger.Info(fmt.Sprintf("moving %d upcoming title(s) of the imdb watchlist to trakt list %s", len(upcoming), slug))
+	watchlist.ListItems = released
+	s.user.imdbLists[upcomingListID] = entities.IMDbList{
+		ListID:      upcomingListID,
+		ListName:    name,
+		Description: upcomingListDescription,
+		ListItems:   upcoming,
 	}
+	s.user.traktListNames[upcomingListID] = name
+	return watchlist, nil
+}
+
+// hydrateCharts fetches the configured imdb charts on every sync and registers each of them as a list of its own, so
+// that the trakt lists they are synced to follow the charts as titles enter and leave them.
+func (s *Syncer) hydrateCharts() error {
 	if !*s.conf.Lists {
-		s.logger.Info("skipping lists sync")
 		return nil
 	}
-	for _, list := range s.user.imdbLists {
-		traktListSlug := entities.InferTraktListSlug(list.ListName)
-		diff := entities.ListDifference(list, s.user.traktLists[list.ListID])
-		if list.IsWatchlist {
-			if len(diff["add"]) > 0 {
-				if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun {
-					msg := fmt.Sprintf("sync mode %s would have added %d trakt list item(s)", syncMode, len(diff["add"]))
-					s.logger.Info(msg, slog.Any("watchlist", diff["add"]))
-					continue
-				}
-				if err := s.traktClient.WatchlistItemsAdd(diff["add"]); err != nil {
-					return fmt.Errorf("failure adding items to trakt watchlist: %w", err)
-				}
-			}
-			if len(diff["remove"]) > 0 {
-				if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun || syncMode == appconfig.SyncModeAddOnly {
-					msg := fmt.Sprintf("sync mode %s would have deleted %d trakt list item(s)", syncMode, len(diff["remove"]))
-					s.logger.Info(msg, slog.Any("watchlist", diff["remove"]))
-					continue
-				}
-				if err := s.traktClient.WatchlistItemsRemove(diff["remove"]); err != nil {
-					return fmt.Errorf("failure removing items from trakt watchlist: %w", err)
-				}
-			}
+	for chart, name := range s.charts {
+		lid := chartListIDPrefix + chart
+		list, err := s.imdbClient.ChartGet(chart)
+		if err != nil {
+			return fmt.Errorf("failure fetching imdb chart %s: %w", chart, err)
+		}
+		list.ListID = lid
+		ok, err := s.hydrateTraktList(lid, name, list.Description, fmt.Sprintf("imdb chart %s", chart))
+		if err != nil {
+			return err
+		}
+		if !ok {
 			continue
 		}
-		if len(diff["add"]) > 0 {
-			if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun {
-				msg := fmt.Sprintf("sync mode %s would have added %d trakt list item(s)", syncMode, len(diff["add"]))
-				s.logger.Info(msg, slog.Any(traktListSlug, diff["add"]))
-				continue
-			}
-			if err := s.traktClient.ListItemsAdd(traktListSlug, diff["add"]); err != nil {
-				return fmt.Errorf("failure adding items to trakt list %s: %w", traktListSlug, err)
-			}
+		s.user.imdbLists[lid] = s.partitionList(*list)
+		s.user.traktListNames[lid] = name
+	}
+	return nil
+}
+
+// hydrateFanOut splits each imdb list with fan-out rules into the trakt lists of its rules, which are made of the items
+// of the imdb list that pass the filter of the rule, and leaves the imdb list itself out of the sync.
+func (s *Syncer) hydrateFanOut() error {
+	// the fan-out lists are added to the imdb lists along the way, hence why the ids are collected upfront
+	for _, lid := range slices.Collect(maps.Keys(s.user.imdbLists)) {
+		list := s.user.imdbLists[lid]
+		rules := s.conf.FanOutRules(lid)
+		if len(rules) == 0 || list.IsWatchlist {
+			continue
 		}
-		if len(diff["remove"]) > 0 {
-			if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun || syncMode == appconfig.SyncModeAddOnly {
-				msg := fmt.Sprintf("sync mode %s would have deleted %d trakt list item(s)", syncMode, len(diff["remove"]))
-				s.logger.Info(msg, slog.Any(traktListSlug, diff["remove"]))
+		delete(s.user.imdbLists, lid)
+		for _, rule := range rules {
+			ok, err := s.hydrateTraktList(rule.ListID, rule.ListName, list.Description, fmt.Sprintf("imdb list %s", lid))
+			if err != nil {
+				return err
+			}
+			if !ok {
 				continue
 			}
-			if err := s.traktClient.ListItemsRemove(traktListSlug, diff["remove"]); err != nil {
-				return fmt.Errorf("failure removing items from trakt list %s: %w", traktListSlug, err)
+			items := make([]entities.IMDbItem, 0, len(list.ListItems))
+			for _, item := range list.ListItems {
+				if rule.Filter.Allows(item) {
+					items = append(items, item)
+				}
+			}
+			s.logger.Info(fmt.Sprintf("syncing %d item(s) of imdb list %s to trakt list %s", len(items), lid, entities.InferTraktListSlug(rule.ListName)))
+			s.user.imdbLists[rule.ListID] = entities.IMDbList{
+				ListID:      rule.ListID,
+				ListName:    rule.ListName,
+				Description: list.Description,
+				ListItems:   items,
 			}
+			s.user.traktListNames[rule.ListID] = rule.ListName
 		}
 	}
 	return nil
 }
 
-func (s *Syncer) syncRatings() error {
-	if s.authless {
-		s.logger.Info("skipping ratings sync since no imdb auth was provided")
-		return nil
+// hydrateTraktList looks up the trakt list that a list without an imdb counterpart of its own is synced to, and
+// creates it when it is missing. It reports whether the list can be synced, which is not the case when creating the
+// list is not allowed.
+func (s *Syncer) hydrateTraktList(lid, name, description, subject string) (bool, error) {
+	slug := entities.InferTraktListSlug(name)
+	traktLists, delegatedErrors := s.destination.ListsGet(entities.TraktIDMetas{
+		{
+			IMDb:     lid,
+			Slug:     slug,
+			ListName: &name,
+		},
+	})
+	for _, delegatedErr := range delegatedErrors {
+		var notFoundError *client.TraktListNotFoundError
+		if !errors.As(delegatedErr, &notFoundError) {
+			return false, fmt.Errorf("failure hydrating trakt list %s: %w", slug, delegatedErr)
+		}
+		if !s.allowListCreation(slug) {
+			return false, nil
+		}
+		if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun {
+			s.logger.Info(fmt.Sprintf("sync mode %s would have created trakt list %s for %s", syncMode, slug, subject))
+			continue
+		}
+		if err := s.destination.ListAdd(slug, name, description); err != nil {
+			return false, fmt.Errorf("failure creating trakt list %s: %w", slug, err)
+		}
+	}
+	for _, traktList := range traktLists {
+		s.user.traktLists[lid] = traktList
+	}
+	return true, nil
+}
+
+// hydrateTrakt fetches the trakt data of every enabled entity concurrently, since none of it depends on imdb data,
+// apart from the lists which are looked up by the names of the imdb lists. Each fetch writes to its own field of the
+// user, while writes to trakt only happen once the hydration has finished, one at a time.
+func (s *Syncer) hydrateTrakt() error {
+	group := new(errgroup.Group)
+	if *s.conf.Watchlist && (!s.authless || s.publicWatchlist) {
+		group.Go(func() error {
+			traktWatchlist, err := s.destination.WatchlistGet()
+			if err != nil {
+				return fmt.Errorf("failure fetching trakt watchlist: %w", err)
+			}
+			s.user.traktWatchlist = traktWatchlist
+			return nil
+		})
+	}
+	if s.mirrorListEnabled(*s.conf.Collection) {
+		group.Go(func() error {
+			traktCollection, err := s.destination.CollectionGet()
+			if err != nil {
+				return fmt.Errorf("failure fetching trakt collection: %w", err)
+			}
+			return mapTraktItems(traktCollection, s.user.traktCollection)
+		})
+	}
+	if s.mirrorListEnabled(*s.conf.Favorites) {
+		group.Go(func() error {
+			traktFavorites, err := s.destination.FavoritesGet()
+			if err != nil {
+				return fmt.Errorf("failure fetching trakt favorites: %w", err)
+			}
+			return mapTraktItems(traktFavorites, s.user.traktFavorites)
+		})
+	}
+	if *s.conf.Lists || *s.conf.Watchlist {
+		group.Go(func() error {
+			traktLimits, err := s.destination.LimitsGet()
+			if err != nil {
+				return fmt.Errorf("failure fetching trakt limits: %w", err)
+			}
+			s.user.traktLimits = traktLimits
+			return nil
+		})
+	}
+	if s.authless {
+		return group.Wait()
+	}
+	if *s.conf.Ratings {
+		group.Go(func() error {
+			traktRatings, err := s.destination.RatingsGet()
+			if err != nil {
+				return fmt.Errorf("failure fetching trakt ratings: %w", err)
+			}
+			for _, traktRating := range traktRatings {
+				id, err := traktRating.GetItemID()
+				if err != nil {
+					return fmt.Errorf("failure fetching trakt item id: %w", err)
+				}
+				if id != nil {
+					s.user.traktRatings[*id] = traktRating
+				}
+			}
+			return nil
+		})
+	}
+	if s.mirrorListEnabled(*s.conf.WatchedList) {
+		group.Go(func() error {
+			traktHistory, err := s.destination.HistoryGetAll()
+			if err != nil {
+				return fmt.Errorf("failure fetching trakt history: %w", err)
+			}
+			return mapTraktItems(traktHistory, s.user.traktWatched)
+		})
+	}
+	if s.mirrorListEnabled(*s.conf.Recommendations) {
+		group.Go(func() error {
+			traktRecommendations, err := s.destination.RecommendationsGet()
+			if err != nil {
+				return fmt.Errorf("failure fetching trakt recommendations: %w", err)
+			}
+			return mapTraktItems(traktRecommendations, s.user.traktRecommendations)
+		})
+	}
+	if s.mirrorListEnabled(*s.conf.Hidden) {
+		for _, section := range hiddenSections() {
+			// the maps of the sections are created upfront, so that the goroutines do not write to the same map
+			hidden := make(map[string]entities.TraktItem)
+			s.user.traktHidden[section] = hidden
+			group.Go(func() error {
+				traktHidden, err := s.destination.HiddenGet(section)
+				if err != nil {
+					return fmt.Errorf("failure fetching trakt hidden %s items: %w", section, err)
+				}
+				return mapTraktItems(traktHidden, hidden)
+			})
+		}
+	}
+	if *s.conf.Reviews {
+		group.Go(func() error {
+			traktComments, err := s.destination.CommentsGet()
+			if err != nil {
+				return fmt.Errorf("failure fetching trakt comments: %w", err)
+			}
+			for _, traktComment := range traktComments {
+				id, err := traktComment.GetItemID()
+				if err != nil {
+					return fmt.Errorf("failure fetching trakt item id: %w", err)
+				}
+				if id != nil {
+					s.user.traktComments[*id] = struct{}{}
+				}
+			}
+			return nil
+		})
+	}
+	return group.Wait()
+}
+
+// allowListCreation reports whether another trakt list may be created without exceeding the list cap of the account,
+// and counts the list towards the cap when it may.
+func (s *Syncer) allowListCreation(slug string) bool {
+	limits := s.user.traktLimits
+	if limits == nil || limits.List.Count == 0 || limits.ListsOwned < limits.List.Count {
+		if limits != nil {
+			limits.ListsOwned++
+		}
+		return true
+	}
+	msg := fmt.Sprintf("creating trakt list %s would exceed the limit of %d lists of the trakt account", slug, limits.List.Count)
+	if *s.conf.ListLimit == appconfig.SyncListLimitWarn {
+		s.logger.Warn(msg)
+		return true
+	}
+	s.logger.Warn(msg + ", skipping it")
+	return false
+}
+
+// enforceListLimits checks whether syncing each list would exceed the item cap of the account, and warns about, truncates
+// or skips the lists that would, depending on the configured strategy, so that the sync does not fail midway.
+func (s *Syncer) enforceListLimits() {
+	limits := s.user.traktLimits
+	if limits == nil {
+		return
+	}
+	for lid, list := range s.user.imdbLists {
+		limit := limits.List.ItemCount
+		if list.IsWatchlist {
+			limit = limits.Watchlist.ItemCount
+		}
+		if limit == 0 {
+			continue
+		}
+		traktList := s.user.traktLists[lid]
+		diff := entities.ListDifference(list, traktList, s.conf.ItemFilter(listEntity(list)))
+		kept := len(traktList.ListItems)
+		if s.conf.ListMode(lid) == appconfig.SyncModeFull {
+			kept -= len(diff["remove"])
+		}
+		size := kept + len(diff["add"])
+		if size <= limit {
+			continue
+		}
+		msg := fmt.Sprintf("syncing imdb list %s would exceed the limit of %d items per trakt list", lid, limit)
+		switch *s.conf.ListLimit {
+		case appconfig.SyncListLimitTruncate:
+			s.logger.Warn(msg+", truncating it", slog.Int("items", size))
+			s.user.imdbLists[lid] = truncateList(list, diff["add"], limit-kept)
+		case appconfig.SyncListLimitSkip:
+			s.logger.Warn(msg+", skipping it", slog.Int("items", size))
+			delete(s.user.imdbLists, lid)
+		default:
+			s.logger.Warn(msg, slog.Int("items", size))
+		}
+	}
+}
+
+// truncateList leaves out the items of the list that would be added to trakt beyond the given number of additions,
+// keeping the first ones in the order of the imdb list.
+func truncateList(list entities.IMDbList, added entities.TraktItems, additions int) entities.IMDbList {
+	addedIDs := make(map[string]struct{}, len(added))
+	for _, item := range added {
+		if id, err := item.GetItemID(); err == nil && id != nil {
+			addedIDs[*id] = struct{}{}
+		}
+	}
+	items := make([]entities.IMDbItem, 0, len(list.ListItems))
+	for _, item := range list.ListItems {
+		if _, found := addedIDs[item.ID]; found {
+			if additions <= 0 {
+				continue
+			}
+			additions--
+		}
+		items = append(items, item)
+	}
+	list.ListItems = items
+	return list
+}
+
+// mirrorListEnabled reports whether an imdb list that is synced with trakt data other than a trakt list, such as the
+// collection, is configured and not disabled.
+func (s *Syncer) mirrorListEnabled(lid string) bool {
+	return lid != "" && s.conf.ListMode(lid) != appconfig.ListModeDisabled
+}
+
+// syncLists syncs each list independently, so that a failing list does not prevent the remaining lists from syncing.
+// Failing lists are not recorded as synced, so that they are not skipped by the next sync if they remain unchanged.
+// When the time budget runs out, the lists that were synced are kept in the checkpoint, and the remaining lists are
+// left for the next sync.
+func (s *Syncer) syncLists() error {
+	if !*s.conf.Watchlist {
+		s.logger.Info("skipping watchlist sync")
+	}
+	if !*s.conf.Lists {
+		s.logger.Info("skipping lists sync")
+		return nil
+	}
+	var errs []error
+	bar := s.progress.Track("lists", len(s.user.imdbLists))
+	defer bar.Done()
+	timeBudget := budget.FromContext(s.ctx)
+	for _, list := range s.user.imdbLists {
+		if s.checkpoint.hasList(list.ListID) {
+			bar.Add(1)
+			continue
+		}
+		if err := timeBudget.Check(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		err := s.syncList(list)
+		bar.Add(1)
+		s.observeNotFound(listEntity(list), list.ListID)
+		if err != nil {
+			errs = append(errs, err)
+			if errors.Is(err, appconfig.ErrUserAborted) || errors.Is(err, budget.ErrExhausted) {
+				break
+			}
+			continue
+		}
+		s.checkpoint.Lists = append(s.checkpoint.Lists, list.ListID)
+	}
+	joined := errors.Join(errs...)
+	if !errors.Is(joined, appconfig.ErrUserAborted) && !errors.Is(joined, budget.ErrExhausted) {
+		errs = append(errs, s.syncListsOrder()...)
+	}
+	for _, err := range errs {
+		var targetErr *targetError
+		if errors.As(err, &targetErr) {
+			delete(s.user.imdbListsModified, targetErr.target)
+		}
+	}
+	if errors.Is(joined, budget.ErrExhausted) {
+		// the lists that the sync did not get to are not synced, even though they did not fail
+		for lid := range s.user.imdbListsModified {
+			if !s.checkpoint.hasList(lid) {
+				delete(s.user.imdbListsModified, lid)
+			}
+		}
+	}
+	if err := s.saveListsModified(); err != nil {
+		s.logger.Warn("failure saving imdb lists modification dates", logger.Error(err))
+	}
+	return errors.Join(errs...)
+}
+
+// partitionList leaves out the items of the list that the destination does not support, such as video games, and
+// reports them instead.
+func (s *Syncer) partitionList(list entities.IMDbList) entities.IMDbList {
+	supported, unsupported := entities.PartitionItems(list.ListItems)
+	if len(unsupported) == 0 {
+		return list
+	}
+	entity := listEntity(list)
+	for _, item := range unsupported {
+		s.summary.addUnsupported(UnsupportedItem{
+			Entity: entity,
+			Target: list.ListID,
+			Kind:   item.Kind,
+			IMDb:   item.ID,
+			Title:  item.Title,
+		})
+	}
+	s.logger.Warn(fmt.Sprintf("skipping %d unsupported item(s) of imdb list %s", len(unsupported), list.ListID), slog.Any("unsupported", unsupported))
+	list.ListItems = supported
+	return list
+}
+
+// itemNotes copies the descriptions of the imdb list items into the notes of the trakt items, which requires vip. Notes
+// are only set when items are added, hence why the notes of items that are already on trakt are left alone.
+func (s *Syncer) itemNotes(items entities.TraktItems) entities.TraktItems {
+	if !*s.conf.ItemNotes || s.user.traktLimits == nil || !s.user.traktLimits.Vip {
+		return items
+	}
+	return items.MapSpecs(func(item entities.TraktItem, spec *entities.TraktItemSpec) {
+		if item.Notes != "" {
+			spec.Notes = &item.Notes
+		}
+	})
+}
+
+func listEntity(list entities.IMDbList) string {
+	if list.IsWatchlist {
+		return entityWatchlist
+	}
+	return entityLists
+}
+
+func (s *Syncer) syncList(list entities.IMDbList) error {
+	listMode := s.conf.ListMode(list.ListID)
+	traktListSlug := entities.InferTraktListSlug(s.user.traktListNames[list.ListID])
+	diff := entities.ListDifference(list, s.user.traktLists[list.ListID], s.conf.ItemFilter(listEntity(list)))
+	if list.IsWatchlist {
+		if len(diff["add"]) > 0 {
+			if listMode == appconfig.SyncModeDryRun {
+				msg := fmt.Sprintf("sync mode %s would have added %d trakt list item(s)", listMode, len(diff["add"]))
+				s.logger.Info(msg, slog.Any("watchlist", diff["add"]))
+				return nil
+			}
+			items, err := s.review(entityWatchlist, operationAdd, entityWatchlist, diff["add"])
+			if err != nil {
+				return s.listError(list, err)
+			}
+			if len(items) > 0 {
+				if err = s.destination.WatchlistItemsAdd(s.itemNotes(items)); err != nil {
+					return s.listError(list, fmt.Errorf("failure adding items to trakt watchlist: %w", err))
+				}
+				s.observeItemsSynced(entityWatchlist, operationAdd, len(items))
+			}
+		}
+		if len(diff["remove"]) > 0 {
+			if s.destinationName == appconfig.SyncDestinationSimkl {
+				// simkl only removes items from its lists by deleting their watch history as well
+				s.logger.Debug(fmt.Sprintf("skipping removal of %d simkl watchlist item(s)", len(diff["remove"])))
+				return nil
+			}
+			if listMode == appconfig.SyncModeDryRun || listMode == appconfig.SyncModeAddOnly {
+				msg := fmt.Sprintf("sync mode %s would have deleted %d trakt list item(s)", listMode, len(diff["remove"]))
+				s.logger.Info(msg, slog.Any("watchlist", diff["remove"]))
+				return nil
+			}
+			items, err := s.review(entityWatchlist, operationRemove, entityWatchlist, diff["remove"])
+			if err != nil {
+				return s.listError(list, err)
+			}
+			if len(items) > 0 {
+				if err = s.destination.WatchlistItemsRemove(items); err != nil {
+					return s.listError(list, fmt.Errorf("failure removing items from trakt watchlist: %w", err))
+				}
+				s.observeItemsSynced(entityWatchlist, operationRemove, len(items))
+			}
+		}
+		return nil
+	}
+	if traktList, found := s.user.traktLists[list.ListID]; found && isListOutdated(list, traktList) {
+		if listMode == appconfig.SyncModeDryRun {
+			s.logger.Info(fmt.Sprintf("sync mode %s would have updated trakt list %s", listMode, traktListSlug))
+		} else if err := s.destination.ListUpdate(traktListSlug, list.Description); err != nil {
+			return s.listError(list, fmt.Errorf("failure updating trakt list %s: %w", traktListSlug, err))
+		}
+	}
+	if len(diff["add"]) > 0 {
+		if listMode == appconfig.SyncModeDryRun {
+			msg := fmt.Sprintf("sync mode %s would have added %d trakt list item(s)", listMode, len(diff["add"]))
+			s.logger.Info(msg, slog.Any(traktListSlug, diff["add"]))
+			return nil
+		}
+		items, err := s.review(entityLists, operationAdd, traktListSlug, diff["add"])
+		if err != nil {
+			return s.listError(list, err)
+		}
+		if len(items) > 0 {
+			if err = s.destination.ListItemsAdd(traktListSlug, s.itemNotes(items)); err != nil {
+				return s.listError(list, fmt.Errorf("failure adding items to trakt list %s: %w", traktListSlug, err))
+			}
+			s.observeItemsSynced(entityLists, operationAdd, len(items))
+		}
+	}
+	if len(diff["remove"]) > 0 {
+		if listMode == appconfig.SyncModeDryRun || listMode == appconfig.SyncModeAddOnly {
+			msg := fmt.Sprintf("sync mode %s would have deleted %d trakt list item(s)", listMode, len(diff["remove"]))
+			s.logger.Info(msg, slog.Any(traktListSlug, diff["remove"]))
+			return nil
+		}
+		items, err := s.review(entityLists, operationRemove, traktListSlug, diff["remove"])
+		if err != nil {
+			return s.listError(list, err)
+		}
+		if len(items) > 0 {
+			if err = s.destination.ListItemsRemove(traktListSlug, items); err != nil {
+				return s.listError(list, fmt.Errorf("failure removing items from trakt list %s: %w", traktListSlug, err))
+			}
+			s.observeItemsSynced(entityLists, operationRemove, len(items))
+		}
+	}
+	return nil
+}
+
+// listError attributes the failure of a list to the watchlist or lists entity, depending on the kind of the list.
+func (s *Syncer) listError(list entities.IMDbList, err error) error {
+	return &targetError{
+		entity: listEntity(list),
+		target: list.ListID,
+		err:    err,
+	}
+}
+
+// skipUnchangedLists drops the lists that have not been modified on imdb since they were last synced and returns the
+// ids of the remaining lists.
+func (s *Syncer) skipUnchangedLists(lids []string) ([]string, error) {
+	modified, err := s.imdbClient.ListsModified(lids...)
+	if err != nil {
+		return nil, fmt.Errorf("failure fetching imdb lists modification dates: %w", err)
+	}
+	synced := make(map[string]time.Time)
+	if err = s.store.Load(stateKeyListsModified, &synced); err != nil && !errors.Is(err, state.ErrNotFound) {
+		return nil, fmt.Errorf("failure loading imdb lists modification dates: %w", err)
+	}
+	s.user.imdbListsModified = modified
+	s.user.imdbListsSyncedModified = synced
+	changed := make([]string, 0, len(lids))
+	for _, lid := range lids {
+		lastModified, found := modified[lid]
+		if lastSynced, ok := synced[lid]; found && ok && lastModified.Equal(lastSynced) {
+			s.logger.Info(fmt.Sprintf("skipping imdb list %s since it has not changed since the last sync", lid))
+			delete(s.user.imdbLists, lid)
+			continue
+		}
+		changed = append(changed, lid)
+	}
+	return changed, nil
+}
+
+// saveListsModified records the modification dates of the lists that were synced, so that the next sync can skip
+// them if they remain unchanged. Lists synced in dry-run mode are not recorded, since nothing was written to trakt.
+func (s *Syncer) saveListsModified() error {
+	if len(s.user.imdbListsModified) == 0 {
+		return nil
+	}
+	synced := s.user.imdbListsSyncedModified
+	for lid, lastModified := range s.user.imdbListsModified {
+		if _, found := s.user.imdbLists[lid]; !found || s.conf.ListMode(lid) == appconfig.SyncModeDryRun {
+			continue
+		}
+		synced[lid] = lastModified
+	}
+	return s.store.Save(stateKeyListsModified, synced)
+}
+
+// isListOutdated reports whether the trakt list settings or description no longer match the desired ones.
+// An imdb list without description leaves the trakt list description untouched.
+func isListOutdated(imdbList entities.IMDbList, traktList entities.TraktList) bool {
+	if traktList.SettingsOutdated {
+		return true
+	}
+	return imdbList.Description != "" && imdbList.Description != traktList.Description
+}
+
+// syncListsOrder reorders the trakt list items to match the positions of the items in the imdb lists.
+func (s *Syncer) syncListsOrder() []error {
+	var errs []error
+	for _, list := range s.user.imdbLists {
+		if list.IsWatchlist || len(list.ListItems) == 0 {
+			continue
+		}
+		listMode := s.conf.ListMode(list.ListID)
+		traktListSlug := entities.InferTraktListSlug(s.user.traktListNames[list.ListID])
+		if listMode == appconfig.SyncModeDryRun {
+			if _, changed := entities.ListRanking(list, s.user.traktLists[list.ListID]); changed {
+				s.logger.Info(fmt.Sprintf("sync mode %s would have reordered trakt list %s", listMode, traktListSlug))
+			}
+			continue
+		}
+		traktList, err := s.destination.ListGet(traktListSlug)
+		if err != nil {
+			errs = append(errs, s.listError(list, fmt.Errorf("failure fetching trakt list %s: %w", traktListSlug, err)))
+			continue
+		}
+		rank, changed := entities.ListRanking(list, *traktList)
+		if !changed {
+			continue
+		}
+		if err = s.destination.ListItemsReorder(traktListSlug, rank); err != nil {
+			errs = append(errs, s.listError(list, fmt.Errorf("failure reordering trakt list %s: %w", traktListSlug, err)))
+		}
+	}
+	return errs
+}
+
+func (s *Syncer) syncRatings() error {
+	if s.authless {
+		s.logger.Info("skipping ratings sync since no imdb auth was provided")
+		return nil
 	}
 	if !*s.conf.Ratings {
 		s.logger.Info("skipping ratings sync")
 		return nil
 	}
-	diff := entities.ItemsDifference(s.user.imdbRatings, s.user.traktRatings)
+	imdbRatings, traktRatings := s.mapRatings()
+	diff := entities.ItemsDifference(imdbRatings, traktRatings, s.conf.ItemFilter(entityRatings))
+	diff["add"] = s.resolveRatingConflicts(diff["add"])
 	if len(diff["add"]) > 0 {
 		if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun {
 			msg := fmt.Sprintf("sync mode %s would have added %d trakt rating item(s)", syncMode, len(diff["add"]))
 			s.logger.Info(msg, slog.Any("ratings", diff["add"]))
 		} else {
-			if err := s.traktClient.RatingsAdd(diff["add"]); err != nil {
-				return fmt.Errorf("failure adding trakt ratings: %w", err)
+			items, err := s.review(entityRatings, operationAdd, entityRatings, diff["add"])
+			if err != nil {
+				return err
+			}
+			if len(items) > 0 {
+				if err = s.destination.RatingsAdd(items); err != nil {
+					return fmt.Errorf("failure adding trakt ratings: %w", err)
+				}
+				s.observeItemsSynced(entityRatings, operationAdd, len(items))
+				s.observeRatingChanges(items, traktRatings, true)
 			}
 		}
 	}
@@ -260,14 +1510,143 @@ func (s *Syncer) syncRatings() error {
 			msg := fmt.Sprintf("sync mode %s would have deleted %d trakt rating item(s)", syncMode, len(diff["remove"]))
 			s.logger.Info(msg, slog.Any("ratings", diff["remove"]))
 		} else {
-			if err := s.traktClient.RatingsRemove(diff["remove"]); err != nil {
-				return fmt.Errorf("failure removing trakt ratings: %w", err)
+			items, err := s.review(entityRatings, operationRemove, entityRatings, diff["remove"])
+			if err != nil {
+				return err
+			}
+			if len(items) > 0 {
+				if err = s.destination.RatingsRemove(items); err != nil {
+					return fmt.Errorf("failure removing trakt ratings: %w", err)
+				}
+				s.observeItemsSynced(entityRatings, operationRemove, len(items))
+				s.observeRatingChanges(items, traktRatings, false)
 			}
 		}
 	}
 	return nil
 }
 
+// observeRatingChanges records the previous and new rating of each added or removed item in the change log of the
+// summary, so that rating overwrites can be audited afterwards.
+func (s *Syncer) observeRatingChanges(items entities.TraktItems, traktRatings map[string]entities.TraktItem, added bool) {
+	for _, item := range items {
+		id, err := item.GetItemID()
+		if err != nil || id == nil {
+			continue
+		}
+		change := RatingChange{
+			IMDb:    *id,
+			Title:   s.user.imdbRatings[*id].Title,
+			Type:    item.Type,
+			Source:  ratingSourceIMDb,
+			Outcome: ratingOutcomeApplied,
+		}
+		if traktRating, found := traktRatings[*id]; found {
+			change.Old = &traktRating.Rating
+		}
+		if added {
+			change.New = item.Spec().Rating
+			change.Source = s.ratingSource(*id, change.New)
+		}
+		s.summary.addRatingChange(change)
+	}
+}
+
+// ratingSource tells whether the rating is the imdb rating of the item itself, or the imdb rating transformed by the
+// ratings map.
+func (s *Syncer) ratingSource(id string, rating *int) string {
+	if imdbRating := s.user.imdbRatings[id].Rating; imdbRating != nil && rating != nil && *imdbRating != *rating {
+		return ratingSourceRatingsMap
+	}
+	return ratingSourceIMDb
+}
+
+// mapRatings applies the configured rating transformations to the imdb ratings. Excluded ratings are dropped from both
+// the imdb and trakt ratings, so that they are neither added to nor removed from trakt.
+func (s *Syncer) mapRatings() (map[string]entities.IMDbItem, map[string]entities.TraktItem) {
+	if len(*s.conf.RatingsMap) == 0 {
+		return s.user.imdbRatings, s.user.traktRatings
+	}
+	imdbRatings := make(map[string]entities.IMDbItem, len(s.user.imdbRatings))
+	traktRatings := maps.Clone(s.user.traktRatings)
+	var excluded int
+	for id, item := range s.user.imdbRatings {
+		if item.Rating == nil {
+			imdbRatings[id] = item
+			continue
+		}
+		rating, ok := s.conf.MapRating(*item.Rating)
+		if !ok {
+			delete(traktRatings, id)
+			excluded++
+			continue
+		}
+		item.Rating = &rating
+		imdbRatings[id] = item
+	}
+	if excluded > 0 {
+		s.logger.Info(fmt.Sprintf("excluded %d imdb rating(s) based on the ratings map", excluded))
+	}
+	return imdbRatings, traktRatings
+}
+
+// resolveRatingConflicts filters the ratings to be added, based on the configured strategy for items that
+// have already been rated differently on trakt. Skipped conflicts are reported in the change log of the summary.
+func (s *Syncer) resolveRatingConflicts(items entities.TraktItems) entities.TraktItems {
+	strategy := *s.conf.RatingsConflict
+	if strategy == appconfig.RatingsConflictIMDbWins {
+		return items
+	}
+	resolved := make(entities.TraktItems, 0, len(items))
+	for _, item := range items {
+		id, err := item.GetItemID()
+		if err != nil || id == nil {
+			resolved = append(resolved, item)
+			continue
+		}
+		traktRating, conflict := s.user.traktRatings[*id]
+		if !conflict {
+			resolved = append(resolved, item)
+			continue
+		}
+		imdbRating := s.user.imdbRatings[*id]
+		switch strategy {
+		case appconfig.RatingsConflictTraktWins:
+			continue
+		case appconfig.RatingsConflictNewestWins:
+			if isTraktRatingNewer(imdbRating, traktRating) {
+				continue
+			}
+			resolved = append(resolved, item)
+		case appconfig.RatingsConflictSkip:
+			s.logger.Warn("skipping conflicting rating", slog.String("id", *id), slog.Int("imdbRating", *imdbRating.Rating), slog.Int("traktRating", traktRating.Rating))
+			rating := item.Spec().Rating
+			s.summary.addRatingChange(RatingChange{
+				IMDb:    *id,
+				Title:   imdbRating.Title,
+				Type:    item.Type,
+				Old:     &traktRating.Rating,
+				New:     rating,
+				Source:  s.ratingSource(*id, rating),
+				Outcome: ratingOutcomeSkipped,
+			})
+		}
+	}
+	return resolved
+}
+
+// isTraktRatingNewer compares rating dates at day granularity, since imdb does not expose the time of rating
+func isTraktRatingNewer(imdbRating entities.IMDbItem, traktRating entities.TraktItem) bool {
+	if imdbRating.RatingDate == nil {
+		return true
+	}
+	traktRatedAt, err := time.Parse(time.RFC3339, traktRating.RatedAt)
+	if err != nil {
+		return false
+	}
+	return traktRatedAt.UTC().Truncate(24 * time.Hour).After(imdbRating.RatingDate.UTC().Truncate(24 * time.Hour))
+}
+
 func (s *Syncer) syncHistory() error {
 	if s.authless {
 		s.logger.Info("skipping history sync since no imdb auth was provided")
@@ -279,8 +1658,8 @@ func (s *Syncer) syncHistory() error {
 	}
 	// imdb doesn't offer functionality similar to trakt history, hence why there can't be a direct mapping between them
 	// the syncer will assume a user to have watched an item if they've submitted a rating for it
-	// if the above is satisfied and the user's history for this item is empty, a new history entry is added!
-	diff := entities.ItemsDifference(s.user.imdbRatings, s.user.traktRatings)
+	// if the above is satisfied and the user's history has no play of this item within the history window, a new history entry is added!
+	diff := entities.ItemsDifference(s.user.imdbRatings, s.user.traktRatings, s.conf.ItemFilter(entityHistory))
 	if len(diff["add"]) > 0 {
 		var historyToAdd entities.TraktItems
 		for i := range diff["add"] {
@@ -288,11 +1667,11 @@ func (s *Syncer) syncHistory() error {
 			if err != nil {
 				return fmt.Errorf("failure fetching trakt item id: %w", err)
 			}
-			history, err := s.traktClient.HistoryGet(diff["add"][i].Type, *traktItemID)
+			history, err := s.destination.HistoryGet(diff["add"][i].Type, *traktItemID)
 			if err != nil {
 				return fmt.Errorf("failure fetching trakt history for %s %s: %w", diff["add"][i].Type, *traktItemID, err)
 			}
-			if len(history) > 0 {
+			if s.watchedWithinWindow(history, diff["add"][i]) {
 				continue
 			}
 			historyToAdd = append(historyToAdd, diff["add"][i])
@@ -302,8 +1681,15 @@ func (s *Syncer) syncHistory() error {
 				msg := fmt.Sprintf("sync mode %s would have added %d trakt history item(s)", syncMode, len(historyToAdd))
 				s.logger.Info(msg, slog.Any("history", historyToAdd))
 			} else {
-				if err := s.traktClient.HistoryAdd(historyToAdd); err != nil {
-					return fmt.Errorf("failure adding trakt history: %w", err)
+				items, err := s.review(entityHistory, operationAdd, entityHistory, historyToAdd)
+				if err != nil {
+					return err
+				}
+				if len(items) > 0 {
+					if err = s.destination.HistoryAdd(items); err != nil {
+						return fmt.Errorf("failure adding trakt history: %w", err)
+					}
+					s.observeItemsSynced(entityHistory, operationAdd, len(items))
 				}
 			}
 		}
@@ -315,7 +1701,7 @@ func (s *Syncer) syncHistory() error {
 			if err != nil {
 				return fmt.Errorf("failure fetching trakt item id: %w", err)
 			}
-			history, err := s.traktClient.HistoryGet(diff["remove"][i].Type, *traktItemID)
+			history, err := s.destination.HistoryGet(diff["remove"][i].Type, *traktItemID)
 			if err != nil {
 				return fmt.Errorf("failure fetching trakt history for %s %s: %w", diff["remove"][i].Type, *traktItemID, err)
 			}
@@ -329,11 +1715,905 @@ func (s *Syncer) syncHistory() error {
 				msg := fmt.Sprintf("sync mode %s would have deleted %d trakt history item(s)", syncMode, len(historyToRemove))
 				s.logger.Info(msg, slog.Any("history", historyToRemove))
 			} else {
-				if err := s.traktClient.HistoryRemove(historyToRemove); err != nil {
-					return fmt.Errorf("failure removing trakt history: %w", err)
+				items, err := s.review(entityHistory, operationRemove, entityHistory, historyToRemove)
+				if err != nil {
+					return err
+				}
+				if len(items) > 0 {
+					if err = s.destination.HistoryRemove(items); err != nil {
+						return fmt.Errorf("failure removing trakt history: %w", err)
+					}
+					s.observeItemsSynced(entityHistory, operationRemove, len(items))
 				}
 			}
 		}
 	}
 	return nil
 }
+
+// watchedWithinWindow reports whether the trakt history already has a play of the item within SYNC_HISTORYWINDOW, so
+// that syncing the history repeatedly does not add the same play twice. Without a window, any play counts.
+func (s *Syncer) watchedWithinWindow(history entities.TraktItems, item entities.TraktItem) bool {
+	var watchedAt *string
+	if spec := item.Spec(); spec != nil {
+		watchedAt = spec.WatchedAt
+	}
+	return history.WatchedWithin(watchedAt, *s.conf.HistoryWindow)
+}
+
+func (s *Syncer) syncCheckins() error {
+	if s.authless {
+		s.logger.Info("skipping check-ins sync since no imdb auth was provided")
+		return nil
+	}
+	if !*s.conf.Checkins {
+		s.logger.Info("skipping check-ins sync")
+		return nil
+	}
+	// every imdb check-in is treated as a watch of the respective item at the time it was checked in
+	// a new history entry is only added if the user's trakt history has no play of this item within the history window
+	var historyToAdd entities.TraktItems
+	filter := s.conf.ItemFilter(entityCheckins)
+	for _, checkin := range s.user.imdbCheckins {
+		if !filter.Allows(checkin) {
+			continue
+		}
+		traktItem := checkin.ToTraktHistoryItem()
+		traktItemID, err := traktItem.GetItemID()
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt item id: %w", err)
+		}
+		history, err := s.destination.HistoryGet(traktItem.Type, *traktItemID)
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt history for %s %s: %w", traktItem.Type, *traktItemID, err)
+		}
+		if s.watchedWithinWindow(history, traktItem) {
+			continue
+		}
+		historyToAdd = append(historyToAdd, traktItem)
+	}
+	if len(historyToAdd) == 0 {
+		return nil
+	}
+	if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun {
+		msg := fmt.Sprintf("sync mode %s would have added %d trakt history item(s) from imdb check-ins", syncMode, len(historyToAdd))
+		s.logger.Info(msg, slog.Any("checkins", historyToAdd))
+		return nil
+	}
+	items, err := s.review(entityCheckins, operationAdd, entityHistory, historyToAdd)
+	if err != nil || len(items) == 0 {
+		return err
+	}
+	if err = s.destination.HistoryAdd(items); err != nil {
+		return fmt.Errorf("failure adding trakt history from imdb check-ins: %w", err)
+	}
+	s.observeItemsSynced(entityCheckins, operationAdd, len(items))
+	return nil
+}
+
+// hydrateCheckinList fetches the imdb list of titles to check in to trakt.
+func (s *Syncer) hydrateCheckinList() error {
+	lid := *s.conf.CheckinList
+	if !s.mirrorListEnabled(lid) {
+		return nil
+	}
+	imdbItems, err := s.fetchMirrorList(lid, "imdb check-in list")
+	if err != nil {
+		return err
+	}
+	s.user.imdbCheckinList = imdbItems
+	return nil
+}
+
+// syncCheckinList checks in to trakt the movies and episodes of the imdb check-in list, removing each item from the
+// list once it is checked in. Trakt allows a single check-in at a time, so the items that follow a successful check-in
+// are rejected until it expires, and stay on the list for the following syncs.
+func (s *Syncer) syncCheckinList() error {
+	lid := *s.conf.CheckinList
+	if lid == "" {
+		s.logger.Info("skipping imdb check-in list sync")
+		return nil
+	}
+	listMode := s.conf.ListMode(lid)
+	if listMode == appconfig.ListModeDisabled {
+		s.logger.Info(fmt.Sprintf("skipping disabled imdb check-in list %s", lid))
+		return nil
+	}
+	var itemsToCheckin entities.TraktItems
+	for _, imdbItem := range s.user.imdbCheckinList {
+		traktItem := imdbItem.ToTraktWatchedItem()
+		if traktItem.Type != entities.TraktItemTypeMovie && traktItem.Type != entities.TraktItemTypeEpisode {
+			s.logger.Warn(fmt.Sprintf("skipping imdb check-in list item %s, since only movies and episodes can be checked in", imdbItem.ID))
+			continue
+		}
+		itemsToCheckin = append(itemsToCheckin, traktItem)
+	}
+	if len(itemsToCheckin) == 0 {
+		return nil
+	}
+	if listMode == appconfig.SyncModeDryRun {
+		msg := fmt.Sprintf("sync mode %s would have checked in to %d trakt item(s) from imdb check-in list", listMode, len(itemsToCheckin))
+		s.logger.Info(msg, slog.Any(entityCheckinList, itemsToCheckin))
+		return nil
+	}
+	items, err := s.review(entityCheckinList, operationAdd, lid, itemsToCheckin)
+	if err != nil || len(items) == 0 {
+		return err
+	}
+	for i, item := range items {
+		id, err := item.GetItemID()
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt item id: %w", err)
+		}
+		if err = s.destination.Checkin(item); err != nil {
+			if errors.Is(err, client.ErrTraktCheckinInProgress) {
+				s.logger.Info(fmt.Sprintf("leaving %d imdb check-in list item(s) for the next sync, since a trakt check-in is in progress", len(items)-i))
+				return nil
+			}
+			var notFoundErr *client.NotFoundError
+			if errors.As(err, &notFoundErr) {
+				s.logger.Warn(fmt.Sprintf("skipping %s %s of imdb check-in list", item.Type, *id), slog.Any("error", err))
+				s.summary.addNotFound(NotFoundItem{
+					Entity: entityCheckinList,
+					Target: lid,
+					Type:   item.Type,
+					IMDb:   *id,
+				})
+				continue
+			}
+			return fmt.Errorf("failure checking in to trakt %s %s: %w", item.Type, *id, err)
+		}
+		s.observeItemsSynced(entityCheckinList, operationAdd, 1)
+		if err = s.imdbClient.ListItemsRemove(lid, *id); err != nil {
+			return fmt.Errorf("failure removing %s from imdb check-in list: %w", *id, err)
+		}
+		s.observeItemsSynced(entityCheckinList, operationRemove, 1)
+	}
+	return nil
+}
+
+// hydrateCollection fetches the imdb list designated as collection, along with the trakt collection.
+func (s *Syncer) hydrateCollection() error {
+	lid := *s.conf.Collection
+	if !s.mirrorListEnabled(lid) {
+		return nil
+	}
+	if err := s.imdbClient.ListsExport(lid); err != nil {
+		return fmt.Errorf("failure exporting imdb collection list: %w", err)
+	}
+	imdbLists, err := s.imdbClient.ListsGet(lid)
+	if err != nil {
+		return fmt.Errorf("failure fetching imdb collection list: %w", err)
+	}
+	for _, imdbList := range imdbLists {
+		if imdbList.ListID == lid {
+			s.user.imdbCollection = imdbList.ListItems
+		}
+	}
+	s.user.imdbCounts[entityCollection] = len(s.user.imdbCollection)
+	return nil
+}
+
+func (s *Syncer) syncCollection() error {
+	lid := *s.conf.Collection
+	if lid == "" {
+		s.logger.Info("skipping collection sync")
+		return nil
+	}
+	listMode := s.conf.ListMode(lid)
+	if listMode == appconfig.ListModeDisabled {
+		s.logger.Info(fmt.Sprintf("skipping disabled imdb collection list %s", lid))
+		return nil
+	}
+	// trakt collects movies and shows, while collected episodes are nested under their shows
+	// items of any other type are skipped, since they would never match the fetched trakt collection
+	var collectionToAdd, collectionToRemove entities.TraktItems
+	collected := make(map[string]struct{}, len(s.user.imdbCollection))
+	filter := s.conf.ItemFilter(entityCollection)
+	for _, imdbItem := range s.user.imdbCollection {
+		traktItem := imdbItem.ToTraktCollectionItem(*s.conf.CollectionMedia)
+		if traktItem.Type != entities.TraktItemTypeMovie && traktItem.Type != entities.TraktItemTypeShow {
+			s.logger.Warn("skipping imdb collection item that is neither a movie nor a show", slog.String("id", imdbItem.ID))
+			continue
+		}
+		collected[imdbItem.ID] = struct{}{}
+		if !filter.Allows(imdbItem) {
+			continue
+		}
+		if _, found := s.user.traktCollection[imdbItem.ID]; !found {
+			collectionToAdd = append(collectionToAdd, traktItem)
+		}
+	}
+	for id, traktItem := range s.user.traktCollection {
+		if !filter.AllowsType(traktItem.Type) {
+			continue
+		}
+		if _, found := collected[id]; !found {
+			collectionToRemove = append(collectionToRemove, traktItem)
+		}
+	}
+	if len(collectionToAdd) > 0 {
+		if listMode == appconfig.SyncModeDryRun {
+			msg := fmt.Sprintf("sync mode %s would have added %d trakt collection item(s)", listMode, len(collectionToAdd))
+			s.logger.Info(msg, slog.Any("collection", collectionToAdd))
+		} else {
+			items, err := s.review(entityCollection, operationAdd, entityCollection, collectionToAdd)
+			if err != nil {
+				return err
+			}
+			if len(items) > 0 {
+				if err = s.destination.CollectionAdd(items); err != nil {
+					return fmt.Errorf("failure adding trakt collection items: %w", err)
+				}
+				s.observeItemsSynced(entityCollection, operationAdd, len(items))
+			}
+		}
+	}
+	if len(collectionToRemove) > 0 {
+		if listMode == appconfig.SyncModeDryRun || listMode == appconfig.SyncModeAddOnly {
+			msg := fmt.Sprintf("sync mode %s would have deleted %d trakt collection item(s)", listMode, len(collectionToRemove))
+			s.logger.Info(msg, slog.Any("collection", collectionToRemove))
+		} else {
+			items, err := s.review(entityCollection, operationRemove, entityCollection, collectionToRemove)
+			if err != nil {
+				return err
+			}
+			if len(items) > 0 {
+				if err = s.destination.CollectionRemove(items); err != nil {
+					return fmt.Errorf("failure removing trakt collection items: %w", err)
+				}
+				s.observeItemsSynced(entityCollection, operationRemove, len(items))
+			}
+		}
+	}
+	return nil
+}
+
+// hydrateFavorites fetches the imdb list designated as favorites.
+func (s *Syncer) hydrateFavorites() error {
+	lid := *s.conf.Favorites
+	if !s.mirrorListEnabled(lid) {
+		return nil
+	}
+	if err := s.imdbClient.ListsExport(lid); err != nil {
+		return fmt.Errorf("failure exporting imdb favorites list: %w", err)
+	}
+	imdbLists, err := s.imdbClient.ListsGet(lid)
+	if err != nil {
+		return fmt.Errorf("failure fetching imdb favorites list: %w", err)
+	}
+	for _, imdbList := range imdbLists {
+		if imdbList.ListID == lid {
+			s.user.imdbFavorites = imdbList.ListItems
+		}
+	}
+	return nil
+}
+
+// syncFavorites aligns the trakt favorites with the imdb list designated as favorites. Trakt only accepts movies and
+// shows as favorites, so items of any other type are skipped.
+func (s *Syncer) syncFavorites() error {
+	lid := *s.conf.Favorites
+	if lid == "" {
+		s.logger.Info("skipping favorites sync")
+		return nil
+	}
+	listMode := s.conf.ListMode(lid)
+	if listMode == appconfig.ListModeDisabled {
+		s.logger.Info(fmt.Sprintf("skipping disabled imdb favorites list %s", lid))
+		return nil
+	}
+	var favoritesToAdd, favoritesToRemove entities.TraktItems
+	favorites := make(map[string]struct{}, len(s.user.imdbFavorites))
+	for _, imdbItem := range s.user.imdbFavorites {
+		traktItem := imdbItem.ToTraktWatchedItem()
+		if traktItem.Type != entities.TraktItemTypeMovie && traktItem.Type != entities.TraktItemTypeShow {
+			s.logger.Warn("skipping imdb favorites item that is neither a movie nor a show", slog.String("id", imdbItem.ID))
+			continue
+		}
+		favorites[imdbItem.ID] = struct{}{}
+		if _, found := s.user.traktFavorites[imdbItem.ID]; !found {
+			favoritesToAdd = append(favoritesToAdd, traktItem)
+		}
+	}
+	for id, traktItem := range s.user.traktFavorites {
+		if _, found := favorites[id]; !found {
+			favoritesToRemove = append(favoritesToRemove, traktItem)
+		}
+	}
+	if len(favoritesToAdd) > 0 {
+		if listMode == appconfig.SyncModeDryRun {
+			msg := fmt.Sprintf("sync mode %s would have added %d trakt favorites item(s)", listMode, len(favoritesToAdd))
+			s.logger.Info(msg, slog.Any("favorites", favoritesToAdd))
+		} else {
+			items, err := s.review(entityFavorites, operationAdd, entityFavorites, favoritesToAdd)
+			if err != nil {
+				return err
+			}
+			if len(items) > 0 {
+				if err = s.destination.FavoritesAdd(items); err != nil {
+					return fmt.Errorf("failure adding trakt favorites items: %w", err)
+				}
+				s.observeItemsSynced(entityFavorites, operationAdd, len(items))
+			}
+		}
+	}
+	if len(favoritesToRemove) > 0 {
+		if listMode == appconfig.SyncModeDryRun || listMode == appconfig.SyncModeAddOnly {
+			msg := fmt.Sprintf("sync mode %s would have deleted %d trakt favorites item(s)", listMode, len(favoritesToRemove))
+			s.logger.Info(msg, slog.Any("favorites", favoritesToRemove))
+		} else {
+			items, err := s.review(entityFavorites, operationRemove, entityFavorites, favoritesToRemove)
+			if err != nil {
+				return err
+			}
+			if len(items) > 0 {
+				if err = s.destination.FavoritesRemove(items); err != nil {
+					return fmt.Errorf("failure removing trakt favorites items: %w", err)
+				}
+				s.observeItemsSynced(entityFavorites, operationRemove, len(items))
+			}
+		}
+	}
+	return nil
+}
+
+// hydrateWatchedList fetches the imdb list designated to mirror the trakt history.
+func (s *Syncer) hydrateWatchedList() error {
+	lid := *s.conf.WatchedList
+	if !s.mirrorListEnabled(lid) {
+		return nil
+	}
+	imdbItems, err := s.fetchMirrorList(lid, "imdb watched list")
+	if err != nil {
+		return err
+	}
+	s.user.imdbWatchedList = imdbItems
+	return nil
+}
+
+// hydrateRecommendations fetches the imdb list designated to hold the trakt recommendations.
+func (s *Syncer) hydrateRecommendations() error {
+	lid := *s.conf.Recommendations
+	if !s.mirrorListEnabled(lid) {
+		return nil
+	}
+	imdbItems, err := s.fetchMirrorList(lid, "imdb recommendations list")
+	if err != nil {
+		return err
+	}
+	s.user.imdbRecommendations = imdbItems
+	return nil
+}
+
+// hydrateHidden fetches the imdb list of titles that the user is not interested in.
+func (s *Syncer) hydrateHidden() error {
+	lid := *s.conf.Hidden
+	if !s.mirrorListEnabled(lid) {
+		return nil
+	}
+	imdbItems, err := s.fetchMirrorList(lid, "imdb hidden list")
+	if err != nil {
+		return err
+	}
+	s.user.imdbHidden = imdbItems
+	return nil
+}
+
+func hiddenSections() []string {
+	return []string{
+		entities.TraktHiddenSectionRecommendations,
+		entities.TraktHiddenSectionCalendar,
+	}
+}
+
+// fetchMirrorList exports and fetches the items of an imdb list that mirrors trakt data.
+func (s *Syncer) fetchMirrorList(lid, name string) ([]entities.IMDbItem, error) {
+	if err := s.imdbClient.ListsExport(lid); err != nil {
+		return nil, fmt.Errorf("failure exporting %s: %w", name, err)
+	}
+	imdbLists, err := s.imdbClient.ListsGet(lid)
+	if err != nil {
+		return nil, fmt.Errorf("failure fetching %s: %w", name, err)
+	}
+	for _, imdbList := range imdbLists {
+		if imdbList.ListID == lid {
+			return imdbList.ListItems, nil
+		}
+	}
+	return nil, nil
+}
+
+// mapTraktItems indexes the trakt items by their imdb id, leaving out the items without one.
+func mapTraktItems(traktItems entities.TraktItems, mapped map[string]entities.TraktItem) error {
+	for _, traktItem := range traktItems {
+		id, err := traktItem.GetItemID()
+		if err != nil {
+			return fmt.Errorf("failure fetching trakt item id: %w", err)
+		}
+		if id != nil && *id != "" {
+			mapped[*id] = traktItem
+		}
+	}
+	return nil
+}
+
+// syncWatchedList mirrors the trakt history into an imdb list.
+func (s *Syncer) syncWatchedList() error {
+	return s.syncMirrorList(entityWatchedList, "imdb watched list", *s.conf.WatchedList, s.user.imdbWatchedList, s.user.traktWatched)
+}
+
+// syncRecommendations writes the trakt recommendations into an imdb list, so that they can be browsed on imdb.
+func (s *Syncer) syncRecommendations() error {
+	return s.syncMirrorList(entityRecommendations, "imdb recommendations list", *s.conf.Recommendations, s.user.imdbRecommendations, s.user.traktRecommendations)
+}
+
+// syncMirrorList makes an imdb list mirror the given trakt items, which runs in the opposite direction of the other
+// syncs. Items missing from trakt are removed from the imdb list, unless the list mode prevents removals.
+func (s *Syncer) syncMirrorList(entity, name, lid string, imdbItems []entities.IMDbItem, traktItems map[string]entities.TraktItem) error {
+	if lid == "" {
+		s.logger.Info(fmt.Sprintf("skipping %s sync", name))
+		return nil
+	}
+	listMode := s.conf.ListMode(lid)
+	if listMode == appconfig.ListModeDisabled {
+		s.logger.Info(fmt.Sprintf("skipping disabled %s %s", name, lid))
+		return nil
+	}
+	var itemsToAdd, itemsToRemove entities.TraktItems
+	listed := make(map[string]struct{}, len(imdbItems))
+	for _, imdbItem := range imdbItems {
+		listed[imdbItem.ID] = struct{}{}
+		if _, found := traktItems[imdbItem.ID]; !found {
+			itemsToRemove = append(itemsToRemove, imdbItem.ToTraktWatchedItem())
+		}
+	}
+	for id, traktItem := range traktItems {
+		if _, found := listed[id]; !found {
+			itemsToAdd = append(itemsToAdd, traktItem)
+		}
+	}
+	if len(itemsToAdd) > 0 {
+		if listMode == appconfig.SyncModeDryRun {
+			msg := fmt.Sprintf("sync mode %s would have added %d %s item(s)", listMode, len(itemsToAdd), name)
+			s.logger.Info(msg, slog.Any(entity, itemsToAdd))
+		} else {
+			items, err := s.review(entity, operationAdd, lid, itemsToAdd)
+			if err != nil {
+				return err
+			}
+			if len(items) > 0 {
+				if err = s.imdbClient.ListItemsAdd(lid, itemIDs(items)...); err != nil {
+					return fmt.Errorf("failure adding %s items: %w", name, err)
+				}
+				s.observeItemsSynced(entity, operationAdd, len(items))
+			}
+		}
+	}
+	if len(itemsToRemove) > 0 {
+		if listMode == appconfig.SyncModeDryRun || listMode == appconfig.SyncModeAddOnly {
+			msg := fmt.Sprintf("sync mode %s would have deleted %d %s item(s)", listMode, len(itemsToRemove), name)
+			s.logger.Info(msg, slog.Any(entity, itemsToRemove))
+		} else {
+			items, err := s.review(entity, operationRemove, lid, itemsToRemove)
+			if err != nil {
+				return err
+			}
+			if len(items) > 0 {
+				if err = s.imdbClient.ListItemsRemove(lid, itemIDs(items)...); err != nil {
+					return fmt.Errorf("failure removing %s items: %w", name, err)
+				}
+				s.observeItemsSynced(entity, operationRemove, len(items))
+			}
+		}
+	}
+	return nil
+}
+
+// syncHidden hides the movies and shows of the imdb hidden list from the trakt sections, and adds the items hidden on
+// trakt to the imdb list. Nothing is ever unhidden or removed from the list, since there is no telling on which side
+// an item was removed.
+func (s *Syncer) syncHidden() error {
+	lid := *s.conf.Hidden
+	if lid == "" {
+		s.logger.Info("skipping hidden items sync")
+		return nil
+	}
+	listMode := s.conf.ListMode(lid)
+	if listMode == appconfig.ListModeDisabled {
+		s.logger.Info(fmt.Sprintf("skipping disabled imdb hidden list %s", lid))
+		return nil
+	}
+	listed := make(map[string]struct{}, len(s.user.imdbHidden))
+	for _, imdbItem := range s.user.imdbHidden {
+		listed[imdbItem.ID] = struct{}{}
+	}
+	for _, section := range hiddenSections() {
+		var itemsToHide entities.TraktItems
+		for _, imdbItem := range s.user.imdbHidden {
+			item := imdbItem.ToTraktWatchedItem()
+			if item.Type != entities.TraktItemTypeMovie && item.Type != entities.TraktItemTypeShow {
+				continue
+			}
+			if _, found := s.user.traktHidden[section][imdbItem.ID]; !found {
+				itemsToHide = append(itemsToHide, item)
+			}
+		}
+		if len(itemsToHide) == 0 {
+			continue
+		}
+		if listMode == appconfig.SyncModeDryRun {
+			msg := fmt.Sprintf("sync mode %s would have hidden %d trakt %s item(s)", listMode, len(itemsToHide), section)
+			s.logger.Info(msg, slog.Any(entityHidden, itemsToHide))
+			continue
+		}
+		items, err := s.review(entityHidden, operationAdd, section, itemsToHide)
+		if err != nil {
+			return err
+		}
+		if len(items) > 0 {
+			if err = s.destination.HiddenAdd(section, items); err != nil {
+				return fmt.Errorf("failure hiding trakt %s items: %w", section, err)
+			}
+			s.observeItemsSynced(entityHidden, operationAdd, len(items))
+		}
+	}
+	var itemsToList entities.TraktItems
+	for _, section := range hiddenSections() {
+		for id, traktItem := range s.user.traktHidden[section] {
+			if _, found := listed[id]; !found {
+				listed[id] = struct{}{}
+				itemsToList = append(itemsToList, traktItem)
+			}
+		}
+	}
+	if len(itemsToList) == 0 {
+		return nil
+	}
+	if listMode == appconfig.SyncModeDryRun {
+		msg := fmt.Sprintf("sync mode %s would have added %d imdb hidden list item(s)", listMode, len(itemsToList))
+		s.logger.Info(msg, slog.Any(entityHidden, itemsToList))
+		return nil
+	}
+	items, err := s.review(entityHidden, operationAdd, lid, itemsToList)
+	if err != nil {
+		return err
+	}
+	if len(items) > 0 {
+		if err = s.imdbClient.ListItemsAdd(lid, itemIDs(items)...); err != nil {
+			return fmt.Errorf("failure adding imdb hidden list items: %w", err)
+		}
+		s.observeItemsSynced(entityHidden, operationAdd, len(items))
+	}
+	return nil
+}
+
+// syncLikedLists likes the configured trakt lists, such as the official counterparts of the imdb editorial lists, rather
+// than duplicating them as personal lists. The lists that the sync liked are kept in the state, so that only those are
+// unliked once they are no longer configured, while the lists that the user liked on trakt are left alone.
+func (s *Syncer) syncLikedLists() error {
+	if *s.conf.Destination != appconfig.SyncDestinationTrakt {
+		return nil
+	}
+	var managed []string
+	if err := s.store.Load(stateKeyLikedLists, &managed); err != nil && !errors.Is(err, state.ErrNotFound) {
+		return fmt.Errorf("failure loading liked trakt lists: %w", err)
+	}
+	configured := *s.conf.LikedLists
+	if len(configured) == 0 && len(managed) == 0 {
+		s.logger.Info("skipping trakt liked lists sync")
+		return nil
+	}
+	likedLists, err := s.destination.LikedListsGet()
+	if err != nil {
+		return fmt.Errorf("failure fetching liked trakt lists: %w", err)
+	}
+	liked := make(map[string]struct{}, len(likedLists))
+	for _, list := range likedLists {
+		liked[strconv.FormatInt(list.IDMeta.Trakt, 10)] = struct{}{}
+	}
+	var listsToLike, listsToUnlike []string
+	for _, lid := range configured {
+		if _, found := liked[lid]; !found {
+			listsToLike = append(listsToLike, lid)
+		}
+	}
+	for _, lid := range managed {
+		if _, found := liked[lid]; found && !slices.Contains(configured, lid) && *s.conf.Mode == appconfig.SyncModeFull {
+			listsToUnlike = append(listsToUnlike, lid)
+		}
+	}
+	if *s.conf.Mode == appconfig.SyncModeDryRun {
+		if len(listsToLike) > 0 {
+			s.logger.Info(fmt.Sprintf("sync mode %s would have liked %d trakt list(s)", *s.conf.Mode, len(listsToLike)), slog.Any(entityLikedLists, listsToLike))
+		}
+		return nil
+	}
+	// lists that are no longer liked and no longer configured are forgotten, while the rest stay managed until unliked
+	next := make([]string, 0, len(managed)+len(listsToLike))
+	for _, lid := range managed {
+		if _, found := liked[lid]; found || slices.Contains(configured, lid) {
+			next = append(next, lid)
+		}
+	}
+	var errs []error
+	for _, lid := range listsToLike {
+		if err = s.destination.ListLike(lid); err != nil {
+			errs = append(errs, fmt.Errorf("failure liking trakt list %s: %w", lid, err))
+			continue
+		}
+		if !slices.Contains(next, lid) {
+			next = append(next, lid)
+		}
+		s.observeItemsSynced(entityLikedLists, operationAdd, 1)
+	}
+	for _, lid := range listsToUnlike {
+		if err = s.destination.ListUnlike(lid); err != nil {
+			errs = append(errs, fmt.Errorf("failure unliking trakt list %s: %w", lid, err))
+			continue
+		}
+		next = slices.DeleteFunc(next, func(id string) bool {
+			return id == lid
+		})
+		s.observeItemsSynced(entityLikedLists, operationRemove, 1)
+	}
+	if err = s.store.Save(stateKeyLikedLists, next); err != nil {
+		errs = append(errs, fmt.Errorf("failure saving liked trakt lists: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// syncReverseLists syncs the configured trakt lists to imdb lists of their own, which runs in the opposite direction of
+// the lists sync. Each imdb list is created on the first sync of its trakt list, and its id is kept in the state, so
+// that the lists sync leaves the imdb lists that mirror trakt lists alone.
+func (s *Syncer) syncReverseLists() error {
+	if *s.conf.Destination != appconfig.SyncDestinationTrakt {
+		return nil
+	}
+	slugs := *s.conf.ReverseLists
+	if len(slugs) == 0 {
+		s.logger.Info("skipping imdb reverse lists sync")
+		return nil
+	}
+	managed, err := s.loadReverseLists()
+	if err != nil {
+		return err
+	}
+	// the imdb lists of the trakt lists that are no longer configured are forgotten, but never deleted
+	next := make(map[string]string, len(slugs))
+	idMetas := make(entities.TraktIDMetas, 0, len(slugs))
+	for _, slug := range slugs {
+		if lid, found := managed[slug]; found {
+			next[slug] = lid
+		}
+		idMetas = append(idMetas, entities.TraktIDMeta{
+			Slug: slug,
+		})
+	}
+	traktLists, delegatedErrors := s.destination.ListsGet(idMetas)
+	for _, delegatedErr := range delegatedErrors {
+		var notFoundError *client.TraktListNotFoundError
+		if errors.As(delegatedErr, &notFoundError) {
+			s.logger.Warn(fmt.Sprintf("skipping trakt list %s since it does not exist", notFoundError.Slug))
+			continue
+		}
+		return fmt.Errorf("failure fetching trakt lists: %w", delegatedErr)
+	}
+	var errs []error
+	for _, traktList := range traktLists {
+		slug := traktList.IDMeta.Slug
+		lid, err := s.syncReverseList(traktList, next[slug])
+		if lid != "" {
+			next[slug] = lid
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failure syncing trakt list %s to imdb: %w", slug, err))
+		}
+	}
+	if *s.conf.Mode == appconfig.SyncModeDryRun {
+		return errors.Join(errs...)
+	}
+	if err = s.store.Save(stateKeyReverseLists, next); err != nil {
+		errs = append(errs, fmt.Errorf("failure saving imdb reverse lists: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// syncReverseList makes the imdb list mirror the trakt list, creating the imdb list when it does not exist yet, and
+// returns the id of the imdb list.
+func (s *Syncer) syncReverseList(traktList entities.TraktList, lid string) (string, error) {
+	slug := traktList.IDMeta.Slug
+	var imdbItems []entities.IMDbItem
+	if lid != "" {
+		items, err := s.fetchMirrorList(lid, fmt.Sprintf("imdb list %s", lid))
+		var notFoundError *client.NotFoundError
+		switch {
+		case errors.As(err, &notFoundError):
+			s.logger.Warn(fmt.Sprintf("recreating imdb list %s of trakt list %s since it no longer exists", lid, slug))
+			lid = ""
+		case err != nil:
+			return lid, err
+		default:
+			imdbItems = items
+		}
+	}
+	if lid == "" {
+		name := slug
+		if traktList.Name != nil && *traktList.Name != "" {
+			name = *traktList.Name
+		}
+		if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun {
+			s.logger.Info(fmt.Sprintf("sync mode %s would have created imdb list %s to mirror trakt list %s", syncMode, name, slug))
+			return "", nil
+		}
+		created, err := s.imdbClient.ListCreate(name, traktList.Description)
+		if err != nil {
+			return "", fmt.Errorf("failure creating imdb list: %w", err)
+		}
+		lid = created
+	}
+	traktItems := make(map[string]entities.TraktItem, len(traktList.ListItems))
+	if err := mapTraktItems(traktList.ListItems, traktItems); err != nil {
+		return lid, err
+	}
+	return lid, s.syncMirrorList(entityReverseLists, fmt.Sprintf("imdb list %s", lid), lid, imdbItems, traktItems)
+}
+
+// loadReverseLists returns the ids of the imdb lists that mirror trakt lists, keyed by the slug of the trakt list.
+func (s *Syncer) loadReverseLists() (map[string]string, error) {
+	reverseLists := make(map[string]string)
+	if err := s.store.Load(stateKeyReverseLists, &reverseLists); err != nil && !errors.Is(err, state.ErrNotFound) {
+		return nil, fmt.Errorf("failure loading imdb reverse lists: %w", err)
+	}
+	return reverseLists, nil
+}
+
+func isReverseList(reverseLists map[string]string, lid string) bool {
+	for _, id := range reverseLists {
+		if id == lid {
+			return true
+		}
+	}
+	return false
+}
+
+// itemIDs returns the imdb ids of the items, skipping the items without one.
+func itemIDs(items entities.TraktItems) []string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		if id, err := item.GetItemID(); err == nil && id != nil && *id != "" {
+			ids = append(ids, *id)
+		}
+	}
+	return ids
+}
+
+func (s *Syncer) syncReviews() error {
+	if s.authless {
+		s.logger.Info("skipping reviews sync since no imdb auth was provided")
+		return nil
+	}
+	if !*s.conf.Reviews {
+		s.logger.Info("skipping reviews sync")
+		return nil
+	}
+	// reviews are only posted as comments on items that the user has not commented on yet
+	var commentsToAdd []entities.TraktComment
+	for _, review := range s.user.imdbReviews {
+		if _, commented := s.user.traktComments[review.ItemID]; commented {
+			continue
+		}
+		comment := review.ToTraktComment()
+		if words := len(strings.Fields(comment.Comment)); words < traktCommentMinWords {
+			s.logger.Warn("skipping imdb review below the trakt minimum word count", slog.String("id", review.ItemID), slog.Int("words", words))
+			continue
+		}
+		commentsToAdd = append(commentsToAdd, comment)
+	}
+	if len(commentsToAdd) == 0 {
+		return nil
+	}
+	if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun {
+		msg := fmt.Sprintf("sync mode %s would have added %d trakt comment(s) from imdb reviews", syncMode, len(commentsToAdd))
+		s.logger.Info(msg, slog.Any("reviews", commentsToAdd))
+		return nil
+	}
+	added := 0
+	for _, comment := range commentsToAdd {
+		if err := s.destination.CommentAdd(comment); err != nil {
+			var notFoundErr *client.NotFoundError
+			if errors.As(err, &notFoundErr) {
+				s.logger.Warn("skipping imdb review of an item that trakt could not find", logger.Error(err))
+				continue
+			}
+			return fmt.Errorf("failure adding trakt comment from imdb review: %w", err)
+		}
+		added++
+	}
+	s.observeItemsSynced(entityReviews, operationAdd, added)
+	return nil
+}
+
+func (s *Syncer) syncPlexWatchlist() error {
+	if s.plexClient == nil {
+		return nil
+	}
+	if (s.authless && !s.publicWatchlist) || !*s.conf.Watchlist {
+		s.logger.Info("skipping plex watchlist sync")
+		return nil
+	}
+	var imdbWatchlist *entities.IMDbList
+	for _, list := range s.user.imdbLists {
+		if list.IsWatchlist {
+			imdbWatchlist = &list
+			break
+		}
+	}
+	if imdbWatchlist == nil {
+		return nil
+	}
+	// upcoming titles are kept apart on trakt only, so the plex watchlist gets the whole imdb watchlist
+	if upcoming, found := s.user.imdbLists[upcomingListID]; found {
+		imdbWatchlist.ListItems = slices.Concat(imdbWatchlist.ListItems, upcoming.ListItems)
+	}
+	plexWatchlist, err := s.plexClient.WatchlistGet()
+	if err != nil {
+		return fmt.Errorf("failure fetching plex watchlist: %w", err)
+	}
+	plexItems := make(map[string]entities.PlexItem, len(plexWatchlist))
+	for _, plexItem := range plexWatchlist {
+		if imdbID := plexItem.IMDbID(); imdbID != "" {
+			plexItems[imdbID] = plexItem
+		}
+	}
+	imdbItems := make(map[string]struct{}, len(imdbWatchlist.ListItems))
+	var ratingKeysToAdd []string
+	for _, imdbItem := range imdbWatchlist.ListItems {
+		imdbItems[imdbItem.ID] = struct{}{}
+		if _, found := plexItems[imdbItem.ID]; found {
+			continue
+		}
+		itemType := imdbItem.ToPlexItemType()
+		if itemType == "" {
+			continue
+		}
+		plexItem, err := s.plexClient.MetadataMatch(imdbItem.ID, itemType)
+		if err != nil {
+			return fmt.Errorf("failure matching imdb item %s on plex: %w", imdbItem.ID, err)
+		}
+		if plexItem == nil {
+			s.logger.Warn("could not find imdb item on plex", slog.String("id", imdbItem.ID))
+			continue
+		}
+		ratingKeysToAdd = append(ratingKeysToAdd, plexItem.RatingKey)
+	}
+	var ratingKeysToRemove []string
+	for imdbID, plexItem := range plexItems {
+		if _, found := imdbItems[imdbID]; !found {
+			ratingKeysToRemove = append(ratingKeysToRemove, plexItem.RatingKey)
+		}
+	}
+	if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeDryRun {
+		msg := fmt.Sprintf("sync mode %s would have added %d and removed %d plex watchlist item(s)", syncMode, len(ratingKeysToAdd), len(ratingKeysToRemove))
+		s.logger.Info(msg, slog.Any("add", ratingKeysToAdd), slog.Any("remove", ratingKeysToRemove))
+		return nil
+	}
+	for _, ratingKey := range ratingKeysToAdd {
+		if err = s.plexClient.WatchlistItemAdd(ratingKey); err != nil {
+			return fmt.Errorf("failure adding item to plex watchlist: %w", err)
+		}
+	}
+	s.observeItemsSynced(entityPlex, operationAdd, len(ratingKeysToAdd))
+	if syncMode := *s.conf.Mode; syncMode == appconfig.SyncModeAddOnly {
+		msg := fmt.Sprintf("sync mode %s would have removed %d plex watchlist item(s)", syncMode, len(ratingKeysToRemove))
+		s.logger.Info(msg, slog.Any("remove", ratingKeysToRemove))
+		return nil
+	}
+	for _, ratingKey := range ratingKeysToRemove {
+		if err = s.plexClient.WatchlistItemRemove(ratingKey); err != nil {
+			return fmt.Errorf("failure removing item from plex watchlist: %w", err)
+		}
+	}
+	s.observeItemsSynced(entityPlex, operationRemove, len(ratingKeysToRemove))
+	return nil
+}
@@ -0,0 +1,150 @@
+package syncer
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+func TestSyncer_addPeers(t *testing.T) {
+	destinationConf := func(destination string) *appconfig.Config {
+		return &appconfig.Config{Sync: appconfig.Sync{Destination: &destination}}
+	}
+	s := &Syncer{
+		logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+		summary:         newSummary(),
+		destinationName: appconfig.SyncDestinationTrakt,
+	}
+	errUnreachable := errors.New("jellyfin is unreachable")
+	s.addPeers([]*appconfig.Config{destinationConf(appconfig.SyncDestinationJellyfin), destinationConf(appconfig.SyncDestinationKodi)}, func(conf *appconfig.Config) (*Syncer, error) {
+		if *conf.Sync.Destination == appconfig.SyncDestinationJellyfin {
+			return nil, errUnreachable
+		}
+		return &Syncer{summary: newSummary(), destinationName: *conf.Sync.Destination}, nil
+	})
+	assertions := assert.New(t)
+	assertions.Len(s.peers, 1)
+	assertions.Equal(appconfig.SyncDestinationKodi, s.peers[0].destinationName)
+	assertions.Len(s.failedPeers, 1)
+	s.summary.merge(s.destinationName, newSummary())
+	errs := s.mergeFailedPeers()
+	assertions.Len(errs, 1)
+	assertions.ErrorIs(errs[0], errUnreachable)
+	assertions.Len(s.summary.Destinations, 2)
+	assertions.Equal(reportStatusOK, s.summary.Destinations[0].Status)
+	assertions.Equal(appconfig.SyncDestinationJellyfin, s.summary.Destinations[1].Destination)
+	assertions.Equal(reportStatusFailed, s.summary.Destinations[1].Status)
+	assertions.Equal(reportStatusPartial, s.summary.status())
+}
+
+// mockDestinationClient records the items that are removed from the watchlist.
+type mockDestinationClient struct {
+	client.DestinationClientInterface
+	watchlistRemoved entities.TraktItems
+}
+
+func (m *mockDestinationClient) WatchlistItemsRemove(items entities.TraktItems) error {
+	m.watchlistRemoved = append(m.watchlistRemoved, items...)
+	return nil
+}
+
+func TestSyncer_syncList_watchlistRemove(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		removed     int
+	}{
+		{
+			name:        "remove watchlist items from trakt",
+			destination: appconfig.SyncDestinationTrakt,
+			removed:     1,
+		},
+		{
+			name:        "keep watchlist items on simkl",
+			destination: appconfig.SyncDestinationSimkl,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destination := &mockDestinationClient{}
+			mode := appconfig.SyncModeFull
+			watchlist := entities.IMDbList{ListID: "ls000000001", IsWatchlist: true}
+			s := &Syncer{
+				logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+				destination:     destination,
+				destinationName: tt.destination,
+				conf:            appconfig.Sync{Mode: &mode},
+				summary:         newSummary(),
+				user: &user{
+					traktListNames: map[string]string{},
+					traktLists: map[string]entities.TraktList{
+						watchlist.ListID: {
+							IsWatchlist: true,
+							ListItems: entities.TraktItems{
+								{
+									Type:  entities.TraktItemTypeMovie,
+									Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt0000001"}},
+								},
+							},
+						},
+					},
+				},
+			}
+			assertions := assert.New(t)
+			assertions.NoError(s.syncList(watchlist))
+			assertions.Len(destination.watchlistRemoved, tt.removed)
+			assertions.Equal(tt.removed, s.summary.Items[entityWatchlist][operationRemove])
+		})
+	}
+}
+
+func TestSyncer_resolveRatingConflicts(t *testing.T) {
+	imdbRating, traktRating := 8, 6
+	strategy := appconfig.RatingsConflictSkip
+	s := &Syncer{
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		conf:    appconfig.Sync{RatingsConflict: &strategy},
+		summary: newSummary(),
+		user: &user{
+			imdbRatings: map[string]entities.IMDbItem{
+				"tt0000001": {ID: "tt0000001", Title: "Conflict", Rating: &imdbRating},
+				"tt0000002": {ID: "tt0000002", Rating: &imdbRating},
+			},
+			traktRatings: map[string]entities.TraktItem{
+				"tt0000001": {
+					Type:   entities.TraktItemTypeMovie,
+					Rating: traktRating,
+					Movie:  entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt0000001"}},
+				},
+			},
+		},
+	}
+	items := entities.TraktItems{
+		{
+			Type:  entities.TraktItemTypeMovie,
+			Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt0000001"}, Rating: &imdbRating},
+		},
+		{
+			Type:  entities.TraktItemTypeMovie,
+			Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: "tt0000002"}, Rating: &imdbRating},
+		},
+	}
+	assertions := assert.New(t)
+	assertions.Equal(items[1:], s.resolveRatingConflicts(items))
+	assertions.Equal([]RatingChange{{
+		IMDb:    "tt0000001",
+		Title:   "Conflict",
+		Type:    entities.TraktItemTypeMovie,
+		Old:     &traktRating,
+		New:     &imdbRating,
+		Source:  ratingSourceIMDb,
+		Outcome: ratingOutcomeSkipped,
+	}}, s.summary.RatingChanges)
+}
@@ -0,0 +1,178 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+)
+
+const (
+	serviceName = "imdb-trakt-sync"
+	tracerName  = "github.com/cecobask/imdb-trakt-sync"
+)
+
+// Setup installs a tracer provider that exports spans to an otlp collector over http, and returns a function that
+// flushes the pending spans and shuts the provider down. Nothing is installed when tracing is disabled, in which case
+// spans are no-ops.
+func Setup(ctx context.Context, conf appconfig.Tracing) (func(context.Context) error, error) {
+	if !*conf.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	var options []otlptracehttp.Option
+	if *conf.Endpoint != "" {
+		options = append(options, otlptracehttp.WithEndpointURL(*conf.Endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating otlp trace exporter: %w", err)
+	}
+	// attributes of the OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES environment variables take precedence
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", serviceName)),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating trace resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(*conf.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+type scopeKey struct{}
+
+// scope holds the span that is currently running within a sync. Clients keep the context they were created with, so
+// the spans of their requests find the running span through the scope of that context instead.
+type scope struct {
+	mu      sync.Mutex
+	current trace.Span
+}
+
+func (s *scope) swap(span trace.Span) trace.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := s.current
+	s.current = span
+	return previous
+}
+
+func (s *scope) span() trace.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current
+}
+
+// WithScope returns a context that tracks the running span of a sync, so that requests sent with the context or its
+// descendants are traced as children of that span.
+func WithScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeKey{}, &scope{})
+}
+
+// parent returns the context that the spans started with ctx descend from.
+func parent(ctx context.Context) context.Context {
+	if s, ok := ctx.Value(scopeKey{}).(*scope); ok {
+		if span := s.span(); span != nil {
+			return trace.ContextWithSpan(ctx, span)
+		}
+	}
+	return ctx
+}
+
+// Start starts a span that runs until the returned function is called with the outcome of the traced operation. The
+// span becomes the running span of the scope of ctx in the meantime, hence why spans that run concurrently within the
+// same scope must be started with StartRequest instead.
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, func(error)) {
+	ctx, span := otel.Tracer(tracerName).Start(parent(ctx), name, trace.WithAttributes(attributes...))
+	s, scoped := ctx.Value(scopeKey{}).(*scope)
+	var previous trace.Span
+	if scoped {
+		previous = s.swap(span)
+	}
+	return ctx, func(err error) {
+		end(span, err)
+		if scoped {
+			s.swap(previous)
+		}
+	}
+}
+
+// StartRequest starts a span for a request that the named client sends, and returns a function that ends the span with
+// the status code of the response, or the error that prevented a response.
+func StartRequest(ctx context.Context, client, method, rawURL string) func(int, error) {
+	attributes := []attribute.KeyValue{
+		attribute.String("its.client", client),
+		attribute.String("http.request.method", method),
+	}
+	name := method
+	if u, err := url.Parse(rawURL); err == nil {
+		// the query is left out, since some endpoints take credentials as query parameters
+		u.RawQuery, u.User = "", nil
+		name = fmt.Sprintf("%s %s", method, u.Host)
+		attributes = append(attributes, attribute.String("server.address", u.Hostname()), attribute.String("url.full", u.String()))
+	}
+	_, span := otel.Tracer(tracerName).Start(parent(ctx), name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...),
+	)
+	return func(statusCode int, err error) {
+		if statusCode != 0 {
+			span.SetAttributes(attribute.Int("http.response.status_code", statusCode))
+			if err == nil && statusCode >= http.StatusBadRequest {
+				span.SetStatus(codes.Error, http.StatusText(statusCode))
+			}
+		}
+		end(span, err)
+	}
+}
+
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+type tracedTransport struct {
+	client string
+	next   http.RoundTripper
+}
+
+// InstrumentTransport traces each request that the named client sends through the transport.
+func InstrumentTransport(client string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &tracedTransport{
+		client: client,
+		next:   next,
+	}
+}
+
+func (t *tracedTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	finish := StartRequest(request.Context(), t.client, request.Method, request.URL.String())
+	response, err := t.next.RoundTrip(request)
+	var statusCode int
+	if response != nil {
+		statusCode = response.StatusCode
+	}
+	finish(statusCode, err)
+	return response, err
+}
@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStart(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	// clients keep the context they were created with, which predates the spans of the sync steps
+	clientCtx := WithScope(context.Background())
+	httpClient := &http.Client{
+		Transport: InstrumentTransport("trakt", http.DefaultTransport),
+	}
+	_, endSync := Start(clientCtx, "sync")
+	_, endStep := Start(clientCtx, "ratings", attribute.String("its.entity", "ratings"))
+	request, err := http.NewRequestWithContext(clientCtx, http.MethodGet, server.URL+"/sync/ratings?token=secret", http.NoBody)
+	assert.NoError(t, err)
+	response, err := httpClient.Do(request)
+	assert.NoError(t, err)
+	response.Body.Close()
+	endStep(errors.New("failure syncing ratings"))
+	endSync(nil)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+	requestSpan, stepSpan, syncSpan := spans[0], spans[1], spans[2]
+	assert.Equal(t, "ratings", stepSpan.Name())
+	assert.Equal(t, syncSpan.SpanContext().SpanID(), stepSpan.Parent().SpanID())
+	assert.Equal(t, stepSpan.SpanContext().SpanID(), requestSpan.Parent().SpanID())
+	assert.Equal(t, codes.Error, stepSpan.Status().Code)
+	assert.Equal(t, codes.Error, requestSpan.Status().Code)
+	assert.Equal(t, codes.Unset, syncSpan.Status().Code)
+	assert.Contains(t, requestSpan.Attributes(), attribute.String("url.full", server.URL+"/sync/ratings"))
+	assert.Contains(t, requestSpan.Attributes(), attribute.Int("http.response.status_code", http.StatusNotFound))
+}
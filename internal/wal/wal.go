@@ -0,0 +1,206 @@
+package wal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+const (
+	KindCheckin    = "checkin"
+	KindCommentAdd = "comment-add"
+	KindHistoryAdd = "history-add"
+
+	StatusApplied = "applied"
+	StatusPending = "pending"
+
+	// pendingRetention is how long pending mutations are kept, after which the same mutation can be applied again
+	pendingRetention = time.Hour * 24 * 30
+	stateKey         = "sync-wal"
+)
+
+// Entry describes a mutation that was about to be sent to the destination. The mutation is pending until the
+// destination confirms it, which is when it becomes applied.
+type Entry struct {
+	Key    string    `json:"key"`
+	Kind   string    `json:"kind"`
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// Load returns the entries of the write-ahead log.
+func Load(store state.Store) ([]Entry, error) {
+	var entries []Entry
+	if err := store.Load(stateKey, &entries); err != nil && !errors.Is(err, state.ErrNotFound) {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Client wraps a destination client and writes each mutation that cannot be safely repeated, such as history plays,
+// check-ins and comments, to a write-ahead log before it is sent. A mutation is keyed by the hash of its operation, so
+// when a sync is interrupted after the destination applied a mutation but before the sync finished, the next sync
+// recognises the mutation and does not apply it twice. Mutations that were sent without a response are assumed to have
+// been applied, since repeating them would duplicate the history of the user. Every other mutation is left as is,
+// since it can be repeated without side effects.
+type Client struct {
+	client.DestinationClientInterface
+	store   state.Store
+	logger  *slog.Logger
+	now     func() time.Time
+	entries map[string]Entry
+	mu      sync.Mutex
+}
+
+func NewClient(destination client.DestinationClientInterface, store state.Store, entries []Entry, log *slog.Logger) *Client {
+	c := &Client{
+		DestinationClientInterface: destination,
+		store:                      store,
+		logger:                     log,
+		now:                        time.Now,
+		entries:                    make(map[string]Entry, len(entries)),
+	}
+	for _, entry := range entries {
+		c.entries[entry.Key] = entry
+	}
+	return c
+}
+
+// Prune removes the applied mutations from the write-ahead log, once the sync that applied them has finished. Pending
+// mutations are kept for a while, since their outcome is still unknown.
+func (c *Client) Prune() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for key, entry := range c.entries {
+		if entry.Status == StatusApplied || now.Sub(entry.At) > pendingRetention {
+			delete(c.entries, key)
+		}
+	}
+	return c.save()
+}
+
+func (c *Client) save() error {
+	entries := make([]Entry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		if n := a.At.Compare(b.At); n != 0 {
+			return n
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return c.store.Save(stateKey, entries)
+}
+
+// guard applies the mutation, unless the write-ahead log already holds it. The mutation is written to the log before it
+// is applied, and marked as applied once the destination confirms it. Mutations that fail are removed from the log,
+// unless the sync was interrupted while waiting for the response or the destination applied some of their batches.
+func (c *Client) guard(kind string, payload any, apply func() error) error {
+	key, err := operationKey(kind, payload)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	if entry, found := c.entries[key]; found {
+		c.mu.Unlock()
+		if entry.Status == StatusPending {
+			c.logger.Warn(fmt.Sprintf("skipping %s that an interrupted sync may have already applied", kind), slog.String("key", key))
+		} else {
+			c.logger.Info(fmt.Sprintf("skipping %s that an interrupted sync already applied", kind), slog.String("key", key))
+		}
+		return nil
+	}
+	c.entries[key] = Entry{
+		Key:    key,
+		Kind:   kind,
+		Status: StatusPending,
+		At:     c.now().UTC(),
+	}
+	if err = c.save(); err != nil {
+		delete(c.entries, key)
+		c.mu.Unlock()
+		return fmt.Errorf("failure saving write-ahead log: %w", err)
+	}
+	c.mu.Unlock()
+	err = apply()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case err == nil:
+		entry := c.entries[key]
+		entry.Status = StatusApplied
+		entry.At = c.now().UTC()
+		c.entries[key] = entry
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return err
+	case errors.As(err, new(*client.PartialError)):
+		// some batches of the mutation were applied, so repeating it would apply them twice
+		return err
+	default:
+		delete(c.entries, key)
+	}
+	if saveErr := c.save(); saveErr != nil {
+		c.logger.Warn("failure saving write-ahead log", logger.Error(saveErr))
+	}
+	return err
+}
+
+// operationKey hashes the kind and payload of a mutation. The items of a payload are hashed in sorted order, so
+// that the same items sent in a different order produce the same key.
+func operationKey(kind string, payload any) (string, error) {
+	var parts []string
+	if items, ok := payload.(entities.TraktItems); ok {
+		parts = make([]string, 0, len(items))
+		for _, item := range items {
+			data, err := json.Marshal(item)
+			if err != nil {
+				return "", fmt.Errorf("failure marshaling %s item: %w", kind, err)
+			}
+			parts = append(parts, string(data))
+		}
+		slices.Sort(parts)
+	} else {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return "", fmt.Errorf("failure marshaling %s payload: %w", kind, err)
+		}
+		parts = []string{string(data)}
+	}
+	hash := sha256.New()
+	hash.Write([]byte(kind + "\n"))
+	hash.Write([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (c *Client) HistoryAdd(items entities.TraktItems) error {
+	return c.guard(KindHistoryAdd, items, func() error {
+		return c.DestinationClientInterface.HistoryAdd(items)
+	})
+}
+
+func (c *Client) Checkin(item entities.TraktItem) error {
+	return c.guard(KindCheckin, item, func() error {
+		return c.DestinationClientInterface.Checkin(item)
+	})
+}
+
+func (c *Client) CommentAdd(comment entities.TraktComment) error {
+	return c.guard(KindCommentAdd, comment, func() error {
+		return c.DestinationClientInterface.CommentAdd(comment)
+	})
+}
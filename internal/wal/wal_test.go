@@ -0,0 +1,95 @@
+package wal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+)
+
+type mockDestinationClient struct {
+	client.DestinationClientInterface
+	added []entities.TraktItems
+	err   error
+}
+
+func (m *mockDestinationClient) HistoryAdd(items entities.TraktItems) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.added = append(m.added, items)
+	return nil
+}
+
+func movie(imdbID string) entities.TraktItem {
+	return entities.TraktItem{
+		Type:  entities.TraktItemTypeMovie,
+		Movie: entities.TraktItemSpec{IDMeta: entities.TraktIDMeta{IMDb: imdbID}},
+	}
+}
+
+func TestClient(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store, err := state.NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	newClient := func(destination *mockDestinationClient) *Client {
+		entries, err := Load(store)
+		assert.NoError(t, err)
+		c := NewClient(destination, store, entries, log)
+		c.now = func() time.Time { return now }
+		return c
+	}
+
+	// the sync is interrupted while waiting for the response, so the outcome of the mutation is unknown
+	interrupted := &mockDestinationClient{err: context.Canceled}
+	c := newClient(interrupted)
+	assert.ErrorIs(t, c.HistoryAdd(entities.TraktItems{movie("tt0000001"), movie("tt0000002")}), context.Canceled)
+	entries, err := Load(store)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, StatusPending, entries[0].Status)
+
+	// the same items in a different order are recognised, while failed mutations are not kept
+	destination := &mockDestinationClient{}
+	c = newClient(destination)
+	assert.NoError(t, c.HistoryAdd(entities.TraktItems{movie("tt0000002"), movie("tt0000001")}))
+	assert.Empty(t, destination.added)
+	destination.err = errors.New("failure")
+	assert.Error(t, c.HistoryAdd(entities.TraktItems{movie("tt0000003")}))
+	destination.err = nil
+	assert.NoError(t, c.HistoryAdd(entities.TraktItems{movie("tt0000004")}))
+	assert.Equal(t, []entities.TraktItems{{movie("tt0000004")}}, destination.added)
+	entries, err = Load(store)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, StatusApplied, entries[1].Status)
+
+	// mutations that were partially applied are kept pending, since repeating them would apply their batches twice
+	destination.err = &client.PartialError{Applied: entities.TraktItems{movie("tt0000005")}, Err: errors.New("failure")}
+	assert.Error(t, c.HistoryAdd(entities.TraktItems{movie("tt0000005"), movie("tt0000006")}))
+	destination.err = nil
+	assert.NoError(t, c.HistoryAdd(entities.TraktItems{movie("tt0000006"), movie("tt0000005")}))
+	assert.Equal(t, []entities.TraktItems{{movie("tt0000004")}}, destination.added)
+
+	// applied mutations are pruned once the sync finishes, while pending mutations are kept until they expire
+	assert.NoError(t, c.Prune())
+	entries, err = Load(store)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, StatusPending, entries[0].Status)
+	assert.Equal(t, StatusPending, entries[1].Status)
+	now = now.Add(pendingRetention + time.Hour)
+	assert.NoError(t, c.Prune())
+	entries, err = Load(store)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -7,6 +7,7 @@ import (
 	"syscall"
 
 	"github.com/cecobask/imdb-trakt-sync/cmd/root"
+	"github.com/cecobask/imdb-trakt-sync/internal/syncer"
 )
 
 func main() {
@@ -14,6 +15,6 @@ func main() {
 	defer stop()
 	err := root.NewCommand(ctx).Execute()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(syncer.ExitCode(err))
 	}
 }
@@ -0,0 +1,288 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+)
+
+const (
+	clientNameAnime = "anime"
+
+	animePathAniList      = "https://graphql.anilist.co"
+	animePathBaseMAL      = "https://api.myanimelist.net"
+	animePathBaseMappings = "https://raw.githubusercontent.com/Fribb/anime-lists"
+	animePathMAL          = animePathBaseMAL + "/v2/users/%s/animelist?fields=list_status&limit=1000&nsfw=true"
+	animePathMappings     = animePathBaseMappings + "/master/anime-list-full.json"
+
+	animeHeaderKeyMALClientID = "X-MAL-CLIENT-ID"
+
+	aniListQuery = `query ($userName: String) {
+  MediaListCollection(userName: $userName, type: ANIME) {
+    lists {
+      entries {
+        status
+        score(format: POINT_10_DECIMAL)
+        updatedAt
+        completedAt { year month day }
+        media { id idMal }
+      }
+    }
+  }
+}`
+)
+
+// AnimeClient merges the anime list of an anilist or myanimelist user into the imdb watchlist, ratings and check-ins.
+// Anime are mapped to imdb titles through the anime-lists project, and the imdb data wins whenever a title is on both.
+type AnimeClient struct {
+	IMDbClientInterface
+	ctx       context.Context
+	client    *http.Client
+	config    appconfig.Anime
+	logger    *slog.Logger
+	rounding  string
+	watchlist []entities.IMDbItem
+	ratings   []entities.IMDbItem
+	completed []entities.IMDbItem
+}
+
+func NewAnimeClient(ctx context.Context, imdbClient IMDbClientInterface, conf appconfig.Anime, httpConf appconfig.HTTP, rounding string, store state.Store, logger *slog.Logger) (IMDbClientInterface, error) {
+	transport, err := newTransport(clientNameAnime, httpConf, store)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating transport: %w", err)
+	}
+	c := &AnimeClient{
+		IMDbClientInterface: imdbClient,
+		ctx:                 ctx,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   httpConf.ClientTimeout(clientNameAnime),
+		},
+		config:   conf,
+		logger:   logger,
+		rounding: rounding,
+	}
+	if err = c.hydrate(); err != nil {
+		return nil, fmt.Errorf("failure hydrating anime client: %w", err)
+	}
+	return c, nil
+}
+
+func (c *AnimeClient) hydrate() error {
+	var entries []entities.AnimeEntry
+	var err error
+	switch *c.config.Provider {
+	case appconfig.AnimeProviderAniList:
+		entries, err = c.aniListEntriesGet()
+	case appconfig.AnimeProviderMyAnimeList:
+		entries, err = c.malEntriesGet()
+	default:
+		return fmt.Errorf("unsupported anime provider %s", *c.config.Provider)
+	}
+	if err != nil {
+		return fmt.Errorf("failure fetching %s anime list: %w", *c.config.Provider, err)
+	}
+	mappings, err := c.mappingsGet()
+	if err != nil {
+		return fmt.Errorf("failure fetching anime mappings: %w", err)
+	}
+	watchlist := make(map[string]entities.IMDbItem)
+	ratings := make(map[string]entities.IMDbItem)
+	completed := make(map[string]entities.IMDbItem)
+	var unmapped int
+	for _, entry := range entries {
+		mapping, found := mappings[entry.AniListID]
+		if *c.config.Provider == appconfig.AnimeProviderMyAnimeList {
+			mapping, found = mappings[entry.MALID]
+		}
+		if !found || !strings.HasPrefix(mapping.IMDbID, "tt") {
+			unmapped++
+			continue
+		}
+		item := entry.ToIMDbItem(mapping)
+		// seasons of a show are separate anime, so the most recently updated entry decides the rating of the show
+		if item.Rating != nil {
+			if rated, ok := ratings[item.ID]; !ok || rated.RatingDate.Before(*item.RatingDate) {
+				ratings[item.ID] = item
+			}
+		}
+		switch entry.Status {
+		case entities.AnimeStatusPlanned:
+			item.Rating, item.RatingDate = nil, nil
+			watchlist[item.ID] = item
+		case entities.AnimeStatusCompleted:
+			if checkedIn, ok := completed[item.ID]; !ok || checkedIn.Created.Before(*item.Created) {
+				completed[item.ID] = item
+			}
+		}
+	}
+	c.watchlist, c.ratings, c.completed = sortedItems(watchlist), sortedItems(ratings), sortedItems(completed)
+	c.logger.Info(
+		fmt.Sprintf("hydrated %s anime list", *c.config.Provider),
+		slog.Int("entries", len(entries)),
+		slog.Int("unmapped", unmapped),
+		slog.Int("watchlist", len(c.watchlist)),
+		slog.Int("ratings", len(c.ratings)),
+		slog.Int("completed", len(c.completed)),
+	)
+	return nil
+}
+
+func (c *AnimeClient) aniListEntriesGet() ([]entities.AnimeEntry, error) {
+	body, err := json.Marshal(map[string]any{
+		"query": aniListQuery,
+		"variables": map[string]string{
+			"userName": *c.config.Username,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(http.MethodPost, animePathAniList, body, nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := decodeReader[entities.AniListResponse](response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("anilist returned error: %s", result.Errors[0].Message)
+	}
+	return result.Entries(c.rounding), nil
+}
+
+func (c *AnimeClient) malEntriesGet() ([]entities.AnimeEntry, error) {
+	var entries []entities.AnimeEntry
+	headers := map[string]string{
+		animeHeaderKeyMALClientID: *c.config.MALClientID,
+	}
+	next := fmt.Sprintf(animePathMAL, url.PathEscape(*c.config.Username))
+	for next != "" {
+		response, err := c.doRequest(http.MethodGet, next, nil, headers)
+		if err != nil {
+			return nil, err
+		}
+		page, err := decodeReader[entities.MALAnimeListResponse](response.Body)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, page.Entries()...)
+		next = page.Paging.Next
+	}
+	return entries, nil
+}
+
+// mappingsGet returns the anime mappings keyed by the anime id of the provider.
+func (c *AnimeClient) mappingsGet() (map[int]entities.AnimeMapping, error) {
+	response, err := c.doRequest(http.MethodGet, animePathMappings, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := decodeReader[[]entities.AnimeMapping](response.Body)
+	if err != nil {
+		return nil, err
+	}
+	indexed := make(map[int]entities.AnimeMapping, len(mappings))
+	for _, mapping := range mappings {
+		id := mapping.AniListID
+		if *c.config.Provider == appconfig.AnimeProviderMyAnimeList {
+			id = mapping.MALID
+		}
+		if id != 0 {
+			indexed[id] = mapping
+		}
+	}
+	return indexed, nil
+}
+
+func (c *AnimeClient) doRequest(method, endpoint string, body []byte, headers map[string]string) (*http.Response, error) {
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(c.ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, fmt.Errorf("error creating http request %s %s: %w", method, endpoint, err)
+	}
+	request.Header.Set("Accept", "application/json")
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error sending http request %s %s: %w", method, endpoint, err)
+	}
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, &ApiError{
+			httpMethod: method,
+			url:        endpoint,
+			StatusCode: response.StatusCode,
+			details:    fmt.Sprintf("unexpected status code %d", response.StatusCode),
+		}
+	}
+	return response, nil
+}
+
+func (c *AnimeClient) WatchlistGet() (*entities.IMDbList, error) {
+	list, err := c.IMDbClientInterface.WatchlistGet()
+	if err != nil {
+		return nil, err
+	}
+	list.ListItems = mergeIMDbItems(list.ListItems, c.watchlist)
+	return list, nil
+}
+
+func (c *AnimeClient) RatingsGet() ([]entities.IMDbItem, error) {
+	ratings, err := c.IMDbClientInterface.RatingsGet()
+	if err != nil {
+		return nil, err
+	}
+	return mergeIMDbItems(ratings, c.ratings), nil
+}
+
+func (c *AnimeClient) CheckinsGet() (*entities.IMDbList, error) {
+	checkins, err := c.IMDbClientInterface.CheckinsGet()
+	if err != nil {
+		return nil, err
+	}
+	checkins.ListItems = mergeIMDbItems(checkins.ListItems, c.completed)
+	return checkins, nil
+}
+
+// mergeIMDbItems appends the anime items that are not part of the imdb items already.
+func mergeIMDbItems(items, animeItems []entities.IMDbItem) []entities.IMDbItem {
+	listed := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		listed[item.ID] = struct{}{}
+	}
+	for _, item := range animeItems {
+		if _, found := listed[item.ID]; !found {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// sortedItems returns the items sorted by imdb id, so that the merged items are in a stable order.
+func sortedItems(items map[string]entities.IMDbItem) []entities.IMDbItem {
+	return slices.SortedFunc(maps.Values(items), func(a, b entities.IMDbItem) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+}
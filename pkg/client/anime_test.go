@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/rating"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+const (
+	dummyAnimeMappings = `[
+  {"anilist_id": 1, "mal_id": 11, "imdb_id": "tt0000001", "type": "TV"},
+  {"anilist_id": 2, "mal_id": 22, "imdb_id": "tt0000001", "type": "TV"},
+  {"anilist_id": 3, "mal_id": 33, "imdb_id": "tt0000003", "type": "MOVIE"},
+  {"anilist_id": 4, "mal_id": 44, "type": "OVA"}
+]`
+	dummyAniListResponse = `{"data": {"MediaListCollection": {"lists": [{"entries": [
+  {"status": "COMPLETED", "score": 7, "updatedAt": 1700000000, "completedAt": {"year": 2023, "month": 11, "day": 1}, "media": {"id": 1, "idMal": 11}},
+  {"status": "CURRENT", "score": 8.5, "updatedAt": 1700100000, "completedAt": {}, "media": {"id": 2, "idMal": 22}},
+  {"status": "PLANNING", "score": 0, "updatedAt": 1700000000, "completedAt": {}, "media": {"id": 3, "idMal": 33}},
+  {"status": "PLANNING", "score": 0, "updatedAt": 1700000000, "completedAt": {}, "media": {"id": 4, "idMal": 44}}
+]}]}}}`
+	dummyMALResponse = `{"data": [
+  {"node": {"id": 33}, "list_status": {"status": "completed", "score": 8, "updated_at": "2023-11-01T00:00:00+00:00", "finish_date": "2023-10-31"}}
+], "paging": {}}`
+)
+
+type mockIMDbClient struct {
+	IMDbClientInterface
+	watchlist []entities.IMDbItem
+	ratings   []entities.IMDbItem
+}
+
+func (m *mockIMDbClient) WatchlistGet() (*entities.IMDbList, error) {
+	return &entities.IMDbList{ListItems: m.watchlist, IsWatchlist: true}, nil
+}
+
+func (m *mockIMDbClient) RatingsGet() ([]entities.IMDbItem, error) {
+	return m.ratings, nil
+}
+
+func (m *mockIMDbClient) CheckinsGet() (*entities.IMDbList, error) {
+	return &entities.IMDbList{}, nil
+}
+
+func buildTestAnimeClient(provider string, imdbClient IMDbClientInterface) *AnimeClient {
+	return &AnimeClient{
+		IMDbClientInterface: imdbClient,
+		ctx:                 context.Background(),
+		client: &http.Client{
+			Transport: httpmock.DefaultTransport,
+		},
+		config: appconfig.Anime{
+			Provider:    pointer(provider),
+			Username:    pointer("user"),
+			MALClientID: pointer("client-id"),
+		},
+		logger:   logger.NewLogger(io.Discard),
+		rounding: rating.RoundingHalfStar,
+	}
+}
+
+func TestAnimeClient_hydrate(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     string
+		imdbClient   *mockIMDbClient
+		requirements func()
+		assertions   func(*assert.Assertions, *AnimeClient, error)
+	}{
+		{
+			name:     "successfully merge anilist list",
+			provider: appconfig.AnimeProviderAniList,
+			imdbClient: &mockIMDbClient{
+				watchlist: []entities.IMDbItem{{ID: "tt0000003", Kind: "movie"}},
+			},
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodPost, animePathAniList, httpmock.NewStringResponder(http.StatusOK, dummyAniListResponse))
+				httpmock.RegisterResponder(http.MethodGet, animePathMappings, httpmock.NewStringResponder(http.StatusOK, dummyAnimeMappings))
+			},
+			assertions: func(assertions *assert.Assertions, c *AnimeClient, err error) {
+				assertions.NoError(err)
+				watchlist, err := c.WatchlistGet()
+				assertions.NoError(err)
+				assertions.Len(watchlist.ListItems, 1)
+				ratings, err := c.RatingsGet()
+				assertions.NoError(err)
+				assertions.Len(ratings, 1)
+				assertions.Equal("tt0000001", ratings[0].ID)
+				assertions.Equal(9, *ratings[0].Rating)
+				checkins, err := c.CheckinsGet()
+				assertions.NoError(err)
+				assertions.Len(checkins.ListItems, 1)
+				assertions.Equal("2023-11-01", checkins.ListItems[0].Created.Format("2006-01-02"))
+			},
+		},
+		{
+			name:       "successfully merge myanimelist list",
+			provider:   appconfig.AnimeProviderMyAnimeList,
+			imdbClient: &mockIMDbClient{},
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodGet, "https://api.myanimelist.net/v2/users/user/animelist", httpmock.NewStringResponder(http.StatusOK, dummyMALResponse))
+				httpmock.RegisterResponder(http.MethodGet, animePathMappings, httpmock.NewStringResponder(http.StatusOK, dummyAnimeMappings))
+			},
+			assertions: func(assertions *assert.Assertions, c *AnimeClient, err error) {
+				assertions.NoError(err)
+				ratings, err := c.RatingsGet()
+				assertions.NoError(err)
+				assertions.Len(ratings, 1)
+				assertions.Equal("tt0000003", ratings[0].ID)
+				assertions.Equal("movie", ratings[0].Kind)
+				checkins, err := c.CheckinsGet()
+				assertions.NoError(err)
+				assertions.Len(checkins.ListItems, 1)
+				assertions.Equal("2023-10-31", checkins.ListItems[0].Created.Format("2006-01-02"))
+			},
+		},
+		{
+			name:       "failure fetching anilist list",
+			provider:   appconfig.AnimeProviderAniList,
+			imdbClient: &mockIMDbClient{},
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodPost, animePathAniList, httpmock.NewStringResponder(http.StatusOK, `{"errors": [{"message": "User not found"}]}`))
+			},
+			assertions: func(assertions *assert.Assertions, c *AnimeClient, err error) {
+				assertions.ErrorContains(err, "User not found")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestAnimeClient(tt.provider, tt.imdbClient)
+			err := c.hydrate()
+			tt.assertions(assert.New(t), c, err)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+)
+
+// defaultBaseURLs maps the services whose base url can be overridden in the config to their default base url.
+var defaultBaseURLs = map[string]string{
+	appconfig.HTTPBaseURLServiceAniList:    animePathAniList,
+	appconfig.HTTPBaseURLServiceAnimeLists: animePathBaseMappings,
+	appconfig.HTTPBaseURLServiceIMDb:       imdbPathBase,
+	appconfig.HTTPBaseURLServiceIMDbGQL:    strings.TrimSuffix(imdbGraphQLPathBase, "/"),
+	appconfig.HTTPBaseURLServiceMAL:        animePathBaseMAL,
+	appconfig.HTTPBaseURLServicePlexDisc:   plexPathBaseDiscover,
+	appconfig.HTTPBaseURLServicePlexMeta:   plexPathBaseMetadata,
+	appconfig.HTTPBaseURLServiceSimkl:      simklPathBase,
+	appconfig.HTTPBaseURLServiceTrakt:      traktPathBaseAPI,
+	appconfig.HTTPBaseURLServiceTraktWeb:   traktPathBaseBrowser,
+}
+
+// rebaseURL replaces the default base url of a service with the one that the config overrides it with, and returns
+// the url as it is when it does not belong to a service with an override.
+func rebaseURL(httpConf appconfig.HTTP, rawURL string) string {
+	for service, base := range defaultBaseURLs {
+		rest, found := strings.CutPrefix(rawURL, base)
+		if !found || (rest != "" && rest[0] != '/' && rest[0] != '?') {
+			continue
+		}
+		if override, ok := httpConf.BaseURL(service); ok {
+			return override + rest
+		}
+		return rawURL
+	}
+	return rawURL
+}
+
+// rebaseTransport sends the requests to the base urls that the config overrides the default ones with, so that the
+// clients can be pointed at mocks, regional mirrors or self-hosted proxies without changing their code.
+type rebaseTransport struct {
+	next     http.RoundTripper
+	httpConf appconfig.HTTP
+}
+
+func newRebaseTransport(next http.RoundTripper, httpConf appconfig.HTTP) http.RoundTripper {
+	if httpConf.BaseURLs == nil || len(*httpConf.BaseURLs) == 0 {
+		return next
+	}
+	return &rebaseTransport{
+		next:     next,
+		httpConf: httpConf,
+	}
+}
+
+func (t *rebaseTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	rawURL := request.URL.String()
+	rebased := rebaseURL(t.httpConf, rawURL)
+	if rebased == rawURL {
+		return t.next.RoundTrip(request)
+	}
+	target, err := url.Parse(rebased)
+	if err != nil {
+		return nil, err
+	}
+	request = request.Clone(request.Context())
+	request.URL = target
+	request.Host = ""
+	return t.next.RoundTrip(request)
+}
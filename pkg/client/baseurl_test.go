@@ -0,0 +1,71 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+)
+
+func Test_rebaseURL(t *testing.T) {
+	httpConf := appconfig.HTTP{
+		BaseURLs: &[]string{
+			"trakt:http://localhost:8080/trakt/",
+			"myanimelist:https://mal.mirror.example",
+		},
+	}
+	tests := []struct {
+		name   string
+		rawURL string
+		want   string
+	}{
+		{
+			name:   "rebase url with path",
+			rawURL: traktPathBaseAPI + "/sync/watchlist?page=1",
+			want:   "http://localhost:8080/trakt/sync/watchlist?page=1",
+		},
+		{
+			name:   "rebase url with format string",
+			rawURL: animePathMAL,
+			want:   "https://mal.mirror.example/v2/users/%s/animelist?fields=list_status&limit=1000&nsfw=true",
+		},
+		{
+			name:   "keep url of service without override",
+			rawURL: traktPathBaseBrowser + "/users/me",
+			want:   traktPathBaseBrowser + "/users/me",
+		},
+		{
+			name:   "keep url of host sharing the prefix",
+			rawURL: traktPathBaseAPI + ".example/sync",
+			want:   traktPathBaseAPI + ".example/sync",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rebaseURL(httpConf, tt.rawURL))
+		})
+	}
+}
+
+func Test_rebaseTransport(t *testing.T) {
+	assertions := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertions.Equal("/mock/sync/history", r.URL.Path)
+		assertions.Equal("page=2", r.URL.RawQuery)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	httpConf := appconfig.HTTP{
+		BaseURLs: &[]string{"trakt:" + server.URL + "/mock"},
+	}
+	client := &http.Client{
+		Transport: newRebaseTransport(http.DefaultTransport, httpConf),
+	}
+	response, err := client.Get(traktPathBaseAPI + "/sync/history?page=2")
+	assertions.NoError(err)
+	assertions.Equal(http.StatusNoContent, response.StatusCode)
+	assertions.NoError(response.Body.Close())
+}
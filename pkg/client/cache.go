@@ -0,0 +1,105 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+)
+
+const (
+	cacheStateKeyPrefix       = "http-cache-"
+	cacheHeaderKeyETag        = "ETag"
+	cacheHeaderKeyLastMod     = "Last-Modified"
+	cacheHeaderKeyIfNoneMatch = "If-None-Match"
+	cacheHeaderKeyIfModSince  = "If-Modified-Since"
+	cacheHeaderKeyControl     = "Cache-Control"
+)
+
+type cacheEntry struct {
+	ETag         string      `json:"etag"`
+	LastModified string      `json:"lastModified"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// cacheTransport stores the bodies of GET responses that carry an ETag or Last-Modified header, and revalidates them
+// with conditional requests, so that unchanged payloads are served from the state store instead of downloaded again.
+type cacheTransport struct {
+	next  http.RoundTripper
+	store state.Store
+}
+
+func newCacheTransport(next http.RoundTripper, store state.Store) http.RoundTripper {
+	return &cacheTransport{
+		next:  next,
+		store: store,
+	}
+}
+
+func (t *cacheTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Method != http.MethodGet {
+		return t.next.RoundTrip(request)
+	}
+	key := cacheKey(request)
+	var entry *cacheEntry
+	if err := t.store.Load(key, &entry); err != nil {
+		entry = nil
+	}
+	if entry != nil {
+		request = request.Clone(request.Context())
+		if entry.ETag != "" {
+			request.Header.Set(cacheHeaderKeyIfNoneMatch, entry.ETag)
+		}
+		if entry.LastModified != "" {
+			request.Header.Set(cacheHeaderKeyIfModSince, entry.LastModified)
+		}
+	}
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusNotModified && entry != nil {
+		response.Body.Close()
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         response.Proto,
+			ProtoMajor:    response.ProtoMajor,
+			ProtoMinor:    response.ProtoMinor,
+			Header:        entry.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+			ContentLength: int64(len(entry.Body)),
+			Request:       request,
+		}, nil
+	}
+	if response.StatusCode != http.StatusOK || strings.Contains(response.Header.Get(cacheHeaderKeyControl), "no-store") {
+		return response, nil
+	}
+	etag, lastModified := response.Header.Get(cacheHeaderKeyETag), response.Header.Get(cacheHeaderKeyLastMod)
+	if etag == "" && lastModified == "" {
+		return response, nil
+	}
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	_ = t.store.Save(key, cacheEntry{
+		ETag:         etag,
+		LastModified: lastModified,
+		Header:       response.Header.Clone(),
+		Body:         body,
+	})
+	return response, nil
+}
+
+func cacheKey(request *http.Request) string {
+	sum := sha256.Sum256([]byte(request.URL.String()))
+	return cacheStateKeyPrefix + hex.EncodeToString(sum[:8])
+}
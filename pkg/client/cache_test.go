@@ -0,0 +1,134 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+)
+
+func TestCacheTransport_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		method     string
+		assertions func(*assert.Assertions, []string, int)
+	}{
+		{
+			name: "serve unchanged body from cache by etag",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get(cacheHeaderKeyIfNoneMatch) == `"v1"` {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set(cacheHeaderKeyETag, `"v1"`)
+				_, _ = w.Write([]byte("payload"))
+			},
+			method: http.MethodGet,
+			assertions: func(assertions *assert.Assertions, bodies []string, downloads int) {
+				assertions.Equal([]string{"payload", "payload"}, bodies)
+				assertions.Equal(1, downloads)
+			},
+		},
+		{
+			name: "serve unchanged body from cache by last modified",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get(cacheHeaderKeyIfModSince) != "" {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set(cacheHeaderKeyLastMod, "Wed, 21 Oct 2015 07:28:00 GMT")
+				_, _ = w.Write([]byte("payload"))
+			},
+			method: http.MethodGet,
+			assertions: func(assertions *assert.Assertions, bodies []string, downloads int) {
+				assertions.Equal([]string{"payload", "payload"}, bodies)
+				assertions.Equal(1, downloads)
+			},
+		},
+		{
+			name: "skip responses without validators",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("payload"))
+			},
+			method: http.MethodGet,
+			assertions: func(assertions *assert.Assertions, bodies []string, downloads int) {
+				assertions.Equal([]string{"payload", "payload"}, bodies)
+				assertions.Equal(2, downloads)
+			},
+		},
+		{
+			name: "skip responses marked as no-store",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get(cacheHeaderKeyIfNoneMatch) != "" {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set(cacheHeaderKeyETag, `"v1"`)
+				w.Header().Set(cacheHeaderKeyControl, "no-store")
+				_, _ = w.Write([]byte("payload"))
+			},
+			method: http.MethodGet,
+			assertions: func(assertions *assert.Assertions, bodies []string, downloads int) {
+				assertions.Equal([]string{"payload", "payload"}, bodies)
+				assertions.Equal(2, downloads)
+			},
+		},
+		{
+			name: "skip non get requests",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get(cacheHeaderKeyIfNoneMatch) != "" {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set(cacheHeaderKeyETag, `"v1"`)
+				_, _ = w.Write([]byte("payload"))
+			},
+			method: http.MethodPost,
+			assertions: func(assertions *assert.Assertions, bodies []string, downloads int) {
+				assertions.Equal([]string{"payload", "payload"}, bodies)
+				assertions.Equal(2, downloads)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var downloads int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				recorder := httptest.NewRecorder()
+				tt.handler(recorder, r)
+				if recorder.Code == http.StatusOK {
+					downloads++
+				}
+				for key, values := range recorder.Header() {
+					w.Header()[key] = values
+				}
+				w.WriteHeader(recorder.Code)
+				_, _ = w.Write(recorder.Body.Bytes())
+			}))
+			defer server.Close()
+			store, err := state.NewFileStore(t.TempDir())
+			assert.NoError(t, err)
+			client := &http.Client{
+				Transport: newCacheTransport(http.DefaultTransport, store),
+			}
+			var bodies []string
+			for range 2 {
+				request, err := http.NewRequest(tt.method, server.URL, http.NoBody)
+				assert.NoError(t, err)
+				response, err := client.Do(request)
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, response.StatusCode)
+				body, err := io.ReadAll(response.Body)
+				assert.NoError(t, err)
+				response.Body.Close()
+				bodies = append(bodies, string(body))
+			}
+			tt.assertions(assert.New(t), bodies, downloads)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+)
+
+var (
+	cassettesMu sync.Mutex
+	cassettes   = make(map[string]*cassette)
+)
+
+// cassette holds the http interactions of a sync run, so that the run can be replayed offline.
+type cassette struct {
+	mu           sync.Mutex
+	path         string
+	mode         string
+	Interactions []*interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+		Body   string `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		StatusCode int         `json:"statusCode"`
+		Header     http.Header `json:"header,omitempty"`
+		Body       string      `json:"body,omitempty"`
+	} `json:"response"`
+	replayed bool
+}
+
+// openCassette returns the cassette stored at the path, sharing it between the clients of the process, so that
+// their interactions are recorded to and replayed from the same file.
+func openCassette(path, mode string) (*cassette, error) {
+	cassettesMu.Lock()
+	defer cassettesMu.Unlock()
+	if c, ok := cassettes[path]; ok {
+		return c, nil
+	}
+	c := &cassette{
+		path: path,
+		mode: mode,
+	}
+	if mode == appconfig.HTTPCassetteModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failure reading cassette %s: %w", path, err)
+		}
+		if err = json.Unmarshal(data, c); err != nil {
+			return nil, fmt.Errorf("failure unmarshalling cassette %s: %w", path, err)
+		}
+	}
+	cassettes[path] = c
+	return c, nil
+}
+
+// cassetteTransport records the interactions with the next transport to a cassette, or replays them from the
+// cassette without sending any request. The cassette is opened on the first request.
+type cassetteTransport struct {
+	next http.RoundTripper
+	path string
+	mode string
+}
+
+func newCassetteTransport(next http.RoundTripper, path, mode string) http.RoundTripper {
+	return &cassetteTransport{
+		next: next,
+		path: path,
+		mode: mode,
+	}
+}
+
+func (t *cassetteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	c, err := openCassette(t.path, t.mode)
+	if err != nil {
+		return nil, err
+	}
+	request, body, err := requestBody(request)
+	if err != nil {
+		return nil, err
+	}
+	if c.mode == appconfig.HTTPCassetteModeReplay {
+		return c.replay(request, body)
+	}
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.record(request, body, response); err != nil {
+		response.Body.Close()
+		return nil, err
+	}
+	return response, nil
+}
+
+// replay returns the response of the first interaction that matches the request and has not been replayed yet.
+func (c *cassette) replay(request *http.Request, body string) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, i := range c.Interactions {
+		if i.replayed || i.Request.Method != request.Method || i.Request.URL != request.URL.String() || i.Request.Body != body {
+			continue
+		}
+		i.replayed = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", i.Response.StatusCode, http.StatusText(i.Response.StatusCode)),
+			StatusCode:    i.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        i.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewBufferString(i.Response.Body)),
+			ContentLength: int64(len(i.Response.Body)),
+			Request:       request,
+		}, nil
+	}
+	return nil, fmt.Errorf("failure finding interaction for http request %s %s in cassette %s", request.Method, request.URL, c.path)
+}
+
+// record appends the interaction and persists the cassette, so that the interactions of an aborted run are kept.
+// Request headers are left out, since they carry credentials.
+func (c *cassette) record(request *http.Request, body string, response *http.Response) error {
+	data, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failure reading response body of %s %s: %w", request.Method, request.URL, err)
+	}
+	response.Body = io.NopCloser(bytes.NewReader(data))
+	i := &interaction{}
+	i.Request.Method = request.Method
+	i.Request.URL = request.URL.String()
+	i.Request.Body = body
+	i.Response.StatusCode = response.StatusCode
+	i.Response.Header = response.Header.Clone()
+	i.Response.Header.Del("Set-Cookie")
+	i.Response.Body = string(data)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Interactions = append(c.Interactions, i)
+	data, err = json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failure marshalling cassette %s: %w", c.path, err)
+	}
+	if err = os.WriteFile(c.path, data, 0600); err != nil {
+		return fmt.Errorf("failure writing cassette %s: %w", c.path, err)
+	}
+	return nil
+}
+
+// requestBody reads the body of the request, and returns a copy of the request that can still be sent, since the
+// original request may be sent again by the caller.
+func requestBody(request *http.Request) (*http.Request, string, error) {
+	if request.Body == nil || request.Body == http.NoBody {
+		return request, "", nil
+	}
+	data, err := io.ReadAll(request.Body)
+	request.Body.Close()
+	if err != nil {
+		return nil, "", fmt.Errorf("failure reading request body of %s %s: %w", request.Method, request.URL, err)
+	}
+	clone := request.Clone(request.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(data))
+	return clone, string(data), nil
+}
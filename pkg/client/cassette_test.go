@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+func TestCassetteTransport_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		requests   []string
+		replays    []string
+		assertions func(*assert.Assertions, []string, error)
+	}{
+		{
+			name:     "replay recorded interactions in order",
+			requests: []string{"first", "second", "first"},
+			replays:  []string{"first", "first", "second"},
+			assertions: func(assertions *assert.Assertions, bodies []string, err error) {
+				assertions.NoError(err)
+				assertions.Equal([]string{"first-1", "first-3", "second-2"}, bodies)
+			},
+		},
+		{
+			name:     "failure replaying interaction that was not recorded",
+			requests: []string{"first"},
+			replays:  []string{"first", "first"},
+			assertions: func(assertions *assert.Assertions, bodies []string, err error) {
+				assertions.Equal([]string{"first-1"}, bodies)
+				assertions.ErrorContains(err, "failure finding interaction")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var count int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				count++
+				body, _ := io.ReadAll(r.Body)
+				_, _ = w.Write([]byte(string(body) + "-" + strconv.Itoa(count)))
+			}))
+			path := filepath.Join(t.TempDir(), "cassette.json")
+			recorder := &http.Client{
+				Transport: newCassetteTransport(http.DefaultTransport, path, appconfig.HTTPCassetteModeRecord),
+			}
+			for _, body := range tt.requests {
+				response, err := recorder.Post(server.URL, "text/plain", strings.NewReader(body))
+				assert.NoError(t, err)
+				response.Body.Close()
+			}
+			server.Close()
+			delete(cassettes, path)
+			player := &http.Client{
+				Transport: newCassetteTransport(http.DefaultTransport, path, appconfig.HTTPCassetteModeReplay),
+			}
+			var (
+				bodies []string
+				err    error
+			)
+			for _, body := range tt.replays {
+				var response *http.Response
+				if response, err = player.Post(server.URL, "text/plain", strings.NewReader(body)); err != nil {
+					break
+				}
+				data, _ := io.ReadAll(response.Body)
+				response.Body.Close()
+				bodies = append(bodies, string(data))
+			}
+			tt.assertions(assert.New(t), bodies, err)
+		})
+	}
+}
+
+func TestCassetteTransport_TraktClient(t *testing.T) {
+	c := &TraktClient{
+		ctx: context.Background(),
+		client: &http.Client{
+			Transport: newCassetteTransport(nil, "testdata/cassette.json", appconfig.HTTPCassetteModeReplay),
+		},
+		config: traktConfig{
+			Trakt: dummyAppConfigTrakt,
+		},
+		logger: logger.NewLogger(io.Discard),
+	}
+	assertions := assert.New(t)
+	watchlist, err := c.WatchlistGet()
+	assertions.NoError(err)
+	assertions.Len(watchlist.ListItems, 1)
+	assertions.Equal("tt0372784", watchlist.ListItems[0].Movie.IDMeta.IMDb)
+	err = c.WatchlistItemsAdd(entities.TraktItems{
+		{
+			Type: entities.TraktItemTypeMovie,
+			Movie: entities.TraktItemSpec{
+				IDMeta: entities.TraktIDMeta{
+					IMDb: "tt0111161",
+				},
+			},
+		},
+	})
+	assertions.NoError(err)
+}
@@ -2,31 +2,50 @@ package client
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
 	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/metrics"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/internal/tracing"
 )
 
 type IMDbClientInterface interface {
 	ListsExport(ids ...string) error
 	ListsGet(ids ...string) ([]entities.IMDbList, error)
+	ListsGetAll() ([]entities.IMDbList, error)
+	ListsModified(ids ...string) (map[string]time.Time, error)
+	ListCreate(name, description string) (string, error)
+	ListItemsAdd(id string, itemIDs ...string) error
+	ListItemsRemove(id string, itemIDs ...string) error
 	WatchlistExport() error
 	WatchlistGet() (*entities.IMDbList, error)
 	RatingsExport() error
 	RatingsGet() ([]entities.IMDbItem, error)
+	CheckinsExport() error
+	CheckinsGet() (*entities.IMDbList, error)
+	ChartGet(chart string) (*entities.IMDbList, error)
+	ReviewsGet() ([]entities.IMDbReview, error)
+	Close()
 }
 
-type TraktClientInterface interface {
-	BrowseSignIn() (*string, error)
-	SignIn(authenticityToken string) error
-	BrowseActivate() (*string, error)
-	Activate(userCode, authenticityToken string) (*string, error)
-	ActivateAuthorize(authenticityToken string) error
-	GetAccessToken(deviceCode string) (*entities.TraktAuthTokensResponse, error)
-	GetAuthCodes() (*entities.TraktAuthCodesResponse, error)
+// DestinationClientInterface is implemented by the services that imdb data can be synced to.
+type DestinationClientInterface interface {
 	WatchlistGet() (*entities.TraktList, error)
 	WatchlistItemsAdd(items entities.TraktItems) error
 	WatchlistItemsRemove(items entities.TraktItems) error
@@ -34,14 +53,58 @@ type TraktClientInterface interface {
 	ListsGet(idMeta entities.TraktIDMetas) ([]entities.TraktList, []error)
 	ListItemsAdd(listID string, items entities.TraktItems) error
 	ListItemsRemove(listID string, items entities.TraktItems) error
-	ListAdd(listID, listName string) error
+	ListItemsReorder(listID string, rank []int64) error
+	ListAdd(listID, listName, description string) error
+	ListUpdate(listID, description string) error
+	ListRemove(listID string) error
+	LikedListsGet() ([]entities.TraktList, error)
+	ListLike(listID string) error
+	ListUnlike(listID string) error
 	RatingsGet() (entities.TraktItems, error)
 	RatingsAdd(items entities.TraktItems) error
 	RatingsRemove(items entities.TraktItems) error
 	HistoryGet(itemType, itemID string) (entities.TraktItems, error)
 	HistoryAdd(items entities.TraktItems) error
 	HistoryRemove(items entities.TraktItems) error
+	HistoryGetAll() (entities.TraktItems, error)
+	RecommendationsGet() (entities.TraktItems, error)
+	HiddenGet(section string) (entities.TraktItems, error)
+	HiddenAdd(section string, items entities.TraktItems) error
+	HiddenRemove(section string, items entities.TraktItems) error
+	CollectionGet() (entities.TraktItems, error)
+	CollectionAdd(items entities.TraktItems) error
+	CollectionRemove(items entities.TraktItems) error
+	FavoritesGet() (entities.TraktItems, error)
+	FavoritesAdd(items entities.TraktItems) error
+	FavoritesRemove(items entities.TraktItems) error
+	CommentsGet() (entities.TraktItems, error)
+	CommentAdd(comment entities.TraktComment) error
+	Checkin(item entities.TraktItem) error
+	LimitsGet() (*entities.TraktLimits, error)
+	NotFound() entities.TraktItems
+}
+
+type TraktClientInterface interface {
+	DestinationClientInterface
+	BrowseSignIn() (*string, error)
+	SignIn(authenticityToken string) error
+	BrowseActivate() (*string, error)
+	Activate(userCode, authenticityToken string) (*string, error)
+	ActivateAuthorize(authenticityToken string) error
+	GetAccessToken(deviceCode string) (*entities.TraktAuthTokensResponse, error)
+	GetAuthCodes() (*entities.TraktAuthCodesResponse, error)
+	RefreshAccessToken(refreshToken string) (*entities.TraktAuthTokensResponse, error)
 	UserInfoGet() (*entities.TraktUserInfo, error)
+	UserListsGet() ([]entities.TraktList, error)
+	Search(itemType, query string, year int) ([]entities.TraktSearchResult, error)
+}
+
+type PlexClientInterface interface {
+	WatchlistGet() ([]entities.PlexItem, error)
+	WatchlistItemAdd(ratingKey string) error
+	WatchlistItemRemove(ratingKey string) error
+	MetadataGet(ratingKey string) (*entities.PlexItem, error)
+	MetadataMatch(imdbID, itemType string) (*entities.PlexItem, error)
 }
 
 type requestFields struct {
@@ -107,12 +170,124 @@ type ApiError struct {
 	url        string
 	StatusCode int
 	details    string
+	retryable  bool
 }
 
 func (e *ApiError) Error() string {
 	return fmt.Sprintf("http request %s %s returned status code %d: %s", e.httpMethod, e.url, e.StatusCode, e.details)
 }
 
+// Retryable reports whether the request may succeed when sent again, as opposed to a permanent failure.
+func (e *ApiError) Retryable() bool {
+	if e.retryable {
+		return true
+	}
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// As classifies the error into the error categories below, so that callers can decide whether to retry, skip or abort
+// with errors.As, regardless of the client that returned the error.
+func (e *ApiError) As(target any) bool {
+	switch t := target.(type) {
+	case **AuthError:
+		if e.retryable || (e.StatusCode != http.StatusUnauthorized && e.StatusCode != http.StatusForbidden) {
+			return false
+		}
+		*t = &AuthError{Err: e}
+	case **RateLimitError:
+		if e.StatusCode != http.StatusTooManyRequests && e.StatusCode != traktStatusCodeEnhanceYourCalm {
+			return false
+		}
+		*t = &RateLimitError{Err: e}
+	case **NotFoundError:
+		if e.StatusCode != http.StatusNotFound {
+			return false
+		}
+		*t = &NotFoundError{Err: e}
+	case **TransientError:
+		if !e.Retryable() {
+			return false
+		}
+		*t = &TransientError{Err: e}
+	default:
+		return false
+	}
+	return true
+}
+
+// AuthError is returned when a service rejects the credentials of the client, in which case every following request
+// fails the same way until the credentials are fixed.
+type AuthError struct {
+	Err error
+}
+
+func (e *AuthError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError is returned when a service throttles the requests of the client, or when the account exceeded one of
+// its limits. Throttled requests are transient errors as well, while exceeded account limits are not.
+type RateLimitError struct {
+	Err error
+}
+
+func (e *RateLimitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// NotFoundError is returned when the requested resource does not exist.
+type NotFoundError struct {
+	Err error
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// TransientError is returned when the request may succeed when sent again, such as on server errors or timeouts.
+type TransientError struct {
+	Err error
+}
+
+func (e *TransientError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+// PartialError is returned when a request that is sent in several batches fails after some of its batches were
+// applied, so that callers can account for the items that were changed regardless.
+type PartialError struct {
+	Applied entities.TraktItems
+	Err     error
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("failure after applying %d item(s): %s", len(e.Applied), e.Err)
+}
+
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
 type TraktListNotFoundError struct {
 	Slug string
 }
@@ -121,6 +296,140 @@ func (e *TraktListNotFoundError) Error() string {
 	return fmt.Sprintf("list with id %s could not be found", e.Slug)
 }
 
+// As classifies the error as a NotFoundError.
+func (e *TraktListNotFoundError) As(target any) bool {
+	t, ok := target.(**NotFoundError)
+	if ok {
+		*t = &NotFoundError{Err: e}
+	}
+	return ok
+}
+
+// newTransport instruments the requests of the named client, caches their responses in the store when enabled, and
+// records or replays them when a cassette is configured. The requests are sent to the base urls that the config
+// overrides the default ones with, before any of the other layers sees them.
+func newTransport(client string, httpConf appconfig.HTTP, store state.Store) (http.RoundTripper, error) {
+	transport, err := newBaseTransport(httpConf)
+	if err != nil {
+		return nil, err
+	}
+	transport = newDecodeTransport(transport)
+	if *httpConf.CassetteMode != appconfig.HTTPCassetteModeOff {
+		transport = newCassetteTransport(transport, *httpConf.Cassette, *httpConf.CassetteMode)
+	}
+	if *httpConf.Cache && store != nil {
+		transport = newCacheTransport(transport, store)
+	}
+	return newRebaseTransport(tracing.InstrumentTransport(client, metrics.InstrumentTransport(client, transport)), httpConf), nil
+}
+
+// newBaseTransport returns a dedicated copy of the default transport, tuned with the keep-alive, idle connection and
+// tls settings of the config, and with the proxy and the trusted certificates replaced when they are configured.
+func newBaseTransport(httpConf appconfig.HTTP) (http.RoundTripper, error) {
+	proxy, caCert := valueOrEmpty(httpConf.Proxy), valueOrEmpty(httpConf.CACert)
+	transport := &http.Transport{}
+	if defaultTransport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = defaultTransport.Clone()
+	}
+	dialer := &net.Dialer{
+		Timeout:   time.Second * 30,
+		KeepAlive: valueOrDefault(httpConf.KeepAlive, appconfig.HTTPKeepAliveDefault),
+	}
+	transport.DialContext = dialer.DialContext
+	transport.IdleConnTimeout = valueOrDefault(httpConf.IdleConnTimeout, appconfig.HTTPIdleConnTimeoutDefault)
+	transport.MaxIdleConnsPerHost = valueOrDefault(httpConf.MaxIdleConns, appconfig.HTTPMaxIdleConnsDefault)
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.MinVersion = tls.VersionTLS12
+	if valueOrEmpty(httpConf.TLSMinVersion) == appconfig.HTTPTLSVersion13 {
+		transport.TLSClientConfig.MinVersion = tls.VersionTLS13
+	}
+	if proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("failure parsing proxy url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	if caCert != "" {
+		pool, err := loadCertPool(caCert)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+	return transport, nil
+}
+
+// loadCertPool returns the system certificate pool extended with the certificates of the pem bundle at the given path.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	bundle, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading ca bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, fmt.Errorf("ca bundle %s does not contain any pem certificate", path)
+	}
+	return pool, nil
+}
+
+// certificateFingerprints returns the base64 encoded sha256 hashes of the public keys of the certificates in the pem
+// bundle at the given path, in the format that chrome expects to trust them.
+func certificateFingerprints(path string) ([]string, error) {
+	bundle, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading ca bundle: %w", err)
+	}
+	var fingerprints []string
+	for {
+		var block *pem.Block
+		if block, bundle = pem.Decode(bundle); block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failure parsing ca certificate: %w", err)
+		}
+		sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+		fingerprints = append(fingerprints, base64.StdEncoding.EncodeToString(sum[:]))
+	}
+	return fingerprints, nil
+}
+
+func valueOrEmpty(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+func valueOrDefault[T any](v *T, fallback T) T {
+	if v == nil {
+		return fallback
+	}
+	return *v
+}
+
+// sleep pauses for the given duration, returning early with the context error when the context is cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 func pointer[T any](v T) *T {
 	return &v
 }
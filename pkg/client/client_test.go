@@ -1,11 +1,20 @@
 package client
 
 import (
+	"crypto/tls"
+	"encoding/pem"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
 )
 
 func Test_scrapeSelectorAttribute(t *testing.T) {
@@ -67,6 +76,104 @@ func Test_scrapeSelectorAttribute(t *testing.T) {
 	}
 }
 
+func Test_newBaseTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	caCert := filepath.Join(dir, "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NoError(t, os.WriteFile(caCert, certificate, 0600))
+	invalidCACert := filepath.Join(dir, "invalid.pem")
+	assert.NoError(t, os.WriteFile(invalidCACert, []byte("invalid"), 0600))
+	tests := []struct {
+		name       string
+		httpConf   appconfig.HTTP
+		assertions func(*assert.Assertions, http.RoundTripper, error)
+	}{
+		{
+			name:     "dedicated transport without proxy and ca bundle",
+			httpConf: appconfig.HTTP{},
+			assertions: func(assertions *assert.Assertions, transport http.RoundTripper, err error) {
+				assertions.Nil(err)
+				assertions.NotSame(http.DefaultTransport, transport)
+				httpTransport := transport.(*http.Transport)
+				assertions.Equal(appconfig.HTTPIdleConnTimeoutDefault, httpTransport.IdleConnTimeout)
+				assertions.Equal(appconfig.HTTPMaxIdleConnsDefault, httpTransport.MaxIdleConnsPerHost)
+				assertions.Equal(uint16(tls.VersionTLS12), httpTransport.TLSClientConfig.MinVersion)
+			},
+		},
+		{
+			name: "success with transport tuning",
+			httpConf: appconfig.HTTP{
+				IdleConnTimeout: pointer(time.Minute),
+				MaxIdleConns:    pointer(4),
+				TLSMinVersion:   pointer(appconfig.HTTPTLSVersion13),
+			},
+			assertions: func(assertions *assert.Assertions, transport http.RoundTripper, err error) {
+				assertions.Nil(err)
+				httpTransport := transport.(*http.Transport)
+				assertions.Equal(time.Minute, httpTransport.IdleConnTimeout)
+				assertions.Equal(4, httpTransport.MaxIdleConnsPerHost)
+				assertions.Equal(uint16(tls.VersionTLS13), httpTransport.TLSClientConfig.MinVersion)
+			},
+		},
+		{
+			name: "success with proxy",
+			httpConf: appconfig.HTTP{
+				Proxy: pointer("socks5://127.0.0.1:1080"),
+			},
+			assertions: func(assertions *assert.Assertions, transport http.RoundTripper, err error) {
+				assertions.Nil(err)
+				request := httptest.NewRequest(http.MethodGet, "https://api.trakt.tv", nil)
+				proxyURL, err := transport.(*http.Transport).Proxy(request)
+				assertions.Nil(err)
+				assertions.Equal("socks5://127.0.0.1:1080", proxyURL.String())
+			},
+		},
+		{
+			name: "success with ca bundle",
+			httpConf: appconfig.HTTP{
+				CACert: &caCert,
+			},
+			assertions: func(assertions *assert.Assertions, transport http.RoundTripper, err error) {
+				assertions.Nil(err)
+				response, err := (&http.Client{Transport: transport}).Get(server.URL)
+				assertions.Nil(err)
+				assertions.Equal(http.StatusNoContent, response.StatusCode)
+				response.Body.Close()
+			},
+		},
+		{
+			name: "failure with missing ca bundle",
+			httpConf: appconfig.HTTP{
+				CACert: pointer(filepath.Join(dir, "missing.pem")),
+			},
+			assertions: func(assertions *assert.Assertions, transport http.RoundTripper, err error) {
+				assertions.Nil(transport)
+				assertions.ErrorContains(err, "failure reading ca bundle")
+			},
+		},
+		{
+			name: "failure with invalid ca bundle",
+			httpConf: appconfig.HTTP{
+				CACert: &invalidCACert,
+			},
+			assertions: func(assertions *assert.Assertions, transport http.RoundTripper, err error) {
+				assertions.Nil(transport)
+				assertions.ErrorContains(err, "does not contain any pem certificate")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, err := newBaseTransport(tt.httpConf)
+			tt.assertions(assert.New(t), transport, err)
+		})
+	}
+}
+
 type stuckReadCloser struct{}
 
 func (*stuckReadCloser) Read([]byte) (int, error) {
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+const (
+	encodingHeaderKeyAccept  = "Accept-Encoding"
+	encodingHeaderKeyContent = "Content-Encoding"
+	encodingHeaderKeyLength  = "Content-Length"
+	encodingHeaderKeyRange   = "Range"
+	encodingBrotli           = "br"
+	encodingGzip             = "gzip"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+)
+
+// decompress returns a reader of the decompressed data when r holds gzip-compressed data, or a reader of the
+// unchanged data otherwise, so that exports can be read the same way whether they were compressed or not.
+func decompress(r io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(len(gzipMagic))
+	if err != nil || !bytes.Equal(magic, gzipMagic) {
+		return buffered, nil
+	}
+	reader, err := gzip.NewReader(buffered)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating gzip reader: %w", err)
+	}
+	return reader, nil
+}
+
+// decodeTransport asks for gzip or brotli compressed responses and decodes their bodies while they are read. The
+// default transport only ever asks for gzip, and leaves the decoding to the caller when the request already carries
+// an Accept-Encoding header.
+type decodeTransport struct {
+	next http.RoundTripper
+}
+
+func newDecodeTransport(next http.RoundTripper) http.RoundTripper {
+	return &decodeTransport{
+		next: next,
+	}
+}
+
+func (t *decodeTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Method == http.MethodHead || request.Header.Get(encodingHeaderKeyAccept) != "" || request.Header.Get(encodingHeaderKeyRange) != "" {
+		return t.next.RoundTrip(request)
+	}
+	request = request.Clone(request.Context())
+	request.Header.Set(encodingHeaderKeyAccept, encodingGzip+", "+encodingBrotli)
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	var decode func(io.Reader) (io.Reader, error)
+	switch strings.ToLower(response.Header.Get(encodingHeaderKeyContent)) {
+	case encodingGzip:
+		decode = func(r io.Reader) (io.Reader, error) {
+			return gzip.NewReader(r)
+		}
+	case encodingBrotli:
+		decode = func(r io.Reader) (io.Reader, error) {
+			return brotli.NewReader(r), nil
+		}
+	default:
+		return response, nil
+	}
+	response.Body = &decodedBody{
+		body:   response.Body,
+		decode: decode,
+	}
+	response.Header.Del(encodingHeaderKeyContent)
+	response.Header.Del(encodingHeaderKeyLength)
+	response.ContentLength = -1
+	response.Uncompressed = true
+	return response, nil
+}
+
+// decodedBody creates its decoder on the first read, since creating a gzip reader already reads from the body, which
+// is empty for responses such as 204 No Content.
+type decodedBody struct {
+	body   io.ReadCloser
+	decode func(io.Reader) (io.Reader, error)
+	reader io.Reader
+	err    error
+}
+
+func (b *decodedBody) Read(p []byte) (int, error) {
+	if b.reader == nil && b.err == nil {
+		b.reader, b.err = b.decode(b.body)
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.reader.Read(p)
+}
+
+func (b *decodedBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_decodeTransport(t *testing.T) {
+	const payload = `{"title":"Dunkirk"}`
+	tests := []struct {
+		name       string
+		encoding   string
+		handler    http.HandlerFunc
+		assertions func(*assert.Assertions, *http.Response, error)
+	}{
+		{
+			name:     "success with gzip response",
+			encoding: encodingGzip,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(encodingHeaderKeyContent, encodingGzip)
+				writer := gzip.NewWriter(w)
+				_, _ = writer.Write([]byte(payload))
+				_ = writer.Close()
+			},
+		},
+		{
+			name:     "success with brotli response",
+			encoding: encodingBrotli,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(encodingHeaderKeyContent, encodingBrotli)
+				writer := brotli.NewWriter(w)
+				_, _ = writer.Write([]byte(payload))
+				_ = writer.Close()
+			},
+		},
+		{
+			name: "success with uncompressed response",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(payload))
+			},
+		},
+		{
+			name:     "success with empty compressed response",
+			encoding: encodingGzip,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(encodingHeaderKeyContent, encodingGzip)
+				w.WriteHeader(http.StatusNoContent)
+			},
+			assertions: func(assertions *assert.Assertions, response *http.Response, err error) {
+				assertions.Nil(err)
+				assertions.Equal(http.StatusNoContent, response.StatusCode)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertions := assert.New(t)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assertions.Equal("gzip, br", r.Header.Get(encodingHeaderKeyAccept))
+				tt.handler(w, r)
+			}))
+			defer server.Close()
+			client := &http.Client{Transport: newDecodeTransport(http.DefaultTransport)}
+			response, err := client.Get(server.URL)
+			assertions.Nil(err)
+			defer response.Body.Close()
+			if tt.assertions != nil {
+				tt.assertions(assertions, response, err)
+				return
+			}
+			body, err := io.ReadAll(response.Body)
+			assertions.Nil(err)
+			assertions.Equal(payload, string(body))
+			assertions.Empty(response.Header.Get(encodingHeaderKeyContent))
+			assertions.Equal(tt.encoding != "", response.Uncompressed)
+		})
+	}
+}
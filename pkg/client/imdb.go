@@ -1,51 +1,219 @@
 package client
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
 	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/proto"
 
 	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
 	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/metrics"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+	"github.com/cecobask/imdb-trakt-sync/internal/tracing"
 )
 
 const (
+	clientNameIMDb         = "imdb"
 	imdbPathBase           = "https://www.imdb.com"
+	imdbPathChart          = "/chart/%s/"
+	imdbPathCheckins       = "/user/%s/checkins"
 	imdbPathExports        = "/exports"
+	imdbPathExportCenter   = "/exports/center"
 	imdbPathList           = "/list/%s"
 	imdbPathLists          = "/profile/lists"
 	imdbPathRatings        = "/user/%s/ratings"
+	imdbPathRatingsPage    = "/user/%s/ratings/?sort=date_added%%2Cdesc&paginationKey=%s"
+	imdbPathReviews        = "/user/%s/reviews"
 	imdbPathSignIn         = "/registration/ap-signin-handler/imdb_us"
+	imdbPathUserLists      = "/user/%s/lists?page=%d"
+	imdbPathUserWatchlist  = "/user/%s/watchlist"
 	imdbPathWatchlist      = "/list/watchlist"
 	imdbCookieNameAtMain   = "at-main"
 	imdbCookieNameUbidMain = "ubid-main"
 	imdbCookieDomain       = ".imdb.com"
+	imdbStateKeyCookies    = "imdb-cookies"
+
+	imdbCloudflareChallengeTitle = "Just a moment"
+
+	imdbAcceptHTML     = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+	imdbAcceptJSON     = "application/json"
+	imdbAcceptLanguage = "en-US,en;q=0.9"
 )
 
+// imdbUserAgents are recent desktop browsers that the requests rotate through when IMDB_USERAGENTROTATE is enabled.
+var imdbUserAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36 Edg/131.0.0.0",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:133.0) Gecko/20100101 Firefox/133.0",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.1 Safari/605.1.15",
+}
+
+// imdbChartNames are the names of the charts that can be synced, keyed by the identifier of the chart in imdb urls.
+var imdbChartNames = map[string]string{
+	appconfig.IMDbChartTop:        "IMDb Top 250 Movies",
+	appconfig.IMDbChartMovieMeter: "IMDb Most Popular Movies",
+	appconfig.IMDbChartTVMeter:    "IMDb Most Popular TV Shows",
+}
+
+var imdbChromeVersionRegex = regexp.MustCompile(`Chrome/(\d+)`)
+
 type IMDbClient struct {
-	config  *imdbConfig
-	logger  *slog.Logger
-	browser *rod.Browser
+	httpConf appconfig.HTTP
+	config   *imdbConfig
+	logger   *slog.Logger
+	browser  *rod.Browser
+	launcher *launcher.Launcher
+	store    state.Store
+	jar      *imdbCookieJar
+}
+
+type imdbCookies struct {
+	AtMain   string `json:"atMain"`
+	UbidMain string `json:"ubidMain"`
 }
 
 type imdbConfig struct {
 	*appconfig.IMDb
+	timeout     time.Duration
 	userID      string
 	username    string
 	watchlistID string
+	checkinsID  string
+	requests    atomic.Uint64
+}
+
+// userAgent returns the user agent of the next request, which is the configured one unless rotation is enabled.
+func (c *imdbConfig) userAgent() string {
+	userAgent := appconfig.IMDbUserAgentDefault
+	if c.UserAgent != nil && *c.UserAgent != "" {
+		userAgent = *c.UserAgent
+	}
+	if c.UserAgentRotate == nil || !*c.UserAgentRotate {
+		return userAgent
+	}
+	agents := append([]string{userAgent}, imdbUserAgents...)
+	return agents[(c.requests.Add(1)-1)%uint64(len(agents))]
+}
+
+// setBrowserHeaders makes the request look like it was sent by the browser that the user agent belongs to, since imdb
+// blocks requests that do not. The client hints are only sent by chromium based browsers.
+func setBrowserHeaders(header http.Header, userAgent, accept string) {
+	header.Set("User-Agent", userAgent)
+	header.Set("Accept", accept)
+	header.Set("Accept-Language", imdbAcceptLanguage)
+	match := imdbChromeVersionRegex.FindStringSubmatch(userAgent)
+	if match == nil {
+		return
+	}
+	brand := "Google Chrome"
+	if strings.Contains(userAgent, "Edg/") {
+		brand = "Microsoft Edge"
+	}
+	header.Set("sec-ch-ua", fmt.Sprintf(`"%s";v="%s", "Chromium";v="%s", "Not_A Brand";v="24"`, brand, match[1], match[1]))
+	header.Set("sec-ch-ua-mobile", "?0")
+	header.Set("sec-ch-ua-platform", fmt.Sprintf("%q", userAgentPlatform(userAgent)))
+}
+
+func userAgentPlatform(userAgent string) string {
+	switch {
+	case strings.Contains(userAgent, "Windows"):
+		return "Windows"
+	case strings.Contains(userAgent, "Macintosh"):
+		return "macOS"
+	case strings.Contains(userAgent, "Android"):
+		return "Android"
+	default:
+		return "Linux"
+	}
+}
+
+func NewIMDbClient(ctx context.Context, conf *appconfig.IMDb, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger) (IMDbClientInterface, error) {
+	c, err := launchIMDbClient(ctx, conf, httpConf, store, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.authenticateUser(); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failure authenticating user: %w", err)
+	}
+	if err = c.hydrate(); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failure hydrating client: %w", err)
+	}
+	return c, nil
+}
+
+// ValidateIMDbAuth authenticates to imdb without hydrating the client, and returns the expiry time of the
+// authentication cookie. The returned time is nil for session cookies or when no authentication is configured.
+func ValidateIMDbAuth(ctx context.Context, conf *appconfig.IMDb, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger) (*time.Time, error) {
+	if *conf.Auth == appconfig.IMDbAuthMethodNone {
+		return nil, nil
+	}
+	c, err := launchIMDbClient(ctx, conf, httpConf, store, logger)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	if err = c.authenticateUser(); err != nil {
+		return nil, fmt.Errorf("failure authenticating user: %w", err)
+	}
+	cookies, err := c.browser.GetCookies()
+	if err != nil {
+		return nil, fmt.Errorf("failure retrieving browser cookies: %w", err)
+	}
+	for _, cookie := range cookies {
+		if cookie.Name != imdbCookieNameAtMain || cookie.Session || cookie.Expires <= 0 {
+			continue
+		}
+		expiresAt := cookie.Expires.Time()
+		return &expiresAt, nil
+	}
+	return nil, nil
 }
 
-func NewIMDbClient(ctx context.Context, conf *appconfig.IMDb, logger *slog.Logger) (IMDbClientInterface, error) {
+// configureBrowserTransport sends the browser traffic through the configured proxy and trusts the certificates of the
+// custom ca bundle. Chrome does not accept proxy credentials on the command line, so they are dropped.
+func configureBrowserTransport(l *launcher.Launcher, httpConf appconfig.HTTP) error {
+	if proxy := valueOrEmpty(httpConf.Proxy); proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return fmt.Errorf("failure parsing proxy url: %w", err)
+		}
+		l.Proxy(proxyURL.Scheme + "://" + proxyURL.Host)
+	}
+	if caCert := valueOrEmpty(httpConf.CACert); caCert != "" {
+		fingerprints, err := certificateFingerprints(caCert)
+		if err != nil {
+			return err
+		}
+		l.Set("ignore-certificate-errors-spki-list", fingerprints...)
+	}
+	return nil
+}
+
+func launchIMDbClient(ctx context.Context, conf *appconfig.IMDb, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger) (*IMDbClient, error) {
 	l := launcher.New().Headless(*conf.Headless).Bin(getBrowserPathOrFallback(conf)).
 		Set("allow-running-insecure-content").
 		Set("autoplay-policy", "user-gesture-required").
@@ -68,53 +236,203 @@ func NewIMDbClient(ctx context.Context, conf *appconfig.IMDb, logger *slog.Logge
 		Set("no-pings").
 		Set("no-sandbox").
 		Set("no-zygote").
-		Set("single-process")
+		Set("single-process").
+		Set("user-agent", (&imdbConfig{IMDb: conf}).userAgent()).
+		Set("lang", "en-US")
+	if err := configureBrowserTransport(l, httpConf); err != nil {
+		return nil, err
+	}
 	browserURL, err := l.Launch()
 	if err != nil {
 		return nil, fmt.Errorf("failure launching browser: %w", err)
 	}
 	browser := rod.New().Context(ctx).ControlURL(browserURL).Trace(*conf.Trace)
 	if err = browser.Connect(); err != nil {
+		l.Kill()
 		return nil, fmt.Errorf("failure connecting to browser: %w", err)
 	}
 	logger.Info("launched new browser instance", slog.String("url", browserURL), slog.Bool("headless", *conf.Headless), slog.Bool("trace", *conf.Trace))
-	c := &IMDbClient{
+	return &IMDbClient{
+		httpConf: httpConf,
 		config: &imdbConfig{
-			IMDb: conf,
+			IMDb:    conf,
+			timeout: httpConf.ClientTimeout(clientNameIMDb),
 		},
-		logger:  logger,
-		browser: browser,
-	}
-	if err = c.authenticateUser(); err != nil {
-		return nil, fmt.Errorf("failure authenticating user: %w", err)
+		logger:   logger,
+		browser:  browser,
+		launcher: l,
+		store:    store,
+	}, nil
+}
+
+func (c *IMDbClient) Close() {
+	if c.jar != nil {
+		if err := c.storeCookieJar(); err != nil {
+			c.logger.Warn("failure storing imdb cookie jar", slog.Any("error", err))
+		}
 	}
-	if err = c.hydrate(); err != nil {
-		return nil, fmt.Errorf("failure hydrating client: %w", err)
+	if err := c.browser.Close(); err != nil {
+		c.logger.Warn("failure closing browser gracefully, killing its process instead", slog.Any("error", err))
+		c.launcher.Kill()
 	}
-	return c, nil
+	c.launcher.Cleanup()
 }
 
 func (c *IMDbClient) authenticateUser() error {
-	if *c.config.Auth == appconfig.IMDbAuthMethodNone {
+	var err error
+	switch *c.config.Auth {
+	case appconfig.IMDbAuthMethodNone:
 		return nil
+	case appconfig.IMDbAuthMethodCookies:
+		err = c.cookiesAuthenticate()
+	default:
+		err = c.jarAuthenticate()
 	}
-	if *c.config.Auth == appconfig.IMDbAuthMethodCookies {
-		if err := setBrowserCookies(c.browser, c.config.IMDb); err != nil {
+	if err != nil || c.jar == nil {
+		return err
+	}
+	return c.storeCookieJar()
+}
+
+// jarAuthenticate reuses the session in the cookie jar when it is still valid, and signs in with credentials otherwise.
+func (c *IMDbClient) jarAuthenticate() error {
+	if !*c.config.CookieJar {
+		return c.credentialsAuthenticate()
+	}
+	restored, err := c.restoreCookieJar("")
+	if err != nil {
+		return err
+	}
+	if restored {
+		authenticated, err := c.isAuthenticated()
+		if err != nil {
 			return err
 		}
-		tab, err := c.navigateAndValidateResponse(imdbPathBase)
-		if err != nil {
-			return fmt.Errorf("failure navigating and validating response: %w", err)
+		if authenticated {
+			c.logger.Info("reused imdb session from the cookie jar")
+			return nil
 		}
-		authenticated, _, err := tab.Has("#nblogout")
-		if err != nil {
-			return fmt.Errorf("failure finding logout div")
+		if err = c.browser.SetCookies(nil); err != nil {
+			return fmt.Errorf("failure clearing browser cookies: %w", err)
 		}
-		if !authenticated {
-			return fmt.Errorf("failure authenticating with the provided cookies")
+	}
+	return c.credentialsAuthenticate()
+}
+
+// restoreCookieJar loads the cookie jar and sets its cookies in the browser, overriding the configured ones. It
+// reports whether the jar had any cookies to restore.
+func (c *IMDbClient) restoreCookieJar(seed string) (bool, error) {
+	jar, err := loadIMDbCookieJar(c.store, seed)
+	if err != nil {
+		return false, err
+	}
+	c.jar = jar
+	params := jar.browserParams()
+	if len(params) == 0 {
+		return false, nil
+	}
+	if err = c.browser.SetCookies(params); err != nil {
+		return false, fmt.Errorf("failure setting browser cookies from the cookie jar: %w", err)
+	}
+	return true, nil
+}
+
+// storeCookieJar persists the imdb cookies of the browser, which include the ones that imdb renewed during the run.
+func (c *IMDbClient) storeCookieJar() error {
+	cookies, err := c.browser.GetCookies()
+	if err != nil {
+		return fmt.Errorf("failure retrieving browser cookies: %w", err)
+	}
+	c.jar.updateFromBrowser(cookies)
+	return c.jar.save(c.store)
+}
+
+// isAuthenticated loads the home page and reports whether the user is signed in.
+func (c *IMDbClient) isAuthenticated() (bool, error) {
+	tab, err := c.navigateAndValidateResponse(imdbPathBase)
+	if err != nil {
+		return false, fmt.Errorf("failure navigating and validating response: %w", err)
+	}
+	authenticated, _, err := tab.Has("#nblogout")
+	if err != nil {
+		return false, fmt.Errorf("failure finding logout div")
+	}
+	return authenticated, nil
+}
+
+func (c *IMDbClient) cookiesAuthenticate() error {
+	cookies := imdbCookies{
+		AtMain:   *c.config.CookieAtMain,
+		UbidMain: *c.config.CookieUbidMain,
+	}
+	if *c.config.CookieRefresh {
+		if err := c.store.Load(imdbStateKeyCookies, &cookies); err != nil && !errors.Is(err, state.ErrNotFound) {
+			return fmt.Errorf("failure loading stored imdb cookies: %w", err)
+		}
+	}
+	if err := setBrowserCookies(c.browser, cookies); err != nil {
+		return err
+	}
+	if *c.config.CookieJar {
+		if _, err := c.restoreCookieJar(*c.config.CookieAtMain); err != nil {
+			return err
 		}
+	}
+	authenticated, err := c.isAuthenticated()
+	if err != nil {
+		return err
+	}
+	if authenticated {
 		return nil
 	}
+	if !*c.config.CookieRefresh {
+		return &AuthError{Err: errors.New("failure authenticating with the provided cookies")}
+	}
+	c.logger.Info("imdb cookies have expired, signing in with credentials to refresh them")
+	return c.refreshCookies()
+}
+
+// refreshCookies signs in with credentials and persists the obtained cookies, so that they can be reused by later runs.
+func (c *IMDbClient) refreshCookies() error {
+	if err := c.browser.SetCookies(nil); err != nil {
+		return fmt.Errorf("failure clearing browser cookies: %w", err)
+	}
+	if err := c.credentialsAuthenticate(); err != nil {
+		return fmt.Errorf("failure refreshing cookies: %w", err)
+	}
+	cookies, err := c.browserCookies()
+	if err != nil {
+		return err
+	}
+	if err = c.store.Save(imdbStateKeyCookies, cookies); err != nil {
+		return fmt.Errorf("failure storing refreshed imdb cookies: %w", err)
+	}
+	c.logger.Info("refreshed imdb cookies")
+	return nil
+}
+
+// browserCookies returns the authentication cookies of the browser, which are only set once the user is signed in.
+func (c *IMDbClient) browserCookies() (*imdbCookies, error) {
+	browserCookies, err := c.browser.GetCookies()
+	if err != nil {
+		return nil, fmt.Errorf("failure retrieving browser cookies: %w", err)
+	}
+	var cookies imdbCookies
+	for _, cookie := range browserCookies {
+		switch cookie.Name {
+		case imdbCookieNameAtMain:
+			cookies.AtMain = cookie.Value
+		case imdbCookieNameUbidMain:
+			cookies.UbidMain = cookie.Value
+		}
+	}
+	if cookies.AtMain == "" || cookies.UbidMain == "" {
+		return nil, fmt.Errorf("failure finding cookies %s and %s after signing in", imdbCookieNameAtMain, imdbCookieNameUbidMain)
+	}
+	return &cookies, nil
+}
+
+func (c *IMDbClient) credentialsAuthenticate() error {
 	tab, err := c.navigateAndValidateResponse(imdbPathBase + imdbPathSignIn)
 	if err != nil {
 		return fmt.Errorf("failure navigating and validating response: %w", err)
@@ -149,7 +467,7 @@ func (c *IMDbClient) authenticateUser() error {
 		return fmt.Errorf("failure checking for authentication error match: %w", err)
 	}
 	if authFailed {
-		return fmt.Errorf("failure authenticating with the provided credentials")
+		return &AuthError{Err: errors.New("failure authenticating with the provided credentials")}
 	}
 	captcha, err := race.Matches("img[alt='captcha']")
 	if err != nil {
@@ -163,6 +481,21 @@ func (c *IMDbClient) authenticateUser() error {
 
 func (c *IMDbClient) hydrate() error {
 	if *c.config.Auth == appconfig.IMDbAuthMethodNone {
+		if *c.config.UserID == "" {
+			return nil
+		}
+		c.config.userID = *c.config.UserID
+		watchlistID, err := c.watchlistIDScrape()
+		if err != nil {
+			return fmt.Errorf("failure scraping watchlist id: %w", err)
+		}
+		c.config.watchlistID = watchlistID
+		if c.config.AllLists() {
+			if err = c.allListsResolve(); err != nil {
+				return err
+			}
+		}
+		c.logger.Info("hydrated imdb client", slog.String("userID", c.config.userID), slog.String("watchlistID", watchlistID), slog.Any("lists", *c.config.Lists))
 		return nil
 	}
 	tab, err := c.navigateAndValidateResponse(imdbPathBase + imdbPathWatchlist)
@@ -200,6 +533,12 @@ func (c *IMDbClient) hydrate() error {
 		return fmt.Errorf("failure extracting watchlist id from href: %w", err)
 	}
 	c.config.watchlistID = watchlistID
+	allLists := c.config.AllLists()
+	if allLists {
+		if err = c.allListsResolve(); err != nil {
+			return err
+		}
+	}
 	lids := slices.DeleteFunc(*c.config.Lists, func(lid string) bool {
 		if lid == watchlistID {
 			c.logger.Warn("removing watchlist id from provided lists; please use config option SYNC_WATCHLIST instead")
@@ -207,7 +546,7 @@ func (c *IMDbClient) hydrate() error {
 		}
 		return false
 	})
-	if len(lids) == 0 {
+	if len(lids) == 0 && !allLists {
 		lids, err = c.lidsScrape()
 		if err != nil {
 			return fmt.Errorf("failure scraping list ids: %w", err)
@@ -218,8 +557,81 @@ func (c *IMDbClient) hydrate() error {
 	return nil
 }
 
+// allListsResolve replaces the all keyword of the configured lists with the ids of every list of the user.
+func (c *IMDbClient) allListsResolve() error {
+	lists, err := c.ListsGetAll()
+	if err != nil {
+		return fmt.Errorf("failure discovering all lists: %w", err)
+	}
+	lids := make([]string, 0, len(lists))
+	for _, list := range lists {
+		if list.ListID != c.config.watchlistID {
+			lids = append(lids, list.ListID)
+		}
+	}
+	c.config.Lists = &lids
+	return nil
+}
+
+// ListsGetAll returns the id and name of every public list of the user, without the list items. The lists page is
+// paginated, so pages are scraped until one does not contain any list that has not been seen yet.
+func (c *IMDbClient) ListsGetAll() ([]entities.IMDbList, error) {
+	lists := make([]entities.IMDbList, 0)
+	seen := make(map[string]struct{})
+	for page := 1; ; page++ {
+		tab, err := c.navigateAndValidateResponse(imdbPathBase + fmt.Sprintf(imdbPathUserLists, c.config.userID, page))
+		if err != nil {
+			return nil, fmt.Errorf("failure navigating and validating response: %w", err)
+		}
+		html, err := tab.HTML()
+		if err != nil {
+			return nil, fmt.Errorf("failure extracting lists page html: %w", err)
+		}
+		pageLists, err := listsParse(strings.NewReader(html))
+		if err != nil {
+			return nil, err
+		}
+		var added int
+		for _, list := range pageLists {
+			if _, ok := seen[list.ListID]; ok {
+				continue
+			}
+			seen[list.ListID] = struct{}{}
+			lists = append(lists, list)
+			added++
+		}
+		if added == 0 {
+			break
+		}
+	}
+	c.logger.Info("discovered imdb lists", slog.Int("count", len(lists)))
+	return lists, nil
+}
+
+// watchlistIDScrape resolves the list id of a public watchlist from the id of its owner, which does not require
+// authentication, since the watchlist page embeds the details of the underlying list.
+func (c *IMDbClient) watchlistIDScrape() (string, error) {
+	tab, err := c.navigateAndValidateResponse(imdbPathBase + fmt.Sprintf(imdbPathUserWatchlist, c.config.userID))
+	if err != nil {
+		return "", fmt.Errorf("failure navigating and validating response: %w", err)
+	}
+	data, err := nextDataScrape(tab)
+	if err != nil {
+		return "", err
+	}
+	page, err := listPageParse(data)
+	if err != nil {
+		return "", err
+	}
+	watchlistID := page.Props.PageProps.MainColumnData.List.ID
+	if !strings.HasPrefix(watchlistID, "ls") {
+		return "", fmt.Errorf("failure finding watchlist of user %s, make sure that the watchlist is public", c.config.userID)
+	}
+	return watchlistID, nil
+}
+
 func (c *IMDbClient) WatchlistExport() error {
-	if *c.config.Auth == appconfig.IMDbAuthMethodNone {
+	if c.config.watchlistID == "" {
 		return nil
 	}
 	return c.ListExport(c.config.watchlistID)
@@ -233,9 +645,105 @@ func (c *IMDbClient) WatchlistGet() (*entities.IMDbList, error) {
 	return &lists[0], nil
 }
 
+func (c *IMDbClient) CheckinsExport() error {
+	if *c.config.Auth == appconfig.IMDbAuthMethodNone {
+		return nil
+	}
+	if err := c.checkinsIDScrape(); err != nil {
+		return fmt.Errorf("failure scraping check-ins list id: %w", err)
+	}
+	return c.ListExport(c.config.checkinsID)
+}
+
+func (c *IMDbClient) CheckinsGet() (*entities.IMDbList, error) {
+	if err := c.checkinsIDScrape(); err != nil {
+		return nil, fmt.Errorf("failure scraping check-ins list id: %w", err)
+	}
+	lists, err := c.ListsGet(c.config.checkinsID)
+	if err != nil {
+		return nil, fmt.Errorf("failure downloading check-ins: %w", err)
+	}
+	return &lists[0], nil
+}
+
+func (c *IMDbClient) checkinsIDScrape() error {
+	if c.config.checkinsID != "" {
+		return nil
+	}
+	tab, err := c.navigateAndValidateResponse(imdbPathBase + fmt.Sprintf(imdbPathCheckins, c.config.userID))
+	if err != nil {
+		return fmt.Errorf("failure navigating and validating response: %w", err)
+	}
+	hyperlink, err := tab.Element("a[data-testid='hero-list-subnav-edit-button']")
+	if err != nil {
+		return fmt.Errorf("failure finding hyperlink element: %w", err)
+	}
+	href, err := hyperlink.Attribute("href")
+	if err != nil {
+		return fmt.Errorf("failure extracting href from hyperlink: %w", err)
+	}
+	checkinsID, err := idExtract(*href)
+	if err != nil {
+		return fmt.Errorf("failure extracting check-ins id from href: %w", err)
+	}
+	c.config.checkinsID = checkinsID
+	c.logger.Info("resolved imdb check-ins list", slog.String("checkinsID", checkinsID))
+	return nil
+}
+
+// ListCreate creates the list through the graphql api, since exports are read-only.
+func (c *IMDbClient) ListCreate(name, description string) (string, error) {
+	gc, err := c.graphqlClient()
+	if err != nil {
+		return "", err
+	}
+	return gc.ListCreate(name, description)
+}
+
+// ListItemsAdd adds the titles to the list through the graphql api, since exports are read-only.
+func (c *IMDbClient) ListItemsAdd(id string, itemIDs ...string) error {
+	gc, err := c.graphqlClient()
+	if err != nil {
+		return err
+	}
+	return gc.ListItemsAdd(id, itemIDs...)
+}
+
+// ListItemsRemove removes the titles from the list through the graphql api, since exports are read-only.
+func (c *IMDbClient) ListItemsRemove(id string, itemIDs ...string) error {
+	gc, err := c.graphqlClient()
+	if err != nil {
+		return err
+	}
+	return gc.ListItemsRemove(id, itemIDs...)
+}
+
+// graphqlClient returns a client for the imdb graphql api that is authenticated with the cookies of the browser.
+func (c *IMDbClient) graphqlClient() (*IMDbGraphQLClient, error) {
+	cookies, err := c.browserCookies()
+	if err != nil {
+		return nil, err
+	}
+	transport, err := newTransport(clientNameIMDb, c.httpConf, c.store)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating transport: %w", err)
+	}
+	return &IMDbGraphQLClient{
+		ctx: c.browser.GetContext(),
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   c.config.timeout,
+		},
+		config:  c.config,
+		logger:  c.logger,
+		store:   c.store,
+		cookies: cookies,
+	}, nil
+}
+
 func (c *IMDbClient) ListExport(id string) error {
 	listURL := imdbPathBase + fmt.Sprintf(imdbPathList, id)
-	if err := c.exportResource(listURL); err != nil {
+	if err := c.exportResource(listURL, id); err != nil {
 		return fmt.Errorf("failure exporting list %s: %w", id, err)
 	}
 	c.logger.Info("exported list", slog.String("id", id))
@@ -271,15 +779,58 @@ func (c *IMDbClient) ListsGet(ids ...string) ([]entities.IMDbList, error) {
 		}
 		lists[i] = *list
 	}
+	// descriptions are scraped only after all downloads, since navigating away invalidates the export resources
+	// the description is optional, hence why a list whose description cannot be scraped is kept without one
+	for i := range lists {
+		page, err := c.listPageScrape(lists[i].ListID)
+		if err != nil {
+			c.logger.Warn("failure scraping list description, keeping the list without one", slog.String("id", lists[i].ListID), slog.Any("error", err))
+			continue
+		}
+		lists[i].Description = page.Description()
+	}
 	return lists, nil
 }
 
+// ListsModified returns the time each list was last modified, which is cheaper to find out than exporting the list.
+// Lists without a modification date are left out of the result.
+func (c *IMDbClient) ListsModified(ids ...string) (map[string]time.Time, error) {
+	modified := make(map[string]time.Time, len(ids))
+	for _, id := range ids {
+		page, err := c.listPageScrape(id)
+		if err != nil {
+			return nil, fmt.Errorf("failure scraping list %s: %w", id, err)
+		}
+		lastModified, err := page.LastModified()
+		if err != nil {
+			return nil, fmt.Errorf("failure reading modification date of list %s: %w", id, err)
+		}
+		if lastModified != nil {
+			modified[id] = *lastModified
+		}
+	}
+	return modified, nil
+}
+
+// listPageScrape reads the data embedded in a list page, which holds details that list exports do not include.
+func (c *IMDbClient) listPageScrape(id string) (*entities.IMDbListPage, error) {
+	tab, err := c.navigateAndValidateResponse(imdbPathBase + fmt.Sprintf(imdbPathList, id))
+	if err != nil {
+		return nil, fmt.Errorf("failure navigating and validating response: %w", err)
+	}
+	data, err := nextDataScrape(tab)
+	if err != nil {
+		return nil, err
+	}
+	return listPageParse(data)
+}
+
 func (c *IMDbClient) RatingsExport() error {
-	if *c.config.Auth == appconfig.IMDbAuthMethodNone {
+	if *c.config.Auth == appconfig.IMDbAuthMethodNone || *c.config.RatingsPaginated {
 		return nil
 	}
 	ratingsURL := imdbPathBase + fmt.Sprintf(imdbPathRatings, c.config.userID)
-	if err := c.exportResource(ratingsURL); err != nil {
+	if err := c.exportResource(ratingsURL, c.config.userID); err != nil {
 		return fmt.Errorf("failure exporting ratings resource: %w", err)
 	}
 	c.logger.Info("exported ratings")
@@ -287,6 +838,9 @@ func (c *IMDbClient) RatingsExport() error {
 }
 
 func (c *IMDbClient) RatingsGet() ([]entities.IMDbItem, error) {
+	if *c.config.RatingsPaginated {
+		return c.ratingsScrape()
+	}
 	resources, err := c.getExportedResources(c.config.userID)
 	if err != nil {
 		return nil, fmt.Errorf("failure fetching exported resources: %w", err)
@@ -298,6 +852,94 @@ func (c *IMDbClient) RatingsGet() ([]entities.IMDbItem, error) {
 	return c.ratingsDownload(filteredResources[0])
 }
 
+// ratingsScrape walks through the pages of the ratings page, which unlike the ratings export is not limited in size.
+func (c *IMDbClient) ratingsScrape() ([]entities.IMDbItem, error) {
+	items := make([]entities.IMDbItem, 0)
+	var paginationKey string
+	for {
+		tab, err := c.navigateAndValidateResponse(imdbPathBase + fmt.Sprintf(imdbPathRatingsPage, c.config.userID, url.QueryEscape(paginationKey)))
+		if err != nil {
+			return nil, fmt.Errorf("failure navigating and validating response: %w", err)
+		}
+		data, err := nextDataScrape(tab)
+		if err != nil {
+			return nil, err
+		}
+		page, err := ratingsPageParse(data)
+		if err != nil {
+			return nil, err
+		}
+		pageItems, err := page.Items()
+		if err != nil {
+			return nil, fmt.Errorf("failure converting ratings page to items: %w", err)
+		}
+		items = append(items, pageItems...)
+		if paginationKey = page.NextPaginationKey(); paginationKey == "" {
+			break
+		}
+	}
+	c.logger.Info("scraped ratings", slog.Int("count", len(items)))
+	return items, nil
+}
+
+// ChartGet scrapes the ranked titles of a chart, which is public and therefore does not require authentication.
+func (c *IMDbClient) ChartGet(chart string) (*entities.IMDbList, error) {
+	tab, err := c.navigateAndValidateResponse(imdbPathBase + fmt.Sprintf(imdbPathChart, chart))
+	if err != nil {
+		return nil, fmt.Errorf("failure navigating and validating response: %w", err)
+	}
+	data, err := nextDataScrape(tab)
+	if err != nil {
+		return nil, err
+	}
+	page, err := chartPageParse(data)
+	if err != nil {
+		return nil, err
+	}
+	list := chartList(chart, page.Props.PageProps.PageData.ChartTitles.Items())
+	c.logger.Info("scraped imdb chart", slog.String("chart", chart), slog.Int("count", len(list.ListItems)))
+	return list, nil
+}
+
+func (c *IMDbClient) ReviewsGet() ([]entities.IMDbReview, error) {
+	if *c.config.Auth == appconfig.IMDbAuthMethodNone {
+		return make([]entities.IMDbReview, 0), nil
+	}
+	tab, err := c.navigateAndValidateResponse(imdbPathBase + fmt.Sprintf(imdbPathReviews, c.config.userID))
+	if err != nil {
+		return nil, fmt.Errorf("failure navigating and validating response: %w", err)
+	}
+	for {
+		hasMore, loadMoreButton, err := tab.Has("span.ipc-see-more > button")
+		if err != nil {
+			return nil, fmt.Errorf("failure finding load more button: %w", err)
+		}
+		if !hasMore {
+			break
+		}
+		if err = loadMoreButton.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			return nil, fmt.Errorf("failure clicking on load more button: %w", err)
+		}
+		if err = tab.WaitStable(time.Second); err != nil {
+			return nil, fmt.Errorf("failure waiting for tab to become stable: %w", err)
+		}
+	}
+	articles, err := tab.Elements("article.user-review-item")
+	if err != nil {
+		return nil, fmt.Errorf("failure finding review elements: %w", err)
+	}
+	reviews := make([]entities.IMDbReview, 0, len(articles))
+	for _, article := range articles {
+		review, err := reviewScrape(article)
+		if err != nil {
+			return nil, fmt.Errorf("failure scraping review: %w", err)
+		}
+		reviews = append(reviews, *review)
+	}
+	c.logger.Info("scraped reviews", slog.Int("count", len(reviews)))
+	return reviews, nil
+}
+
 func (c *IMDbClient) ratingsDownload(resource *rod.Element) ([]entities.IMDbItem, error) {
 	downloadButton, err := resource.Element("button[data-testid='export-status-button']")
 	if err != nil {
@@ -307,7 +949,7 @@ func (c *IMDbClient) ratingsDownload(resource *rod.Element) ([]entities.IMDbItem
 	if err = downloadButton.Click(proto.InputMouseButtonLeft, 1); err != nil {
 		return nil, fmt.Errorf("failure clicking on download button: %w", err)
 	}
-	items, err := transformData(wait())
+	items, err := transformDownload(wait())
 	if err != nil {
 		return nil, fmt.Errorf("failure transforming ratings data: %w", err)
 	}
@@ -340,7 +982,7 @@ func (c *IMDbClient) listDownload(resource *rod.Element) (*entities.IMDbList, er
 	if err = downloadButton.Click(proto.InputMouseButtonLeft, 1); err != nil {
 		return nil, fmt.Errorf("failure clicking on download button: %w", err)
 	}
-	items, err := transformData(wait())
+	items, err := transformDownload(wait())
 	if err != nil {
 		return nil, fmt.Errorf("failure transforming list data: %w", err)
 	}
@@ -368,12 +1010,20 @@ func (c *IMDbClient) getExportedResources(ids ...string) (rod.Elements, error) {
 	return resources, nil
 }
 
-func (c *IMDbClient) exportResource(url string) error {
+// exportResource starts the export of the resource at the given url, falling back to the export center when the page
+// of the resource no longer offers a direct export.
+func (c *IMDbClient) exportResource(url, id string) error {
 	tab, err := c.navigateAndValidateResponse(url)
 	if err != nil {
 		return fmt.Errorf("failure navigating and validating response: %w", err)
 	}
-	race, err := tab.Race().Element("div[data-testid='hero-list-subnav-export-button'] button").Element("div[data-testid='list-page-mc-private-list-content']").Do()
+	timed := tab.Timeout(c.config.timeout)
+	defer timed.CancelTimeout()
+	race, err := timed.Race().Element("div[data-testid='hero-list-subnav-export-button'] button").Element("div[data-testid='list-page-mc-private-list-content']").Do()
+	if errors.Is(err, context.DeadlineExceeded) {
+		c.logger.Warn("direct export is unavailable, falling back to the export center", slog.String("id", id))
+		return c.exportCenterRequest(id)
+	}
 	if err != nil {
 		return fmt.Errorf("failure doing selector race: %w", err)
 	}
@@ -392,6 +1042,32 @@ func (c *IMDbClient) exportResource(url string) error {
 	return nil
 }
 
+// exportCenterRequest requests the export of a resource from the export center, which prepares the export in the
+// background and offers it as a zip archive on the exports page, like the direct exports.
+func (c *IMDbClient) exportCenterRequest(id string) error {
+	tab, err := c.navigateAndValidateResponse(imdbPathBase + imdbPathExportCenter)
+	if err != nil {
+		return fmt.Errorf("failure navigating and validating response: %w", err)
+	}
+	resource, err := tab.Element(fmt.Sprintf("input[data-testid='export-center-resource'][value='%s']", id))
+	if err != nil {
+		return fmt.Errorf("failure finding resource %s in the export center: %w", id, err)
+	}
+	if err = resource.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return fmt.Errorf("failure selecting resource %s in the export center: %w", id, err)
+	}
+	submitButton, err := tab.Element("button[data-testid='export-center-submit']")
+	if err != nil {
+		return fmt.Errorf("failure finding export center submit button: %w", err)
+	}
+	wait := tab.WaitRequestIdle(time.Second, []string{"pageAction=start-export"}, nil, nil)
+	if err = submitButton.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return fmt.Errorf("failure clicking on export center submit button: %w", err)
+	}
+	wait()
+	return nil
+}
+
 func (c *IMDbClient) waitExportsReady(tab *rod.Page, ids ...string) error {
 	maxRetries := 30
 	for attempt := 1; attempt <= maxRetries; attempt++ {
@@ -419,7 +1095,9 @@ func (c *IMDbClient) waitExportsReady(tab *rod.Page, ids ...string) error {
 		}
 		duration := 30 * time.Second
 		c.logger.Info(fmt.Sprintf("waiting %s before reloading exports tab to check the latest status", duration), slog.Int("attempt", attempt))
-		time.Sleep(duration)
+		if err = sleep(c.browser.GetContext(), duration); err != nil {
+			return err
+		}
 		if err = tab.Reload(); err != nil {
 			return fmt.Errorf("failure reloading exports tab: %w", err)
 		}
@@ -536,23 +1214,233 @@ func (c *IMDbClient) scrollUntilAllElementsVisible(tab *rod.Page, selector strin
 }
 
 func (c *IMDbClient) navigateAndValidateResponse(url string) (*rod.Page, error) {
+	for attempt := 1; ; attempt++ {
+		tab, err := c.navigate(url)
+		if err == nil {
+			return tab, nil
+		}
+		var transientErr *TransientError
+		if !errors.As(err, &transientErr) || attempt >= *c.config.RetryMaxAttempts {
+			return nil, err
+		}
+		delay := imdbRetryDelay(attempt, *c.config.RetryBackoff, *c.config.RetryJitter)
+		c.logger.Warn(fmt.Sprintf("imdb responded with a transient error, waiting %s then retrying", delay), slog.String("url", url), slog.Int("attempt", attempt), slog.Any("error", err))
+		if err = sleep(c.browser.GetContext(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *IMDbClient) navigate(url string) (tab *rod.Page, err error) {
+	url = rebaseURL(c.httpConf, url)
+	start := time.Now()
+	finish := tracing.StartRequest(c.browser.GetContext(), clientNameIMDb, http.MethodGet, url)
+	defer func() {
+		metrics.ObserveRequest(clientNameIMDb, time.Since(start), err == nil)
+		finish(0, err)
+	}()
 	pages, err := c.browser.Pages()
 	if err != nil {
 		return nil, fmt.Errorf("failure retrieving browser pages: %w", err)
 	}
-	tab := pages.First()
+	tab = pages.First()
 	if pages.Empty() {
 		tab = c.browser.MustPage()
 	}
-	if err = tab.Navigate(url); err != nil {
+	timed := tab.Timeout(c.config.timeout)
+	defer timed.CancelTimeout()
+	if err = timed.Navigate(url); err != nil {
 		return nil, fmt.Errorf("failure navigating to url %s: %w", url, err)
 	}
-	if err = tab.WaitLoad(); err != nil {
+	if err = timed.WaitLoad(); err != nil {
 		return nil, fmt.Errorf("failure waiting for tab %s to load: %w", url, err)
 	}
+	if err = validateResponse(tab, url); err != nil {
+		return nil, err
+	}
 	return tab, nil
 }
 
+// validateResponse turns server errors and cloudflare challenges into an ApiError, so that they can be retried.
+func validateResponse(tab *rod.Page, url string) error {
+	result, err := tab.Eval(`() => performance.getEntriesByType("navigation")[0]?.responseStatus ?? 0`)
+	if err != nil {
+		return fmt.Errorf("failure evaluating response status of tab %s: %w", url, err)
+	}
+	info, err := tab.Info()
+	if err != nil {
+		return fmt.Errorf("failure retrieving info of tab %s: %w", url, err)
+	}
+	statusCode := result.Value.Int()
+	if strings.HasPrefix(info.Title, imdbCloudflareChallengeTitle) {
+		return &ApiError{
+			httpMethod: http.MethodGet,
+			url:        url,
+			StatusCode: statusCode,
+			details:    "imdb responded with a cloudflare challenge",
+			retryable:  true,
+		}
+	}
+	apiErr := &ApiError{
+		httpMethod: http.MethodGet,
+		url:        url,
+		StatusCode: statusCode,
+		details:    fmt.Sprintf("unexpected status code %d", statusCode),
+	}
+	if apiErr.Retryable() {
+		return apiErr
+	}
+	return nil
+}
+
+// imdbRetryDelay returns an exponential backoff for the given attempt, with a random jitter on top.
+func imdbRetryDelay(attempt int, backoff, jitter time.Duration) time.Duration {
+	delay := backoff << (attempt - 1)
+	if jitter > 0 {
+		delay += rand.N(jitter)
+	}
+	return delay
+}
+
+// nextDataScrape extracts the json data that imdb embeds in its pages to render them.
+func nextDataScrape(tab *rod.Page) ([]byte, error) {
+	script, err := tab.Element("script#__NEXT_DATA__")
+	if err != nil {
+		return nil, fmt.Errorf("failure finding page data: %w", err)
+	}
+	data, err := script.Text()
+	if err != nil {
+		return nil, fmt.Errorf("failure extracting page data: %w", err)
+	}
+	return []byte(data), nil
+}
+
+// listsParse extracts the id and name of the lists linked from a lists page.
+func listsParse(body io.Reader) ([]entities.IMDbList, error) {
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating goquery document from lists page: %w", err)
+	}
+	lists := make([]entities.IMDbList, 0)
+	doc.Find("a.ipc-metadata-list-summary-item__t").Each(func(_ int, hyperlink *goquery.Selection) {
+		href, ok := hyperlink.Attr("href")
+		if !ok {
+			return
+		}
+		lid, err := idExtract(href)
+		if err != nil || !strings.HasPrefix(lid, "ls") {
+			return
+		}
+		lists = append(lists, entities.IMDbList{
+			ListID:   lid,
+			ListName: strings.TrimSpace(hyperlink.Text()),
+		})
+	})
+	return lists, nil
+}
+
+func listPageParse(data []byte) (*entities.IMDbListPage, error) {
+	var page entities.IMDbListPage
+	if err := json.Unmarshal(data, &page); err != nil {
+		return nil, fmt.Errorf("failure unmarshalling list page data: %w", err)
+	}
+	return &page, nil
+}
+
+func chartPageParse(data []byte) (*entities.IMDbChartPage, error) {
+	var page entities.IMDbChartPage
+	if err := json.Unmarshal(data, &page); err != nil {
+		return nil, fmt.Errorf("failure unmarshalling chart page data: %w", err)
+	}
+	return &page, nil
+}
+
+// chartList wraps the ranked titles of a chart in a list named after the chart.
+func chartList(chart string, items []entities.IMDbItem) *entities.IMDbList {
+	name := imdbChartNames[chart]
+	return &entities.IMDbList{
+		ListID:      chart,
+		ListName:    name,
+		Description: fmt.Sprintf("Titles ranked on the %s chart", name),
+		ListItems:   items,
+	}
+}
+
+func ratingsPageParse(data []byte) (*entities.IMDbRatingsPage, error) {
+	var page entities.IMDbRatingsPage
+	if err := json.Unmarshal(data, &page); err != nil {
+		return nil, fmt.Errorf("failure unmarshalling ratings page data: %w", err)
+	}
+	return &page, nil
+}
+
+// reviewKindParse returns the title type of the reviewed item from the heading of the review, which mentions the title
+// type of anything but movies, such as "Breaking Bad (TV Series 2008–2013)".
+func reviewKindParse(heading string) string {
+	kinds := []struct {
+		label string
+		kind  string
+	}{
+		{label: "TV Mini Series", kind: "tvMiniSeries"},
+		{label: "TV Series", kind: "tvSeries"},
+		{label: "TV Episode", kind: "tvEpisode"},
+	}
+	for _, k := range kinds {
+		if strings.Contains(heading, k.label) {
+			return k.kind
+		}
+	}
+	return "movie"
+}
+
+func reviewScrape(article *rod.Element) (*entities.IMDbReview, error) {
+	hyperlink, err := article.Element("a[href^='/title/tt']")
+	if err != nil {
+		return nil, fmt.Errorf("failure finding title hyperlink: %w", err)
+	}
+	href, err := hyperlink.Attribute("href")
+	if err != nil {
+		return nil, fmt.Errorf("failure extracting href from hyperlink: %w", err)
+	}
+	itemID, err := idExtract(*href)
+	if err != nil {
+		return nil, fmt.Errorf("failure extracting title id from href: %w", err)
+	}
+	// the title type is shown next to the title of the reviewed item, unless the item is a movie
+	heading, err := hyperlink.Parent()
+	if err != nil {
+		return nil, fmt.Errorf("failure finding title heading: %w", err)
+	}
+	headingText, err := heading.Text()
+	if err != nil {
+		return nil, fmt.Errorf("failure extracting title heading: %w", err)
+	}
+	review := entities.IMDbReview{
+		ItemID: itemID,
+		Kind:   reviewKindParse(headingText),
+	}
+	hasSummary, summary, err := article.Has("[data-testid='review-summary']")
+	if err != nil {
+		return nil, fmt.Errorf("failure finding review summary: %w", err)
+	}
+	if hasSummary {
+		if review.Summary, err = summary.Text(); err != nil {
+			return nil, fmt.Errorf("failure extracting review summary: %w", err)
+		}
+	}
+	content, err := article.Element("div.ipc-html-content-inner-div")
+	if err != nil {
+		return nil, fmt.Errorf("failure finding review content: %w", err)
+	}
+	if review.Text, err = content.Text(); err != nil {
+		return nil, fmt.Errorf("failure extracting review content: %w", err)
+	}
+	if review.Spoiler, _, err = article.Has("[data-testid='review-spoiler-button']"); err != nil {
+		return nil, fmt.Errorf("failure finding review spoiler button: %w", err)
+	}
+	return &review, nil
+}
+
 func isListHyperlink(href string) bool {
 	return strings.HasPrefix(href, "/list/ls")
 }
@@ -617,60 +1505,187 @@ func isRatingsList(header []string) bool {
 	})
 }
 
-func transformData(data []byte) ([]entities.IMDbItem, error) {
-	csvReader := csv.NewReader(bytes.NewReader(data))
+// transformDownload parses a downloaded imdb export, which is either a csv file, or a zip archive holding the csv file
+// when the export was prepared by the export center.
+func transformDownload(data []byte) ([]entities.IMDbItem, error) {
+	if !bytes.HasPrefix(data, zipMagic) {
+		return transformData(bytes.NewReader(data))
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failure opening export archive: %w", err)
+	}
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || path.Ext(file.Name) != imdbFileExtension {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failure opening %s of export archive: %w", file.Name, err)
+		}
+		defer reader.Close()
+		return transformData(reader)
+	}
+	return nil, fmt.Errorf("export archive does not contain any csv file")
+}
+
+// transformData parses an imdb csv export record by record, instead of reading every record upfront, so that big
+// exports do not have to be held in memory twice. Gzip-compressed exports are decompressed on the fly.
+func transformData(r io.Reader) ([]entities.IMDbItem, error) {
+	r, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	csvReader := csv.NewReader(r)
 	csvReader.LazyQuotes = true
 	csvReader.FieldsPerRecord = -1
-	csvData, err := csvReader.ReadAll()
+	csvReader.ReuseRecord = true
+	header, err := csvReader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("expected csv records to have at least header row, but got empty result")
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failure reading csv records: %w", err)
 	}
-	if len(csvData) == 0 {
-		return nil, fmt.Errorf("expected csv records to have at least header row, but got empty result")
-	}
-	var (
-		header  = csvData[0]
-		records = csvData[1:]
-		items   = make([]entities.IMDbItem, len(records))
-	)
-	if isTitlesList(header) {
-		for i, record := range records {
-			items[i] = entities.IMDbItem{
-				ID:   record[1],
-				Kind: record[8],
-			}
+	var transform func(record []string) (*entities.IMDbItem, error)
+	switch {
+	case isTitlesList(header):
+		transform = titleItem
+	case isRatingsList(header):
+		transform = ratingItem
+	case isPeopleList(header):
+		transform = personItem
+	default:
+		return nil, fmt.Errorf("unrecognized list type with header %s", header)
+	}
+	items := make([]entities.IMDbItem, 0)
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			return items, nil
 		}
-		return items, nil
-	}
-	if isRatingsList(header) {
-		for i, record := range records {
-			rating, err := strconv.Atoi(record[1])
-			if err != nil {
-				return nil, fmt.Errorf("failure parsing rating value to integer: %w", err)
-			}
-			ratingDate, err := time.Parse(time.DateOnly, record[2])
-			if err != nil {
-				return nil, fmt.Errorf("failure parsing rating date: %w", err)
-			}
-			items[i] = entities.IMDbItem{
-				ID:         record[0],
-				Kind:       record[6],
-				Rating:     &rating,
-				RatingDate: &ratingDate,
-			}
+		if err != nil {
+			return nil, fmt.Errorf("failure reading csv records: %w", err)
 		}
-		return items, nil
-	}
-	if isPeopleList(header) {
-		for i, record := range records {
-			items[i] = entities.IMDbItem{
-				ID:   record[1],
-				Kind: "Person",
-			}
+		item, err := transform(record)
+		if err != nil {
+			return nil, err
 		}
-		return items, nil
+		items = append(items, *item)
+	}
+}
+
+func titleItem(record []string) (*entities.IMDbItem, error) {
+	position, err := strconv.Atoi(column(record, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failure parsing position value to integer: %w", err)
+	}
+	created, err := time.Parse(time.DateOnly, column(record, 2))
+	if err != nil {
+		return nil, fmt.Errorf("failure parsing created date: %w", err)
+	}
+	// lists that mix titles with people are exported with the columns of titles, leaving those of people empty
+	kind := column(record, 8)
+	if entities.IsIMDbPersonID(column(record, 1)) {
+		kind = "Person"
+	}
+	return &entities.IMDbItem{
+		ID:          column(record, 1),
+		Kind:        kind,
+		Title:       column(record, 5),
+		Description: column(record, 4),
+		Position:    &position,
+		Created:     &created,
+		ReleaseDate: optionalDate(column(record, 14)),
+		Runtime:     optionalInt(column(record, 10)),
+		Year:        optionalInt(column(record, 11)),
+		Genres:      splitValues(column(record, 12)),
+		IMDbRating:  optionalFloat(column(record, 9)),
+		NumVotes:    optionalInt(column(record, 13)),
+		Directors:   splitValues(column(record, 15)),
+	}, nil
+}
+
+func ratingItem(record []string) (*entities.IMDbItem, error) {
+	rating, err := strconv.Atoi(column(record, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failure parsing rating value to integer: %w", err)
+	}
+	ratingDate, err := time.Parse(time.DateOnly, column(record, 2))
+	if err != nil {
+		return nil, fmt.Errorf("failure parsing rating date: %w", err)
+	}
+	return &entities.IMDbItem{
+		ID:          column(record, 0),
+		Kind:        column(record, 6),
+		Title:       column(record, 3),
+		Rating:      &rating,
+		RatingDate:  &ratingDate,
+		ReleaseDate: optionalDate(column(record, 12)),
+		Runtime:     optionalInt(column(record, 8)),
+		Year:        optionalInt(column(record, 9)),
+		Genres:      splitValues(column(record, 10)),
+		IMDbRating:  optionalFloat(column(record, 7)),
+		NumVotes:    optionalInt(column(record, 11)),
+		Directors:   splitValues(column(record, 13)),
+	}, nil
+}
+
+func personItem(record []string) (*entities.IMDbItem, error) {
+	position, err := strconv.Atoi(column(record, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failure parsing position value to integer: %w", err)
+	}
+	return &entities.IMDbItem{
+		ID:          column(record, 1),
+		Kind:        "Person",
+		Description: column(record, 4),
+		Position:    &position,
+	}, nil
+}
+
+// column returns the value of the column at the given index, or an empty string when the record is shorter, since
+// rows of mixed lists do not always have a value for every column.
+func column(record []string, index int) string {
+	if index >= len(record) {
+		return ""
+	}
+	return record[index]
+}
+
+// optionalDate parses an optional date column, such as the release date, which is empty for unreleased titles.
+func optionalDate(value string) *time.Time {
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil
+	}
+	return &date
+}
+
+// optionalInt parses an optional numeric column, such as the year or runtime, which is empty for some titles.
+func optionalInt(value string) *int {
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return nil
+	}
+	return &number
+}
+
+// optionalFloat parses an optional decimal column, such as the imdb rating, which is empty for titles without votes.
+func optionalFloat(value string) *float64 {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return &number
+}
+
+// splitValues splits a column that holds several values, such as the genres or directors of a title.
+func splitValues(value string) []string {
+	if value == "" {
+		return nil
 	}
-	return nil, fmt.Errorf("unrecognized list type with header %s", header)
+	return strings.Split(value, ", ")
 }
 
 func idExtract(href string) (string, error) {
@@ -694,20 +1709,20 @@ func buildSelector(ids ...string) string {
 	return fmt.Sprintf(format, selectors.String())
 }
 
-func setBrowserCookies(browser *rod.Browser, config *appconfig.IMDb) error {
-	cookies := []*proto.NetworkCookieParam{
+func setBrowserCookies(browser *rod.Browser, cookies imdbCookies) error {
+	params := []*proto.NetworkCookieParam{
 		{
 			Name:   imdbCookieNameAtMain,
-			Value:  *config.CookieAtMain,
+			Value:  cookies.AtMain,
 			Domain: imdbCookieDomain,
 		},
 		{
 			Name:   imdbCookieNameUbidMain,
-			Value:  *config.CookieUbidMain,
+			Value:  cookies.UbidMain,
 			Domain: imdbCookieDomain,
 		},
 	}
-	if err := browser.SetCookies(cookies); err != nil {
+	if err := browser.SetCookies(params); err != nil {
 		return fmt.Errorf("failure setting browser cookies: %w", err)
 	}
 	return nil
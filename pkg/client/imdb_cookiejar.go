@@ -0,0 +1,198 @@
+package client
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+)
+
+const imdbStateKeyCookieJar = "imdb-cookie-jar"
+
+// imdbCookieJar keeps the imdb cookies between runs, updated from the Set-Cookie headers that imdb responds with, so
+// that the session that imdb extends while it is in use outlives the cookies that were configured initially.
+type imdbCookieJar struct {
+	mu sync.Mutex
+	// Seed is the configured at-main cookie that the jar was started from. A different configured cookie means that the
+	// user refreshed it manually, and the jar is started over from it.
+	Seed      string          `json:"seed"`
+	Cookies   []imdbJarCookie `json:"cookies"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+type imdbJarCookie struct {
+	Name     string     `json:"name"`
+	Value    string     `json:"value"`
+	Domain   string     `json:"domain"`
+	Path     string     `json:"path"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	Secure   bool       `json:"secure"`
+	HTTPOnly bool       `json:"httpOnly"`
+}
+
+// loadIMDbCookieJar loads the jar of the previous run, and drops the cookies that have expired since. An empty jar is
+// returned when there is no jar yet, or when it was started from another seed.
+func loadIMDbCookieJar(store state.Store, seed string) (*imdbCookieJar, error) {
+	jar := &imdbCookieJar{
+		Seed: seed,
+	}
+	var stored imdbCookieJar
+	if err := store.Load(imdbStateKeyCookieJar, &stored); err != nil {
+		if errors.Is(err, state.ErrNotFound) {
+			return jar, nil
+		}
+		return nil, fmt.Errorf("failure loading imdb cookie jar: %w", err)
+	}
+	if stored.Seed != seed {
+		return jar, nil
+	}
+	now := time.Now()
+	for _, cookie := range stored.Cookies {
+		if cookie.Expires == nil || cookie.Expires.After(now) {
+			jar.Cookies = append(jar.Cookies, cookie)
+		}
+	}
+	jar.UpdatedAt = stored.UpdatedAt
+	return jar, nil
+}
+
+func (j *imdbCookieJar) save(store state.Store) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.UpdatedAt = time.Now().UTC()
+	if err := store.Save(imdbStateKeyCookieJar, j); err != nil {
+		return fmt.Errorf("failure storing imdb cookie jar: %w", err)
+	}
+	return nil
+}
+
+// value returns the value of the named cookie, or an empty string when the jar does not have it.
+func (j *imdbCookieJar) value(name string) string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, cookie := range j.Cookies {
+		if cookie.Name == name {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// set adds the cookie to the jar, replacing the cookie with the same name, domain and path. A cookie that has expired
+// removes the one it replaces instead.
+func (j *imdbCookieJar) set(cookie imdbJarCookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Cookies = slices.DeleteFunc(j.Cookies, func(c imdbJarCookie) bool {
+		return c.Name == cookie.Name && c.Domain == cookie.Domain && c.Path == cookie.Path
+	})
+	if cookie.Expires != nil && !cookie.Expires.After(time.Now()) {
+		return
+	}
+	j.Cookies = append(j.Cookies, cookie)
+}
+
+// update applies the Set-Cookie headers of a response to the jar, ignoring the cookies of other domains.
+func (j *imdbCookieJar) update(cookies []*http.Cookie) {
+	for _, cookie := range cookies {
+		domain := cookie.Domain
+		if domain == "" {
+			domain = imdbCookieDomain
+		}
+		if !isIMDbCookieDomain(domain) {
+			continue
+		}
+		jarCookie := imdbJarCookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   domain,
+			Path:     cmp.Or(cookie.Path, "/"),
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HttpOnly,
+		}
+		switch {
+		case cookie.MaxAge < 0:
+			expires := time.Unix(0, 0)
+			jarCookie.Expires = &expires
+		case cookie.MaxAge > 0:
+			expires := time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+			jarCookie.Expires = &expires
+		case !cookie.Expires.IsZero():
+			expires := cookie.Expires
+			jarCookie.Expires = &expires
+		}
+		j.set(jarCookie)
+	}
+}
+
+// updateFromBrowser replaces the cookies of the jar with the imdb cookies of the browser, which has applied the
+// Set-Cookie headers of every page it loaded.
+func (j *imdbCookieJar) updateFromBrowser(cookies []*proto.NetworkCookie) {
+	jarCookies := make([]imdbJarCookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		if !isIMDbCookieDomain(cookie.Domain) {
+			continue
+		}
+		jarCookie := imdbJarCookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HTTPOnly,
+		}
+		if !cookie.Session && cookie.Expires > 0 {
+			expires := cookie.Expires.Time()
+			jarCookie.Expires = &expires
+		}
+		jarCookies = append(jarCookies, jarCookie)
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Cookies = jarCookies
+}
+
+// browserParams returns the cookies of the jar in the form that the browser sets them in.
+func (j *imdbCookieJar) browserParams() []*proto.NetworkCookieParam {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	params := make([]*proto.NetworkCookieParam, 0, len(j.Cookies))
+	for _, cookie := range j.Cookies {
+		param := &proto.NetworkCookieParam{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HTTPOnly,
+		}
+		if cookie.Expires != nil {
+			param.Expires = proto.TimeSinceEpoch(cookie.Expires.Unix())
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// header returns the cookies of the jar as the value of a Cookie request header.
+func (j *imdbCookieJar) header() string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	pairs := make([]string, 0, len(j.Cookies))
+	for _, cookie := range j.Cookies {
+		pairs = append(pairs, cookie.Name+"="+cookie.Value)
+	}
+	return strings.Join(pairs, "; ")
+}
+
+func isIMDbCookieDomain(domain string) bool {
+	return strings.TrimPrefix(domain, ".") == strings.TrimPrefix(imdbCookieDomain, ".") || strings.HasSuffix(domain, imdbCookieDomain)
+}
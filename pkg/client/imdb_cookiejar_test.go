@@ -0,0 +1,82 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+)
+
+func Test_imdbCookieJar(t *testing.T) {
+	tests := []struct {
+		name       string
+		seed       string
+		update     []*http.Cookie
+		assertions func(*assert.Assertions, *imdbCookieJar)
+	}{
+		{
+			name: "persist renewed cookies",
+			seed: "seed",
+			update: []*http.Cookie{
+				{Name: imdbCookieNameAtMain, Value: "renewed", Domain: imdbCookieDomain, MaxAge: 3600},
+				{Name: imdbCookieNameUbidMain, Value: "ubid", Domain: "www.imdb.com"},
+			},
+			assertions: func(assertions *assert.Assertions, jar *imdbCookieJar) {
+				assertions.Equal("renewed", jar.value(imdbCookieNameAtMain))
+				assertions.Equal("ubid", jar.value(imdbCookieNameUbidMain))
+				assertions.Contains(jar.header(), imdbCookieNameAtMain+"=renewed")
+				assertions.Len(jar.browserParams(), 2)
+			},
+		},
+		{
+			name: "drop expired and deleted cookies",
+			seed: "seed",
+			update: []*http.Cookie{
+				{Name: imdbCookieNameAtMain, Value: "expired", Domain: imdbCookieDomain, Expires: time.Now().Add(-time.Hour)},
+				{Name: imdbCookieNameUbidMain, Value: "ubid", Domain: imdbCookieDomain},
+				{Name: imdbCookieNameUbidMain, Value: "", Domain: imdbCookieDomain, MaxAge: -1},
+			},
+			assertions: func(assertions *assert.Assertions, jar *imdbCookieJar) {
+				assertions.Empty(jar.Cookies)
+			},
+		},
+		{
+			name: "ignore cookies of other domains",
+			seed: "seed",
+			update: []*http.Cookie{
+				{Name: imdbCookieNameAtMain, Value: "foreign", Domain: "notimdb.com"},
+			},
+			assertions: func(assertions *assert.Assertions, jar *imdbCookieJar) {
+				assertions.Empty(jar.Cookies)
+			},
+		},
+		{
+			name: "start over from another seed",
+			seed: "another",
+			update: []*http.Cookie{
+				{Name: imdbCookieNameAtMain, Value: "renewed", Domain: imdbCookieDomain},
+			},
+			assertions: func(assertions *assert.Assertions, jar *imdbCookieJar) {
+				assertions.Empty(jar.Cookies)
+				assertions.Equal("another", jar.Seed)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertions := assert.New(t)
+			store, err := state.NewFileStore(t.TempDir())
+			assertions.NoError(err)
+			jar, err := loadIMDbCookieJar(store, "seed")
+			assertions.NoError(err)
+			jar.update(tt.update)
+			assertions.NoError(jar.save(store))
+			loaded, err := loadIMDbCookieJar(store, tt.seed)
+			assertions.NoError(err)
+			tt.assertions(assertions, loaded)
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package client
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+)
+
+const (
+	imdbFileCheckins    = "checkins.csv"
+	imdbFileRatings     = "ratings.csv"
+	imdbFileWatchlist   = "watchlist.csv"
+	imdbDirLists        = "lists"
+	imdbFileExtension   = ".csv"
+	imdbWatchlistFileID = "watchlist"
+	imdbCheckinsFileID  = "checkins"
+)
+
+var (
+	errIMDbFileReadOnly = errors.New("imdb exports are read-only")
+	errIMDbFileNoCharts = errors.New("imdb exports do not include charts")
+)
+
+var imdbListFileRegex = regexp.MustCompile(`^(ls[0-9]+)[-_ ]*(.*)$`)
+
+// IMDbFileClient reads imdb data from a directory or zip archive of csv exports, instead of scraping imdb.
+type IMDbFileClient struct {
+	config *appconfig.IMDb
+	logger *slog.Logger
+	fsys   fs.FS
+	closer io.Closer
+	lists  map[string]imdbListFile
+}
+
+type imdbListFile struct {
+	name string
+	path string
+}
+
+func NewIMDbFileClient(conf *appconfig.IMDb, logger *slog.Logger) (IMDbClientInterface, error) {
+	exportPath := *conf.ExportDir
+	info, err := os.Stat(exportPath)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading imdb export path: %w", err)
+	}
+	c := &IMDbFileClient{
+		config: conf,
+		logger: logger,
+	}
+	if info.IsDir() {
+		c.fsys = os.DirFS(exportPath)
+	} else {
+		archive, err := zip.OpenReader(exportPath)
+		if err != nil {
+			return nil, fmt.Errorf("failure opening imdb export archive: %w", err)
+		}
+		c.fsys = archive
+		c.closer = archive
+	}
+	if err = c.hydrate(); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failure hydrating client: %w", err)
+	}
+	return c, nil
+}
+
+func (c *IMDbFileClient) hydrate() error {
+	c.lists = make(map[string]imdbListFile)
+	entries, err := fs.ReadDir(c.fsys, imdbDirLists)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failure reading imdb lists directory: %w", err)
+	}
+	lids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != imdbFileExtension {
+			continue
+		}
+		matches := imdbListFileRegex.FindStringSubmatch(strings.TrimSuffix(entry.Name(), imdbFileExtension))
+		if matches == nil {
+			c.logger.Warn("skipping imdb list file without list id prefix", slog.String("file", entry.Name()))
+			continue
+		}
+		lid, listName := matches[1], matches[2]
+		if listName == "" {
+			listName = lid
+		}
+		c.lists[lid] = imdbListFile{
+			name: listName,
+			path: path.Join(imdbDirLists, entry.Name()),
+		}
+		lids = append(lids, lid)
+	}
+	if len(*c.config.Lists) == 0 || c.config.AllLists() {
+		c.config.Lists = &lids
+	}
+	c.logger.Info("hydrated imdb file client", slog.String("path", *c.config.ExportDir), slog.Any("lists", *c.config.Lists))
+	return nil
+}
+
+func (c *IMDbFileClient) ListsExport(...string) error {
+	return nil
+}
+
+func (c *IMDbFileClient) ListsGet(ids ...string) ([]entities.IMDbList, error) {
+	lists := make([]entities.IMDbList, 0, len(ids))
+	for _, id := range ids {
+		listFile, ok := c.lists[id]
+		if !ok {
+			return nil, fmt.Errorf("failure finding csv file for list %s in directory %s", id, imdbDirLists)
+		}
+		items, err := c.readFile(listFile.path)
+		if err != nil {
+			return nil, fmt.Errorf("failure reading list %s: %w", id, err)
+		}
+		lists = append(lists, entities.IMDbList{
+			ListID:    id,
+			ListName:  listFile.name,
+			ListItems: items,
+		})
+	}
+	return lists, nil
+}
+
+// ListsGetAll returns the id and name of every list file, without reading the list items.
+func (c *IMDbFileClient) ListsGetAll() ([]entities.IMDbList, error) {
+	lists := make([]entities.IMDbList, 0, len(c.lists))
+	for id, listFile := range c.lists {
+		lists = append(lists, entities.IMDbList{
+			ListID:   id,
+			ListName: listFile.name,
+		})
+	}
+	slices.SortFunc(lists, func(a, b entities.IMDbList) int {
+		return strings.Compare(a.ListID, b.ListID)
+	})
+	return lists, nil
+}
+
+func (c *IMDbFileClient) ListCreate(string, string) (string, error) {
+	return "", errIMDbFileReadOnly
+}
+
+func (c *IMDbFileClient) ListItemsAdd(string, ...string) error {
+	return errIMDbFileReadOnly
+}
+
+func (c *IMDbFileClient) ListItemsRemove(string, ...string) error {
+	return errIMDbFileReadOnly
+}
+
+// ListsModified returns no modification dates, because imdb does not include them in csv exports.
+func (c *IMDbFileClient) ListsModified(...string) (map[string]time.Time, error) {
+	return make(map[string]time.Time), nil
+}
+
+func (c *IMDbFileClient) WatchlistExport() error {
+	return nil
+}
+
+func (c *IMDbFileClient) WatchlistGet() (*entities.IMDbList, error) {
+	items, err := c.readFile(imdbFileWatchlist)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading watchlist: %w", err)
+	}
+	return &entities.IMDbList{
+		ListID:      imdbWatchlistFileID,
+		ListName:    imdbWatchlistFileID,
+		ListItems:   items,
+		IsWatchlist: true,
+	}, nil
+}
+
+func (c *IMDbFileClient) RatingsExport() error {
+	return nil
+}
+
+func (c *IMDbFileClient) RatingsGet() ([]entities.IMDbItem, error) {
+	items, err := c.readFile(imdbFileRatings)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading ratings: %w", err)
+	}
+	return items, nil
+}
+
+func (c *IMDbFileClient) ChartGet(string) (*entities.IMDbList, error) {
+	return nil, errIMDbFileNoCharts
+}
+
+// ReviewsGet returns no reviews, because imdb does not include them in csv exports.
+func (c *IMDbFileClient) ReviewsGet() ([]entities.IMDbReview, error) {
+	return make([]entities.IMDbReview, 0), nil
+}
+
+func (c *IMDbFileClient) CheckinsExport() error {
+	return nil
+}
+
+func (c *IMDbFileClient) CheckinsGet() (*entities.IMDbList, error) {
+	items, err := c.readFile(imdbFileCheckins)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading check-ins: %w", err)
+	}
+	return &entities.IMDbList{
+		ListID:    imdbCheckinsFileID,
+		ListName:  imdbCheckinsFileID,
+		ListItems: items,
+	}, nil
+}
+
+func (c *IMDbFileClient) Close() {
+	if c.closer == nil {
+		return
+	}
+	if err := c.closer.Close(); err != nil {
+		c.logger.Warn("failure closing imdb export archive", slog.Any("error", err))
+	}
+}
+
+func (c *IMDbFileClient) readFile(name string) ([]entities.IMDbItem, error) {
+	file, err := c.fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading file %s: %w", name, err)
+	}
+	defer file.Close()
+	items, err := transformData(file)
+	if err != nil {
+		return nil, fmt.Errorf("failure transforming data from file %s: %w", name, err)
+	}
+	c.logger.Info("read imdb export file", slog.String("file", name), slog.Int("count", len(items)))
+	return items, nil
+}
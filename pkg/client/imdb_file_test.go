@@ -0,0 +1,132 @@
+package client
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+const (
+	dummyTitlesCSV = `Position,Const,Created,Modified,Description,Title,Original Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors,Your Rating,Date Rated
+1,tt5013056,2023-08-03,2023-08-03,,Dunkirk,Dunkirk,https://www.imdb.com/title/tt5013056/,Movie,7.8,106,2017,"Action, Drama, History, Thriller, War",718267,2017-07-13,Christopher Nolan,8,2017-12-25
+`
+	dummyRatingsCSV = `Const,Your Rating,Date Rated,Title,Original Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors
+tt15398776,6,2023-11-25,Oppenheimer,Oppenheimer,https://www.imdb.com/title/tt15398776/,Movie,8.5,180,2023,"Biography, Drama, History",513747,2023-07-11,Christopher Nolan
+`
+)
+
+var dummyExportFiles = map[string]string{
+	imdbFileRatings:                     dummyRatingsCSV,
+	imdbFileWatchlist:                   dummyTitlesCSV,
+	"lists/ls123456789 My List.csv":     dummyTitlesCSV,
+	"lists/ls987654321.csv":             dummyTitlesCSV,
+	"lists/without-identifier-list.csv": dummyTitlesCSV,
+}
+
+func writeDummyExportDir(t *testing.T) string {
+	dir := t.TempDir()
+	for name, content := range dummyExportFiles {
+		path := filepath.Join(dir, name)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.Nil(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func writeDummyExportArchive(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "exports.zip")
+	file, err := os.Create(path)
+	require.Nil(t, err)
+	defer file.Close()
+	writer := zip.NewWriter(file)
+	for name, content := range dummyExportFiles {
+		w, err := writer.Create(name)
+		require.Nil(t, err)
+		_, err = w.Write([]byte(content))
+		require.Nil(t, err)
+	}
+	require.Nil(t, writer.Close())
+	return path
+}
+
+func TestNewIMDbFileClient(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func(*testing.T) string
+		assertions   func(*assert.Assertions, IMDbClientInterface, error)
+	}{
+		{
+			name:         "success with directory",
+			requirements: writeDummyExportDir,
+			assertions: func(assertions *assert.Assertions, c IMDbClientInterface, err error) {
+				assertions.Nil(err)
+				defer c.Close()
+				lists, err := c.ListsGet("ls123456789", "ls987654321")
+				assertions.Nil(err)
+				assertions.Len(lists, 2)
+				assertions.Equal("My List", lists[0].ListName)
+				assertions.Equal("ls987654321", lists[1].ListName)
+				allLists, err := c.ListsGetAll()
+				assertions.Nil(err)
+				assertions.Equal("ls123456789", allLists[0].ListID)
+				assertions.Equal("My List", allLists[0].ListName)
+				assertions.Empty(allLists[0].ListItems)
+				assertions.Equal("ls987654321", allLists[1].ListID)
+				watchlist, err := c.WatchlistGet()
+				assertions.Nil(err)
+				assertions.True(watchlist.IsWatchlist)
+				assertions.Len(watchlist.ListItems, 1)
+				ratings, err := c.RatingsGet()
+				assertions.Nil(err)
+				assertions.Len(ratings, 1)
+				_, err = c.CheckinsGet()
+				assertions.ErrorContains(err, "failure reading check-ins")
+			},
+		},
+		{
+			name:         "success with zip archive",
+			requirements: writeDummyExportArchive,
+			assertions: func(assertions *assert.Assertions, c IMDbClientInterface, err error) {
+				assertions.Nil(err)
+				defer c.Close()
+				lists, err := c.ListsGet("ls123456789")
+				assertions.Nil(err)
+				assertions.Len(lists, 1)
+				_, err = c.ListsGet("ls000000001")
+				assertions.ErrorContains(err, "failure finding csv file")
+			},
+		},
+		{
+			name: "failure with missing path",
+			requirements: func(t *testing.T) string {
+				return filepath.Join(t.TempDir(), "missing")
+			},
+			assertions: func(assertions *assert.Assertions, c IMDbClientInterface, err error) {
+				assertions.Nil(c)
+				assertions.ErrorContains(err, "failure reading imdb export path")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exportDir := tt.requirements(t)
+			conf := &appconfig.IMDb{
+				ExportDir: &exportDir,
+				Lists:     pointer(make([]string, 0)),
+			}
+			c, err := NewIMDbFileClient(conf, logger.NewLogger(io.Discard))
+			tt.assertions(assert.New(t), c, err)
+			if err == nil {
+				assert.ElementsMatch(t, []string{"ls123456789", "ls987654321"}, *conf.Lists)
+			}
+		})
+	}
+}
@@ -0,0 +1,690 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+)
+
+const (
+	imdbGraphQLPathBase              = "https://caching.graphql.imdb.com/"
+	imdbGraphQLPageSize              = 250
+	imdbGraphQLClassTypeWatchlist    = "WATCH_LIST"
+	imdbGraphQLClassTypeCheckins     = "CHECK_INS"
+	imdbGraphQLListVisibilityPrivate = "PRIVATE"
+	imdbGraphQLHeaderKeyCookie       = "Cookie"
+	imdbGraphQLHeaderKeyContent      = "Content-Type"
+	imdbGraphQLOperationChart        = "Chart"
+	imdbGraphQLOperationList         = "List"
+	imdbGraphQLOperationListAdd      = "AddConstToList"
+	imdbGraphQLOperationListCreate   = "CreateList"
+	imdbGraphQLOperationListRemove   = "RemoveConstFromList"
+	imdbGraphQLOperationPredefined   = "PredefinedList"
+	imdbGraphQLOperationRatings      = "Ratings"
+
+	imdbGraphQLQueryPredefinedList = `query PredefinedList($classType: ListClassId!) {
+  predefinedList(classType: $classType) {
+    id
+    author {
+      userId
+    }
+  }
+}`
+	imdbGraphQLQueryList = `query List($id: ID!, $first: Int!, $after: String) {
+  list(id: $id) {
+    id
+    name {
+      originalText
+    }
+    description {
+      originalText {
+        plainText
+      }
+    }
+    lastModifiedDate
+    titleListItemSearch(first: $first, after: $after) {
+      pageInfo {
+        endCursor
+        hasNextPage
+      }
+      edges {
+        createdDate
+        description {
+          originalText {
+            plainText
+          }
+        }
+        listItem {
+          __typename
+          ... on Title {
+            id
+            titleType {
+              id
+            }
+            releaseDate {
+              year
+              month
+              day
+            }
+          }
+          ... on Name {
+            id
+          }
+        }
+      }
+    }
+  }
+}`
+	imdbGraphQLQueryChart = `query Chart($chartType: ChartTitleType!, $first: Int!) {
+  chartTitles(first: $first, chart: {chartType: $chartType}) {
+    edges {
+      node {
+        id
+        titleType {
+          id
+        }
+        titleText {
+          text
+        }
+        releaseDate {
+          year
+          month
+          day
+        }
+        ratingsSummary {
+          aggregateRating
+          voteCount
+        }
+      }
+    }
+  }
+}`
+	imdbGraphQLMutationListAdd = `mutation AddConstToList($listId: ID!, $constId: ID!) {
+  addItemToList(input: {listId: $listId, item: {itemElementId: $constId}}) {
+    listId
+  }
+}`
+	imdbGraphQLMutationListCreate = `mutation CreateList($name: String!, $description: String, $visibility: ListVisibilityId!) {
+  createList(input: {name: $name, description: $description, listType: TITLES, visibility: $visibility}) {
+    listId
+  }
+}`
+	imdbGraphQLMutationListRemove = `mutation RemoveConstFromList($listId: ID!, $constId: ID!) {
+  removeElementFromList(input: {listId: $listId, itemElementId: $constId}) {
+    listId
+  }
+}`
+	imdbGraphQLQueryRatings = `query Ratings($userId: ID!, $first: Int!, $after: String) {
+  advancedTitleSearch(
+    first: $first
+    after: $after
+    constraints: {userRatingsConstraint: {ratedTitlesConstraint: {anyUserIds: [$userId]}}}
+    sort: {sortBy: USER_RATING_DATE, sortOrder: DESC}
+  ) {
+    pageInfo {
+      endCursor
+      hasNextPage
+    }
+    edges {
+      node {
+        title {
+          id
+          titleType {
+            id
+          }
+          userRating {
+            value
+            date
+          }
+        }
+      }
+    }
+  }
+}`
+)
+
+// imdbGraphQLChartTypes are the graphql chart types of the charts that can be synced.
+var imdbGraphQLChartTypes = map[string]string{
+	appconfig.IMDbChartTop:        "TOP_RATED_MOVIES",
+	appconfig.IMDbChartMovieMeter: "MOST_POPULAR_MOVIES",
+	appconfig.IMDbChartTVMeter:    "MOST_POPULAR_TV_SHOWS",
+}
+
+// IMDbGraphQLClient fetches imdb data from the graphql api that powers the imdb website, instead of exporting csv
+// files through a browser. Lists and the public watchlist are available without authentication, while ratings and
+// check-ins require the authentication cookies.
+type IMDbGraphQLClient struct {
+	ctx     context.Context
+	client  *http.Client
+	config  *imdbConfig
+	logger  *slog.Logger
+	store   state.Store
+	cookies *imdbCookies
+	jar     *imdbCookieJar
+}
+
+func NewIMDbGraphQLClient(ctx context.Context, conf *appconfig.IMDb, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger) (IMDbClientInterface, error) {
+	transport, err := newTransport(clientNameIMDb, httpConf, store)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating transport: %w", err)
+	}
+	c := &IMDbGraphQLClient{
+		ctx: ctx,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   httpConf.ClientTimeout(clientNameIMDb),
+		},
+		config: &imdbConfig{
+			IMDb:    conf,
+			timeout: httpConf.ClientTimeout(clientNameIMDb),
+		},
+		logger: logger,
+		store:  store,
+	}
+	if err := c.authenticateUser(); err != nil {
+		return nil, fmt.Errorf("failure authenticating user: %w", err)
+	}
+	if err := c.hydrate(); err != nil {
+		return nil, fmt.Errorf("failure hydrating client: %w", err)
+	}
+	return c, nil
+}
+
+// authenticateUser picks the cookies to send along with every request, preferring the ones that were refreshed by
+// the browser client, since the graphql api has no way of signing in.
+func (c *IMDbGraphQLClient) authenticateUser() error {
+	if *c.config.Auth != appconfig.IMDbAuthMethodCookies {
+		return nil
+	}
+	cookies := imdbCookies{
+		AtMain:   *c.config.CookieAtMain,
+		UbidMain: *c.config.CookieUbidMain,
+	}
+	if *c.config.CookieRefresh {
+		if err := c.store.Load(imdbStateKeyCookies, &cookies); err != nil && !errors.Is(err, state.ErrNotFound) {
+			return fmt.Errorf("failure loading stored imdb cookies: %w", err)
+		}
+	}
+	if *c.config.CookieJar {
+		jar, err := loadIMDbCookieJar(c.store, *c.config.CookieAtMain)
+		if err != nil {
+			return err
+		}
+		if jar.value(imdbCookieNameAtMain) == "" || jar.value(imdbCookieNameUbidMain) == "" {
+			jar.update([]*http.Cookie{
+				{Name: imdbCookieNameAtMain, Value: cookies.AtMain, Domain: imdbCookieDomain},
+				{Name: imdbCookieNameUbidMain, Value: cookies.UbidMain, Domain: imdbCookieDomain},
+			})
+		}
+		cookies.AtMain = jar.value(imdbCookieNameAtMain)
+		cookies.UbidMain = jar.value(imdbCookieNameUbidMain)
+		c.jar = jar
+	}
+	c.cookies = &cookies
+	return nil
+}
+
+func (c *IMDbGraphQLClient) hydrate() error {
+	if c.cookies == nil {
+		if *c.config.UserID == "" {
+			return nil
+		}
+		c.config.userID = *c.config.UserID
+		watchlistID, err := c.watchlistIDFetch()
+		if err != nil {
+			return fmt.Errorf("failure fetching watchlist id: %w", err)
+		}
+		c.config.watchlistID = watchlistID
+		if c.config.AllLists() {
+			if err = c.allListsResolve(); err != nil {
+				return err
+			}
+		}
+		c.logger.Info("hydrated imdb graphql client", slog.String("userID", c.config.userID), slog.String("watchlistID", watchlistID), slog.Any("lists", *c.config.Lists))
+		return nil
+	}
+	watchlist, err := c.predefinedListGet(imdbGraphQLClassTypeWatchlist)
+	if err != nil {
+		return fmt.Errorf("failure fetching watchlist, make sure that the imdb cookies are valid: %w", err)
+	}
+	c.config.userID = watchlist.Author.UserID
+	c.config.watchlistID = watchlist.ID
+	if c.config.AllLists() || len(*c.config.Lists) == 0 {
+		if err = c.allListsResolve(); err != nil {
+			return err
+		}
+	}
+	lids := slices.DeleteFunc(*c.config.Lists, func(lid string) bool {
+		if lid == c.config.watchlistID {
+			c.logger.Warn("removing watchlist id from provided lists; please use config option SYNC_WATCHLIST instead")
+			return true
+		}
+		return false
+	})
+	c.config.Lists = &lids
+	c.logger.Info("hydrated imdb graphql client", slog.String("userID", c.config.userID), slog.String("watchlistID", c.config.watchlistID), slog.Any("lists", lids))
+	return nil
+}
+
+// allListsResolve replaces the configured lists with the ids of every list of the user.
+func (c *IMDbGraphQLClient) allListsResolve() error {
+	lists, err := c.ListsGetAll()
+	if err != nil {
+		return fmt.Errorf("failure discovering all lists: %w", err)
+	}
+	lids := make([]string, 0, len(lists))
+	for _, list := range lists {
+		if list.ListID != c.config.watchlistID {
+			lids = append(lids, list.ListID)
+		}
+	}
+	c.config.Lists = &lids
+	return nil
+}
+
+// watchlistIDFetch resolves the list id of a public watchlist from the id of its owner, since the graphql api only
+// exposes the watchlist of the authenticated user.
+func (c *IMDbGraphQLClient) watchlistIDFetch() (string, error) {
+	response, err := c.doRequest(http.MethodGet, imdbPathBase+fmt.Sprintf(imdbPathUserWatchlist, c.config.userID), nil)
+	if err != nil {
+		return "", err
+	}
+	data, err := nextDataParse(response.Body)
+	if err != nil {
+		return "", err
+	}
+	page, err := listPageParse(data)
+	if err != nil {
+		return "", err
+	}
+	watchlistID := page.Props.PageProps.MainColumnData.List.ID
+	if !strings.HasPrefix(watchlistID, "ls") {
+		return "", fmt.Errorf("failure finding watchlist of user %s, make sure that the watchlist is public", c.config.userID)
+	}
+	return watchlistID, nil
+}
+
+func (c *IMDbGraphQLClient) ListsExport(...string) error {
+	return nil
+}
+
+func (c *IMDbGraphQLClient) ListsGet(ids ...string) ([]entities.IMDbList, error) {
+	lists := make([]entities.IMDbList, 0, len(ids))
+	for _, id := range ids {
+		list, err := c.listGet(id)
+		if err != nil {
+			return nil, fmt.Errorf("failure fetching list %s: %w", id, err)
+		}
+		lists = append(lists, *list)
+	}
+	return lists, nil
+}
+
+// ListsGetAll returns the id and name of every public list of the user, without the list items. The graphql api does
+// not expose the lists of a user, so the paginated lists page is parsed instead.
+func (c *IMDbGraphQLClient) ListsGetAll() ([]entities.IMDbList, error) {
+	lists := make([]entities.IMDbList, 0)
+	seen := make(map[string]struct{})
+	for page := 1; ; page++ {
+		response, err := c.doRequest(http.MethodGet, imdbPathBase+fmt.Sprintf(imdbPathUserLists, c.config.userID, page), nil)
+		if err != nil {
+			return nil, err
+		}
+		pageLists, err := listsParse(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		var added int
+		for _, list := range pageLists {
+			if _, ok := seen[list.ListID]; ok {
+				continue
+			}
+			seen[list.ListID] = struct{}{}
+			lists = append(lists, list)
+			added++
+		}
+		if added == 0 {
+			break
+		}
+	}
+	c.logger.Info("discovered imdb lists", slog.Int("count", len(lists)))
+	return lists, nil
+}
+
+// ListsModified returns the time each list was last modified, which only requires the first page of each list.
+// Lists without a modification date are left out of the result.
+func (c *IMDbGraphQLClient) ListsModified(ids ...string) (map[string]time.Time, error) {
+	modified := make(map[string]time.Time, len(ids))
+	for _, id := range ids {
+		page, err := c.listPageGet(id, "", 1)
+		if err != nil {
+			return nil, fmt.Errorf("failure fetching list %s: %w", id, err)
+		}
+		lastModified, err := page.LastModified()
+		if err != nil {
+			return nil, fmt.Errorf("failure reading modification date of list %s: %w", id, err)
+		}
+		if lastModified != nil {
+			modified[id] = *lastModified
+		}
+	}
+	return modified, nil
+}
+
+// ListCreate creates a private list of titles, and returns the id of the created list.
+func (c *IMDbGraphQLClient) ListCreate(name, description string) (string, error) {
+	if c.cookies == nil {
+		return "", fmt.Errorf("failure creating list %s, since creating lists requires imdb authentication", name)
+	}
+	variables := map[string]any{
+		"name":        name,
+		"description": description,
+		"visibility":  imdbGraphQLListVisibilityPrivate,
+	}
+	data, err := graphqlQuery[entities.IMDbGraphQLListCreate](c, imdbGraphQLOperationListCreate, imdbGraphQLMutationListCreate, variables)
+	if err != nil {
+		return "", fmt.Errorf("failure creating list %s: %w", name, err)
+	}
+	if data.CreateList == nil || data.CreateList.ListID == "" {
+		return "", fmt.Errorf("failure creating list %s: response is missing the list id", name)
+	}
+	c.logger.Info("created imdb list", slog.String("id", data.CreateList.ListID), slog.String("name", name))
+	return data.CreateList.ListID, nil
+}
+
+// ListItemsAdd adds the titles to the list one at a time, since the api does not support adding them in bulk.
+func (c *IMDbGraphQLClient) ListItemsAdd(id string, itemIDs ...string) error {
+	return c.listItemsEdit(imdbGraphQLOperationListAdd, imdbGraphQLMutationListAdd, id, itemIDs)
+}
+
+// ListItemsRemove removes the titles from the list one at a time, since the api does not support removing them in bulk.
+func (c *IMDbGraphQLClient) ListItemsRemove(id string, itemIDs ...string) error {
+	return c.listItemsEdit(imdbGraphQLOperationListRemove, imdbGraphQLMutationListRemove, id, itemIDs)
+}
+
+func (c *IMDbGraphQLClient) listItemsEdit(operation, mutation, id string, itemIDs []string) error {
+	if c.cookies == nil {
+		return fmt.Errorf("failure editing list %s, since editing lists requires imdb authentication", id)
+	}
+	for _, itemID := range itemIDs {
+		variables := map[string]any{
+			"listId":  id,
+			"constId": itemID,
+		}
+		if _, err := graphqlQuery[json.RawMessage](c, operation, mutation, variables); err != nil {
+			return fmt.Errorf("failure editing list %s with item %s: %w", id, itemID, err)
+		}
+	}
+	c.logger.Info("edited imdb list", slog.String("id", id), slog.String("operation", operation), slog.Int("count", len(itemIDs)))
+	return nil
+}
+
+func (c *IMDbGraphQLClient) WatchlistExport() error {
+	return nil
+}
+
+func (c *IMDbGraphQLClient) WatchlistGet() (*entities.IMDbList, error) {
+	list, err := c.listGet(c.config.watchlistID)
+	if err != nil {
+		return nil, fmt.Errorf("failure fetching watchlist: %w", err)
+	}
+	list.IsWatchlist = true
+	return list, nil
+}
+
+func (c *IMDbGraphQLClient) RatingsExport() error {
+	return nil
+}
+
+func (c *IMDbGraphQLClient) RatingsGet() ([]entities.IMDbItem, error) {
+	items := make([]entities.IMDbItem, 0)
+	var cursor string
+	for {
+		variables := map[string]any{
+			"userId": c.config.userID,
+			"first":  imdbGraphQLPageSize,
+		}
+		if cursor != "" {
+			variables["after"] = cursor
+		}
+		data, err := graphqlQuery[entities.IMDbGraphQLRatings](c, imdbGraphQLOperationRatings, imdbGraphQLQueryRatings, variables)
+		if err != nil {
+			return nil, fmt.Errorf("failure fetching ratings: %w", err)
+		}
+		pageItems, err := data.AdvancedTitleSearch.Items()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, pageItems...)
+		if cursor = data.AdvancedTitleSearch.NextCursor(); cursor == "" {
+			break
+		}
+	}
+	c.logger.Info("fetched imdb ratings", slog.Int("count", len(items)))
+	return items, nil
+}
+
+func (c *IMDbGraphQLClient) CheckinsExport() error {
+	return nil
+}
+
+func (c *IMDbGraphQLClient) CheckinsGet() (*entities.IMDbList, error) {
+	if c.config.checkinsID == "" {
+		checkins, err := c.predefinedListGet(imdbGraphQLClassTypeCheckins)
+		if err != nil {
+			return nil, fmt.Errorf("failure fetching check-ins list id: %w", err)
+		}
+		c.config.checkinsID = checkins.ID
+	}
+	list, err := c.listGet(c.config.checkinsID)
+	if err != nil {
+		return nil, fmt.Errorf("failure fetching check-ins: %w", err)
+	}
+	return list, nil
+}
+
+func (c *IMDbGraphQLClient) ChartGet(chart string) (*entities.IMDbList, error) {
+	chartType, ok := imdbGraphQLChartTypes[chart]
+	if !ok {
+		return nil, fmt.Errorf("failure finding chart %s", chart)
+	}
+	variables := map[string]any{
+		"chartType": chartType,
+		"first":     imdbGraphQLPageSize,
+	}
+	data, err := graphqlQuery[entities.IMDbGraphQLChart](c, imdbGraphQLOperationChart, imdbGraphQLQueryChart, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failure fetching chart %s: %w", chart, err)
+	}
+	if data.ChartTitles == nil {
+		return nil, &NotFoundError{Err: fmt.Errorf("failure finding chart %s", chart)}
+	}
+	list := chartList(chart, data.ChartTitles.Items())
+	c.logger.Info("fetched imdb chart", slog.String("chart", chart), slog.Int("count", len(list.ListItems)))
+	return list, nil
+}
+
+// ReviewsGet returns no reviews, because the graphql api does not expose the reviews of a user.
+func (c *IMDbGraphQLClient) ReviewsGet() ([]entities.IMDbReview, error) {
+	return make([]entities.IMDbReview, 0), nil
+}
+
+// Close persists the cookie jar, which holds the cookies that imdb renewed during the run.
+func (c *IMDbGraphQLClient) Close() {
+	if c.jar == nil {
+		return
+	}
+	if err := c.jar.save(c.store); err != nil {
+		c.logger.Warn("failure storing imdb cookie jar", slog.Any("error", err))
+	}
+}
+
+func (c *IMDbGraphQLClient) predefinedListGet(classType string) (*entities.IMDbPredefinedList, error) {
+	variables := map[string]any{
+		"classType": classType,
+	}
+	data, err := graphqlQuery[entities.IMDbGraphQLPredefinedList](c, imdbGraphQLOperationPredefined, imdbGraphQLQueryPredefinedList, variables)
+	if err != nil {
+		return nil, err
+	}
+	if data.PredefinedList == nil {
+		return nil, fmt.Errorf("failure finding predefined list %s", classType)
+	}
+	return data.PredefinedList, nil
+}
+
+// listGet fetches every page of the list, following the cursor of each page.
+func (c *IMDbGraphQLClient) listGet(id string) (*entities.IMDbList, error) {
+	list := &entities.IMDbList{
+		ListID:    id,
+		ListItems: make([]entities.IMDbItem, 0),
+	}
+	var cursor string
+	for {
+		page, err := c.listPageGet(id, cursor, imdbGraphQLPageSize)
+		if err != nil {
+			return nil, err
+		}
+		items, err := page.Items(len(list.ListItems))
+		if err != nil {
+			return nil, err
+		}
+		list.ListName = page.List.Name.OriginalText
+		list.Description = strings.TrimSpace(page.List.Description.OriginalText.PlainText)
+		list.ListItems = append(list.ListItems, items...)
+		if cursor = page.NextCursor(); cursor == "" {
+			break
+		}
+	}
+	c.logger.Info("fetched imdb list", slog.String("id", id), slog.Int("count", len(list.ListItems)))
+	return list, nil
+}
+
+func (c *IMDbGraphQLClient) listPageGet(id, cursor string, first int) (*entities.IMDbGraphQLList, error) {
+	variables := map[string]any{
+		"id":    id,
+		"first": first,
+	}
+	if cursor != "" {
+		variables["after"] = cursor
+	}
+	data, err := graphqlQuery[entities.IMDbGraphQLList](c, imdbGraphQLOperationList, imdbGraphQLQueryList, variables)
+	if err != nil {
+		return nil, err
+	}
+	if data.List == nil {
+		return nil, &NotFoundError{Err: fmt.Errorf("failure finding list %s, make sure that it exists and is public", id)}
+	}
+	return data, nil
+}
+
+func graphqlQuery[T any](c *IMDbGraphQLClient, operation, query string, variables map[string]any) (*T, error) {
+	body, err := json.Marshal(entities.IMDbGraphQLRequest{
+		OperationName: operation,
+		Query:         query,
+		Variables:     variables,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failure marshalling graphql request %s: %w", operation, err)
+	}
+	response, err := c.doRequest(http.MethodPost, imdbGraphQLPathBase, body)
+	if err != nil {
+		return nil, err
+	}
+	graphqlResponse, err := decodeReader[entities.IMDbGraphQLResponse[T]](response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err = graphqlResponse.Err(); err != nil {
+		return nil, err
+	}
+	return &graphqlResponse.Data, nil
+}
+
+// doRequest sends the request, retrying it with the configured backoff when imdb responds with a retryable error.
+func (c *IMDbGraphQLClient) doRequest(method, url string, body []byte) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		response, err := c.send(method, url, body)
+		if err == nil {
+			return response, nil
+		}
+		var transientErr *TransientError
+		if !errors.As(err, &transientErr) || attempt >= *c.config.RetryMaxAttempts {
+			return nil, err
+		}
+		delay := imdbRetryDelay(attempt, *c.config.RetryBackoff, *c.config.RetryJitter)
+		c.logger.Warn(fmt.Sprintf("imdb responded with a transient error, waiting %s then retrying", delay), slog.String("url", url), slog.Int("attempt", attempt), slog.Any("error", err))
+		if err = sleep(c.ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *IMDbGraphQLClient) send(method, url string, body []byte) (*http.Response, error) {
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(c.ctx, method, url, reader)
+	if err != nil {
+		return nil, fmt.Errorf("error creating http request %s %s: %w", method, url, err)
+	}
+	if body != nil {
+		setBrowserHeaders(request.Header, c.config.userAgent(), imdbAcceptJSON)
+		request.Header.Set(imdbGraphQLHeaderKeyContent, "application/json")
+	} else {
+		setBrowserHeaders(request.Header, c.config.userAgent(), imdbAcceptHTML)
+	}
+	switch {
+	case c.jar != nil:
+		request.Header.Set(imdbGraphQLHeaderKeyCookie, c.jar.header())
+	case c.cookies != nil:
+		request.Header.Set(imdbGraphQLHeaderKeyCookie, fmt.Sprintf("%s=%s; %s=%s", imdbCookieNameAtMain, c.cookies.AtMain, imdbCookieNameUbidMain, c.cookies.UbidMain))
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error sending http request %s, %s: %w", request.Method, request.URL, err)
+	}
+	if c.jar != nil {
+		c.jar.update(response.Cookies())
+	}
+	if response.StatusCode == http.StatusOK {
+		return response, nil
+	}
+	response.Body.Close()
+	return nil, &ApiError{
+		httpMethod: request.Method,
+		url:        request.URL.String(),
+		StatusCode: response.StatusCode,
+		details:    fmt.Sprintf("unexpected status code %d", response.StatusCode),
+	}
+}
+
+// nextDataParse extracts the json data that imdb embeds in its pages to render them.
+func nextDataParse(body io.ReadCloser) ([]byte, error) {
+	defer body.Close()
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating goquery document from response: %w", err)
+	}
+	script := doc.Find("script#__NEXT_DATA__")
+	if script.Length() == 0 {
+		return nil, fmt.Errorf("failure finding page data")
+	}
+	return []byte(script.Text()), nil
+}
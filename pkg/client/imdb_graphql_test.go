@@ -0,0 +1,291 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+const (
+	dummyGraphQLListPage1 = `{"data":{"list":{"id":"ls000000001","name":{"originalText":"Favourites"},"description":{"originalText":{"plainText":" Best ones "}},"lastModifiedDate":"2024-01-01T00:00:00Z","titleListItemSearch":{"pageInfo":{"endCursor":"cursor1","hasNextPage":true},"edges":[{"createdDate":"2023-12-01T00:00:00Z","description":{"originalText":{"plainText":" Hope "}},"listItem":{"id":"tt0111161","titleType":{"id":"movie"}}}]}}}}`
+	dummyGraphQLListPage2 = `{"data":{"list":{"id":"ls000000001","name":{"originalText":"Favourites"},"description":{"originalText":{"plainText":" Best ones "}},"lastModifiedDate":"2024-01-01T00:00:00Z","titleListItemSearch":{"pageInfo":{"endCursor":"","hasNextPage":false},"edges":[{"createdDate":"2023-12-02T00:00:00Z","listItem":{"__typename":"Title","id":"tt0903747","titleType":{"id":"tvSeries"}}},{"createdDate":"2023-12-03T00:00:00Z","listItem":{"__typename":"Name","id":"nm0634240"}}]}}}}`
+	dummyGraphQLChart     = `{"data":{"chartTitles":{"edges":[{"node":{"id":"tt0111161","titleType":{"id":"movie"},"titleText":{"text":"The Shawshank Redemption"},"ratingsSummary":{"aggregateRating":9.3,"voteCount":3000000}}},{"node":{"id":"tt0068646","titleType":{"id":"movie"},"titleText":{"text":"The Godfather"},"ratingsSummary":{"aggregateRating":9.2,"voteCount":2000000}}}]}}}`
+	dummyGraphQLRatings   = `{"data":{"advancedTitleSearch":{"pageInfo":{"endCursor":"","hasNextPage":false},"edges":[{"node":{"title":{"id":"tt0111161","titleType":{"id":"movie"},"userRating":{"value":9,"date":"2024-01-01T00:00:00Z"}}}}]}}}`
+)
+
+func buildTestIMDbGraphQLClient() *IMDbGraphQLClient {
+	return &IMDbGraphQLClient{
+		ctx: context.Background(),
+		client: &http.Client{
+			Transport: httpmock.DefaultTransport,
+		},
+		config: &imdbConfig{
+			IMDb: &appconfig.IMDb{
+				Lists:            pointer(make([]string, 0)),
+				RetryMaxAttempts: pointer(1),
+				RetryBackoff:     pointer(time.Duration(0)),
+				RetryJitter:      pointer(time.Duration(0)),
+			},
+			userID:      "ur12345678",
+			watchlistID: "ls000000001",
+		},
+		logger: logger.NewLogger(io.Discard),
+	}
+}
+
+// graphqlResponder responds to each graphql operation with the body registered for it, consuming bodies in order.
+func graphqlResponder(bodies map[string][]string) httpmock.Responder {
+	return func(request *http.Request) (*http.Response, error) {
+		var body entities.IMDbGraphQLRequest
+		if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+		responses := bodies[body.OperationName]
+		if len(responses) == 0 {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+		}
+		bodies[body.OperationName] = responses[1:]
+		return httpmock.NewStringResponse(http.StatusOK, responses[0]), nil
+	}
+}
+
+func TestIMDbGraphQLClient_ListsGet(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func()
+		assertions   func(*assert.Assertions, []entities.IMDbList, error)
+	}{
+		{
+			name: "successfully get list across pages",
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodPost, imdbGraphQLPathBase, graphqlResponder(map[string][]string{
+					imdbGraphQLOperationList: {dummyGraphQLListPage1, dummyGraphQLListPage2},
+				}))
+			},
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, err error) {
+				assertions.NoError(err)
+				assertions.Len(lists, 1)
+				assertions.Equal("Favourites", lists[0].ListName)
+				assertions.Equal("Best ones", lists[0].Description)
+				assertions.Len(lists[0].ListItems, 3)
+				assertions.Equal("Hope", lists[0].ListItems[0].Description)
+				assertions.Equal("tt0903747", lists[0].ListItems[1].ID)
+				assertions.Equal("tvSeries", lists[0].ListItems[1].Kind)
+				assertions.Equal(2, *lists[0].ListItems[1].Position)
+				assertions.NotNil(lists[0].ListItems[1].Created)
+				assertions.Equal("nm0634240", lists[0].ListItems[2].ID)
+				assertions.Equal("Person", lists[0].ListItems[2].Kind)
+			},
+		},
+		{
+			name: "failure with missing list",
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodPost, imdbGraphQLPathBase, graphqlResponder(map[string][]string{
+					imdbGraphQLOperationList: {`{"data":{"list":null}}`},
+				}))
+			},
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, err error) {
+				assertions.Nil(lists)
+				assertions.ErrorContains(err, "failure finding list ls000000001")
+			},
+		},
+		{
+			name: "failure with graphql errors",
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodPost, imdbGraphQLPathBase, graphqlResponder(map[string][]string{
+					imdbGraphQLOperationList: {`{"data":{"list":null},"errors":[{"message":"not authorized"}]}`},
+				}))
+			},
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, err error) {
+				assertions.Nil(lists)
+				assertions.ErrorContains(err, "not authorized")
+			},
+		},
+		{
+			name: "failure with unexpected status code",
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodPost, imdbGraphQLPathBase, httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+			},
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, err error) {
+				assertions.Nil(lists)
+				assertions.ErrorContains(err, "status code 503")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			lists, err := buildTestIMDbGraphQLClient().ListsGet("ls000000001")
+			tt.assertions(assert.New(t), lists, err)
+		})
+	}
+}
+
+func TestIMDbGraphQLClient_RatingsGet(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodPost, imdbGraphQLPathBase, graphqlResponder(map[string][]string{
+		imdbGraphQLOperationRatings: {dummyGraphQLRatings},
+	}))
+	ratings, err := buildTestIMDbGraphQLClient().RatingsGet()
+	assertions := assert.New(t)
+	assertions.NoError(err)
+	assertions.Len(ratings, 1)
+	assertions.Equal("tt0111161", ratings[0].ID)
+	assertions.Equal(9, *ratings[0].Rating)
+}
+
+func TestIMDbGraphQLClient_ChartGet(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodPost, imdbGraphQLPathBase, graphqlResponder(map[string][]string{
+		imdbGraphQLOperationChart: {dummyGraphQLChart},
+	}))
+	list, err := buildTestIMDbGraphQLClient().ChartGet(appconfig.IMDbChartTop)
+	assertions := assert.New(t)
+	assertions.NoError(err)
+	assertions.Equal(appconfig.IMDbChartTop, list.ListID)
+	assertions.Equal("IMDb Top 250 Movies", list.ListName)
+	assertions.Len(list.ListItems, 2)
+	assertions.Equal("tt0068646", list.ListItems[1].ID)
+	assertions.Equal("The Godfather", list.ListItems[1].Title)
+	assertions.Equal(2, *list.ListItems[1].Position)
+	assertions.Equal(9.2, *list.ListItems[1].IMDbRating)
+	_, err = buildTestIMDbGraphQLClient().ChartGet("bottom")
+	assertions.ErrorContains(err, "failure finding chart bottom")
+}
+
+func TestIMDbGraphQLClient_hydrate(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodPost, imdbGraphQLPathBase, graphqlResponder(map[string][]string{
+		imdbGraphQLOperationPredefined: {`{"data":{"predefinedList":{"id":"ls000000009","author":{"userId":"ur00000001"}}}}`},
+	}))
+	c := buildTestIMDbGraphQLClient()
+	c.cookies = &imdbCookies{}
+	c.config.Lists = &[]string{"ls000000009", "ls000000002"}
+	err := c.hydrate()
+	assertions := assert.New(t)
+	assertions.NoError(err)
+	assertions.Equal("ur00000001", c.config.userID)
+	assertions.Equal("ls000000009", c.config.watchlistID)
+	assertions.Equal([]string{"ls000000002"}, *c.config.Lists)
+}
+
+func TestIMDbGraphQLClient_ListCreate(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func(*IMDbGraphQLClient)
+		assertions   func(*assert.Assertions, string, error)
+	}{
+		{
+			name: "successfully create list",
+			requirements: func(c *IMDbGraphQLClient) {
+				c.cookies = &imdbCookies{}
+				httpmock.RegisterResponder(http.MethodPost, imdbGraphQLPathBase, graphqlResponder(map[string][]string{
+					imdbGraphQLOperationListCreate: {`{"data":{"createList":{"listId":"ls000000003"}}}`},
+				}))
+			},
+			assertions: func(assertions *assert.Assertions, lid string, err error) {
+				assertions.NoError(err)
+				assertions.Equal("ls000000003", lid)
+			},
+		},
+		{
+			name:         "failure without authentication",
+			requirements: func(c *IMDbGraphQLClient) {},
+			assertions: func(assertions *assert.Assertions, lid string, err error) {
+				assertions.ErrorContains(err, "requires imdb authentication")
+				assertions.Empty(lid)
+				assertions.Equal(0, httpmock.GetTotalCallCount())
+			},
+		},
+		{
+			name: "failure with missing list id",
+			requirements: func(c *IMDbGraphQLClient) {
+				c.cookies = &imdbCookies{}
+				httpmock.RegisterResponder(http.MethodPost, imdbGraphQLPathBase, graphqlResponder(map[string][]string{
+					imdbGraphQLOperationListCreate: {`{"data":{"createList":null}}`},
+				}))
+			},
+			assertions: func(assertions *assert.Assertions, lid string, err error) {
+				assertions.ErrorContains(err, "missing the list id")
+				assertions.Empty(lid)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			c := buildTestIMDbGraphQLClient()
+			tt.requirements(c)
+			lid, err := c.ListCreate("Watched", "Mirrored from trakt")
+			tt.assertions(assert.New(t), lid, err)
+		})
+	}
+}
+
+func TestIMDbGraphQLClient_ListItemsAdd(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func(*IMDbGraphQLClient)
+		assertions   func(*assert.Assertions, error)
+	}{
+		{
+			name: "successfully add items",
+			requirements: func(c *IMDbGraphQLClient) {
+				c.cookies = &imdbCookies{}
+				httpmock.RegisterResponder(http.MethodPost, imdbGraphQLPathBase, graphqlResponder(map[string][]string{
+					imdbGraphQLOperationListAdd: {`{"data":{"addItemToList":{"listId":"ls000000002"}}}`, `{"data":{"addItemToList":{"listId":"ls000000002"}}}`},
+				}))
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+				assertions.Equal(2, httpmock.GetTotalCallCount())
+			},
+		},
+		{
+			name:         "failure without authentication",
+			requirements: func(c *IMDbGraphQLClient) {},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.ErrorContains(err, "requires imdb authentication")
+				assertions.Equal(0, httpmock.GetTotalCallCount())
+			},
+		},
+		{
+			name: "failure with graphql errors",
+			requirements: func(c *IMDbGraphQLClient) {
+				c.cookies = &imdbCookies{}
+				httpmock.RegisterResponder(http.MethodPost, imdbGraphQLPathBase, graphqlResponder(map[string][]string{
+					imdbGraphQLOperationListAdd: {`{"data":null,"errors":[{"message":"not authorized"}]}`},
+				}))
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.ErrorContains(err, "not authorized")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			c := buildTestIMDbGraphQLClient()
+			tt.requirements(c)
+			err := c.ListItemsAdd("ls000000002", "tt0111161", "tt0903747")
+			tt.assertions(assert.New(t), err)
+		})
+	}
+}
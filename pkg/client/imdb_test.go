@@ -0,0 +1,700 @@
+package client
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+)
+
+func Test_transformData(t *testing.T) {
+	type args struct {
+		data []byte
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, []entities.IMDbItem, error)
+	}{
+		{
+			name: "success with titles list",
+			args: args{
+				data: []byte(`Position,Const,Created,Modified,Description,Title,Original Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors,Your Rating,Date Rated
+1,tt5013056,2023-08-03,2023-08-03,Best war movie,Dunkirk,Dunkirk,https://www.imdb.com/title/tt5013056/,Movie,7.8,106,2017,"Action, Drama, History, Thriller, War",718267,2017-07-13,Christopher Nolan,8,2017-12-25
+`),
+			},
+			assertions: func(assertions *assert.Assertions, items []entities.IMDbItem, err error) {
+				assertions.Nil(err)
+				assertions.Len(items, 1)
+				assertions.Equal("tt5013056", items[0].ID)
+				assertions.Equal("Movie", items[0].Kind)
+				assertions.Equal(1, *items[0].Position)
+				assertions.Equal(time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC), *items[0].Created)
+				assertions.Equal(time.Date(2017, time.July, 13, 0, 0, 0, 0, time.UTC), *items[0].ReleaseDate)
+				assertions.Equal("Dunkirk", items[0].Title)
+				assertions.Equal("Best war movie", items[0].Description)
+				assertions.Equal(7.8, *items[0].IMDbRating)
+				assertions.Equal(718267, *items[0].NumVotes)
+				assertions.Equal([]string{"Christopher Nolan"}, items[0].Directors)
+				assertions.Equal(106, *items[0].Runtime)
+				assertions.Equal(2017, *items[0].Year)
+				assertions.Equal([]string{"Action", "Drama", "History", "Thriller", "War"}, items[0].Genres)
+			},
+		},
+		{
+			name: "success with unreleased title",
+			args: args{
+				data: []byte(`Position,Const,Created,Modified,Description,Title,Original Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors,Your Rating,Date Rated
+1,tt0000001,2023-08-03,2023-08-03,,Untitled,Untitled,https://www.imdb.com/title/tt0000001/,Movie,,,,Drama,,,,,
+`),
+			},
+			assertions: func(assertions *assert.Assertions, items []entities.IMDbItem, err error) {
+				assertions.Nil(err)
+				assertions.Len(items, 1)
+				assertions.Nil(items[0].ReleaseDate)
+				assertions.Nil(items[0].Year)
+				assertions.True(items[0].Upcoming(time.Now()))
+			},
+		},
+		{
+			name: "success with ratings list",
+			args: args{
+				data: []byte(`Const,Your Rating,Date Rated,Title,Original Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors
+tt15398776,6,2023-11-25,Oppenheimer,Oppenheimer,https://www.imdb.com/title/tt15398776/,Movie,8.5,180,2023,"Biography, Drama, History",513747,2023-07-11,Christopher Nolan
+`),
+			},
+			assertions: func(assertions *assert.Assertions, items []entities.IMDbItem, err error) {
+				assertions.Nil(err)
+				assertions.Len(items, 1)
+				assertions.Equal("tt15398776", items[0].ID)
+				assertions.Equal(6, *items[0].Rating)
+				assertions.Equal(time.Date(2023, time.November, 25, 0, 0, 0, 0, time.UTC), *items[0].RatingDate)
+				assertions.Equal(time.Date(2023, time.July, 11, 0, 0, 0, 0, time.UTC), *items[0].ReleaseDate)
+				assertions.Equal(180, *items[0].Runtime)
+				assertions.Equal(2023, *items[0].Year)
+				assertions.Equal([]string{"Biography", "Drama", "History"}, items[0].Genres)
+			},
+		},
+		{
+			name: "success with people list",
+			args: args{
+				data: []byte(`Position,Const,Created,Modified,Description,Name,Known For,Birth Date
+1,nm0634240,2023-08-03,2023-08-03,,Christopher Nolan,Oppenheimer,1970-07-30
+`),
+			},
+			assertions: func(assertions *assert.Assertions, items []entities.IMDbItem, err error) {
+				assertions.Nil(err)
+				assertions.Len(items, 1)
+				assertions.Equal("nm0634240", items[0].ID)
+				assertions.Equal("Person", items[0].Kind)
+				assertions.Equal(1, *items[0].Position)
+			},
+		},
+		{
+			name: "success with mixed list",
+			args: args{
+				data: []byte(`Position,Const,Created,Modified,Description,Title,Original Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors,Your Rating,Date Rated
+1,tt5013056,2023-08-03,2023-08-03,,Dunkirk,Dunkirk,https://www.imdb.com/title/tt5013056/,Movie,7.8,106,2017,"Action, Drama, History, Thriller, War",718267,2017-07-13,Christopher Nolan,8,2017-12-25
+2,nm0634240,2023-08-04,2023-08-04,,Christopher Nolan
+3,tt4981636,2023-08-05,2023-08-05,,Titanfall 2,Titanfall 2,https://www.imdb.com/title/tt4981636/,Video Game,8.9,,2016,"Action, Adventure, Sci-Fi",10561,2016-10-28,Steve Fukuda,,
+`),
+			},
+			assertions: func(assertions *assert.Assertions, items []entities.IMDbItem, err error) {
+				assertions.Nil(err)
+				assertions.Len(items, 3)
+				assertions.Equal("Person", items[1].Kind)
+				assertions.Nil(items[1].Year)
+				assertions.Equal("Video Game", items[2].Kind)
+				supported, unsupported := entities.PartitionItems(items)
+				assertions.Len(supported, 2)
+				assertions.Len(unsupported, 1)
+				assertions.Equal("tt4981636", unsupported[0].ID)
+			},
+		},
+		{
+			name: "failure parsing position",
+			args: args{
+				data: []byte(`Position,Const,Created,Modified,Description,Title,Original Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors,Your Rating,Date Rated
+first,tt5013056,2023-08-03,2023-08-03,,Dunkirk,Dunkirk,https://www.imdb.com/title/tt5013056/,Movie,7.8,106,2017,"Action, Drama, History, Thriller, War",718267,2017-07-13,Christopher Nolan,8,2017-12-25
+`),
+			},
+			assertions: func(assertions *assert.Assertions, items []entities.IMDbItem, err error) {
+				assertions.Nil(items)
+				assertions.ErrorContains(err, "failure parsing position")
+			},
+		},
+		{
+			name: "failure parsing created date",
+			args: args{
+				data: []byte(`Position,Const,Created,Modified,Description,Title,Original Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors,Your Rating,Date Rated
+1,tt5013056,invalid,2023-08-03,,Dunkirk,Dunkirk,https://www.imdb.com/title/tt5013056/,Movie,7.8,106,2017,"Action, Drama, History, Thriller, War",718267,2017-07-13,Christopher Nolan,8,2017-12-25
+`),
+			},
+			assertions: func(assertions *assert.Assertions, items []entities.IMDbItem, err error) {
+				assertions.Nil(items)
+				assertions.ErrorContains(err, "failure parsing created date")
+			},
+		},
+		{
+			name: "failure with empty data",
+			args: args{
+				data: []byte(""),
+			},
+			assertions: func(assertions *assert.Assertions, items []entities.IMDbItem, err error) {
+				assertions.Nil(items)
+				assertions.ErrorContains(err, "at least header row")
+			},
+		},
+		{
+			name: "success with gzip-compressed ratings",
+			args: args{
+				data: gzipCompress([]byte(`Const,Your Rating,Date Rated,Title,Original Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors
+tt5013056,8,2017-12-25,Dunkirk,Dunkirk,https://www.imdb.com/title/tt5013056/,Movie,7.8,106,2017,"Action, Drama",718267,2017-07-13,Christopher Nolan
+tt0816692,9,2018-01-02,Interstellar,Interstellar,https://www.imdb.com/title/tt0816692/,Movie,8.7,169,2014,"Adventure, Drama",2100000,2014-10-26,Christopher Nolan
+`)),
+			},
+			assertions: func(assertions *assert.Assertions, items []entities.IMDbItem, err error) {
+				assertions.Nil(err)
+				assertions.Len(items, 2)
+				assertions.Equal("tt5013056", items[0].ID)
+				assertions.Equal(8, *items[0].Rating)
+				assertions.Equal("tt0816692", items[1].ID)
+				assertions.Equal("Interstellar", items[1].Title)
+				assertions.Equal(8.7, *items[1].IMDbRating)
+				assertions.Equal([]string{"Adventure", "Drama"}, items[1].Genres)
+			},
+		},
+		{
+			name: "failure with unrecognized header",
+			args: args{
+				data: []byte("Unknown,Header\n"),
+			},
+			assertions: func(assertions *assert.Assertions, items []entities.IMDbItem, err error) {
+				assertions.Nil(items)
+				assertions.ErrorContains(err, "unrecognized list type")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := transformData(bytes.NewReader(tt.args.data))
+			tt.assertions(assert.New(t), items, err)
+		})
+	}
+}
+
+func Test_transformDownload(t *testing.T) {
+	csvData := []byte(`Position,Const,Created,Modified,Description,Title,Original Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres,Num Votes,Release Date,Directors,Your Rating,Date Rated
+1,tt5013056,2023-08-03,2023-08-03,,Dunkirk,Dunkirk,https://www.imdb.com/title/tt5013056/,Movie,7.8,106,2017,"Action, Drama, History, Thriller, War",718267,2017-07-13,Christopher Nolan,8,2017-12-25
+`)
+	tests := []struct {
+		name       string
+		data       []byte
+		assertions func(*assert.Assertions, []entities.IMDbItem, error)
+	}{
+		{
+			name: "success with csv file",
+			data: csvData,
+			assertions: func(assertions *assert.Assertions, items []entities.IMDbItem, err error) {
+				assertions.Nil(err)
+				assertions.Len(items, 1)
+			},
+		},
+		{
+			name: "success with zip archive",
+			data: zipArchive(map[string][]byte{
+				"README.txt":      []byte("exported by the imdb export center"),
+				"ls123456789.csv": csvData,
+			}),
+			assertions: func(assertions *assert.Assertions, items []entities.IMDbItem, err error) {
+				assertions.Nil(err)
+				assertions.Len(items, 1)
+				assertions.Equal("tt5013056", items[0].ID)
+			},
+		},
+		{
+			name: "failure with zip archive without csv file",
+			data: zipArchive(map[string][]byte{
+				"README.txt": []byte("exported by the imdb export center"),
+			}),
+			assertions: func(assertions *assert.Assertions, items []entities.IMDbItem, err error) {
+				assertions.Nil(items)
+				assertions.ErrorContains(err, "does not contain any csv file")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := transformDownload(tt.data)
+			tt.assertions(assert.New(t), items, err)
+		})
+	}
+}
+
+func zipArchive(files map[string][]byte) []byte {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for name, data := range files {
+		file, _ := writer.Create(name)
+		_, _ = file.Write(data)
+	}
+	_ = writer.Close()
+	return buffer.Bytes()
+}
+
+func gzipCompress(data []byte) []byte {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	_, _ = writer.Write(data)
+	_ = writer.Close()
+	return buffer.Bytes()
+}
+
+func Test_imdbRetryDelay(t *testing.T) {
+	type args struct {
+		attempt int
+		backoff time.Duration
+		jitter  time.Duration
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, time.Duration)
+	}{
+		{
+			name: "first attempt without jitter",
+			args: args{
+				attempt: 1,
+				backoff: time.Second,
+			},
+			assertions: func(assertions *assert.Assertions, delay time.Duration) {
+				assertions.Equal(time.Second, delay)
+			},
+		},
+		{
+			name: "third attempt without jitter",
+			args: args{
+				attempt: 3,
+				backoff: time.Second,
+			},
+			assertions: func(assertions *assert.Assertions, delay time.Duration) {
+				assertions.Equal(4*time.Second, delay)
+			},
+		},
+		{
+			name: "second attempt with jitter",
+			args: args{
+				attempt: 2,
+				backoff: time.Second,
+				jitter:  time.Second,
+			},
+			assertions: func(assertions *assert.Assertions, delay time.Duration) {
+				assertions.GreaterOrEqual(delay, 2*time.Second)
+				assertions.Less(delay, 3*time.Second)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay := imdbRetryDelay(tt.args.attempt, tt.args.backoff, tt.args.jitter)
+			tt.assertions(assert.New(t), delay)
+		})
+	}
+}
+
+func Test_imdbConfig_userAgent(t *testing.T) {
+	tests := []struct {
+		name       string
+		conf       *appconfig.IMDb
+		assertions func(*assert.Assertions, *imdbConfig)
+	}{
+		{
+			name: "default user agent",
+			conf: &appconfig.IMDb{},
+			assertions: func(assertions *assert.Assertions, c *imdbConfig) {
+				assertions.Equal(appconfig.IMDbUserAgentDefault, c.userAgent())
+				assertions.Equal(appconfig.IMDbUserAgentDefault, c.userAgent())
+			},
+		},
+		{
+			name: "rotate user agents",
+			conf: &appconfig.IMDb{
+				UserAgent:       pointer("custom"),
+				UserAgentRotate: pointer(true),
+			},
+			assertions: func(assertions *assert.Assertions, c *imdbConfig) {
+				assertions.Equal("custom", c.userAgent())
+				for _, userAgent := range imdbUserAgents {
+					assertions.Equal(userAgent, c.userAgent())
+				}
+				assertions.Equal("custom", c.userAgent())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(assert.New(t), &imdbConfig{IMDb: tt.conf})
+		})
+	}
+}
+
+func Test_setBrowserHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		userAgent  string
+		assertions func(*assert.Assertions, http.Header)
+	}{
+		{
+			name:      "chrome sends client hints",
+			userAgent: imdbUserAgents[0],
+			assertions: func(assertions *assert.Assertions, header http.Header) {
+				assertions.Equal(imdbUserAgents[0], header.Get("User-Agent"))
+				assertions.Equal(imdbAcceptLanguage, header.Get("Accept-Language"))
+				assertions.Equal(`"Google Chrome";v="131", "Chromium";v="131", "Not_A Brand";v="24"`, header.Get("sec-ch-ua"))
+				assertions.Equal(`"Windows"`, header.Get("sec-ch-ua-platform"))
+			},
+		},
+		{
+			name:      "edge sends its own brand",
+			userAgent: imdbUserAgents[2],
+			assertions: func(assertions *assert.Assertions, header http.Header) {
+				assertions.Contains(header.Get("sec-ch-ua"), `"Microsoft Edge";v="131"`)
+			},
+		},
+		{
+			name:      "firefox does not send client hints",
+			userAgent: imdbUserAgents[3],
+			assertions: func(assertions *assert.Assertions, header http.Header) {
+				assertions.Equal(imdbAcceptHTML, header.Get("Accept"))
+				assertions.Empty(header.Get("sec-ch-ua"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := make(http.Header)
+			setBrowserHeaders(header, tt.userAgent, imdbAcceptHTML)
+			tt.assertions(assert.New(t), header)
+		})
+	}
+}
+
+func TestApiError_Retryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiError *ApiError
+		expected bool
+	}{
+		{
+			name: "service unavailable",
+			apiError: &ApiError{
+				StatusCode: http.StatusServiceUnavailable,
+			},
+			expected: true,
+		},
+		{
+			name: "too many requests",
+			apiError: &ApiError{
+				StatusCode: http.StatusTooManyRequests,
+			},
+			expected: true,
+		},
+		{
+			name: "cloudflare challenge",
+			apiError: &ApiError{
+				StatusCode: http.StatusForbidden,
+				retryable:  true,
+			},
+			expected: true,
+		},
+		{
+			name: "not found",
+			apiError: &ApiError{
+				StatusCode: http.StatusNotFound,
+			},
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.apiError.Retryable())
+		})
+	}
+}
+
+func TestApiError_As(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		assertions func(*assert.Assertions, error)
+	}{
+		{
+			name: "unauthorized",
+			err:  fmt.Errorf("failure fetching trakt watchlist: %w", &ApiError{StatusCode: http.StatusUnauthorized}),
+			assertions: func(assertions *assert.Assertions, err error) {
+				var authErr *AuthError
+				assertions.ErrorAs(err, &authErr)
+				var apiErr *ApiError
+				assertions.ErrorAs(authErr, &apiErr)
+				assertions.Equal(http.StatusUnauthorized, apiErr.StatusCode)
+				var transientErr *TransientError
+				assertions.False(errors.As(err, &transientErr))
+			},
+		},
+		{
+			name: "too many requests",
+			err:  &ApiError{StatusCode: http.StatusTooManyRequests},
+			assertions: func(assertions *assert.Assertions, err error) {
+				var rateLimitErr *RateLimitError
+				assertions.ErrorAs(err, &rateLimitErr)
+				var transientErr *TransientError
+				assertions.ErrorAs(err, &transientErr)
+			},
+		},
+		{
+			name: "trakt account limit exceeded",
+			err:  &ApiError{StatusCode: traktStatusCodeEnhanceYourCalm},
+			assertions: func(assertions *assert.Assertions, err error) {
+				var rateLimitErr *RateLimitError
+				assertions.ErrorAs(err, &rateLimitErr)
+				var transientErr *TransientError
+				assertions.False(errors.As(err, &transientErr))
+			},
+		},
+		{
+			name: "cloudflare challenge",
+			err:  &ApiError{StatusCode: http.StatusForbidden, retryable: true},
+			assertions: func(assertions *assert.Assertions, err error) {
+				var authErr *AuthError
+				assertions.False(errors.As(err, &authErr))
+				var transientErr *TransientError
+				assertions.ErrorAs(err, &transientErr)
+			},
+		},
+		{
+			name: "trakt list not found",
+			err:  &TraktListNotFoundError{Slug: "watched"},
+			assertions: func(assertions *assert.Assertions, err error) {
+				var notFoundErr *NotFoundError
+				assertions.ErrorAs(err, &notFoundErr)
+				var listNotFoundErr *TraktListNotFoundError
+				assertions.ErrorAs(notFoundErr, &listNotFoundErr)
+			},
+		},
+		{
+			name: "bad request",
+			err:  &ApiError{StatusCode: http.StatusBadRequest},
+			assertions: func(assertions *assert.Assertions, err error) {
+				var authErr *AuthError
+				assertions.False(errors.As(err, &authErr))
+				var rateLimitErr *RateLimitError
+				assertions.False(errors.As(err, &rateLimitErr))
+				var notFoundErr *NotFoundError
+				assertions.False(errors.As(err, &notFoundErr))
+				var transientErr *TransientError
+				assertions.False(errors.As(err, &transientErr))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertions(assert.New(t), tt.err)
+		})
+	}
+}
+
+func Test_ratingsPageParse(t *testing.T) {
+	type args struct {
+		data []byte
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, *entities.IMDbRatingsPage, error)
+	}{
+		{
+			name: "success with next page",
+			args: args{
+				data: httpmock.File("testdata/imdb_ratings_page.json").Bytes(),
+			},
+			assertions: func(assertions *assert.Assertions, page *entities.IMDbRatingsPage, err error) {
+				assertions.Nil(err)
+				assertions.Equal("eyJlc1Rva2VuIjpbIjEwMCJdfQ==", page.NextPaginationKey())
+				items, err := page.Items()
+				assertions.Nil(err)
+				assertions.Len(items, 2)
+				assertions.Equal("tt15398776", items[0].ID)
+				assertions.Equal(6, *items[0].Rating)
+				assertions.Equal(time.Date(2023, time.November, 25, 0, 0, 0, 0, time.UTC), *items[0].RatingDate)
+				assertions.Equal("tvEpisode", items[1].Kind)
+			},
+		},
+		{
+			name: "success with last page",
+			args: args{
+				data: []byte(`{"props":{"pageProps":{"mainColumnData":{"advancedTitleSearch":{"pageInfo":{"endCursor":"abc","hasNextPage":false},"edges":[]}}}}}`),
+			},
+			assertions: func(assertions *assert.Assertions, page *entities.IMDbRatingsPage, err error) {
+				assertions.Nil(err)
+				assertions.Empty(page.NextPaginationKey())
+				items, err := page.Items()
+				assertions.Nil(err)
+				assertions.Empty(items)
+			},
+		},
+		{
+			name: "failure unmarshalling",
+			args: args{
+				data: []byte("invalid"),
+			},
+			assertions: func(assertions *assert.Assertions, page *entities.IMDbRatingsPage, err error) {
+				assertions.NotNil(err)
+				assertions.Nil(page)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := ratingsPageParse(tt.args.data)
+			tt.assertions(assert.New(t), page, err)
+		})
+	}
+}
+
+func Test_chartPageParse(t *testing.T) {
+	page, err := chartPageParse([]byte(`{"props":{"pageProps":{"pageData":{"chartTitles":{"edges":[{"node":{"id":"tt0111161","titleType":{"id":"movie"},"titleText":{"text":"The Shawshank Redemption"},"releaseDate":{"year":1994,"month":10,"day":14}}}]}}}}}`))
+	assertions := assert.New(t)
+	assertions.Nil(err)
+	items := page.Props.PageProps.PageData.ChartTitles.Items()
+	assertions.Len(items, 1)
+	assertions.Equal("tt0111161", items[0].ID)
+	assertions.Equal(1, *items[0].Position)
+	assertions.Equal(time.Date(1994, time.October, 14, 0, 0, 0, 0, time.UTC), *items[0].ReleaseDate)
+	_, err = chartPageParse([]byte("invalid"))
+	assertions.NotNil(err)
+}
+
+func Test_reviewKindParse(t *testing.T) {
+	tests := []struct {
+		heading string
+		want    string
+	}{
+		{heading: "The Dark Knight (2008)", want: "movie"},
+		{heading: "Breaking Bad (TV Series 2008–2013)", want: "tvSeries"},
+		{heading: "Chernobyl (TV Mini Series 2019)", want: "tvMiniSeries"},
+		{heading: "Breaking Bad: Ozymandias (TV Episode 2013)", want: "tvEpisode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.heading, func(t *testing.T) {
+			assert.Equal(t, tt.want, reviewKindParse(tt.heading))
+		})
+	}
+}
+
+func Test_listsParse(t *testing.T) {
+	type args struct {
+		body io.Reader
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, []entities.IMDbList, error)
+	}{
+		{
+			name: "success with lists",
+			args: args{
+				body: bytes.NewReader(httpmock.File("testdata/imdb_lists.html").Bytes()),
+			},
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, err error) {
+				assertions.Nil(err)
+				assertions.Equal([]entities.IMDbList{
+					{ListID: "ls123456789", ListName: "Watched (2023)"},
+					{ListID: "ls987654321", ListName: "Watched (2022)"},
+				}, lists)
+			},
+		},
+		{
+			name: "success without lists",
+			args: args{
+				body: strings.NewReader(`<ul><li><a class="ipc-metadata-list-summary-item__t" href="/title/tt0111161/">Title</a></li></ul>`),
+			},
+			assertions: func(assertions *assert.Assertions, lists []entities.IMDbList, err error) {
+				assertions.Nil(err)
+				assertions.Empty(lists)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lists, err := listsParse(tt.args.body)
+			tt.assertions(assert.New(t), lists, err)
+		})
+	}
+}
+
+func Test_listPageParse(t *testing.T) {
+	type args struct {
+		data []byte
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertions func(*assert.Assertions, *entities.IMDbListPage, error)
+	}{
+		{
+			name: "success with description",
+			args: args{
+				data: httpmock.File("testdata/imdb_list_page.json").Bytes(),
+			},
+			assertions: func(assertions *assert.Assertions, page *entities.IMDbListPage, err error) {
+				assertions.Nil(err)
+				assertions.Equal("ls123456789", page.Props.PageProps.MainColumnData.List.ID)
+				assertions.Equal("Movies I have watched", page.Description())
+				lastModified, err := page.LastModified()
+				assertions.Nil(err)
+				assertions.Equal(time.Date(2024, time.March, 17, 18, 42, 5, 0, time.UTC), *lastModified)
+			},
+		},
+		{
+			name: "success without description",
+			args: args{
+				data: []byte(`{"props":{"pageProps":{"mainColumnData":{"list":{"description":null}}}}}`),
+			},
+			assertions: func(assertions *assert.Assertions, page *entities.IMDbListPage, err error) {
+				assertions.Nil(err)
+				assertions.Empty(page.Description())
+				lastModified, err := page.LastModified()
+				assertions.Nil(err)
+				assertions.Nil(lastModified)
+			},
+		},
+		{
+			name: "failure with invalid data",
+			args: args{
+				data: []byte("<html></html>"),
+			},
+			assertions: func(assertions *assert.Assertions, page *entities.IMDbListPage, err error) {
+				assertions.Nil(page)
+				assertions.ErrorContains(err, "failure unmarshalling list page data")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := listPageParse(tt.args.data)
+			tt.assertions(assert.New(t), page, err)
+		})
+	}
+}
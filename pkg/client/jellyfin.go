@@ -0,0 +1,392 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+)
+
+const (
+	clientNameJellyfin = "jellyfin"
+
+	jellyfinHeaderKeyContentType = "Content-Type"
+	jellyfinHeaderKeyToken       = "X-Emby-Token"
+
+	jellyfinPathFavoriteItem = "/Users/%s/FavoriteItems/%s"
+	jellyfinPathItems        = "/Users/%s/Items?Recursive=true&IncludeItemTypes=Movie,Series,Episode&Fields=ProviderIds&EnableUserData=true"
+	jellyfinPathPlayedItem   = "/Users/%s/PlayedItems/%s"
+	jellyfinPathRating       = "/Users/%s/Items/%s/Rating"
+	jellyfinPathUserData     = "/Users/%s/Items/%s/UserData"
+	jellyfinPathUsers        = "/Users"
+)
+
+var errJellyfinUnsupported = errors.New("operation is not supported by jellyfin")
+
+// JellyfinClient syncs imdb data to a jellyfin or emby server, which only supports the ratings, history and
+// favorites. Items are matched to the library of the server by their imdb provider ids.
+type JellyfinClient struct {
+	ctx      context.Context
+	client   *http.Client
+	config   appconfig.Jellyfin
+	logger   *slog.Logger
+	rounding string
+	userID   string
+	library  map[string]entities.JellyfinItem
+	notFound entities.TraktItems
+}
+
+func NewJellyfinClient(ctx context.Context, conf appconfig.Jellyfin, httpConf appconfig.HTTP, rounding string, store state.Store, logger *slog.Logger) (DestinationClientInterface, error) {
+	transport, err := newTransport(clientNameJellyfin, httpConf, store)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating transport: %w", err)
+	}
+	c := &JellyfinClient{
+		ctx: ctx,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   httpConf.ClientTimeout(clientNameJellyfin),
+		},
+		config:   conf,
+		logger:   logger,
+		rounding: rounding,
+	}
+	if err = c.userIDGet(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ValidateJellyfinAuth checks whether jellyfin accepts the api key, and knows the configured user.
+func ValidateJellyfinAuth(ctx context.Context, conf appconfig.Jellyfin, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger) error {
+	_, err := NewJellyfinClient(ctx, conf, httpConf, "", store, logger)
+	return err
+}
+
+// userIDGet looks up the id of the configured user, since the user endpoints are addressed by id rather than name.
+func (jc *JellyfinClient) userIDGet() error {
+	response, err := jc.doRequest(http.MethodGet, jellyfinPathUsers, nil)
+	if err != nil {
+		return fmt.Errorf("failure fetching jellyfin users: %w", err)
+	}
+	users, err := decodeReader[[]entities.JellyfinUser](response.Body)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		if strings.EqualFold(user.Name, *jc.config.Username) {
+			jc.userID = user.ID
+			return nil
+		}
+	}
+	return fmt.Errorf("jellyfin user %s not found", *jc.config.Username)
+}
+
+func (jc *JellyfinClient) WatchlistGet() (*entities.TraktList, error) {
+	return nil, errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) WatchlistItemsAdd(entities.TraktItems) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) WatchlistItemsRemove(entities.TraktItems) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) ListGet(string) (*entities.TraktList, error) {
+	return nil, errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) ListsGet(idMeta entities.TraktIDMetas) ([]entities.TraktList, []error) {
+	if len(idMeta) == 0 {
+		return make([]entities.TraktList, 0), nil
+	}
+	return nil, []error{errJellyfinUnsupported}
+}
+
+func (jc *JellyfinClient) ListItemsAdd(string, entities.TraktItems) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) ListItemsRemove(string, entities.TraktItems) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) ListItemsReorder(string, []int64) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) ListAdd(string, string, string) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) ListUpdate(string, string) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) ListRemove(string) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) RatingsGet() (entities.TraktItems, error) {
+	return jc.filter(func(item entities.JellyfinItem) bool {
+		return item.UserData.Rating != nil
+	})
+}
+
+func (jc *JellyfinClient) RatingsAdd(items entities.TraktItems) error {
+	return jc.apply("rated", items, func(itemID string, spec *entities.TraktItemSpec) error {
+		if spec.Rating == nil {
+			return nil
+		}
+		// only the rating is sent, since jellyfin would reset the played and favorite state of the item otherwise
+		data, err := json.Marshal(entities.JellyfinRatingBody{
+			Rating: float64(*spec.Rating),
+		})
+		if err != nil {
+			return err
+		}
+		return jc.send(http.MethodPost, fmt.Sprintf(jellyfinPathUserData, jc.userID, itemID), data)
+	})
+}
+
+func (jc *JellyfinClient) RatingsRemove(items entities.TraktItems) error {
+	return jc.apply("unrated", items, func(itemID string, _ *entities.TraktItemSpec) error {
+		return jc.send(http.MethodDelete, fmt.Sprintf(jellyfinPathRating, jc.userID, itemID), nil)
+	})
+}
+
+// HistoryGet returns the item if it is played on jellyfin, since jellyfin only keeps the last time an item was played.
+func (jc *JellyfinClient) HistoryGet(itemType, itemID string) (entities.TraktItems, error) {
+	library, err := jc.libraryGet()
+	if err != nil {
+		return nil, err
+	}
+	item, found := library[itemID]
+	if !found || !item.UserData.Played {
+		return nil, nil
+	}
+	if traktItem := item.ToTraktItem(jc.rounding); traktItem.Type == itemType {
+		return entities.TraktItems{traktItem}, nil
+	}
+	return nil, nil
+}
+
+func (jc *JellyfinClient) HistoryAdd(items entities.TraktItems) error {
+	return jc.apply("played", items, func(itemID string, spec *entities.TraktItemSpec) error {
+		endpoint := fmt.Sprintf(jellyfinPathPlayedItem, jc.userID, itemID)
+		if datePlayed := entities.JellyfinDate(spec.WatchedAt); datePlayed != "" {
+			endpoint += "?DatePlayed=" + url.QueryEscape(datePlayed)
+		}
+		return jc.send(http.MethodPost, endpoint, nil)
+	})
+}
+
+func (jc *JellyfinClient) HistoryRemove(items entities.TraktItems) error {
+	return jc.apply("unplayed", items, func(itemID string, _ *entities.TraktItemSpec) error {
+		return jc.send(http.MethodDelete, fmt.Sprintf(jellyfinPathPlayedItem, jc.userID, itemID), nil)
+	})
+}
+
+func (jc *JellyfinClient) HistoryGetAll() (entities.TraktItems, error) {
+	return nil, errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) LikedListsGet() ([]entities.TraktList, error) {
+	return nil, errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) ListLike(string) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) ListUnlike(string) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) RecommendationsGet() (entities.TraktItems, error) {
+	return nil, errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) HiddenGet(string) (entities.TraktItems, error) {
+	return nil, errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) HiddenAdd(string, entities.TraktItems) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) HiddenRemove(string, entities.TraktItems) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) CollectionGet() (entities.TraktItems, error) {
+	return nil, errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) CollectionAdd(entities.TraktItems) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) CollectionRemove(entities.TraktItems) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) FavoritesGet() (entities.TraktItems, error) {
+	return jc.filter(func(item entities.JellyfinItem) bool {
+		return item.UserData.IsFavorite
+	})
+}
+
+func (jc *JellyfinClient) FavoritesAdd(items entities.TraktItems) error {
+	return jc.apply("favorited", items, func(itemID string, _ *entities.TraktItemSpec) error {
+		return jc.send(http.MethodPost, fmt.Sprintf(jellyfinPathFavoriteItem, jc.userID, itemID), nil)
+	})
+}
+
+func (jc *JellyfinClient) FavoritesRemove(items entities.TraktItems) error {
+	return jc.apply("unfavorited", items, func(itemID string, _ *entities.TraktItemSpec) error {
+		return jc.send(http.MethodDelete, fmt.Sprintf(jellyfinPathFavoriteItem, jc.userID, itemID), nil)
+	})
+}
+
+func (jc *JellyfinClient) CommentsGet() (entities.TraktItems, error) {
+	return nil, errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) CommentAdd(entities.TraktComment) error {
+	return errJellyfinUnsupported
+}
+
+func (jc *JellyfinClient) Checkin(entities.TraktItem) error {
+	return errJellyfinUnsupported
+}
+
+// LimitsGet returns no limits, since jellyfin does not cap the user data of a library.
+func (jc *JellyfinClient) LimitsGet() (*entities.TraktLimits, error) {
+	return nil, nil
+}
+
+// NotFound returns the items that are missing from the jellyfin library, since the last time it was called.
+func (jc *JellyfinClient) NotFound() entities.TraktItems {
+	notFound := jc.notFound
+	jc.notFound = nil
+	return notFound
+}
+
+// libraryGet fetches the movies, shows and episodes of the user's jellyfin library once, indexed by their imdb id, and
+// caches them until the next mutation. Items without an imdb id can't be matched, so they are left out.
+func (jc *JellyfinClient) libraryGet() (map[string]entities.JellyfinItem, error) {
+	if jc.library != nil {
+		return jc.library, nil
+	}
+	response, err := jc.doRequest(http.MethodGet, fmt.Sprintf(jellyfinPathItems, jc.userID), nil)
+	if err != nil {
+		return nil, err
+	}
+	items, err := decodeReader[entities.JellyfinItems](response.Body)
+	if err != nil {
+		return nil, err
+	}
+	library := make(map[string]entities.JellyfinItem, len(items.Items))
+	for _, item := range items.Items {
+		if id := item.IMDbID(); id != "" {
+			library[id] = item
+		}
+	}
+	jc.library = library
+	return library, nil
+}
+
+// filter returns the library items that satisfy the predicate as trakt items.
+func (jc *JellyfinClient) filter(predicate func(entities.JellyfinItem) bool) (entities.TraktItems, error) {
+	library, err := jc.libraryGet()
+	if err != nil {
+		return nil, err
+	}
+	var items entities.TraktItems
+	for _, item := range library {
+		if predicate(item) {
+			items = append(items, item.ToTraktItem(jc.rounding))
+		}
+	}
+	return items, nil
+}
+
+// apply calls the function with the jellyfin id of each item, since jellyfin updates the user data of one item per
+// request. Items that are missing from the library are reported as not found.
+func (jc *JellyfinClient) apply(action string, items entities.TraktItems, fn func(itemID string, spec *entities.TraktItemSpec) error) error {
+	library, err := jc.libraryGet()
+	if err != nil {
+		return err
+	}
+	jc.library = nil
+	var applied int
+	for _, item := range items {
+		spec := item.Spec()
+		if spec == nil {
+			continue
+		}
+		libraryItem, found := library[spec.IDMeta.IMDb]
+		if !found {
+			jc.notFound = append(jc.notFound, item)
+			continue
+		}
+		if err = fn(libraryItem.ID, spec); err != nil {
+			return fmt.Errorf("failure updating jellyfin item %s: %w", spec.IDMeta.IMDb, err)
+		}
+		applied++
+	}
+	jc.logger.Info("synced jellyfin items", slog.String("action", action), slog.Int("count", applied))
+	return nil
+}
+
+func (jc *JellyfinClient) send(method, endpoint string, body []byte) error {
+	response, err := jc.doRequest(method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	return nil
+}
+
+func (jc *JellyfinClient) doRequest(method, endpoint string, body []byte) (*http.Response, error) {
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	target := strings.TrimSuffix(*jc.config.URL, "/") + endpoint
+	request, err := http.NewRequestWithContext(jc.ctx, method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("error creating http request %s %s: %w", method, target, err)
+	}
+	request.Header.Set(jellyfinHeaderKeyContentType, "application/json")
+	request.Header.Set(jellyfinHeaderKeyToken, *jc.config.APIKey)
+	response, err := jc.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error sending http request %s, %s: %w", request.Method, request.URL, err)
+	}
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return response, nil
+	default:
+		response.Body.Close()
+		return nil, &ApiError{
+			httpMethod: request.Method,
+			url:        request.URL.String(),
+			StatusCode: response.StatusCode,
+			details:    fmt.Sprintf("unexpected status code %d", response.StatusCode),
+		}
+	}
+}
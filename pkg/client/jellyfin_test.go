@@ -0,0 +1,232 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/rating"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+const (
+	dummyJellyfinURL     = "http://jellyfin.local"
+	dummyJellyfinLibrary = `{"Items": [
+  {"Id": "a1", "Name": "Dunkirk", "Type": "Movie", "ProviderIds": {"Imdb": "tt5013056"}, "UserData": {"Rating": 7.5, "Played": true, "IsFavorite": false, "LastPlayedDate": "2024-01-01T00:00:00Z"}},
+  {"Id": "b2", "Name": "Breaking Bad", "Type": "Series", "ProviderIds": {"IMDB": "tt0903747"}, "UserData": {"Played": false, "IsFavorite": true}},
+  {"Id": "c3", "Name": "Home Video", "Type": "Movie", "ProviderIds": {}, "UserData": {"Played": true, "IsFavorite": true}}
+]}`
+)
+
+func buildTestJellyfinClient() *JellyfinClient {
+	return &JellyfinClient{
+		ctx: context.Background(),
+		client: &http.Client{
+			Transport: httpmock.DefaultTransport,
+		},
+		config: appconfig.Jellyfin{
+			URL:      pointer(dummyJellyfinURL + "/"),
+			APIKey:   pointer("api-key"),
+			Username: pointer("alice"),
+		},
+		logger:   logger.NewLogger(io.Discard),
+		rounding: rating.RoundingFloor,
+		userID:   "user",
+	}
+}
+
+func TestJellyfinClient_userIDGet(t *testing.T) {
+	tests := []struct {
+		name       string
+		users      string
+		assertions func(*assert.Assertions, *JellyfinClient, error)
+	}{
+		{
+			name:  "successfully resolve user",
+			users: `[{"Id": "bob-id", "Name": "bob"}, {"Id": "alice-id", "Name": "Alice"}]`,
+			assertions: func(assertions *assert.Assertions, c *JellyfinClient, err error) {
+				assertions.NoError(err)
+				assertions.Equal("alice-id", c.userID)
+			},
+		},
+		{
+			name:  "failure resolving missing user",
+			users: `[{"Id": "bob-id", "Name": "bob"}]`,
+			assertions: func(assertions *assert.Assertions, c *JellyfinClient, err error) {
+				assertions.ErrorContains(err, "jellyfin user alice not found")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			assertions := assert.New(t)
+			httpmock.RegisterResponder(http.MethodGet, dummyJellyfinURL+jellyfinPathUsers, func(request *http.Request) (*http.Response, error) {
+				assertions.Equal("api-key", request.Header.Get(jellyfinHeaderKeyToken))
+				return httpmock.NewStringResponse(http.StatusOK, tt.users), nil
+			})
+			c := buildTestJellyfinClient()
+			c.userID = ""
+			tt.assertions(assertions, c, c.userIDGet())
+		})
+	}
+}
+
+func TestJellyfinClient_Library(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func()
+		assertions   func(*assert.Assertions, *JellyfinClient)
+	}{
+		{
+			name: "successfully get ratings",
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodGet, dummyJellyfinURL+fmt.Sprintf(jellyfinPathItems, "user"), httpmock.NewStringResponder(http.StatusOK, dummyJellyfinLibrary))
+			},
+			assertions: func(assertions *assert.Assertions, c *JellyfinClient) {
+				ratings, err := c.RatingsGet()
+				assertions.NoError(err)
+				assertions.Len(ratings, 1)
+				assertions.Equal(7, ratings[0].Rating)
+				assertions.Equal("tt5013056", ratings[0].Movie.IDMeta.IMDb)
+			},
+		},
+		{
+			name: "successfully get favorites",
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodGet, dummyJellyfinURL+fmt.Sprintf(jellyfinPathItems, "user"), httpmock.NewStringResponder(http.StatusOK, dummyJellyfinLibrary))
+			},
+			assertions: func(assertions *assert.Assertions, c *JellyfinClient) {
+				favorites, err := c.FavoritesGet()
+				assertions.NoError(err)
+				assertions.Len(favorites, 1)
+				assertions.Equal(entities.TraktItemTypeShow, favorites[0].Type)
+				assertions.Equal("tt0903747", favorites[0].Show.IDMeta.IMDb)
+			},
+		},
+		{
+			name: "successfully get history",
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodGet, dummyJellyfinURL+fmt.Sprintf(jellyfinPathItems, "user"), httpmock.NewStringResponder(http.StatusOK, dummyJellyfinLibrary))
+			},
+			assertions: func(assertions *assert.Assertions, c *JellyfinClient) {
+				history, err := c.HistoryGet(entities.TraktItemTypeMovie, "tt5013056")
+				assertions.NoError(err)
+				assertions.Len(history, 1)
+				assertions.Equal("2024-01-01T00:00:00Z", history[0].WatchedAt)
+				history, err = c.HistoryGet(entities.TraktItemTypeShow, "tt0903747")
+				assertions.NoError(err)
+				assertions.Empty(history)
+				assertions.Equal(1, httpmock.GetTotalCallCount())
+			},
+		},
+		{
+			name: "failure getting library",
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodGet, dummyJellyfinURL+fmt.Sprintf(jellyfinPathItems, "user"), httpmock.NewStringResponder(http.StatusUnauthorized, ""))
+			},
+			assertions: func(assertions *assert.Assertions, c *JellyfinClient) {
+				ratings, err := c.RatingsGet()
+				assertions.Nil(ratings)
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusUnauthorized, apiError.StatusCode)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			tt.assertions(assert.New(t), buildTestJellyfinClient())
+		})
+	}
+}
+
+func TestJellyfinClient_Mutations(t *testing.T) {
+	rated := 8
+	watchedAt := "2024-02-03 04:05:06 +0000 UTC"
+	items := entities.TraktItems{
+		{
+			Type: entities.TraktItemTypeMovie,
+			Movie: entities.TraktItemSpec{
+				IDMeta:    entities.TraktIDMeta{IMDb: "tt5013056"},
+				Rating:    &rated,
+				WatchedAt: &watchedAt,
+			},
+		},
+		{
+			Type: entities.TraktItemTypeMovie,
+			Movie: entities.TraktItemSpec{
+				IDMeta: entities.TraktIDMeta{IMDb: "tt0000404"},
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		requirements func(*assert.Assertions)
+		mutate       func(*JellyfinClient) error
+	}{
+		{
+			name: "successfully add ratings",
+			requirements: func(assertions *assert.Assertions) {
+				httpmock.RegisterResponder(http.MethodPost, dummyJellyfinURL+fmt.Sprintf(jellyfinPathUserData, "user", "a1"), func(request *http.Request) (*http.Response, error) {
+					body, err := io.ReadAll(request.Body)
+					assertions.NoError(err)
+					assertions.JSONEq(`{"Rating": 8}`, string(body))
+					return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+				})
+			},
+			mutate: func(c *JellyfinClient) error {
+				return c.RatingsAdd(items)
+			},
+		},
+		{
+			name: "successfully add history",
+			requirements: func(assertions *assert.Assertions) {
+				httpmock.RegisterResponder(http.MethodPost, dummyJellyfinURL+fmt.Sprintf(jellyfinPathPlayedItem, "user", "a1"), func(request *http.Request) (*http.Response, error) {
+					assertions.Equal("2024-02-03T04:05:06Z", request.URL.Query().Get("DatePlayed"))
+					return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+				})
+			},
+			mutate: func(c *JellyfinClient) error {
+				return c.HistoryAdd(items)
+			},
+		},
+		{
+			name: "successfully remove favorites",
+			requirements: func(assertions *assert.Assertions) {
+				httpmock.RegisterResponder(http.MethodDelete, dummyJellyfinURL+fmt.Sprintf(jellyfinPathFavoriteItem, "user", "a1"), httpmock.NewStringResponder(http.StatusOK, "{}"))
+			},
+			mutate: func(c *JellyfinClient) error {
+				return c.FavoritesRemove(items)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			assertions := assert.New(t)
+			httpmock.RegisterResponder(http.MethodGet, dummyJellyfinURL+fmt.Sprintf(jellyfinPathItems, "user"), httpmock.NewStringResponder(http.StatusOK, dummyJellyfinLibrary))
+			tt.requirements(assertions)
+			c := buildTestJellyfinClient()
+			assertions.NoError(tt.mutate(c))
+			assertions.Equal(2, httpmock.GetTotalCallCount())
+			notFound := c.NotFound()
+			assertions.Len(notFound, 1)
+			assertions.Equal("tt0000404", notFound[0].Movie.IDMeta.IMDb)
+			assertions.Empty(c.NotFound())
+		})
+	}
+}
@@ -0,0 +1,413 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+)
+
+const (
+	clientNameKodi = "kodi"
+
+	kodiHeaderKeyContentType = "Content-Type"
+
+	kodiJSONRPCVersion     = "2.0"
+	kodiMethodGetEpisodes  = "VideoLibrary.GetEpisodes"
+	kodiMethodGetMovies    = "VideoLibrary.GetMovies"
+	kodiMethodGetTVShows   = "VideoLibrary.GetTVShows"
+	kodiMethodPing         = "JSONRPC.Ping"
+	kodiMethodSetEpisode   = "VideoLibrary.SetEpisodeDetails"
+	kodiMethodSetMovie     = "VideoLibrary.SetMovieDetails"
+	kodiMethodSetTVShow    = "VideoLibrary.SetTVShowDetails"
+	kodiPathJSONRPC        = "/jsonrpc"
+	kodiPropertyLastPlayed = "lastplayed"
+	kodiPropertyPlayCount  = "playcount"
+	kodiPropertyTitle      = "title"
+	kodiPropertyUniqueID   = "uniqueid"
+	kodiPropertyUserRating = "userrating"
+
+	kodiUnratedUserRating  = 0
+	kodiUnwatchedPlayCount = 0
+	kodiWatchedPlayCount   = 1
+)
+
+var errKodiUnsupported = errors.New("operation is not supported by kodi")
+
+// KodiClient syncs imdb data to a kodi library through its json-rpc api, which only supports the ratings and history.
+// Items are matched to the library by their imdb unique ids, which are provided by the scrapers of the library.
+type KodiClient struct {
+	ctx       context.Context
+	client    *http.Client
+	config    appconfig.Kodi
+	logger    *slog.Logger
+	requestID atomic.Int64
+	library   map[string]entities.KodiItem
+	notFound  entities.TraktItems
+}
+
+func NewKodiClient(ctx context.Context, conf appconfig.Kodi, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger) (DestinationClientInterface, error) {
+	return newKodiClient(ctx, conf, httpConf, store, logger)
+}
+
+// ValidateKodiAuth checks whether kodi accepts the credentials, by pinging its json-rpc api.
+func ValidateKodiAuth(ctx context.Context, conf appconfig.Kodi, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger) error {
+	c, err := newKodiClient(ctx, conf, httpConf, store, logger)
+	if err != nil {
+		return err
+	}
+	var pong string
+	if err = c.call(kodiMethodPing, nil, &pong); err != nil {
+		return fmt.Errorf("failure pinging kodi: %w", err)
+	}
+	return nil
+}
+
+func newKodiClient(ctx context.Context, conf appconfig.Kodi, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger) (*KodiClient, error) {
+	transport, err := newTransport(clientNameKodi, httpConf, store)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating transport: %w", err)
+	}
+	return &KodiClient{
+		ctx: ctx,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   httpConf.ClientTimeout(clientNameKodi),
+		},
+		config: conf,
+		logger: logger,
+	}, nil
+}
+
+func (kc *KodiClient) WatchlistGet() (*entities.TraktList, error) {
+	return nil, errKodiUnsupported
+}
+
+func (kc *KodiClient) WatchlistItemsAdd(entities.TraktItems) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) WatchlistItemsRemove(entities.TraktItems) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) ListGet(string) (*entities.TraktList, error) {
+	return nil, errKodiUnsupported
+}
+
+func (kc *KodiClient) ListsGet(idMeta entities.TraktIDMetas) ([]entities.TraktList, []error) {
+	if len(idMeta) == 0 {
+		return make([]entities.TraktList, 0), nil
+	}
+	return nil, []error{errKodiUnsupported}
+}
+
+func (kc *KodiClient) ListItemsAdd(string, entities.TraktItems) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) ListItemsRemove(string, entities.TraktItems) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) ListItemsReorder(string, []int64) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) ListAdd(string, string, string) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) ListUpdate(string, string) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) ListRemove(string) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) RatingsGet() (entities.TraktItems, error) {
+	return kc.filter(func(item entities.KodiItem) bool {
+		return item.UserRating != kodiUnratedUserRating
+	})
+}
+
+func (kc *KodiClient) RatingsAdd(items entities.TraktItems) error {
+	return kc.apply("rated", items, func(item entities.KodiItem, spec *entities.TraktItemSpec) map[string]any {
+		if spec.Rating == nil {
+			return nil
+		}
+		return map[string]any{kodiPropertyUserRating: *spec.Rating}
+	})
+}
+
+func (kc *KodiClient) RatingsRemove(items entities.TraktItems) error {
+	return kc.apply("unrated", items, func(entities.KodiItem, *entities.TraktItemSpec) map[string]any {
+		return map[string]any{kodiPropertyUserRating: kodiUnratedUserRating}
+	})
+}
+
+// HistoryGet returns the item if it is watched in the kodi library, since kodi only keeps a play count per item.
+func (kc *KodiClient) HistoryGet(itemType, itemID string) (entities.TraktItems, error) {
+	library, err := kc.libraryGet()
+	if err != nil {
+		return nil, err
+	}
+	item, found := library[itemID]
+	if !found || item.PlayCount == kodiUnwatchedPlayCount {
+		return nil, nil
+	}
+	if traktItem := item.ToTraktItem(); traktItem.Type == itemType {
+		return entities.TraktItems{traktItem}, nil
+	}
+	return nil, nil
+}
+
+// HistoryAdd marks movies and episodes as watched. Kodi derives the play count of a show from its episodes, so shows
+// are left untouched.
+func (kc *KodiClient) HistoryAdd(items entities.TraktItems) error {
+	return kc.apply("watched", items, func(item entities.KodiItem, spec *entities.TraktItemSpec) map[string]any {
+		if item.Type() == entities.KodiItemTypeTVShow {
+			return nil
+		}
+		properties := map[string]any{kodiPropertyPlayCount: max(item.PlayCount, kodiWatchedPlayCount)}
+		if lastPlayed := entities.KodiDateTime(spec.WatchedAt); lastPlayed != "" {
+			properties[kodiPropertyLastPlayed] = lastPlayed
+		}
+		return properties
+	})
+}
+
+func (kc *KodiClient) HistoryRemove(items entities.TraktItems) error {
+	return kc.apply("unwatched", items, func(item entities.KodiItem, _ *entities.TraktItemSpec) map[string]any {
+		if item.Type() == entities.KodiItemTypeTVShow {
+			return nil
+		}
+		return map[string]any{kodiPropertyPlayCount: kodiUnwatchedPlayCount}
+	})
+}
+
+func (kc *KodiClient) HistoryGetAll() (entities.TraktItems, error) {
+	return nil, errKodiUnsupported
+}
+
+func (kc *KodiClient) LikedListsGet() ([]entities.TraktList, error) {
+	return nil, errKodiUnsupported
+}
+
+func (kc *KodiClient) ListLike(string) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) ListUnlike(string) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) RecommendationsGet() (entities.TraktItems, error) {
+	return nil, errKodiUnsupported
+}
+
+func (kc *KodiClient) HiddenGet(string) (entities.TraktItems, error) {
+	return nil, errKodiUnsupported
+}
+
+func (kc *KodiClient) HiddenAdd(string, entities.TraktItems) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) HiddenRemove(string, entities.TraktItems) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) CollectionGet() (entities.TraktItems, error) {
+	return nil, errKodiUnsupported
+}
+
+func (kc *KodiClient) CollectionAdd(entities.TraktItems) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) CollectionRemove(entities.TraktItems) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) FavoritesGet() (entities.TraktItems, error) {
+	return nil, errKodiUnsupported
+}
+
+func (kc *KodiClient) FavoritesAdd(entities.TraktItems) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) FavoritesRemove(entities.TraktItems) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) CommentsGet() (entities.TraktItems, error) {
+	return nil, errKodiUnsupported
+}
+
+func (kc *KodiClient) CommentAdd(entities.TraktComment) error {
+	return errKodiUnsupported
+}
+
+func (kc *KodiClient) Checkin(entities.TraktItem) error {
+	return errKodiUnsupported
+}
+
+// LimitsGet returns no limits, since kodi does not cap the size of the library.
+func (kc *KodiClient) LimitsGet() (*entities.TraktLimits, error) {
+	return nil, nil
+}
+
+// NotFound returns the items that are missing from the kodi library, since the last time it was called.
+func (kc *KodiClient) NotFound() entities.TraktItems {
+	notFound := kc.notFound
+	kc.notFound = nil
+	return notFound
+}
+
+// libraryGet fetches the movies, shows and episodes of the kodi library once, indexed by their imdb id, and caches them
+// until the next mutation. Items without an imdb id can't be matched, so they are left out.
+func (kc *KodiClient) libraryGet() (map[string]entities.KodiItem, error) {
+	if kc.library != nil {
+		return kc.library, nil
+	}
+	params := map[string]any{
+		"properties": []string{kodiPropertyTitle, kodiPropertyUniqueID, kodiPropertyPlayCount, kodiPropertyUserRating, kodiPropertyLastPlayed},
+	}
+	var movies, shows, episodes entities.KodiLibrary
+	if err := kc.call(kodiMethodGetMovies, params, &movies); err != nil {
+		return nil, fmt.Errorf("failure fetching kodi movies: %w", err)
+	}
+	// shows have no last played time of their own
+	showParams := map[string]any{
+		"properties": []string{kodiPropertyTitle, kodiPropertyUniqueID, kodiPropertyPlayCount, kodiPropertyUserRating},
+	}
+	if err := kc.call(kodiMethodGetTVShows, showParams, &shows); err != nil {
+		return nil, fmt.Errorf("failure fetching kodi shows: %w", err)
+	}
+	if err := kc.call(kodiMethodGetEpisodes, params, &episodes); err != nil {
+		return nil, fmt.Errorf("failure fetching kodi episodes: %w", err)
+	}
+	library := make(map[string]entities.KodiItem, len(movies.Movies)+len(shows.TVShows)+len(episodes.Episodes))
+	for _, group := range [][]entities.KodiItem{movies.Movies, shows.TVShows, episodes.Episodes} {
+		for _, item := range group {
+			if id := item.IMDbID(); id != "" {
+				library[id] = item
+			}
+		}
+	}
+	kc.library = library
+	return library, nil
+}
+
+// filter returns the library items that satisfy the predicate as trakt items.
+func (kc *KodiClient) filter(predicate func(entities.KodiItem) bool) (entities.TraktItems, error) {
+	library, err := kc.libraryGet()
+	if err != nil {
+		return nil, err
+	}
+	var items entities.TraktItems
+	for _, item := range library {
+		if predicate(item) {
+			items = append(items, item.ToTraktItem())
+		}
+	}
+	return items, nil
+}
+
+// apply updates the details of each item with the properties that the function returns, since kodi updates one item
+// per request. Items that the function returns no properties for are skipped, while items that are missing from the
+// library are reported as not found.
+func (kc *KodiClient) apply(action string, items entities.TraktItems, fn func(item entities.KodiItem, spec *entities.TraktItemSpec) map[string]any) error {
+	library, err := kc.libraryGet()
+	if err != nil {
+		return err
+	}
+	kc.library = nil
+	var applied int
+	for _, item := range items {
+		spec := item.Spec()
+		if spec == nil {
+			continue
+		}
+		libraryItem, found := library[spec.IDMeta.IMDb]
+		if !found {
+			kc.notFound = append(kc.notFound, item)
+			continue
+		}
+		params := fn(libraryItem, spec)
+		if params == nil {
+			continue
+		}
+		idKey, id := libraryItem.ID()
+		params[idKey] = id
+		method := kodiMethodSetEpisode
+		switch libraryItem.Type() {
+		case entities.KodiItemTypeMovie:
+			method = kodiMethodSetMovie
+		case entities.KodiItemTypeTVShow:
+			method = kodiMethodSetTVShow
+		}
+		var result string
+		if err = kc.call(method, params, &result); err != nil {
+			return fmt.Errorf("failure updating kodi item %s: %w", spec.IDMeta.IMDb, err)
+		}
+		applied++
+	}
+	kc.logger.Info("synced kodi items", slog.String("action", action), slog.Int("count", applied))
+	return nil
+}
+
+// call invokes a json-rpc method of kodi, and decodes its result.
+func (kc *KodiClient) call(method string, params any, result any) error {
+	body, err := json.Marshal(entities.KodiRequest{
+		JSONRPC: kodiJSONRPCVersion,
+		ID:      kc.requestID.Add(1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+	target := strings.TrimSuffix(*kc.config.URL, "/") + kodiPathJSONRPC
+	request, err := http.NewRequestWithContext(kc.ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating http request %s %s: %w", http.MethodPost, target, err)
+	}
+	request.Header.Set(kodiHeaderKeyContentType, "application/json")
+	if *kc.config.Username != "" {
+		request.SetBasicAuth(*kc.config.Username, *kc.config.Password)
+	}
+	response, err := kc.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("error sending http request %s, %s: %w", request.Method, request.URL, err)
+	}
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return &ApiError{
+			httpMethod: request.Method,
+			url:        request.URL.String(),
+			StatusCode: response.StatusCode,
+			details:    fmt.Sprintf("unexpected status code %d", response.StatusCode),
+		}
+	}
+	decoded, err := decodeReader[entities.KodiResponse[json.RawMessage]](response.Body)
+	if err != nil {
+		return err
+	}
+	if decoded.Error != nil {
+		return decoded.Error
+	}
+	return json.Unmarshal(decoded.Result, result)
+}
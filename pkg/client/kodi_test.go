@@ -0,0 +1,223 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+const (
+	dummyKodiURL      = "http://kodi.local"
+	dummyKodiMovies   = `{"movies": [{"movieid": 1, "label": "Dunkirk", "uniqueid": {"imdb": "tt5013056", "tmdb": "374720"}, "playcount": 2, "userrating": 7, "lastplayed": "2024-01-01 00:00:00"}, {"movieid": 2, "label": "Home Video", "uniqueid": {}, "playcount": 1, "userrating": 5}]}`
+	dummyKodiShows    = `{"tvshows": [{"tvshowid": 3, "label": "Breaking Bad", "uniqueid": {"imdb": "tt0903747"}, "playcount": 0, "userrating": 0}]}`
+	dummyKodiEpisodes = `{"episodes": [{"episodeid": 4, "label": "Pilot", "uniqueid": {"imdb": "tt0959621"}, "playcount": 1, "userrating": 9, "lastplayed": "2024-01-02 00:00:00"}]}`
+)
+
+func buildTestKodiClient() *KodiClient {
+	return &KodiClient{
+		ctx: context.Background(),
+		client: &http.Client{
+			Transport: httpmock.DefaultTransport,
+		},
+		config: appconfig.Kodi{
+			URL:      pointer(dummyKodiURL + "/"),
+			Username: pointer("kodi"),
+			Password: pointer("secret"),
+		},
+		logger: logger.NewLogger(io.Discard),
+	}
+}
+
+// registerKodiResponder answers the json-rpc requests of the library with the dummy library, and hands every other
+// request to the handler.
+func registerKodiResponder(assertions *assert.Assertions, handler func(request entities.KodiRequest) string) {
+	httpmock.RegisterResponder(http.MethodPost, dummyKodiURL+kodiPathJSONRPC, func(request *http.Request) (*http.Response, error) {
+		username, password, ok := request.BasicAuth()
+		assertions.True(ok)
+		assertions.Equal("kodi", username)
+		assertions.Equal("secret", password)
+		var body entities.KodiRequest
+		assertions.NoError(json.NewDecoder(request.Body).Decode(&body))
+		var result string
+		switch body.Method {
+		case kodiMethodGetMovies:
+			result = dummyKodiMovies
+		case kodiMethodGetTVShows:
+			result = dummyKodiShows
+		case kodiMethodGetEpisodes:
+			result = dummyKodiEpisodes
+		default:
+			return httpmock.NewStringResponse(http.StatusOK, handler(body)), nil
+		}
+		return httpmock.NewStringResponse(http.StatusOK, `{"jsonrpc": "2.0", "id": 1, "result": `+result+`}`), nil
+	})
+}
+
+func TestKodiClient_Library(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func(*assert.Assertions)
+		assertions   func(*assert.Assertions, *KodiClient)
+	}{
+		{
+			name: "successfully get ratings",
+			requirements: func(assertions *assert.Assertions) {
+				registerKodiResponder(assertions, nil)
+			},
+			assertions: func(assertions *assert.Assertions, c *KodiClient) {
+				ratings, err := c.RatingsGet()
+				assertions.NoError(err)
+				assertions.Len(ratings, 2)
+				for _, item := range ratings {
+					switch item.Type {
+					case entities.TraktItemTypeMovie:
+						assertions.Equal("tt5013056", item.Movie.IDMeta.IMDb)
+						assertions.Equal(7, item.Rating)
+					case entities.TraktItemTypeEpisode:
+						assertions.Equal("tt0959621", item.Episode.IDMeta.IMDb)
+						assertions.Equal(9, item.Rating)
+					default:
+						assertions.Failf("unexpected item type", "type %s", item.Type)
+					}
+				}
+			},
+		},
+		{
+			name: "successfully get history",
+			requirements: func(assertions *assert.Assertions) {
+				registerKodiResponder(assertions, nil)
+			},
+			assertions: func(assertions *assert.Assertions, c *KodiClient) {
+				history, err := c.HistoryGet(entities.TraktItemTypeMovie, "tt5013056")
+				assertions.NoError(err)
+				assertions.Len(history, 1)
+				assertions.Equal("2024-01-01 00:00:00", history[0].WatchedAt)
+				history, err = c.HistoryGet(entities.TraktItemTypeShow, "tt0903747")
+				assertions.NoError(err)
+				assertions.Empty(history)
+				assertions.Equal(3, httpmock.GetTotalCallCount())
+			},
+		},
+		{
+			name: "failure getting library",
+			requirements: func(*assert.Assertions) {
+				httpmock.RegisterResponder(http.MethodPost, dummyKodiURL+kodiPathJSONRPC, httpmock.NewStringResponder(http.StatusUnauthorized, ""))
+			},
+			assertions: func(assertions *assert.Assertions, c *KodiClient) {
+				ratings, err := c.RatingsGet()
+				assertions.Nil(ratings)
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusUnauthorized, apiError.StatusCode)
+			},
+		},
+		{
+			name: "failure with json-rpc error",
+			requirements: func(*assert.Assertions) {
+				httpmock.RegisterResponder(http.MethodPost, dummyKodiURL+kodiPathJSONRPC, httpmock.NewStringResponder(http.StatusOK, `{"jsonrpc": "2.0", "id": 1, "error": {"code": -32601, "message": "Method not found."}}`))
+			},
+			assertions: func(assertions *assert.Assertions, c *KodiClient) {
+				ratings, err := c.RatingsGet()
+				assertions.Nil(ratings)
+				var kodiError *entities.KodiError
+				assertions.True(errors.As(err, &kodiError))
+				assertions.Equal(-32601, kodiError.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			assertions := assert.New(t)
+			tt.requirements(assertions)
+			tt.assertions(assertions, buildTestKodiClient())
+		})
+	}
+}
+
+func TestKodiClient_Mutations(t *testing.T) {
+	rated := 8
+	watchedAt := "2024-02-03T04:05:06Z"
+	items := entities.TraktItems{
+		{
+			Type: entities.TraktItemTypeMovie,
+			Movie: entities.TraktItemSpec{
+				IDMeta:    entities.TraktIDMeta{IMDb: "tt5013056"},
+				Rating:    &rated,
+				WatchedAt: &watchedAt,
+			},
+		},
+		{
+			Type: entities.TraktItemTypeMovie,
+			Movie: entities.TraktItemSpec{
+				IDMeta: entities.TraktIDMeta{IMDb: "tt0000404"},
+			},
+		},
+	}
+	tests := []struct {
+		name   string
+		want   string
+		mutate func(*KodiClient) error
+	}{
+		{
+			name: "successfully add ratings",
+			want: `{"movieid": 1, "userrating": 8}`,
+			mutate: func(c *KodiClient) error {
+				return c.RatingsAdd(items)
+			},
+		},
+		{
+			name: "successfully remove ratings",
+			want: `{"movieid": 1, "userrating": 0}`,
+			mutate: func(c *KodiClient) error {
+				return c.RatingsRemove(items)
+			},
+		},
+		{
+			name: "successfully add history",
+			want: `{"movieid": 1, "playcount": 2, "lastplayed": "2024-02-03 04:05:06"}`,
+			mutate: func(c *KodiClient) error {
+				return c.HistoryAdd(items)
+			},
+		},
+		{
+			name: "successfully remove history",
+			want: `{"movieid": 1, "playcount": 0}`,
+			mutate: func(c *KodiClient) error {
+				return c.HistoryRemove(items)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			assertions := assert.New(t)
+			registerKodiResponder(assertions, func(request entities.KodiRequest) string {
+				assertions.Equal(kodiMethodSetMovie, request.Method)
+				params, err := json.Marshal(request.Params)
+				assertions.NoError(err)
+				assertions.JSONEq(tt.want, string(params))
+				return `{"jsonrpc": "2.0", "id": 1, "result": "OK"}`
+			})
+			c := buildTestKodiClient()
+			assertions.NoError(tt.mutate(c))
+			assertions.Equal(4, httpmock.GetTotalCallCount())
+			notFound := c.NotFound()
+			assertions.Len(notFound, 1)
+			assertions.Equal("tt0000404", notFound[0].Movie.IDMeta.IMDb)
+			assertions.Empty(c.NotFound())
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+)
+
+const (
+	clientNamePlex = "plex"
+
+	plexHeaderKeyAccept = "Accept"
+	plexHeaderKeyToken  = "X-Plex-Token"
+
+	plexPathBaseDiscover    = "https://discover.provider.plex.tv"
+	plexPathBaseMetadata    = "https://metadata.provider.plex.tv"
+	plexPathMetadata        = "/library/metadata/%s?includeGuids=1"
+	plexPathMetadataMatches = "/library/metadata/matches?type=%s&guid=%s"
+	plexPathWatchlist       = "/library/sections/watchlist/all?includeGuids=1&X-Plex-Container-Start=%d&X-Plex-Container-Size=%d"
+	plexPathWatchlistAdd    = "/actions/addToWatchlist?ratingKey=%s"
+	plexPathWatchlistRemove = "/actions/removeFromWatchlist?ratingKey=%s"
+	plexWatchlistPageSize   = 100
+	plexMetadataTypeMovie   = "1"
+	plexMetadataTypeShow    = "2"
+)
+
+type PlexClient struct {
+	ctx    context.Context
+	client *http.Client
+	config appconfig.Plex
+	logger *slog.Logger
+}
+
+func NewPlexClient(ctx context.Context, conf appconfig.Plex, httpConf appconfig.HTTP, logger *slog.Logger) (PlexClientInterface, error) {
+	transport, err := newTransport(clientNamePlex, httpConf, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating transport: %w", err)
+	}
+	return &PlexClient{
+		ctx: ctx,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   httpConf.ClientTimeout(clientNamePlex),
+		},
+		config: conf,
+		logger: logger,
+	}, nil
+}
+
+func (pc *PlexClient) WatchlistGet() ([]entities.PlexItem, error) {
+	var items []entities.PlexItem
+	for offset := 0; ; offset += plexWatchlistPageSize {
+		response, err := pc.doRequest(http.MethodGet, plexPathBaseDiscover, fmt.Sprintf(plexPathWatchlist, offset, plexWatchlistPageSize))
+		if err != nil {
+			return nil, err
+		}
+		plexResponse, err := decodeReader[*entities.PlexResponse](response.Body)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, plexResponse.MediaContainer.Metadata...)
+		if len(plexResponse.MediaContainer.Metadata) == 0 || len(items) >= plexResponse.MediaContainer.TotalSize {
+			break
+		}
+	}
+	for i := range items {
+		if items[i].IMDbID() != "" {
+			continue
+		}
+		item, err := pc.MetadataGet(items[i].RatingKey)
+		if err != nil {
+			return nil, fmt.Errorf("failure fetching plex metadata for %s: %w", items[i].Title, err)
+		}
+		items[i].GUIDs = item.GUIDs
+	}
+	return items, nil
+}
+
+func (pc *PlexClient) WatchlistItemAdd(ratingKey string) error {
+	response, err := pc.doRequest(http.MethodPut, plexPathBaseDiscover, fmt.Sprintf(plexPathWatchlistAdd, url.QueryEscape(ratingKey)))
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	return nil
+}
+
+func (pc *PlexClient) WatchlistItemRemove(ratingKey string) error {
+	response, err := pc.doRequest(http.MethodPut, plexPathBaseDiscover, fmt.Sprintf(plexPathWatchlistRemove, url.QueryEscape(ratingKey)))
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	return nil
+}
+
+func (pc *PlexClient) MetadataGet(ratingKey string) (*entities.PlexItem, error) {
+	response, err := pc.doRequest(http.MethodGet, plexPathBaseMetadata, fmt.Sprintf(plexPathMetadata, url.PathEscape(ratingKey)))
+	if err != nil {
+		return nil, err
+	}
+	plexResponse, err := decodeReader[*entities.PlexResponse](response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(plexResponse.MediaContainer.Metadata) == 0 {
+		return nil, fmt.Errorf("plex metadata for %s is empty", ratingKey)
+	}
+	return &plexResponse.MediaContainer.Metadata[0], nil
+}
+
+// MetadataMatch finds the plex item with the provided imdb id, returning nil when plex has no match.
+func (pc *PlexClient) MetadataMatch(imdbID, itemType string) (*entities.PlexItem, error) {
+	metadataType := plexMetadataTypeMovie
+	if itemType == entities.PlexItemTypeShow {
+		metadataType = plexMetadataTypeShow
+	}
+	guid := url.QueryEscape(entities.PlexGUIDFromIMDbID(imdbID))
+	response, err := pc.doRequest(http.MethodGet, plexPathBaseMetadata, fmt.Sprintf(plexPathMetadataMatches, metadataType, guid))
+	if err != nil {
+		return nil, err
+	}
+	plexResponse, err := decodeReader[*entities.PlexResponse](response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(plexResponse.MediaContainer.Metadata) == 0 {
+		return nil, nil
+	}
+	return &plexResponse.MediaContainer.Metadata[0], nil
+}
+
+func (pc *PlexClient) doRequest(method, basePath, endpoint string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(pc.ctx, method, basePath+endpoint, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating http request %s %s: %w", method, basePath+endpoint, err)
+	}
+	request.Header.Set(plexHeaderKeyAccept, "application/json")
+	request.Header.Set(plexHeaderKeyToken, *pc.config.Token)
+	response, err := pc.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error sending http request %s, %s: %w", request.Method, request.URL, err)
+	}
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return response, nil
+	default:
+		response.Body.Close()
+		return nil, &ApiError{
+			httpMethod: request.Method,
+			url:        request.URL.String(),
+			StatusCode: response.StatusCode,
+			details:    "unexpected status code " + strconv.Itoa(response.StatusCode),
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+func buildTestPlexClient() *PlexClient {
+	return &PlexClient{
+		ctx: context.Background(),
+		client: &http.Client{
+			Transport: httpmock.DefaultTransport,
+		},
+		config: appconfig.Plex{
+			Enabled: pointer(true),
+			Token:   pointer("token"),
+		},
+		logger: logger.NewLogger(io.Discard),
+	}
+}
+
+func TestPlexClient_WatchlistGet(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func()
+		assertions   func(*assert.Assertions, []entities.PlexItem, error)
+	}{
+		{
+			name: "successfully get watchlist",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					plexPathBaseDiscover+fmt.Sprintf(plexPathWatchlist, 0, plexWatchlistPageSize),
+					httpmock.NewStringResponder(http.StatusOK, `{"MediaContainer":{"totalSize":2,"Metadata":[{"ratingKey":"5d776825880197001ec967c6","type":"movie","title":"The Shawshank Redemption","Guid":[{"id":"imdb://tt0111161"}]},{"ratingKey":"5d9c086c46115600200aa2fe","type":"show","title":"Breaking Bad"}]}}`),
+				)
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					plexPathBaseMetadata+fmt.Sprintf(plexPathMetadata, "5d9c086c46115600200aa2fe"),
+					httpmock.NewStringResponder(http.StatusOK, `{"MediaContainer":{"Metadata":[{"ratingKey":"5d9c086c46115600200aa2fe","type":"show","title":"Breaking Bad","Guid":[{"id":"tvdb://81189"},{"id":"imdb://tt0903747"}]}]}}`),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, items []entities.PlexItem, err error) {
+				assertions.NoError(err)
+				assertions.Len(items, 2)
+				assertions.Equal("tt0111161", items[0].IMDbID())
+				assertions.Equal("tt0903747", items[1].IMDbID())
+			},
+		},
+		{
+			name: "failure getting watchlist",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					plexPathBaseDiscover+fmt.Sprintf(plexPathWatchlist, 0, plexWatchlistPageSize),
+					httpmock.NewStringResponder(http.StatusUnauthorized, ""),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, items []entities.PlexItem, err error) {
+				assertions.Nil(items)
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusUnauthorized, apiError.StatusCode)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestPlexClient()
+			items, err := c.WatchlistGet()
+			tt.assertions(assert.New(t), items, err)
+		})
+	}
+}
+
+func TestPlexClient_MetadataMatch(t *testing.T) {
+	matchesURL := plexPathBaseMetadata + fmt.Sprintf(plexPathMetadataMatches, plexMetadataTypeMovie, url.QueryEscape("imdb://tt0111161"))
+	tests := []struct {
+		name         string
+		requirements func()
+		assertions   func(*assert.Assertions, *entities.PlexItem, error)
+	}{
+		{
+			name: "successfully match item",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					matchesURL,
+					httpmock.NewStringResponder(http.StatusOK, `{"MediaContainer":{"Metadata":[{"ratingKey":"5d776825880197001ec967c6","type":"movie"}]}}`),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, item *entities.PlexItem, err error) {
+				assertions.NoError(err)
+				assertions.Equal("5d776825880197001ec967c6", item.RatingKey)
+			},
+		},
+		{
+			name: "handle missing match",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodGet,
+					matchesURL,
+					httpmock.NewStringResponder(http.StatusOK, `{"MediaContainer":{"Metadata":[]}}`),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, item *entities.PlexItem, err error) {
+				assertions.NoError(err)
+				assertions.Nil(item)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestPlexClient()
+			item, err := c.MetadataMatch("tt0111161", entities.PlexItemTypeMovie)
+			tt.assertions(assert.New(t), item, err)
+		})
+	}
+}
+
+func TestPlexClient_WatchlistItemAdd(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func()
+		assertions   func(*assert.Assertions, error)
+	}{
+		{
+			name: "successfully add item",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPut,
+					plexPathBaseDiscover+fmt.Sprintf(plexPathWatchlistAdd, "5d776825880197001ec967c6"),
+					httpmock.NewStringResponder(http.StatusOK, ""),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "failure adding item",
+			requirements: func() {
+				httpmock.RegisterResponder(
+					http.MethodPut,
+					plexPathBaseDiscover+fmt.Sprintf(plexPathWatchlistAdd, "5d776825880197001ec967c6"),
+					httpmock.NewStringResponder(http.StatusInternalServerError, ""),
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			c := buildTestPlexClient()
+			err := c.WatchlistItemAdd("5d776825880197001ec967c6")
+			tt.assertions(assert.New(t), err)
+		})
+	}
+}
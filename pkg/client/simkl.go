@@ -0,0 +1,405 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
+)
+
+const (
+	clientNameSimkl = "simkl"
+
+	simklHeaderKeyApiKey        = "simkl-api-key"
+	simklHeaderKeyAuthorization = "Authorization"
+	simklHeaderKeyContentType   = "Content-Type"
+
+	simklPathAllItems      = "/sync/all-items/"
+	simklPathAddToList     = "/sync/add-to-list"
+	simklPathBase          = "https://api.simkl.com"
+	simklPathHistory       = "/sync/history"
+	simklPathHistoryRemove = "/sync/history/remove"
+	simklPathPin           = "/oauth/pin?client_id=%s"
+	simklPathPinCheck      = "/oauth/pin/%s?client_id=%s"
+	simklPathRatings       = "/sync/ratings"
+	simklPathRatingsRemove = "/sync/ratings/remove"
+	simklPathUserSettings  = "/users/settings"
+
+	simklResultOK        = "OK"
+	simklStateKeyTokens  = "simkl-tokens"
+	simklDefaultInterval = 5
+)
+
+var errSimklUnsupported = errors.New("operation is not supported by simkl")
+
+// SimklClient syncs imdb data to simkl, which only supports the watchlist, ratings and history.
+type SimklClient struct {
+	ctx         context.Context
+	client      *http.Client
+	config      appconfig.Simkl
+	logger      *slog.Logger
+	store       state.Store
+	accessToken string
+	// library is the cached library of the user, which the syncer reads from several goroutines at once.
+	library   *entities.SimklAllItems
+	libraryMu sync.Mutex
+}
+
+func NewSimklClient(ctx context.Context, conf appconfig.Simkl, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger) (DestinationClientInterface, error) {
+	return loadSimklClient(ctx, conf, httpConf, store, logger)
+}
+
+func loadSimklClient(ctx context.Context, conf appconfig.Simkl, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger) (*SimklClient, error) {
+	c, err := newSimklClient(ctx, conf, httpConf, store, logger)
+	if err != nil {
+		return nil, err
+	}
+	var tokens entities.SimklTokenResponse
+	if err := store.Load(simklStateKeyTokens, &tokens); err != nil {
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, fmt.Errorf("no stored simkl tokens found, run the auth simkl command first")
+		}
+		return nil, fmt.Errorf("failure loading stored simkl tokens: %w", err)
+	}
+	c.accessToken = tokens.AccessToken
+	return c, nil
+}
+
+// AuthorizeSimklPin runs the simkl pin flow interactively and persists the obtained token in the store.
+// The prompt function is called with the user code and verification url that the user needs to visit.
+func AuthorizeSimklPin(ctx context.Context, conf appconfig.Simkl, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger, prompt func(*entities.SimklPinResponse)) error {
+	c, err := newSimklClient(ctx, conf, httpConf, store, logger)
+	if err != nil {
+		return err
+	}
+	response, err := c.doRequest(http.MethodGet, fmt.Sprintf(simklPathPin, url.QueryEscape(*conf.ClientID)), nil)
+	if err != nil {
+		return fmt.Errorf("failure generating pin: %w", err)
+	}
+	pin, err := decodeReader[*entities.SimklPinResponse](response.Body)
+	if err != nil {
+		return err
+	}
+	prompt(pin)
+	interval := time.Duration(max(pin.Interval, simklDefaultInterval)) * time.Second
+	deadline := time.Now().Add(time.Duration(pin.ExpiresIn) * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		response, err = c.doRequest(http.MethodGet, fmt.Sprintf(simklPathPinCheck, url.PathEscape(pin.UserCode), url.QueryEscape(*conf.ClientID)), nil)
+		if err != nil {
+			return fmt.Errorf("failure polling simkl for access token: %w", err)
+		}
+		tokens, err := decodeReader[*entities.SimklTokenResponse](response.Body)
+		if err != nil {
+			return err
+		}
+		if tokens.Result != simklResultOK || tokens.AccessToken == "" {
+			continue
+		}
+		if err = store.Save(simklStateKeyTokens, tokens); err != nil {
+			return fmt.Errorf("failure storing simkl tokens: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("pin expired before the user authorized the app")
+}
+
+// ValidateSimklAuth checks whether the stored simkl access token is still accepted by simkl.
+func ValidateSimklAuth(ctx context.Context, conf appconfig.Simkl, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger) error {
+	c, err := loadSimklClient(ctx, conf, httpConf, store, logger)
+	if err != nil {
+		return err
+	}
+	response, err := c.doRequest(http.MethodPost, simklPathUserSettings, nil)
+	if err != nil {
+		return fmt.Errorf("failure fetching simkl user settings: %w", err)
+	}
+	response.Body.Close()
+	return nil
+}
+
+func newSimklClient(ctx context.Context, conf appconfig.Simkl, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger) (*SimklClient, error) {
+	transport, err := newTransport(clientNameSimkl, httpConf, store)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating transport: %w", err)
+	}
+	return &SimklClient{
+		ctx: ctx,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   httpConf.ClientTimeout(clientNameSimkl),
+		},
+		config: conf,
+		logger: logger,
+		store:  store,
+	}, nil
+}
+
+func (sc *SimklClient) WatchlistGet() (*entities.TraktList, error) {
+	library, err := sc.libraryGet()
+	if err != nil {
+		return nil, err
+	}
+	return &entities.TraktList{
+		IDMeta: entities.TraktIDMeta{
+			Slug: "watchlist",
+		},
+		ListItems: library.Filter(func(item entities.SimklAllItem) bool {
+			return item.Status == entities.SimklStatusPlanToWatch
+		}),
+		IsWatchlist: true,
+	}, nil
+}
+
+func (sc *SimklClient) WatchlistItemsAdd(items entities.TraktItems) error {
+	return sc.post(simklPathAddToList, entities.NewSimklBody(items, entities.SimklStatusPlanToWatch))
+}
+
+// WatchlistItemsRemove leaves the items on the simkl watchlist, since simkl only removes items from its lists by
+// deleting their watch history as well. The syncer never removes items from the simkl watchlist, so this is only
+// reached when undoing a sync that added items to it.
+func (sc *SimklClient) WatchlistItemsRemove(items entities.TraktItems) error {
+	if len(items) > 0 {
+		sc.logger.Warn(fmt.Sprintf("skipping removal of %d simkl watchlist item(s), since simkl would delete their watch history as well", len(items)))
+	}
+	return nil
+}
+
+func (sc *SimklClient) ListGet(string) (*entities.TraktList, error) {
+	return nil, errSimklUnsupported
+}
+
+func (sc *SimklClient) ListsGet(idMeta entities.TraktIDMetas) ([]entities.TraktList, []error) {
+	if len(idMeta) == 0 {
+		return make([]entities.TraktList, 0), nil
+	}
+	return nil, []error{errSimklUnsupported}
+}
+
+func (sc *SimklClient) ListItemsAdd(string, entities.TraktItems) error {
+	return errSimklUnsupported
+}
+
+func (sc *SimklClient) ListItemsRemove(string, entities.TraktItems) error {
+	return errSimklUnsupported
+}
+
+func (sc *SimklClient) ListItemsReorder(string, []int64) error {
+	return errSimklUnsupported
+}
+
+func (sc *SimklClient) ListAdd(string, string, string) error {
+	return errSimklUnsupported
+}
+
+func (sc *SimklClient) ListUpdate(string, string) error {
+	return errSimklUnsupported
+}
+
+func (sc *SimklClient) ListRemove(string) error {
+	return errSimklUnsupported
+}
+
+func (sc *SimklClient) RatingsGet() (entities.TraktItems, error) {
+	library, err := sc.libraryGet()
+	if err != nil {
+		return nil, err
+	}
+	return library.Filter(func(item entities.SimklAllItem) bool {
+		return item.UserRating != nil
+	}), nil
+}
+
+func (sc *SimklClient) RatingsAdd(items entities.TraktItems) error {
+	return sc.post(simklPathRatings, entities.NewSimklBody(items, ""))
+}
+
+func (sc *SimklClient) RatingsRemove(items entities.TraktItems) error {
+	return sc.post(simklPathRatingsRemove, entities.NewSimklBody(items, ""))
+}
+
+// HistoryGet returns the item if it is completed in the simkl library, since simkl does not expose watch history per item.
+func (sc *SimklClient) HistoryGet(itemType, itemID string) (entities.TraktItems, error) {
+	library, err := sc.libraryGet()
+	if err != nil {
+		return nil, err
+	}
+	return library.Filter(func(item entities.SimklAllItem) bool {
+		traktItem := item.ToTraktItem()
+		id, err := traktItem.GetItemID()
+		return err == nil && id != nil && *id == itemID && traktItem.Type == itemType && item.Status == entities.SimklStatusCompleted
+	}), nil
+}
+
+func (sc *SimklClient) HistoryAdd(items entities.TraktItems) error {
+	return sc.post(simklPathHistory, entities.NewSimklBody(items, ""))
+}
+
+func (sc *SimklClient) HistoryRemove(items entities.TraktItems) error {
+	return sc.post(simklPathHistoryRemove, entities.NewSimklBody(items, ""))
+}
+
+func (sc *SimklClient) HistoryGetAll() (entities.TraktItems, error) {
+	return nil, errSimklUnsupported
+}
+
+func (sc *SimklClient) LikedListsGet() ([]entities.TraktList, error) {
+	return nil, errSimklUnsupported
+}
+
+func (sc *SimklClient) ListLike(string) error {
+	return errSimklUnsupported
+}
+
+func (sc *SimklClient) ListUnlike(string) error {
+	return errSimklUnsupported
+}
+
+func (sc *SimklClient) RecommendationsGet() (entities.TraktItems, error) {
+	return nil, errSimklUnsupported
+}
+
+func (sc *SimklClient) HiddenGet(string) (entities.TraktItems, error) {
+	return nil, errSimklUnsupported
+}
+
+func (sc *SimklClient) HiddenAdd(string, entities.TraktItems) error {
+	return errSimklUnsupported
+}
+
+func (sc *SimklClient) HiddenRemove(string, entities.TraktItems) error {
+	return errSimklUnsupported
+}
+
+func (sc *SimklClient) Checkin(entities.TraktItem) error {
+	return errSimklUnsupported
+}
+
+// LimitsGet returns no limits, since simkl does not cap the size of the library.
+func (sc *SimklClient) LimitsGet() (*entities.TraktLimits, error) {
+	return nil, nil
+}
+
+func (sc *SimklClient) CollectionGet() (entities.TraktItems, error) {
+	return nil, errSimklUnsupported
+}
+
+func (sc *SimklClient) CollectionAdd(entities.TraktItems) error {
+	return errSimklUnsupported
+}
+
+func (sc *SimklClient) CollectionRemove(entities.TraktItems) error {
+	return errSimklUnsupported
+}
+
+func (sc *SimklClient) FavoritesGet() (entities.TraktItems, error) {
+	return nil, errSimklUnsupported
+}
+
+func (sc *SimklClient) FavoritesAdd(entities.TraktItems) error {
+	return errSimklUnsupported
+}
+
+func (sc *SimklClient) FavoritesRemove(entities.TraktItems) error {
+	return errSimklUnsupported
+}
+
+func (sc *SimklClient) CommentsGet() (entities.TraktItems, error) {
+	return nil, errSimklUnsupported
+}
+
+// NotFound returns no items, since simkl does not report the items that it could not find.
+func (sc *SimklClient) NotFound() entities.TraktItems {
+	return nil
+}
+
+func (sc *SimklClient) CommentAdd(entities.TraktComment) error {
+	return errSimklUnsupported
+}
+
+// libraryGet fetches the user's simkl library once and caches it until the next mutation. Concurrent callers wait for
+// the library that the first caller fetches.
+func (sc *SimklClient) libraryGet() (*entities.SimklAllItems, error) {
+	sc.libraryMu.Lock()
+	defer sc.libraryMu.Unlock()
+	if sc.library != nil {
+		return sc.library, nil
+	}
+	response, err := sc.doRequest(http.MethodGet, simklPathAllItems, nil)
+	if err != nil {
+		return nil, err
+	}
+	library, err := decodeReader[*entities.SimklAllItems](response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if library == nil {
+		library = &entities.SimklAllItems{}
+	}
+	sc.library = library
+	return library, nil
+}
+
+func (sc *SimklClient) post(endpoint string, body entities.SimklBody) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	response, err := sc.doRequest(http.MethodPost, endpoint, data)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	sc.libraryMu.Lock()
+	sc.library = nil
+	sc.libraryMu.Unlock()
+	sc.logger.Info("synced simkl items", slog.String("endpoint", endpoint), slog.Int("movies", len(body.Movies)), slog.Int("shows", len(body.Shows)))
+	return nil
+}
+
+func (sc *SimklClient) doRequest(method, endpoint string, body []byte) (*http.Response, error) {
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(sc.ctx, method, simklPathBase+endpoint, reader)
+	if err != nil {
+		return nil, fmt.Errorf("error creating http request %s %s: %w", method, simklPathBase+endpoint, err)
+	}
+	request.Header.Set(simklHeaderKeyContentType, "application/json")
+	request.Header.Set(simklHeaderKeyApiKey, *sc.config.ClientID)
+	if sc.accessToken != "" {
+		request.Header.Set(simklHeaderKeyAuthorization, fmt.Sprintf("Bearer %s", sc.accessToken))
+	}
+	response, err := sc.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error sending http request %s, %s: %w", request.Method, request.URL, err)
+	}
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return response, nil
+	default:
+		response.Body.Close()
+		return nil, &ApiError{
+			httpMethod: request.Method,
+			url:        request.URL.String(),
+			StatusCode: response.StatusCode,
+			details:    fmt.Sprintf("unexpected status code %d", response.StatusCode),
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+
+	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
+	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/logger"
+)
+
+const dummySimklLibrary = `{
+  "movies": [
+    {"status": "plantowatch", "user_rating": null, "movie": {"title": "Batman Begins", "ids": {"imdb": "tt0372784"}}},
+    {"status": "completed", "user_rating": 8, "user_rated_at": "2024-01-01T00:00:00Z", "movie": {"title": "The Shawshank Redemption", "ids": {"imdb": "tt0111161"}}}
+  ],
+  "shows": [
+    {"status": "completed", "user_rating": 10, "user_rated_at": "2024-01-02T00:00:00Z", "show": {"title": "Breaking Bad", "ids": {"imdb": "tt0903747"}}}
+  ]
+}`
+
+func buildTestSimklClient() *SimklClient {
+	return &SimklClient{
+		ctx: context.Background(),
+		client: &http.Client{
+			Transport: httpmock.DefaultTransport,
+		},
+		config: appconfig.Simkl{
+			ClientID: pointer("client-id"),
+		},
+		logger:      logger.NewLogger(io.Discard),
+		accessToken: "access-token-value",
+	}
+}
+
+func TestSimklClient_Library(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func()
+		assertions   func(*assert.Assertions, *SimklClient)
+	}{
+		{
+			name: "successfully get watchlist",
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodGet, simklPathBase+simklPathAllItems, httpmock.NewStringResponder(http.StatusOK, dummySimklLibrary))
+			},
+			assertions: func(assertions *assert.Assertions, c *SimklClient) {
+				watchlist, err := c.WatchlistGet()
+				assertions.NoError(err)
+				assertions.True(watchlist.IsWatchlist)
+				assertions.Len(watchlist.ListItems, 1)
+				assertions.Equal("tt0372784", watchlist.ListItems[0].Movie.IDMeta.IMDb)
+			},
+		},
+		{
+			name: "successfully get ratings",
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodGet, simklPathBase+simklPathAllItems, httpmock.NewStringResponder(http.StatusOK, dummySimklLibrary))
+			},
+			assertions: func(assertions *assert.Assertions, c *SimklClient) {
+				ratings, err := c.RatingsGet()
+				assertions.NoError(err)
+				assertions.Len(ratings, 2)
+				assertions.Equal(8, ratings[0].Rating)
+				assertions.Equal(entities.TraktItemTypeShow, ratings[1].Type)
+				assertions.Equal("2024-01-02T00:00:00Z", ratings[1].RatedAt)
+			},
+		},
+		{
+			name: "successfully get history",
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodGet, simklPathBase+simklPathAllItems, httpmock.NewStringResponder(http.StatusOK, dummySimklLibrary))
+			},
+			assertions: func(assertions *assert.Assertions, c *SimklClient) {
+				history, err := c.HistoryGet(entities.TraktItemTypeMovie, "tt0111161")
+				assertions.NoError(err)
+				assertions.Len(history, 1)
+				history, err = c.HistoryGet(entities.TraktItemTypeMovie, "tt0372784")
+				assertions.NoError(err)
+				assertions.Empty(history)
+				assertions.Equal(1, httpmock.GetTotalCallCount())
+			},
+		},
+		{
+			name: "failure getting library",
+			requirements: func() {
+				httpmock.RegisterResponder(http.MethodGet, simklPathBase+simklPathAllItems, httpmock.NewStringResponder(http.StatusUnauthorized, ""))
+			},
+			assertions: func(assertions *assert.Assertions, c *SimklClient) {
+				watchlist, err := c.WatchlistGet()
+				assertions.Nil(watchlist)
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusUnauthorized, apiError.StatusCode)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			tt.requirements()
+			tt.assertions(assert.New(t), buildTestSimklClient())
+		})
+	}
+}
+
+func TestSimklClient_LibraryConcurrent(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodGet, simklPathBase+simklPathAllItems, httpmock.NewStringResponder(http.StatusOK, dummySimklLibrary))
+	c := buildTestSimklClient()
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			watchlist, err := c.WatchlistGet()
+			assert.NoError(t, err)
+			assert.Len(t, watchlist.ListItems, 1)
+		}()
+		go func() {
+			defer wg.Done()
+			ratings, err := c.RatingsGet()
+			assert.NoError(t, err)
+			assert.Len(t, ratings, 2)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestSimklClient_WatchlistItemsAdd(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements func(*assert.Assertions)
+		assertions   func(*assert.Assertions, error)
+	}{
+		{
+			name: "successfully add items",
+			requirements: func(assertions *assert.Assertions) {
+				httpmock.RegisterResponder(
+					http.MethodPost,
+					simklPathBase+simklPathAddToList,
+					func(request *http.Request) (*http.Response, error) {
+						body, err := io.ReadAll(request.Body)
+						assertions.NoError(err)
+						assertions.JSONEq(`{"movies":[{"ids":{"imdb":"tt5013056"},"to":"plantowatch"}],"shows":[{"ids":{"imdb":"tt0903747"},"to":"plantowatch"}]}`, string(body))
+						assertions.Equal("client-id", request.Header.Get(simklHeaderKeyApiKey))
+						return httpmock.NewStringResponse(http.StatusCreated, "{}"), nil
+					},
+				)
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				assertions.NoError(err)
+			},
+		},
+		{
+			name: "failure adding items",
+			requirements: func(assertions *assert.Assertions) {
+				httpmock.RegisterResponder(http.MethodPost, simklPathBase+simklPathAddToList, httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+			},
+			assertions: func(assertions *assert.Assertions, err error) {
+				var apiError *ApiError
+				assertions.True(errors.As(err, &apiError))
+				assertions.Equal(http.StatusInternalServerError, apiError.StatusCode)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			assertions := assert.New(t)
+			tt.requirements(assertions)
+			err := buildTestSimklClient().WatchlistItemsAdd(dummyItems)
+			tt.assertions(assertions, err)
+		})
+	}
+}
+
+func TestSimklClient_WatchlistItemsRemove(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	assertions := assert.New(t)
+	assertions.NoError(buildTestSimklClient().WatchlistItemsRemove(dummyItems))
+	assertions.Zero(httpmock.GetTotalCallCount())
+}
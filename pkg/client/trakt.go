@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,16 +11,22 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/cecobask/imdb-trakt-sync/internal/budget"
 	appconfig "github.com/cecobask/imdb-trakt-sync/internal/config"
 	"github.com/cecobask/imdb-trakt-sync/internal/entities"
+	"github.com/cecobask/imdb-trakt-sync/internal/progress"
+	"github.com/cecobask/imdb-trakt-sync/internal/state"
 )
 
 const (
+	clientNameTrakt = "trakt"
+
 	traktFormKeyAuthenticityToken = "authenticity_token"
 	traktFormKeyCode              = "code"
 	traktFormKeyCommit            = "commit"
@@ -33,95 +40,256 @@ const (
 	traktHeaderKeyContentLength = "Content-Length"
 	traktHeaderKeyContentType   = "Content-Type"
 	traktHeaderKeyRetryAfter    = "Retry-After"
+	traktHeaderKeyPageCount     = "X-Pagination-Page-Count"
+
+	traktPathActivate             = "/activate"
+	traktPathActivateAuthorize    = "/activate/authorize"
+	traktPathAuthCodes            = "/oauth/device/code"
+	traktPathAuthRefresh          = "/oauth/token"
+	traktPathAuthSignIn           = "/auth/signin"
+	traktPathAuthTokens           = "/oauth/device/token"
+	traktPathBaseAPI              = "https://api.trakt.tv"
+	traktPathBaseBrowser          = "https://trakt.tv"
+	traktPathCheckin              = "/checkin"
+	traktPathCollection           = "/sync/collection"
+	traktPathCollectionGet        = "/sync/collection/%s"
+	traktPathCollectionRemove     = "/sync/collection/remove"
+	traktPathComments             = "/comments"
+	traktPathFavorites            = "/sync/favorites"
+	traktPathFavoritesRemove      = "/sync/favorites/remove"
+	traktPathHidden               = "/users/hidden/%s"
+	traktPathHiddenRemove         = "/users/hidden/%s/remove"
+	traktPathHistory              = "/sync/history"
+	traktPathHistoryGet           = "/sync/history/%s/%s?limit=%s"
+	traktPathHistoryRemove        = "/sync/history/remove"
+	traktPathListLike             = "/lists/%s/like"
+	traktPathRatings              = "/sync/ratings"
+	traktPathRatingsRemove        = "/sync/ratings/remove"
+	traktPathRecommendations      = "/recommendations/%s?limit=%s&ignore_collected=true&ignore_watchlisted=true"
+	traktPathSearch               = "/search/%s?query=%s&fields=title&limit=%d"
+	traktPathUserComments         = "/users/%s/comments/all/all?include_replies=false"
+	traktPathUserInfo             = "/users/me"
+	traktPathUserLikedLists       = "/users/%s/likes/lists"
+	traktPathUserList             = "/users/%s/lists/%s"
+	traktPathUserLists            = "/users/%s/lists"
+	traktPathUserSettings         = "/users/settings"
+	traktPathUserListItems        = "/users/%s/lists/%s/items"
+	traktPathUserListItemsRemove  = "/users/%s/lists/%s/items/remove"
+	traktPathUserListItemsReorder = "/users/%s/lists/%s/items/reorder"
+	traktPathWatchlist            = "/sync/watchlist"
+	traktPathWatchlistRemove      = "/sync/watchlist/remove"
 
-	traktPathActivate            = "/activate"
-	traktPathActivateAuthorize   = "/activate/authorize"
-	traktPathAuthCodes           = "/oauth/device/code"
-	traktPathAuthSignIn          = "/auth/signin"
-	traktPathAuthTokens          = "/oauth/device/token"
-	traktPathBaseAPI             = "https://api.trakt.tv"
-	traktPathBaseBrowser         = "https://trakt.tv"
-	traktPathHistory             = "/sync/history"
-	traktPathHistoryGet          = "/sync/history/%s/%s?limit=%s"
-	traktPathHistoryRemove       = "/sync/history/remove"
-	traktPathRatings             = "/sync/ratings"
-	traktPathRatingsRemove       = "/sync/ratings/remove"
-	traktPathUserInfo            = "/users/me"
-	traktPathUserList            = "/users/%s/lists/%s"
-	traktPathUserListItems       = "/users/%s/lists/%s/items"
-	traktPathUserListItemsRemove = "/users/%s/lists/%s/items/remove"
-	traktPathWatchlist           = "/sync/watchlist"
-	traktPathWatchlistRemove     = "/sync/watchlist/remove"
+	traktGrantTypeRefreshToken = "refresh_token"
+	traktRedirectURI           = "urn:ietf:wg:oauth:2.0:oob"
+	traktStateKeyTokens        = "trakt-tokens"
+	traktTokenRefreshWindow    = time.Hour
 
+	traktBatchMaxAttempts          = 3
+	traktPageLimit                 = 1000
+	traktSearchLimit               = 10
 	traktStatusCodeEnhanceYourCalm = 420 // https://github.com/trakt/api-help/discussions/350
 )
 
+// errTraktPageNotFound is returned when a paginated endpoint does not exist, such as the items of a deleted list.
+var errTraktPageNotFound = errors.New("trakt page not found")
+
+// ErrTraktCheckinInProgress is returned by Checkin while the user is still checked in to another title.
+var ErrTraktCheckinInProgress = errors.New("a trakt check-in is already in progress")
+
 type TraktClient struct {
-	client *http.Client
-	config traktConfig
-	logger *slog.Logger
+	ctx        context.Context
+	client     *http.Client
+	config     traktConfig
+	logger     *slog.Logger
+	store      state.Store
+	tokens     state.Store
+	tokenMu    sync.RWMutex
+	notFound   entities.TraktItems
+	notFoundMu sync.Mutex
+	progress   *progress.Progress
 }
 
 type traktConfig struct {
 	appconfig.Trakt
-	accessToken string
-	username    string
+	accessToken  string
+	refreshToken string
+	username     string
+}
+
+func NewTraktClient(ctx context.Context, conf appconfig.Trakt, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger) (TraktClientInterface, error) {
+	c, err := newTraktClient(ctx, conf, httpConf, store, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.hydrate(); err != nil {
+		return nil, fmt.Errorf("failure hydrating client: %w", err)
+	}
+	return c, nil
+}
+
+// AuthorizeTraktDevice runs the trakt device code flow interactively and persists the obtained tokens in the store.
+// The prompt function is called with the user code and verification url that the user needs to visit.
+func AuthorizeTraktDevice(ctx context.Context, conf appconfig.Trakt, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger, prompt func(*entities.TraktAuthCodesResponse)) error {
+	c, err := newTraktClient(ctx, conf, httpConf, store, logger)
+	if err != nil {
+		return err
+	}
+	authCodes, err := c.GetAuthCodes()
+	if err != nil {
+		return fmt.Errorf("failure generating auth codes: %w", err)
+	}
+	prompt(authCodes)
+	authTokens, err := c.pollAccessToken(ctx, authCodes)
+	if err != nil {
+		return fmt.Errorf("failure polling trakt for access token: %w", err)
+	}
+	if err = c.tokens.Save(traktStateKeyTokens, authTokens); err != nil {
+		return fmt.Errorf("failure storing trakt tokens: %w", err)
+	}
+	return nil
 }
 
-func NewTraktClient(conf appconfig.Trakt, logger *slog.Logger) (TraktClientInterface, error) {
+func newTraktClient(ctx context.Context, conf appconfig.Trakt, httpConf appconfig.HTTP, store state.Store, logger *slog.Logger) (*TraktClient, error) {
 	jar, err := cookiejar.New(nil)
 	if err != nil {
 		return nil, fmt.Errorf("failure creating cookie jar: %w", err)
 	}
-	c := &TraktClient{
+	tokens, err := newTraktTokenStore(conf, store)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating trakt token store: %w", err)
+	}
+	transport, err := newTransport(clientNameTrakt, httpConf, store)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating transport: %w", err)
+	}
+	return &TraktClient{
+		ctx: ctx,
 		client: &http.Client{
-			Jar: jar,
+			Jar:       jar,
+			Transport: transport,
+			Timeout:   httpConf.ClientTimeout(clientNameTrakt),
 		},
 		config: traktConfig{
 			Trakt: conf,
 		},
 		logger: logger,
+		store:  store,
+		tokens: tokens,
+	}, nil
+}
+
+// newTraktTokenStore returns the store of the tokens obtained with the device code flow. Tokens kept in the os keyring
+// fall back to the state store when the keyring is not available, encrypted if a passphrase is provided.
+func newTraktTokenStore(conf appconfig.Trakt, store state.Store) (state.Store, error) {
+	if *conf.TokenPassphrase != "" {
+		encrypted, err := state.NewEncryptedStore(store, *conf.TokenPassphrase)
+		if err != nil {
+			return nil, err
+		}
+		store = encrypted
 	}
-	if err = c.hydrate(); err != nil {
-		return nil, fmt.Errorf("failure hydrating client: %w", err)
+	if *conf.TokenStorage == appconfig.TraktTokenStorageKeyring {
+		return state.NewKeyringStore(*conf.KeyringService, store), nil
 	}
-	return c, nil
+	return store, nil
 }
 
 func (tc *TraktClient) hydrate() error {
+	var (
+		authTokens *entities.TraktAuthTokensResponse
+		err        error
+	)
+	switch *tc.config.Auth {
+	case appconfig.TraktAuthMethodDevice:
+		authTokens, err = tc.storedAccessToken()
+	default:
+		authTokens, err = tc.credentialsAccessToken()
+	}
+	if err != nil {
+		return err
+	}
+	tc.config.accessToken = authTokens.AccessToken
+	tc.config.refreshToken = authTokens.RefreshToken
+	userInfo, err := tc.UserInfoGet()
+	if err != nil {
+		return fmt.Errorf("failure getting trakt user info: %w", err)
+	}
+	tc.config.username = userInfo.Username
+	return nil
+}
+
+func (tc *TraktClient) credentialsAccessToken() (*entities.TraktAuthTokensResponse, error) {
 	authCodes, err := tc.GetAuthCodes()
 	if err != nil {
-		return fmt.Errorf("failure generating auth codes: %w", err)
+		return nil, fmt.Errorf("failure generating auth codes: %w", err)
 	}
 	authenticityToken, err := tc.BrowseSignIn()
 	if err != nil {
-		return fmt.Errorf("failure simulating browse to the trakt sign in page: %w", err)
+		return nil, fmt.Errorf("failure simulating browse to the trakt sign in page: %w", err)
 	}
 	if err = tc.SignIn(*authenticityToken); err != nil {
-		return fmt.Errorf("failure simulating trakt sign in form submission: %w", err)
+		return nil, fmt.Errorf("failure simulating trakt sign in form submission: %w", err)
 	}
 	authenticityToken, err = tc.BrowseActivate()
 	if err != nil {
-		return fmt.Errorf("failure simulating browse to the trakt device activation page: %w", err)
+		return nil, fmt.Errorf("failure simulating browse to the trakt device activation page: %w", err)
 	}
 	authenticityToken, err = tc.Activate(authCodes.UserCode, *authenticityToken)
 	if err != nil {
-		return fmt.Errorf("failure simulating trakt device activation form submission: %w", err)
+		return nil, fmt.Errorf("failure simulating trakt device activation form submission: %w", err)
 	}
 	if err = tc.ActivateAuthorize(*authenticityToken); err != nil {
-		return fmt.Errorf("failure simulating trakt api app allowlisting: %w", err)
+		return nil, fmt.Errorf("failure simulating trakt api app allowlisting: %w", err)
 	}
 	authTokens, err := tc.GetAccessToken(authCodes.DeviceCode)
 	if err != nil {
-		return fmt.Errorf("failure exchanging trakt device code for access token: %w", err)
+		return nil, fmt.Errorf("failure exchanging trakt device code for access token: %w", err)
 	}
-	tc.config.accessToken = authTokens.AccessToken
-	userInfo, err := tc.UserInfoGet()
+	return authTokens, nil
+}
+
+func (tc *TraktClient) storedAccessToken() (*entities.TraktAuthTokensResponse, error) {
+	var authTokens entities.TraktAuthTokensResponse
+	if err := tc.tokens.Load(traktStateKeyTokens, &authTokens); err != nil {
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, fmt.Errorf("no stored trakt tokens found, run the auth trakt command first")
+		}
+		return nil, fmt.Errorf("failure loading stored trakt tokens: %w", err)
+	}
+	if time.Until(authTokens.ExpiresAt()) > traktTokenRefreshWindow {
+		return &authTokens, nil
+	}
+	tc.logger.Info("refreshing expiring trakt access token", slog.Time("expiresAt", authTokens.ExpiresAt()))
+	refreshedTokens, err := tc.RefreshAccessToken(authTokens.RefreshToken)
 	if err != nil {
-		return fmt.Errorf("failure getting trakt user info: %w", err)
+		return nil, fmt.Errorf("failure refreshing trakt access token: %w", err)
 	}
-	tc.config.username = userInfo.Username
-	return nil
+	if err = tc.tokens.Save(traktStateKeyTokens, refreshedTokens); err != nil {
+		return nil, fmt.Errorf("failure storing refreshed trakt tokens: %w", err)
+	}
+	return refreshedTokens, nil
+}
+
+func (tc *TraktClient) pollAccessToken(ctx context.Context, authCodes *entities.TraktAuthCodesResponse) (*entities.TraktAuthTokensResponse, error) {
+	interval := time.Duration(authCodes.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(authCodes.ExpiresIn) * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		authTokens, err := tc.GetAccessToken(authCodes.DeviceCode)
+		if err == nil {
+			return authTokens, nil
+		}
+		var apiErr *ApiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+			continue // authorization pending
+		}
+		return nil, err
+	}
+	return nil, fmt.Errorf("device code expired before the user authorized the app")
 }
 
 func (tc *TraktClient) BrowseSignIn() (*string, error) {
@@ -238,6 +406,41 @@ func (tc *TraktClient) GetAccessToken(deviceCode string) (*entities.TraktAuthTok
 	if err != nil {
 		return nil, err
 	}
+	if response.StatusCode == http.StatusNotFound {
+		response.Body.Close()
+		return nil, &ApiError{
+			httpMethod: response.Request.Method,
+			url:        response.Request.URL.String(),
+			StatusCode: response.StatusCode,
+			details:    "invalid device code",
+		}
+	}
+	return decodeReader[*entities.TraktAuthTokensResponse](response.Body)
+}
+
+func (tc *TraktClient) RefreshAccessToken(refreshToken string) (*entities.TraktAuthTokensResponse, error) {
+	body, err := json.Marshal(entities.TraktRefreshTokenBody{
+		RefreshToken: refreshToken,
+		ClientID:     *tc.config.ClientID,
+		ClientSecret: *tc.config.ClientSecret,
+		RedirectURI:  traktRedirectURI,
+		GrantType:    traktGrantTypeRefreshToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	response, err := tc.doRequest(requestFields{
+		Method:   http.MethodPost,
+		BasePath: traktPathBaseAPI,
+		Endpoint: traktPathAuthRefresh,
+		Body:     bytes.NewReader(body),
+		Headers: map[string]string{
+			traktHeaderKeyContentType: "application/json",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
 	return decodeReader[*entities.TraktAuthTokensResponse](response.Body)
 }
 
@@ -264,6 +467,8 @@ func (tc *TraktClient) GetAuthCodes() (*entities.TraktAuthCodesResponse, error)
 }
 
 func (tc *TraktClient) defaultApiHeaders() map[string]string {
+	tc.tokenMu.RLock()
+	defer tc.tokenMu.RUnlock()
 	return map[string]string{
 		traktHeaderKeyApiVersion:    "2",
 		traktHeaderKeyContentType:   "application/json",
@@ -272,14 +477,41 @@ func (tc *TraktClient) defaultApiHeaders() map[string]string {
 	}
 }
 
+// refreshAccessTokenOnce exchanges the refresh token for a new access token, unless a concurrent request
+// has already replaced the stale access token. Tokens obtained with the device code flow are persisted.
+func (tc *TraktClient) refreshAccessTokenOnce(staleAccessToken string) (string, error) {
+	tc.tokenMu.Lock()
+	defer tc.tokenMu.Unlock()
+	if tc.config.accessToken != staleAccessToken {
+		return tc.config.accessToken, nil
+	}
+	if tc.config.refreshToken == "" {
+		return "", fmt.Errorf("no trakt refresh token available")
+	}
+	authTokens, err := tc.RefreshAccessToken(tc.config.refreshToken)
+	if err != nil {
+		return "", err
+	}
+	tc.config.accessToken = authTokens.AccessToken
+	tc.config.refreshToken = authTokens.RefreshToken
+	if *tc.config.Auth == appconfig.TraktAuthMethodDevice {
+		if err = tc.tokens.Save(traktStateKeyTokens, authTokens); err != nil {
+			return "", fmt.Errorf("failure storing refreshed trakt tokens: %w", err)
+		}
+	}
+	tc.logger.Info("refreshed trakt access token after unauthorized response")
+	return authTokens.AccessToken, nil
+}
+
 func (tc *TraktClient) doRequest(requestFields requestFields) (*http.Response, error) {
-	request, err := http.NewRequest(requestFields.Method, requestFields.BasePath+requestFields.Endpoint, ReusableReader(requestFields.Body))
+	request, err := http.NewRequestWithContext(tc.ctx, requestFields.Method, requestFields.BasePath+requestFields.Endpoint, ReusableReader(requestFields.Body))
 	if err != nil {
 		return nil, fmt.Errorf("error creating http request %s %s: %w", requestFields.Method, requestFields.BasePath+requestFields.Endpoint, err)
 	}
 	for key, value := range requestFields.Headers {
 		request.Header.Set(key, value)
 	}
+	refreshed := false
 	for retries := 0; retries < 5; retries++ {
 		response, err := tc.client.Do(request)
 		if err != nil {
@@ -307,14 +539,36 @@ func (tc *TraktClient) doRequest(requestFields requestFields) (*http.Response, e
 			duration := time.Duration(retryAfter) * time.Second
 			message := fmt.Sprintf("trakt rate limit reached, waiting %s then retrying http request %s %s", duration, response.Request.Method, response.Request.URL)
 			tc.logger.Warn(message)
-			time.Sleep(duration)
+			if err = sleep(tc.ctx, duration); err != nil {
+				return nil, err
+			}
+			continue
+		case http.StatusUnauthorized:
+			response.Body.Close()
+			authorization := request.Header.Get(traktHeaderKeyAuthorization)
+			if refreshed || authorization == "" {
+				return nil, &ApiError{
+					httpMethod: response.Request.Method,
+					url:        response.Request.URL.String(),
+					StatusCode: response.StatusCode,
+					details:    "trakt access token is invalid or expired",
+				}
+			}
+			accessToken, err := tc.refreshAccessTokenOnce(strings.TrimPrefix(authorization, "Bearer "))
+			if err != nil {
+				return nil, fmt.Errorf("failure refreshing trakt access token: %w", err)
+			}
+			request.Header.Set(traktHeaderKeyAuthorization, fmt.Sprintf("Bearer %s", accessToken))
+			refreshed = true
 			continue
 		case http.StatusRequestTimeout, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
 			response.Body.Close()
 			duration := time.Second
 			message := fmt.Sprintf("unexpected status code %d, waiting for %s then retrying http request %s %s", response.StatusCode, duration, response.Request.Method, response.Request.URL)
 			tc.logger.Warn(message)
-			time.Sleep(duration)
+			if err = sleep(tc.ctx, duration); err != nil {
+				return nil, err
+			}
 			continue
 		default:
 			response.Body.Close()
@@ -326,7 +580,7 @@ func (tc *TraktClient) doRequest(requestFields requestFields) (*http.Response, e
 			}
 		}
 	}
-	return nil, fmt.Errorf("reached max retry attempts for %s %s", request.Method, request.URL)
+	return nil, &TransientError{Err: fmt.Errorf("reached max retry attempts for %s %s", request.Method, request.URL)}
 }
 
 func (tc *TraktClient) UserInfoGet() (*entities.TraktUserInfo, error) {
@@ -343,45 +597,49 @@ func (tc *TraktClient) UserInfoGet() (*entities.TraktUserInfo, error) {
 	return decodeReader[*entities.TraktUserInfo](response.Body)
 }
 
-func (tc *TraktClient) WatchlistGet() (*entities.TraktList, error) {
+// LimitsGet returns the caps of the account on the number of lists and list items, along with the number of personal
+// lists that the user already owns.
+func (tc *TraktClient) LimitsGet() (*entities.TraktLimits, error) {
 	response, err := tc.doRequest(requestFields{
 		Method:   http.MethodGet,
 		BasePath: traktPathBaseAPI,
-		Endpoint: traktPathWatchlist,
+		Endpoint: traktPathUserSettings,
 		Body:     http.NoBody,
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
 		return nil, err
 	}
-	list := entities.TraktList{
+	settings, err := decodeReader[*entities.TraktUserSettings](response.Body)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := tc.UserListsGet()
+	if err != nil {
+		return nil, fmt.Errorf("failure fetching trakt user lists: %w", err)
+	}
+	limits := settings.Limits
+	limits.ListsOwned = len(lists)
+	limits.Vip = settings.User.Vip || settings.User.VipEp
+	return &limits, nil
+}
+
+func (tc *TraktClient) WatchlistGet() (*entities.TraktList, error) {
+	items, err := paginate[entities.TraktItem](tc, traktPathWatchlist)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.TraktList{
 		IDMeta: entities.TraktIDMeta{
 			Slug: "watchlist",
 		},
+		ListItems:   items,
 		IsWatchlist: true,
-	}
-	if err = decodeReaderInto(response.Body, &list.ListItems); err != nil {
-		return nil, err
-	}
-	return &list, nil
+	}, nil
 }
 
 func (tc *TraktClient) WatchlistItemsAdd(items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
-	if err != nil {
-		return err
-	}
-	response, err := tc.doRequest(requestFields{
-		Method:   http.MethodPost,
-		BasePath: traktPathBaseAPI,
-		Endpoint: traktPathWatchlist,
-		Body:     bytes.NewReader(body),
-		Headers:  tc.defaultApiHeaders(),
-	})
-	if err != nil {
-		return err
-	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
+	traktResponse, err := tc.syncItems(traktPathWatchlist, items)
 	if err != nil {
 		return err
 	}
@@ -390,21 +648,7 @@ func (tc *TraktClient) WatchlistItemsAdd(items entities.TraktItems) error {
 }
 
 func (tc *TraktClient) WatchlistItemsRemove(items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
-	if err != nil {
-		return err
-	}
-	response, err := tc.doRequest(requestFields{
-		Method:   http.MethodPost,
-		BasePath: traktPathBaseAPI,
-		Endpoint: traktPathWatchlistRemove,
-		Body:     bytes.NewReader(body),
-		Headers:  tc.defaultApiHeaders(),
-	})
-	if err != nil {
-		return err
-	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
+	traktResponse, err := tc.syncItems(traktPathWatchlistRemove, items)
 	if err != nil {
 		return err
 	}
@@ -413,48 +657,53 @@ func (tc *TraktClient) WatchlistItemsRemove(items entities.TraktItems) error {
 }
 
 func (tc *TraktClient) ListGet(listID string) (*entities.TraktList, error) {
+	items, err := paginate[entities.TraktItem](tc, fmt.Sprintf(traktPathUserListItems, tc.config.username, listID))
+	if err != nil {
+		if errors.Is(err, errTraktPageNotFound) {
+			return nil, &TraktListNotFoundError{
+				Slug: listID,
+			}
+		}
+		return nil, err
+	}
+	return &entities.TraktList{
+		IDMeta: entities.TraktIDMeta{
+			Slug: listID,
+		},
+		ListItems: items,
+	}, nil
+}
+
+// UserListsGet returns the summaries of all personal lists owned by the user, without their items.
+func (tc *TraktClient) UserListsGet() ([]entities.TraktList, error) {
 	response, err := tc.doRequest(requestFields{
 		Method:   http.MethodGet,
 		BasePath: traktPathBaseAPI,
-		Endpoint: fmt.Sprintf(traktPathUserListItems, tc.config.username, listID),
+		Endpoint: fmt.Sprintf(traktPathUserLists, tc.config.username),
 		Body:     http.NoBody,
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
 		return nil, err
 	}
-	if statusCode := response.StatusCode; statusCode == http.StatusNotFound {
-		return nil, &TraktListNotFoundError{
-			Slug: listID,
-		}
-	}
-	list := entities.TraktList{
-		IDMeta: entities.TraktIDMeta{
-			Slug: listID,
-		},
-	}
-	if err = decodeReaderInto(response.Body, &list.ListItems); err != nil {
+	var lists []entities.TraktList
+	if err = decodeReaderInto(response.Body, &lists); err != nil {
 		return nil, err
 	}
-	return &list, nil
+	return lists, nil
 }
 
 func (tc *TraktClient) ListItemsAdd(listID string, items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
-	if err != nil {
-		return err
-	}
-	response, err := tc.doRequest(requestFields{
-		Method:   http.MethodPost,
-		BasePath: traktPathBaseAPI,
-		Endpoint: fmt.Sprintf(traktPathUserListItems, tc.config.username, listID),
-		Body:     bytes.NewReader(body),
-		Headers:  tc.defaultApiHeaders(),
-	})
+	traktResponse, err := tc.syncItems(fmt.Sprintf(traktPathUserListItems, tc.config.username, listID), items)
 	if err != nil {
 		return err
 	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
+	tc.logger.Info("synced trakt list", slog.Any(listID, traktResponse))
+	return nil
+}
+
+func (tc *TraktClient) ListItemsRemove(listID string, items entities.TraktItems) error {
+	traktResponse, err := tc.syncItems(fmt.Sprintf(traktPathUserListItemsRemove, tc.config.username, listID), items)
 	if err != nil {
 		return err
 	}
@@ -462,26 +711,29 @@ func (tc *TraktClient) ListItemsAdd(listID string, items entities.TraktItems) er
 	return nil
 }
 
-func (tc *TraktClient) ListItemsRemove(listID string, items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
+// ListItemsReorder sets the order of the trakt list items, where rank contains all list item ids in the desired order.
+func (tc *TraktClient) ListItemsReorder(listID string, rank []int64) error {
+	body, err := json.Marshal(entities.TraktListReorderBody{
+		Rank: rank,
+	})
 	if err != nil {
 		return err
 	}
 	response, err := tc.doRequest(requestFields{
 		Method:   http.MethodPost,
 		BasePath: traktPathBaseAPI,
-		Endpoint: fmt.Sprintf(traktPathUserListItemsRemove, tc.config.username, listID),
+		Endpoint: fmt.Sprintf(traktPathUserListItemsReorder, tc.config.username, listID),
 		Body:     bytes.NewReader(body),
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
 		return err
 	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
+	reorderResponse, err := decodeReader[*entities.TraktListReorderResponse](response.Body)
 	if err != nil {
 		return err
 	}
-	tc.logger.Info("synced trakt list", slog.Any(listID, traktResponse))
+	tc.logger.Info("reordered trakt list", slog.Any(listID, reorderResponse))
 	return nil
 }
 
@@ -509,7 +761,21 @@ func (tc *TraktClient) ListsGet(idsMeta entities.TraktIDMetas) ([]entities.Trakt
 					errChan <- fmt.Errorf("unexpected error while fetching trakt lists: %w", err)
 					return
 				}
+				summary, err := tc.listSummaryGet(idMeta.Slug)
+				if err != nil {
+					errChan <- fmt.Errorf("unexpected error while fetching trakt list summary: %w", err)
+					return
+				}
 				list.IDMeta = idMeta
+				if summary.Name != "" {
+					list.Name = &summary.Name
+				}
+				list.Description = summary.Description
+				settings := tc.listSettings(idMeta.Slug, false)
+				if settings.Privacy == "" {
+					settings.Privacy = summary.Privacy
+				}
+				list.SettingsOutdated = summary.TraktListSettings != settings
 				outChan <- *list
 			}(idMeta)
 		}
@@ -528,15 +794,14 @@ func (tc *TraktClient) ListsGet(idsMeta entities.TraktIDMetas) ([]entities.Trakt
 	}
 }
 
-func (tc *TraktClient) ListAdd(listID, listName string) error {
+func (tc *TraktClient) ListAdd(listID, listName, description string) error {
+	if description == "" {
+		description = fmt.Sprintf("list auto imported from imdb by https://github.com/cecobask/imdb-trakt-sync on %v", time.Now().Format(time.RFC1123))
+	}
 	body, err := json.Marshal(entities.TraktListAddBody{
-		Name:           listName,
-		Description:    fmt.Sprintf("list auto imported from imdb by https://github.com/cecobask/imdb-trakt-sync on %v", time.Now().Format(time.RFC1123)),
-		Privacy:        "public",
-		DisplayNumbers: false,
-		AllowComments:  true,
-		SortBy:         "rank",
-		SortHow:        "asc",
+		Name:              listName,
+		Description:       description,
+		TraktListSettings: tc.listSettings(listID, true),
 	})
 	if err != nil {
 		return err
@@ -556,36 +821,139 @@ func (tc *TraktClient) ListAdd(listID, listName string) error {
 	return nil
 }
 
-func (tc *TraktClient) RatingsGet() (entities.TraktItems, error) {
+// ListUpdate applies the configured privacy, display and sorting settings to an existing trakt list.
+// The description of the list is only replaced when a non-empty description is provided.
+func (tc *TraktClient) ListUpdate(listID, description string) error {
+	body, err := json.Marshal(entities.TraktListUpdateBody{
+		Description:       description,
+		TraktListSettings: tc.listSettings(listID, false),
+	})
+	if err != nil {
+		return err
+	}
 	response, err := tc.doRequest(requestFields{
-		Method:   http.MethodGet,
+		Method:   http.MethodPut,
 		BasePath: traktPathBaseAPI,
-		Endpoint: traktPathRatings,
+		Endpoint: fmt.Sprintf(traktPathUserList, tc.config.username, listID),
+		Body:     bytes.NewReader(body),
+		Headers:  tc.defaultApiHeaders(),
+	})
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	tc.logger.Info(fmt.Sprintf("updated trakt list %s", listID))
+	return nil
+}
+
+func (tc *TraktClient) ListRemove(listID string) error {
+	response, err := tc.doRequest(requestFields{
+		Method:   http.MethodDelete,
+		BasePath: traktPathBaseAPI,
+		Endpoint: fmt.Sprintf(traktPathUserList, tc.config.username, listID),
 		Body:     http.NoBody,
 		Headers:  tc.defaultApiHeaders(),
 	})
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	tc.logger.Info(fmt.Sprintf("deleted trakt list %s", listID))
+	return nil
+}
+
+// LikedListsGet returns the lists that the user liked, which can belong to other users or be official trakt lists.
+func (tc *TraktClient) LikedListsGet() ([]entities.TraktList, error) {
+	likes, err := paginate[entities.TraktLikedList](tc, fmt.Sprintf(traktPathUserLikedLists, tc.config.username))
 	if err != nil {
 		return nil, err
 	}
-	return decodeReader[entities.TraktItems](response.Body)
+	lists := make([]entities.TraktList, len(likes))
+	for i, like := range likes {
+		lists[i] = like.List
+	}
+	return lists, nil
 }
 
-func (tc *TraktClient) RatingsAdd(items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
+// ListLike likes the list with the given trakt id, which keeps the list visible in the trakt apps of the user without
+// copying it to the personal lists.
+func (tc *TraktClient) ListLike(listID string) error {
+	return tc.listLikeRequest(http.MethodPost, listID)
+}
+
+func (tc *TraktClient) ListUnlike(listID string) error {
+	return tc.listLikeRequest(http.MethodDelete, listID)
+}
+
+func (tc *TraktClient) listLikeRequest(method, listID string) error {
+	response, err := tc.doRequest(requestFields{
+		Method:   method,
+		BasePath: traktPathBaseAPI,
+		Endpoint: fmt.Sprintf(traktPathListLike, listID),
+		Body:     http.NoBody,
+		Headers:  tc.defaultApiHeaders(),
+	})
 	if err != nil {
 		return err
 	}
+	response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return &NotFoundError{Err: fmt.Errorf("trakt could not find list %s", listID)}
+	}
+	if method == http.MethodDelete {
+		tc.logger.Info(fmt.Sprintf("unliked trakt list %s", listID))
+		return nil
+	}
+	tc.logger.Info(fmt.Sprintf("liked trakt list %s", listID))
+	return nil
+}
+
+func (tc *TraktClient) listSummaryGet(listID string) (*entities.TraktListAddBody, error) {
 	response, err := tc.doRequest(requestFields{
-		Method:   http.MethodPost,
+		Method:   http.MethodGet,
+		BasePath: traktPathBaseAPI,
+		Endpoint: fmt.Sprintf(traktPathUserList, tc.config.username, listID),
+		Body:     http.NoBody,
+		Headers:  tc.defaultApiHeaders(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decodeReader[*entities.TraktListAddBody](response.Body)
+}
+
+// listSettings returns the configured settings of the trakt list. The privacy is left empty, so that it is not changed,
+// when it was not set explicitly, unless the list is about to be created.
+func (tc *TraktClient) listSettings(listID string, create bool) entities.TraktListSettings {
+	privacy, explicit := tc.config.ListPrivacyOf(listID)
+	if !explicit && !create {
+		privacy = ""
+	}
+	return entities.TraktListSettings{
+		Privacy:        privacy,
+		DisplayNumbers: *tc.config.ListDisplayNumbers,
+		AllowComments:  *tc.config.ListAllowComments,
+		SortBy:         *tc.config.ListSortBy,
+		SortHow:        *tc.config.ListSortHow,
+	}
+}
+
+func (tc *TraktClient) RatingsGet() (entities.TraktItems, error) {
+	response, err := tc.doRequest(requestFields{
+		Method:   http.MethodGet,
 		BasePath: traktPathBaseAPI,
 		Endpoint: traktPathRatings,
-		Body:     bytes.NewReader(body),
+		Body:     http.NoBody,
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
+	return decodeReader[entities.TraktItems](response.Body)
+}
+
+func (tc *TraktClient) RatingsAdd(items entities.TraktItems) error {
+	traktResponse, err := tc.syncItems(traktPathRatings, items)
 	if err != nil {
 		return err
 	}
@@ -594,33 +962,162 @@ func (tc *TraktClient) RatingsAdd(items entities.TraktItems) error {
 }
 
 func (tc *TraktClient) RatingsRemove(items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
+	traktResponse, err := tc.syncItems(traktPathRatingsRemove, items)
 	if err != nil {
 		return err
 	}
+	tc.logger.Info("synced trakt ratings", slog.Any("ratings", traktResponse))
+	return nil
+}
+
+func (tc *TraktClient) HistoryGet(itemType, itemID string) (entities.TraktItems, error) {
 	response, err := tc.doRequest(requestFields{
-		Method:   http.MethodPost,
+		Method:   http.MethodGet,
 		BasePath: traktPathBaseAPI,
-		Endpoint: traktPathRatingsRemove,
-		Body:     bytes.NewReader(body),
+		Endpoint: fmt.Sprintf(traktPathHistoryGet, itemType+"s", itemID, "1000"),
+		Body:     http.NoBody,
 		Headers:  tc.defaultApiHeaders(),
 	})
+	if err != nil {
+		return nil, err
+	}
+	return decodeReader[entities.TraktItems](response.Body)
+}
+
+// HistoryGetAll returns the watch history of the user across all movies and episodes.
+func (tc *TraktClient) HistoryGetAll() (entities.TraktItems, error) {
+	return paginate[entities.TraktItem](tc, traktPathHistory)
+}
+
+// Search returns the titles of the given type whose title matches the query, most relevant first. A year other than zero
+// narrows the search down to the titles released that year.
+func (tc *TraktClient) Search(itemType, query string, year int) ([]entities.TraktSearchResult, error) {
+	endpoint := fmt.Sprintf(traktPathSearch, itemType, url.QueryEscape(query), traktSearchLimit)
+	if year > 0 {
+		endpoint += "&years=" + strconv.Itoa(year)
+	}
+	response, err := tc.doRequest(requestFields{
+		Method:   http.MethodGet,
+		BasePath: traktPathBaseAPI,
+		Endpoint: endpoint,
+		Body:     http.NoBody,
+		Headers:  tc.defaultApiHeaders(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decodeReader[[]entities.TraktSearchResult](response.Body)
+}
+
+// RecommendationsGet returns the personal movie and show recommendations of the user, leaving out the items that the
+// user has already collected or added to the watchlist.
+func (tc *TraktClient) RecommendationsGet() (entities.TraktItems, error) {
+	var recommendations entities.TraktItems
+	for _, itemType := range []string{entities.TraktItemTypeMovie, entities.TraktItemTypeShow} {
+		response, err := tc.doRequest(requestFields{
+			Method:   http.MethodGet,
+			BasePath: traktPathBaseAPI,
+			Endpoint: fmt.Sprintf(traktPathRecommendations, itemType+"s", "100"),
+			Body:     http.NoBody,
+			Headers:  tc.defaultApiHeaders(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		specs, err := decodeReader[entities.TraktItemSpecs](response.Body)
+		if err != nil {
+			return nil, err
+		}
+		for _, spec := range specs {
+			item := entities.TraktItem{
+				Type: itemType,
+			}
+			if itemType == entities.TraktItemTypeMovie {
+				item.Movie = spec
+			} else {
+				item.Show = spec
+			}
+			recommendations = append(recommendations, item)
+		}
+	}
+	return recommendations, nil
+}
+
+// HiddenGet returns the movies and shows that the user has hidden from the given section, such as recommendations or
+// calendar.
+func (tc *TraktClient) HiddenGet(section string) (entities.TraktItems, error) {
+	return paginate[entities.TraktItem](tc, fmt.Sprintf(traktPathHidden, section))
+}
+
+func (tc *TraktClient) HiddenAdd(section string, items entities.TraktItems) error {
+	traktResponse, err := tc.syncItems(fmt.Sprintf(traktPathHidden, section), items)
 	if err != nil {
 		return err
 	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
+	tc.logger.Info("synced trakt hidden items", slog.String("section", section), slog.Any("hidden", traktResponse))
+	return nil
+}
+
+func (tc *TraktClient) HiddenRemove(section string, items entities.TraktItems) error {
+	traktResponse, err := tc.syncItems(fmt.Sprintf(traktPathHiddenRemove, section), items)
 	if err != nil {
 		return err
 	}
-	tc.logger.Info("synced trakt ratings", slog.Any("ratings", traktResponse))
+	tc.logger.Info("synced trakt hidden items", slog.String("section", section), slog.Any("hidden", traktResponse))
 	return nil
 }
 
-func (tc *TraktClient) HistoryGet(itemType, itemID string) (entities.TraktItems, error) {
+// CollectionGet returns the collected movies and shows of the user. Collected shows include their collected episodes.
+func (tc *TraktClient) CollectionGet() (entities.TraktItems, error) {
+	var collection entities.TraktItems
+	for _, itemType := range []string{entities.TraktItemTypeMovie, entities.TraktItemTypeShow} {
+		response, err := tc.doRequest(requestFields{
+			Method:   http.MethodGet,
+			BasePath: traktPathBaseAPI,
+			Endpoint: fmt.Sprintf(traktPathCollectionGet, itemType+"s"),
+			Body:     http.NoBody,
+			Headers:  tc.defaultApiHeaders(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		items, err := decodeReader[entities.TraktItems](response.Body)
+		if err != nil {
+			return nil, err
+		}
+		// the collection endpoints do not include the item type, since each endpoint returns a single type
+		for i := range items {
+			items[i].Type = itemType
+		}
+		collection = append(collection, items...)
+	}
+	return collection, nil
+}
+
+func (tc *TraktClient) CollectionAdd(items entities.TraktItems) error {
+	traktResponse, err := tc.syncItems(traktPathCollection, items)
+	if err != nil {
+		return err
+	}
+	tc.logger.Info("synced trakt collection", slog.Any("collection", traktResponse))
+	return nil
+}
+
+func (tc *TraktClient) CollectionRemove(items entities.TraktItems) error {
+	traktResponse, err := tc.syncItems(traktPathCollectionRemove, items)
+	if err != nil {
+		return err
+	}
+	tc.logger.Info("synced trakt collection", slog.Any("collection", traktResponse))
+	return nil
+}
+
+// FavoritesGet returns the favorite movies and shows of the user.
+func (tc *TraktClient) FavoritesGet() (entities.TraktItems, error) {
 	response, err := tc.doRequest(requestFields{
 		Method:   http.MethodGet,
 		BasePath: traktPathBaseAPI,
-		Endpoint: fmt.Sprintf(traktPathHistoryGet, itemType+"s", itemID, "1000"),
+		Endpoint: traktPathFavorites,
 		Body:     http.NoBody,
 		Headers:  tc.defaultApiHeaders(),
 	})
@@ -630,45 +1127,97 @@ func (tc *TraktClient) HistoryGet(itemType, itemID string) (entities.TraktItems,
 	return decodeReader[entities.TraktItems](response.Body)
 }
 
-func (tc *TraktClient) HistoryAdd(items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
+func (tc *TraktClient) FavoritesAdd(items entities.TraktItems) error {
+	traktResponse, err := tc.syncItems(traktPathFavorites, items)
+	if err != nil {
+		return err
+	}
+	tc.logger.Info("synced trakt favorites", slog.Any("favorites", traktResponse))
+	return nil
+}
+
+func (tc *TraktClient) FavoritesRemove(items entities.TraktItems) error {
+	traktResponse, err := tc.syncItems(traktPathFavoritesRemove, items)
+	if err != nil {
+		return err
+	}
+	tc.logger.Info("synced trakt favorites", slog.Any("favorites", traktResponse))
+	return nil
+}
+
+func (tc *TraktClient) CommentsGet() (entities.TraktItems, error) {
+	return paginate[entities.TraktItem](tc, fmt.Sprintf(traktPathUserComments, tc.config.username))
+}
+
+func (tc *TraktClient) CommentAdd(comment entities.TraktComment) error {
+	body, err := json.Marshal(comment)
 	if err != nil {
 		return err
 	}
 	response, err := tc.doRequest(requestFields{
 		Method:   http.MethodPost,
 		BasePath: traktPathBaseAPI,
-		Endpoint: traktPathHistory,
+		Endpoint: traktPathComments,
 		Body:     bytes.NewReader(body),
 		Headers:  tc.defaultApiHeaders(),
 	})
 	if err != nil {
 		return err
 	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
-	if err != nil {
-		return err
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return &NotFoundError{Err: fmt.Errorf("trakt could not find the item of the comment")}
 	}
-	tc.logger.Info("synced trakt history", slog.Any("history", traktResponse))
 	return nil
 }
 
-func (tc *TraktClient) HistoryRemove(items entities.TraktItems) error {
-	body, err := json.Marshal(mapTraktItemsToTraktBody(items))
+// Checkin checks the user in to the movie or episode, which marks it as currently watching on trakt.
+func (tc *TraktClient) Checkin(item entities.TraktItem) error {
+	var checkin entities.TraktCheckin
+	switch item.Type {
+	case entities.TraktItemTypeMovie:
+		checkin.Movie = &entities.TraktItemSpec{IDMeta: item.Movie.IDMeta}
+	case entities.TraktItemTypeEpisode:
+		checkin.Episode = &entities.TraktItemSpec{IDMeta: item.Episode.IDMeta}
+	default:
+		return fmt.Errorf("trakt item type %s cannot be checked in", item.Type)
+	}
+	body, err := json.Marshal(checkin)
 	if err != nil {
 		return err
 	}
 	response, err := tc.doRequest(requestFields{
 		Method:   http.MethodPost,
 		BasePath: traktPathBaseAPI,
-		Endpoint: traktPathHistoryRemove,
+		Endpoint: traktPathCheckin,
 		Body:     bytes.NewReader(body),
 		Headers:  tc.defaultApiHeaders(),
 	})
+	if err != nil {
+		var apiErr *ApiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+			return ErrTraktCheckinInProgress
+		}
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return &NotFoundError{Err: fmt.Errorf("trakt could not find the %s to check in to", item.Type)}
+	}
+	return nil
+}
+
+func (tc *TraktClient) HistoryAdd(items entities.TraktItems) error {
+	traktResponse, err := tc.syncItems(traktPathHistory, items)
 	if err != nil {
 		return err
 	}
-	traktResponse, err := decodeReader[*entities.TraktResponse](response.Body)
+	tc.logger.Info("synced trakt history", slog.Any("history", traktResponse))
+	return nil
+}
+
+func (tc *TraktClient) HistoryRemove(items entities.TraktItems) error {
+	traktResponse, err := tc.syncItems(traktPathHistoryRemove, items)
 	if err != nil {
 		return err
 	}
@@ -676,6 +1225,95 @@ func (tc *TraktClient) HistoryRemove(items entities.TraktItems) error {
 	return nil
 }
 
+// syncItems posts the items to the endpoint in batches of at most TRAKT_BATCHSIZE items, since large payloads may
+// exceed the size limits of trakt, and returns the responses of the batches aggregated into one. No further batch is
+// posted once the time budget of the sync runs out, so that the sync stops between batches. Batches that were applied
+// before a failure are reported through a PartialError.
+func (tc *TraktClient) syncItems(endpoint string, items entities.TraktItems) (*entities.TraktResponse, error) {
+	aggregated := &entities.TraktResponse{}
+	var bar *progress.Bar
+	// a single batch completes at once, hence why only requests that are sent in several batches are tracked
+	if batches := (len(items) + *tc.config.BatchSize - 1) / *tc.config.BatchSize; batches > 1 {
+		bar = tc.progress.Track(fmt.Sprintf("trakt batches of %s", endpoint), batches)
+		defer bar.Done()
+	}
+	var applied entities.TraktItems
+	for batch := range slices.Chunk(items, *tc.config.BatchSize) {
+		if err := budget.FromContext(tc.ctx).Check(); err != nil {
+			return nil, partialError(applied, err)
+		}
+		traktResponse, err := tc.syncBatch(endpoint, batch)
+		if err != nil {
+			return nil, partialError(applied, err)
+		}
+		aggregated.Merge(traktResponse)
+		applied = append(applied, batch...)
+		bar.Add(1)
+	}
+	if aggregated.NotFound != nil {
+		if notFound := aggregated.NotFound.Items(); len(notFound) > 0 {
+			tc.logger.Warn(fmt.Sprintf("trakt could not find %d item(s)", len(notFound)), slog.String("endpoint", endpoint), slog.Any("notFound", notFound))
+			tc.notFoundMu.Lock()
+			tc.notFound = append(tc.notFound, notFound...)
+			tc.notFoundMu.Unlock()
+		}
+	}
+	return aggregated, nil
+}
+
+// partialError reports the batches that were applied before the error, since they are not rolled back.
+func partialError(applied entities.TraktItems, err error) error {
+	if len(applied) == 0 {
+		return err
+	}
+	return &PartialError{Applied: applied, Err: err}
+}
+
+// SetProgress reports the progress of the requests that are sent in several batches.
+func (tc *TraktClient) SetProgress(p *progress.Progress) {
+	tc.progress = p
+}
+
+// NotFound returns the items that trakt could not find since the last call, so that callers can attribute them to
+// the requests that they made in between.
+func (tc *TraktClient) NotFound() entities.TraktItems {
+	tc.notFoundMu.Lock()
+	defer tc.notFoundMu.Unlock()
+	notFound := tc.notFound
+	tc.notFound = nil
+	return notFound
+}
+
+// syncBatch posts a single batch of items to the endpoint. Batches that fail for reasons other than an unexpected
+// response from trakt, such as a timeout, are retried, since the remaining batches may still succeed.
+func (tc *TraktClient) syncBatch(endpoint string, batch entities.TraktItems) (*entities.TraktResponse, error) {
+	body, err := json.Marshal(mapTraktItemsToTraktBody(batch))
+	if err != nil {
+		return nil, err
+	}
+	for attempt := 1; ; attempt++ {
+		response, err := tc.doRequest(requestFields{
+			Method:   http.MethodPost,
+			BasePath: traktPathBaseAPI,
+			Endpoint: endpoint,
+			Body:     bytes.NewReader(body),
+			Headers:  tc.defaultApiHeaders(),
+		})
+		if err == nil {
+			return decodeReader[*entities.TraktResponse](response.Body)
+		}
+		var apiErr *ApiError
+		if errors.As(err, &apiErr) || attempt == traktBatchMaxAttempts || tc.ctx.Err() != nil {
+			return nil, err
+		}
+		delay := time.Duration(attempt) * time.Second
+		tc.logger.Warn(fmt.Sprintf("failure syncing batch of %d trakt item(s), waiting %s then retrying", len(batch), delay), slog.String("endpoint", endpoint), slog.Int("attempt", attempt), slog.Any("error", err))
+		if err = sleep(tc.ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
 func mapTraktItemsToTraktBody(items entities.TraktItems) entities.TraktListBody {
 	res := entities.TraktListBody{}
 	for i := range items {
@@ -695,6 +1333,42 @@ func mapTraktItemsToTraktBody(items entities.TraktItems) entities.TraktListBody
 	return res
 }
 
+// paginate fetches every page of the items of a paginated endpoint, one page after the other, until the page count
+// that trakt reports in the pagination headers is reached. Trakt caps the number of items per page, so large lists
+// would otherwise be compared against their first page only. Endpoints that report no page count are fetched once.
+func paginate[T any](tc *TraktClient, endpoint string) ([]T, error) {
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+	items := make([]T, 0)
+	for page := 1; ; page++ {
+		response, err := tc.doRequest(requestFields{
+			Method:   http.MethodGet,
+			BasePath: traktPathBaseAPI,
+			Endpoint: fmt.Sprintf("%s%spage=%d&limit=%d", endpoint, separator, page, traktPageLimit),
+			Body:     http.NoBody,
+			Headers:  tc.defaultApiHeaders(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if response.StatusCode == http.StatusNotFound {
+			response.Body.Close()
+			return nil, errTraktPageNotFound
+		}
+		var pageItems []T
+		if err = decodeReaderInto(response.Body, &pageItems); err != nil {
+			return nil, err
+		}
+		items = append(items, pageItems...)
+		pageCount, err := strconv.Atoi(response.Header.Get(traktHeaderKeyPageCount))
+		if err != nil || page >= pageCount || len(pageItems) == 0 {
+			return items, nil
+		}
+	}
+}
+
 func decodeReader[T any](rc io.ReadCloser) (T, error) {
 	defer rc.Close()
 	var response T
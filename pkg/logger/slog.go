@@ -1,19 +1,53 @@
 package logger
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"io"
 	"log/slog"
+	"strings"
 )
 
-const keyError = "error"
+const (
+	keyError  = "error"
+	keySyncID = "syncID"
+
+	FormatJSON = "json"
+	FormatText = "text"
+)
 
 func NewLogger(writer io.Writer) *slog.Logger {
+	return NewLoggerWithOptions(writer, slog.LevelInfo, FormatJSON)
+}
+
+func NewLoggerWithOptions(writer io.Writer, level slog.Level, format string) *slog.Logger {
 	opts := &slog.HandlerOptions{
 		AddSource: true,
-		Level:     slog.LevelInfo,
+		Level:     level,
+	}
+	if format == FormatText {
+		return slog.New(slog.NewTextHandler(writer, opts))
+	}
+	return slog.New(slog.NewJSONHandler(writer, opts))
+}
+
+func ParseLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.ToUpper(level))); err != nil {
+		return l, fmt.Errorf("failure parsing log level %s: %w", level, err)
 	}
-	handler := slog.NewJSONHandler(writer, opts)
-	return slog.New(handler)
+	return l, nil
+}
+
+func WithSyncID(logger *slog.Logger, syncID string) *slog.Logger {
+	return logger.With(slog.String(keySyncID, syncID))
+}
+
+func NewSyncID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
 }
 
 func Error(err error) slog.Attr {